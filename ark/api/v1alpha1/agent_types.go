@@ -29,7 +29,7 @@ type ToolPartial struct {
 
 type AgentTool struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=built-in;custom;mcp;http;agent;team;builtin
+	// +kubebuilder:validation:Enum=built-in;custom;mcp;http;agent;team;builtin;kubernetes;grpc
 	Type string `json:"type"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
@@ -44,6 +44,10 @@ type AgentTool struct {
 	// from the agent. Parameters defined here are injected at runtime and are not visible or
 	// editable by the agent itself.
 	Partial *ToolPartial `json:"partial,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(fail|report|retry:[1-9][0-9]*)$`
+	// How errors from this tool are handled. Overrides the agent's toolErrorPolicy
+	ErrorPolicy string `json:"errorPolicy,omitempty"`
 }
 
 // GetToolCRDName returns the actual Tool CRD name to lookup in Kubernetes.
@@ -82,6 +86,10 @@ type AgentSpec struct {
 	// +kubebuilder:validation:Optional
 	ModelRef *AgentModelRef `json:"modelRef,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Models tried in order when modelRef cannot be loaded, is not available, or fails with a
+	// rate limit, server or connection error
+	FallbackModels []AgentModelRef `json:"fallbackModels,omitempty"`
+	// +kubebuilder:validation:Optional
 	// ExecutionEngine to use for running this agent. If not specified, uses the built-in OpenAI-compatible engine
 	ExecutionEngine *ExecutionEngineRef `json:"executionEngine,omitempty"`
 	Tools           []AgentTool         `json:"tools,omitempty"`
@@ -89,10 +97,73 @@ type AgentSpec struct {
 	// Parameters for template processing in the prompt field
 	Parameters []Parameter `json:"parameters,omitempty"`
 	// +kubebuilder:validation:Optional
-	// JSON schema for structured output format
+	// JSON schema for structured output format, with type object at its root
 	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of times the model is re-prompted with the validation errors when its final response
+	// does not match outputSchema. Defaults to 2
+	OutputSchemaRepairAttempts *int `json:"outputSchemaRepairAttempts,omitempty"`
+	// +kubebuilder:validation:Optional
 	Overrides []Override `json:"overrides,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of model calls the agent may make while resolving tool calls. Unbounded if not specified
+	MaxIterations *int `json:"maxIterations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When maxIterations is reached, make one final model call without tools instead of failing
+	FinalResponseOnMaxIterations bool `json:"finalResponseOnMaxIterations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tool calls from a single model response executed concurrently. Tool calls run sequentially if not specified
+	MaxParallelToolCalls *int `json:"maxParallelToolCalls,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(fail|report|retry:[1-9][0-9]*)$`
+	// How tool errors are handled: fail the agent, report the error to the model as the tool result,
+	// or retry transient failures up to N times with backoff. Defaults to fail
+	ToolErrorPolicy string `json:"toolErrorPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Keeps the conversation sent to the model within a token budget
+	ContextPolicy *ContextPolicy `json:"contextPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tools that must be approved by a human before they are executed
+	ToolApproval *ToolApprovalPolicy `json:"toolApproval,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Guardrails applied to the agent's input and final response
+	Guardrails []GuardrailRef `json:"guardrails,omitempty"`
+}
+
+// ToolApprovalPolicy selects the tools whose calls pause the query until an approver
+// approves or rejects them through the Query's spec.toolApprovals.
+type ToolApprovalPolicy struct {
+	// +kubebuilder:validation:Optional
+	// Require approval for tools annotated with destructiveHint that are not readOnlyHint
+	Destructive bool `json:"destructive,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Names of tools, as exposed to the agent, that always require approval
+	Tools []string `json:"tools,omitempty"`
+}
+
+// ContextPolicy bounds the messages sent to the model on every call so long
+// conversations do not exceed the model's context window. Token counts are estimated locally.
+type ContextPolicy struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// Maximum estimated number of tokens sent to the model in a single call
+	MaxTokens int `json:"maxTokens"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=drop-oldest;keep-first-last;summarize
+	// +kubebuilder:default=drop-oldest
+	// How messages are removed when the conversation exceeds maxTokens
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of earliest messages kept by the keep-first-last strategy
+	KeepFirst int `json:"keepFirst,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of most recent messages kept by the keep-first-last strategy. Must be at least 1 with that strategy
+	KeepLast int `json:"keepLast,omitempty"`
 }
 
 type AgentStatus struct {
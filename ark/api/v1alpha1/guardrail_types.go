@@ -0,0 +1,114 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// GuardrailSpec defines the checks a Guardrail applies to the input and output of the agents,
+// teams and queries that reference it. The guardrail triggers when any configured check matches.
+type GuardrailSpec struct {
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=input;output
+	// Stages the guardrail is applied to. Defaults to both input and output
+	Stages []string `json:"stages,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=block;redact;warn
+	// +kubebuilder:default=block
+	// Action taken when the guardrail triggers: block the content and fail the execution,
+	// redact the matched text, or only record a warning. With redact, content over maxLength
+	// is truncated, and jsonSchema and classifier violations block since there is nothing to redact
+	Action string `json:"action,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Message reported in place of the blocked content. Defaults to a description of the violation
+	Message string `json:"message,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Terms that must not appear in the content, matched case-insensitively
+	Denylist []string `json:"denylist,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Regular expressions, in RE2 syntax, that must not match the content
+	Patterns []string `json:"patterns,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=email;phone;creditCard;ssn;ipAddress
+	// Kinds of personally identifiable information that must not appear in the content
+	PII []string `json:"pii,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum length of the content in characters
+	MaxLength *int `json:"maxLength,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// JSON schema the content must be a valid JSON document for
+	JSONSchema *runtime.RawExtension `json:"jsonSchema,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// External or model-based classifier that flags content
+	Classifier *GuardrailClassifier `json:"classifier,omitempty"`
+}
+
+// GuardrailClassifier flags content using either an HTTP service or a model. Exactly one
+// of address and modelRef must be set.
+type GuardrailClassifier struct {
+	// +kubebuilder:validation:Optional
+	// Address of an HTTP classifier. It receives a POST with {"stage", "content"} and
+	// responds with {"flagged", "reason"}
+	Address *ValueSource `json:"address,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Headers contains HTTP headers to include in classifier requests
+	Headers []Header `json:"headers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Model asked to classify the content
+	ModelRef *AgentModelRef `json:"modelRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Instructions describing the content the model should flag
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// GuardrailRef references a Guardrail applied to input and output
+type GuardrailRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// Namespace of the Guardrail. Defaults to the namespace of the referencing resource
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="Action taken when the guardrail triggers"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the guardrail"
+
+// Guardrail is the Schema for the guardrails API.
+type Guardrail struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GuardrailSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GuardrailList contains a list of Guardrail.
+type GuardrailList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Guardrail `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Guardrail{}, &GuardrailList{})
+}
@@ -14,6 +14,10 @@ type ModelConfig struct {
 	Azure *AzureModelConfig `json:"azure,omitempty"`
 	// +kubebuilder:validation:Optional
 	Bedrock *BedrockModelConfig `json:"bedrock,omitempty"`
+	// +kubebuilder:validation:Optional
+	Anthropic *AnthropicModelConfig `json:"anthropic,omitempty"`
+	// +kubebuilder:validation:Optional
+	Gemini *GeminiModelConfig `json:"gemini,omitempty"`
 }
 
 // AzureModelConfig contains Azure OpenAI specific parameters
@@ -67,31 +71,280 @@ type BedrockModelConfig struct {
 	Properties map[string]ValueSource `json:"properties,omitempty"`
 }
 
-type ModelSpec struct {
+// AnthropicModelConfig contains Anthropic Messages API specific parameters
+type AnthropicModelConfig struct {
+	// +kubebuilder:validation:Optional
+	// Base URL of the Messages API. Defaults to https://api.anthropic.com
+	BaseURL *ValueSource `json:"baseUrl,omitempty"`
 	// +kubebuilder:validation:Required
-	Model ValueSource `json:"model"`
-	// Type specifies the API capability of the model (e.g., completions, embeddings).
+	APIKey ValueSource `json:"apiKey"`
+	// +kubebuilder:validation:Optional
+	// Value of the anthropic-version header. Defaults to 2023-06-01
+	APIVersion *ValueSource `json:"apiVersion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Mark the system prompt and tool definitions as cacheable so repeated calls
+	// with the same prefix are served from the prompt cache
+	PromptCaching bool `json:"promptCaching,omitempty"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// GeminiModelConfig contains Google Gemini and Vertex AI specific parameters. Exactly one of
+// apiKey and serviceAccountKey must be set.
+type GeminiModelConfig struct {
+	// +kubebuilder:validation:Optional
+	// Base URL of the API. Defaults to the Gemini API with apiKey and to the Vertex AI endpoint
+	// of the location with serviceAccountKey
+	BaseURL *ValueSource `json:"baseUrl,omitempty"`
+	// +kubebuilder:validation:Optional
+	// API key for the Gemini API
+	APIKey *ValueSource `json:"apiKey,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Service account key JSON used to call Vertex AI
+	ServiceAccountKey *ValueSource `json:"serviceAccountKey,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Google Cloud project for Vertex AI. Defaults to the project of the service account
+	Project *ValueSource `json:"project,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Vertex AI location. Defaults to us-central1
+	Location *ValueSource `json:"location,omitempty"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+type ModelSpec struct {
+	// +kubebuilder:validation:Optional
+	// Name of the model at the provider. Required unless the type is router.
+	Model ValueSource `json:"model,omitempty"`
+	// Type specifies the API capability of the model (e.g., completions, embeddings, responses).
 	// Deprecated: The values "openai", "azure", "bedrock" are accepted for backward
 	// compatibility but will be removed in release 1.0. Use spec.provider instead.
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:validation:Enum=completions;openai;azure;bedrock
+	// The type router dispatches each call to one of the models of spec.router.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=completions;embeddings;responses;router;openai;azure;bedrock
 	// +kubebuilder:default=completions
 	Type string `json:"type,omitempty"`
-	// Provider specifies the AI provider client to use (openai, azure, bedrock).
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=openai;azure;bedrock
-	Provider string `json:"provider"`
-	// +kubebuilder:validation:Required
-	Config ModelConfig `json:"config"`
+	// Provider specifies the AI provider client to use (openai, azure, bedrock, anthropic, gemini).
+	// Required unless the type is router.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=openai;azure;bedrock;anthropic;gemini
+	Provider string `json:"provider,omitempty"`
+	// +kubebuilder:validation:Optional
+	Config ModelConfig `json:"config,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="1m"
 	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Limits on calls to the model, shared by all queries handled by the controller
+	RateLimits *ModelRateLimits `json:"rateLimits,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Retries of calls to the model that fail with a rate limit, a server error or a connection failure
+	RetryPolicy *ModelRetryPolicy `json:"retryPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cache of completions, so repeated identical calls are served without calling the model
+	Cache *ModelCache `json:"cache,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Price of the model's tokens, used to report the cost of queries
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Models the calls of a router model are dispatched to. Required when the type is router.
+	Router *ModelRouter `json:"router,omitempty"`
+	// +kubebuilder:validation:Optional
+	// How much reasoning models reason before answering
+	Reasoning *ModelReasoning `json:"reasoning,omitempty"`
+}
+
+// ModelReasoning controls reasoning models. The effort is sent as reasoning_effort to OpenAI and
+// Azure OpenAI, and as a thinking budget to Anthropic, Gemini and Claude models on Bedrock.
+type ModelReasoning struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=low;medium;high
+	// How much the model reasons before answering
+	Effort string `json:"effort"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1024
+	// Maximum number of tokens spent reasoning, for providers that take a thinking budget.
+	// Defaults to 1024, 4096 or 16384 tokens for the low, medium and high efforts.
+	BudgetTokens int `json:"budgetTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Ask the model for a summary of its reasoning and record it in telemetry
+	Summary bool `json:"summary,omitempty"`
+}
+
+// ModelRateLimits bounds the throughput of calls to a model. Calls over a limit wait in a
+// first-come, first-served queue. Token counts are estimated locally before each call.
+type ModelRateLimits struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of calls started per minute
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tokens used per minute
+	TokensPerMinute int `json:"tokensPerMinute,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of calls in progress at once
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+}
+
+// ModelRetryPolicy controls how failed calls to a model are retried. The delay doubles after each
+// attempt, with jitter, unless the provider asks for a delay with a Retry-After header.
+type ModelRetryPolicy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// Maximum number of attempts per call, including the first. Set to 1 to disable retries.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1s"
+	// Delay before the first retry
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// Longest delay between attempts, including delays the provider asks for
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// ModelCache configures the completion cache of a model. Calls are cached on the model, its
+// properties, the messages, the tools and the output schema.
+type ModelCache struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1h"
+	// How long a completion is served from the cache
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1000
+	// Maximum number of completions kept by the in-memory cache
+	MaxEntries int `json:"maxEntries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1048576
+	// Completions larger than this number of bytes are not cached
+	MaxEntryBytes int `json:"maxEntryBytes,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Stores completions in an external service instead of in memory
+	HTTP *ModelCacheHTTP `json:"http,omitempty"`
+}
+
+// ModelPricing is the price of a model's tokens. Prices are decimal strings, per million tokens.
+type ModelPricing struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// Price of a million input tokens
+	InputPerMillion string `json:"inputPerMillion"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// Price of a million output tokens
+	OutputPerMillion string `json:"outputPerMillion"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// Price of a million input tokens read from the provider's prompt cache. Defaults to the input price.
+	CachedInputPerMillion string `json:"cachedInputPerMillion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[A-Z]{3}$`
+	// +kubebuilder:default=USD
+	// ISO 4217 code of the currency of the prices
+	Currency string `json:"currency,omitempty"`
+}
+
+// ModelRouter dispatches each call to one of its routes. The routes whose conditions match the
+// call are candidates, and candidates whose ModelAvailable condition is False are only used when
+// no other candidate is left. A candidate is picked at random in proportion to its weight, and
+// calls that fail with a rate limit, a server error or a connection failure move on to the next.
+type ModelRouter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Models calls are dispatched to
+	Routes []ModelRoute `json:"routes"`
+}
+
+// ModelRoute is a model a router dispatches calls to
+type ModelRoute struct {
+	// +kubebuilder:validation:Required
+	// Model called by the route. Routers cannot be routed to.
+	ModelRef AgentModelRef `json:"modelRef"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// Share of the matching calls sent to the model, relative to the weights of the other matching routes
+	Weight int `json:"weight,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Calls the route is limited to. Routes without conditions match every call.
+	When *ModelRouteCondition `json:"when,omitempty"`
+}
+
+// ModelRouteCondition matches calls on the estimated length of their prompt and their use of
+// tools. All the conditions that are set must match.
+type ModelRouteCondition struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Minimum estimated number of prompt tokens
+	MinPromptTokens int `json:"minPromptTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum estimated number of prompt tokens
+	MaxPromptTokens int `json:"maxPromptTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Matches calls offering tools to the model when true, and calls without tools when false
+	Tools *bool `json:"tools,omitempty"`
+}
+
+// ModelCacheHTTP is a cache service implementing the GET and SET commands of the Redis REST API
+type ModelCacheHTTP struct {
+	// +kubebuilder:validation:Required
+	// Base URL of the service
+	URL ValueSource `json:"url"`
+	// +kubebuilder:validation:Optional
+	// Bearer token sent to the service
+	Token *ValueSource `json:"token,omitempty"`
+}
+
+// ModelCapabilities are the features of a model detected by the model controller. Features the
+// controller could not detect are left unset.
+type ModelCapabilities struct {
+	// +kubebuilder:validation:Optional
+	// Generation of the model the capabilities were detected for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model accepts tool definitions
+	ToolCalling *bool `json:"toolCalling,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model can call several tools in one response
+	ParallelToolCalls *bool `json:"parallelToolCalls,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model can stream its responses
+	Streaming *bool `json:"streaming,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model can answer with JSON following a schema
+	StructuredOutput *bool `json:"structuredOutput,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model accepts images in messages
+	VisionInput *bool `json:"visionInput,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Maximum number of input tokens, when the provider advertises it
+	ContextWindow int64 `json:"contextWindow,omitempty"`
 }
 
 type ModelStatus struct {
 	// +kubebuilder:validation:Optional
 	// ResolvedAddress contains the actual resolved base URL value
 	ResolvedAddress string `json:"resolvedAddress,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Capabilities detected by probing the model, once per generation of the model
+	Capabilities *ModelCapabilities `json:"capabilities,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time of the latest probe that changed the status of the model
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Duration of the call made by the probe at lastProbeTime, if it succeeded
+	ProbeLatency *metav1.Duration `json:"probeLatency,omitempty"`
 	// Conditions represent the latest available observations of a model's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -102,6 +355,7 @@ type ModelStatus struct {
 // +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.provider`
 // +kubebuilder:printcolumn:name="Model",type=string,JSONPath=`.spec.model.value`
 // +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="ModelAvailable")].status`
+// +kubebuilder:printcolumn:name="Latency",type=string,JSONPath=`.status.probeLatency`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type Model struct {
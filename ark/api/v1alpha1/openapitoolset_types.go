@@ -0,0 +1,125 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenAPIToolSetSpec defines an OpenAPI document whose operations are generated as http Tools
+type OpenAPIToolSetSpec struct {
+	// +kubebuilder:validation:Required
+	// OpenAPI 3 document, in JSON or YAML
+	Spec OpenAPISpecSource `json:"spec"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^https?://.*"
+	// URL the operation paths are appended to. Defaults to the first server of the document
+	BaseURL string `json:"baseUrl,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Operations to generate tools for. Defaults to all operations
+	Include *OpenAPIOperationFilter `json:"include,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Operations not to generate tools for, even if included
+	Exclude *OpenAPIOperationFilter `json:"exclude,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Headers sent with the requests of every generated tool
+	Headers []Header `json:"headers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Authentication of the requests of every generated tool
+	Auth *HTTPAuth `json:"auth,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+[smh]?$
+	// Timeout of the requests of every generated tool
+	Timeout string `json:"timeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="5m"
+	// How often the document is loaded again to keep the tools in sync
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+// OpenAPISpecSource is where an OpenAPI document is loaded from. Exactly one field must be set.
+type OpenAPISpecSource struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^https?://.*"
+	// URL the document is fetched from. Relative server URLs of the document are resolved against it
+	URL string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// ConfigMap key holding the document
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// The document itself
+	Inline string `json:"inline,omitempty"`
+}
+
+// OpenAPIOperationFilter matches the operations that match every field it sets, and any entry of
+// each field
+type OpenAPIOperationFilter struct {
+	// +kubebuilder:validation:Optional
+	// Operation IDs, with * and ? wildcards
+	OperationIDs []string `json:"operationIds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Tags of the operations
+	Tags []string `json:"tags,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Paths of the operations, with * and ? wildcards matching within a path segment
+	Paths []string `json:"paths,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=GET;POST;PUT;DELETE;PATCH
+	Methods []string `json:"methods,omitempty"`
+}
+
+// OpenAPIToolSetStatus defines the observed state of OpenAPIToolSet
+type OpenAPIToolSetStatus struct {
+	// +kubebuilder:validation:Optional
+	// Number of tools generated from the document
+	ToolCount int `json:"toolCount,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Operations that were selected but could not be generated as tools, with the reason
+	SkippedOperations []string `json:"skippedOperations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Conditions represent the latest available observations of the tool set's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type=='Available')].status"
+// +kubebuilder:printcolumn:name="Tools",type="integer",JSONPath=".status.toolCount",description="Number of tools"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// OpenAPIToolSet is the Schema for the openapitoolsets API.
+type OpenAPIToolSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenAPIToolSetSpec   `json:"spec,omitempty"`
+	Status OpenAPIToolSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenAPIToolSetList contains a list of OpenAPIToolSet.
+type OpenAPIToolSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenAPIToolSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenAPIToolSet{}, &OpenAPIToolSetList{})
+}
@@ -5,6 +5,8 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/openai/openai-go"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,6 +19,8 @@ type QueryConditionType string
 const (
 	// QueryCompleted indicates that the query has finished (regardless of outcome)
 	QueryCompleted QueryConditionType = "Completed"
+	// QueryGuardrailTriggered indicates that at least one guardrail matched the query input or output
+	QueryGuardrailTriggered QueryConditionType = "GuardrailTriggered"
 )
 
 const (
@@ -82,6 +86,45 @@ type QuerySpec struct {
 	Cancel bool `json:"cancel,omitempty"`
 	// +kubebuilder:validation:Optional
 	Overrides []Override `json:"overrides,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Overrides maxIterations for every agent executed by this query
+	MaxIterations *int `json:"maxIterations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Decisions for tool calls awaiting approval, added by an approver while the query is awaiting-approval
+	ToolApprovals []ToolApproval `json:"toolApprovals,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Guardrails applied to the query input and the final response of the target
+	Guardrails []GuardrailRef `json:"guardrails,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When true, model calls made by this query are neither served from nor stored in model caches
+	BypassCache bool `json:"bypassCache,omitempty"`
+}
+
+// ToolApproval approves or rejects a pending tool call
+type ToolApproval struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// ID of the pending tool call, as shown in status.pendingToolApprovals
+	ToolCallID string `json:"toolCallId"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=approve;reject
+	Decision string `json:"decision"`
+	// +kubebuilder:validation:Optional
+	// Reason for the decision. For rejections it is passed to the agent
+	Reason string `json:"reason,omitempty"`
+}
+
+// PendingToolApproval is a tool call waiting for an approval decision
+type PendingToolApproval struct {
+	ToolCallID string `json:"toolCallId"`
+	ToolName   string `json:"toolName"`
+	// +kubebuilder:validation:Optional
+	Agent string `json:"agent,omitempty"`
+	// +kubebuilder:validation:Optional
+	// JSON encoded arguments the model supplied for the call
+	Arguments   string      `json:"arguments,omitempty"`
+	RequestedAt metav1.Time `json:"requestedAt"`
 }
 
 // A2AMetadata contains optional A2A protocol metadata
@@ -103,6 +146,16 @@ type Response struct {
 	// +kubebuilder:validation:Optional
 	// A2A contains optional A2A protocol metadata (contextId, taskId)
 	A2A *A2AMetadata `json:"a2a,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Structured holds the parsed content when the target is an agent with an outputSchema
+	// and its response matches the schema
+	Structured *runtime.RawExtension `json:"structured,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// Embeddings holds one vector per input when the target is a model of type embeddings
+	Embeddings *runtime.RawExtension `json:"embeddings,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -124,11 +177,20 @@ type TokenUsage struct {
 	PromptTokens     int64 `json:"promptTokens,omitempty"`
 	CompletionTokens int64 `json:"completionTokens,omitempty"`
 	TotalTokens      int64 `json:"totalTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Completion tokens the model spent reasoning before its answer
+	ReasoningTokens int64 `json:"reasoningTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Prompt tokens read from the provider's prompt cache
+	CachedTokens int64 `json:"cachedTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost of the tokens by currency, as decimal strings, for the models that have pricing
+	Cost map[string]string `json:"cost,omitempty"`
 }
 
 type QueryStatus struct {
 	// +kubebuilder:default="pending"
-	// +kubebuilder:validation:Enum=pending;running;error;done;canceled
+	// +kubebuilder:validation:Enum=pending;running;awaiting-approval;error;done;canceled
 	Phase string `json:"phase,omitempty"`
 	// +kubebuilder:validation:Optional
 	// Conditions represent the latest available observations of a query's state
@@ -140,6 +202,9 @@ type QueryStatus struct {
 	ConversationId string `json:"conversationId,omitempty"`
 	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tool calls waiting for a decision in spec.toolApprovals
+	PendingToolApprovals []PendingToolApproval `json:"pendingToolApprovals,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -216,6 +281,41 @@ func (q *QuerySpec) GetInputAsGeneric() (interface{}, error) {
 	}
 }
 
+// Add adds the tokens and the cost of other to u
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.ReasoningTokens += other.ReasoningTokens
+	u.CachedTokens += other.CachedTokens
+	for currency, amount := range other.Cost {
+		u.AddCost(currency, parseCost(amount))
+	}
+}
+
+// AddCost adds an amount in the given currency to the cost of u
+func (u *TokenUsage) AddCost(currency string, amount float64) {
+	if u.Cost == nil {
+		u.Cost = map[string]string{}
+	}
+	u.Cost[currency] = FormatCost(parseCost(u.Cost[currency]) + amount)
+}
+
+// FormatCost formats an amount of money as a decimal string with up to nine decimal places, so
+// the cost of single calls to cheap models is not rounded away
+func FormatCost(amount float64) string {
+	formatted := strings.TrimRight(strconv.FormatFloat(amount, 'f', 9, 64), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+func parseCost(amount string) float64 {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
 func init() {
 	SchemeBuilder.Register(&Query{}, &QueryList{})
 }
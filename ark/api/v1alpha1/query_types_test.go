@@ -0,0 +1,30 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenUsage_Add(t *testing.T) {
+	usage := TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, Cost: map[string]string{"USD": "0.000025"}}
+
+	usage.Add(TokenUsage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30, Cost: map[string]string{"USD": "0.5", "EUR": "0.1"}})
+	usage.Add(TokenUsage{PromptTokens: 1, TotalTokens: 1})
+
+	assert.Equal(t, TokenUsage{
+		PromptTokens:     31,
+		CompletionTokens: 15,
+		TotalTokens:      46,
+		Cost:             map[string]string{"USD": "0.500025", "EUR": "0.1"},
+	}, usage)
+}
+
+func TestFormatCost(t *testing.T) {
+	assert.Equal(t, "0", FormatCost(0))
+	assert.Equal(t, "1.5", FormatCost(1.5))
+	assert.Equal(t, "0.00000005", FormatCost(0.00000005))
+	assert.Equal(t, "0.3", FormatCost(0.1+0.2))
+}
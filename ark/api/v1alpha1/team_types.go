@@ -32,6 +32,9 @@ type TeamSpec struct {
 	MaxTurns    *int              `json:"maxTurns,omitempty"`
 	Selector    *TeamSelectorSpec `json:"selector,omitempty"`
 	Graph       *TeamGraphSpec    `json:"graph,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Guardrails applied to the team's input and final response
+	Guardrails []GuardrailRef `json:"guardrails,omitempty"`
 }
 
 type TeamStatus struct {
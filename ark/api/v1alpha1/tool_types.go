@@ -83,7 +83,7 @@ type ToolAnnotations struct {
 
 type ToolSpec struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=http;mcp;agent;team;builtin
+	// +kubebuilder:validation:Enum=http;mcp;agent;team;builtin;kubernetes;grpc
 	Type string `json:"type"`
 	// Tool description
 	Description string `json:"description,omitempty"`
@@ -108,6 +108,14 @@ type ToolSpec struct {
 	// This field is required only if Type = "builtin".
 	// +kubebuilder:validation:Optional
 	Builtin *BuiltinToolRef `json:"builtin,omitempty"`
+	// Kubernetes-specific configuration for kubernetes tools.
+	// This field is required only if Type = "kubernetes".
+	// +kubebuilder:validation:Optional
+	Kubernetes *KubernetesToolSpec `json:"kubernetes,omitempty"`
+	// gRPC-specific configuration for grpc tools.
+	// This field is required only if Type = "grpc".
+	// +kubebuilder:validation:Optional
+	GRPC *GRPCSpec `json:"grpc,omitempty"`
 }
 
 type HTTPSpec struct {
@@ -126,25 +134,227 @@ type HTTPSpec struct {
 	// +kubebuilder:validation:Optional
 	// Parameters for body template processing
 	BodyParameters []Parameter `json:"bodyParameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Authentication of the requests
+	Auth *HTTPAuth `json:"auth,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Query parameters added to the URL
+	QueryParameters []HTTPQueryParameter `json:"queryParameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// URL encoded form body, instead of body
+	Form []HTTPFormField `json:"form,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Multipart form body, instead of body
+	Multipart []HTTPFormField `json:"multipart,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Pattern=`^[1-5][0-9]{2}(-[1-5][0-9]{2})?$`
+	// Status codes of successful responses, as codes or ranges such as 200-299 (default 200-399)
+	SuccessStatusCodes []string `json:"successStatusCodes,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Responses are truncated to this many bytes before they are passed to the model (default 1048576)
+	MaxResponseBytes int `json:"maxResponseBytes,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=auto;raw
+	// How responses are passed to the model: auto pretty-prints JSON and converts HTML to text,
+	// raw passes the body unchanged (default auto)
+	ResponseFormat string `json:"responseFormat,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Retries of GET, PUT and DELETE requests that fail with a connection error or a 408, 429 or 5xx status
+	RetryPolicy *HTTPRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// HTTPQueryParameter is a query parameter of an HTTP tool's URL
+type HTTPQueryParameter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	// Value with golang template syntax, with the same data as the body. Parameters whose value
+	// is empty are left out.
+	Value string `json:"value"`
+}
+
+// HTTPFormField is a field of a form or multipart body
+type HTTPFormField struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// Value with golang template syntax, with the same data as the body
+	Value string `json:"value,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Sends the field of a multipart body as a file with this name
+	FileName string `json:"fileName,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Content type of a file field (default application/octet-stream)
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// HTTPRetryPolicy retries the requests of an HTTP tool with exponential backoff
+type HTTPRetryPolicy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// Maximum number of attempts per call, including the first
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1s"
+	// Delay before the first retry
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// Longest delay between attempts, unless the server asks for a longer one with Retry-After
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// HTTPAuth authenticates the requests of an HTTP tool. At most one of oauth2 and basic may be
+// set, and either may be combined with a client certificate.
+type HTTPAuth struct {
+	// +kubebuilder:validation:Optional
+	// Bearer tokens from an OAuth2 client credentials grant
+	OAuth2 *OAuth2ClientCredentials `json:"oauth2,omitempty"`
+	// +kubebuilder:validation:Optional
+	// HTTP basic authentication
+	Basic *BasicAuth `json:"basic,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Client certificate for mutual TLS, also used for the OAuth2 token requests
+	ClientCertificate *TLSClientCertificate `json:"clientCertificate,omitempty"`
+}
+
+// OAuth2ClientCredentials gets access tokens with the OAuth2 client credentials grant. Tokens
+// are cached and refreshed before they expire.
+type OAuth2ClientCredentials struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.*"
+	TokenURL string `json:"tokenUrl"`
+	// +kubebuilder:validation:Required
+	ClientID ValueSource `json:"clientId"`
+	// +kubebuilder:validation:Required
+	ClientSecret ValueSource `json:"clientSecret"`
+	// +kubebuilder:validation:Optional
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// BasicAuth is a username and password sent with HTTP basic authentication
+type BasicAuth struct {
+	// +kubebuilder:validation:Required
+	Username ValueSource `json:"username"`
+	// +kubebuilder:validation:Required
+	Password ValueSource `json:"password"`
+}
+
+// TLSClientCertificate is a PEM encoded certificate and private key presented to the server
+type TLSClientCertificate struct {
+	// +kubebuilder:validation:Required
+	// PEM encoded client certificate
+	Cert ValueSource `json:"cert"`
+	// +kubebuilder:validation:Required
+	// PEM encoded private key of the certificate
+	Key ValueSource `json:"key"`
+	// +kubebuilder:validation:Optional
+	// PEM encoded CA certificates that verify the server, instead of the system roots
+	CA *ValueSource `json:"ca,omitempty"`
+}
+
+// KubernetesToolSpec configures a tool that reads and changes cluster resources with the
+// permissions of the query's service account
+type KubernetesToolSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=get;list;describe;logs;patch;scale
+	// Operations the tool offers (default get, list, describe and logs). patch and scale change
+	// resources, so they require the tool annotations destructiveHint true and readOnlyHint false
+	Verbs []string `json:"verbs,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Resources the tool may access, as plural names such as pods, or with their API group such
+	// as deployments.apps (default all resources)
+	Resources []string `json:"resources,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Namespaces the tool may access, or * for all namespaces and cluster-scoped resources
+	// (default the namespace of the tool)
+	Namespaces []string `json:"namespaces,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Output is truncated to this many bytes before it is passed to the model (default 65536)
+	MaxResponseBytes int `json:"maxResponseBytes,omitempty"`
+}
+
+// GRPCSpec configures a tool that calls a unary method of a gRPC service. Without an input
+// schema, the schema is derived from the method's request message.
+type GRPCSpec struct {
+	// +kubebuilder:validation:Required
+	// Address of the server as host:port, or a service reference
+	Address ValueSource `json:"address"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Fully qualified name of the service, such as orders.v1.OrderService
+	Service string `json:"service"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name of the method of the service
+	Method string `json:"method"`
+	// +kubebuilder:validation:Optional
+	// Connects with TLS. Without it, calls and their metadata are sent to the server unencrypted.
+	TLS *GRPCTLS `json:"tls,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Metadata sent with each call
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+[smh]?$
+	// Deadline of each call, as a duration with a unit such as 30s (default 30s)
+	Timeout string `json:"timeout,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Base64 encoded FileDescriptorSet with the service and its dependencies, used instead of
+	// server reflection
+	DescriptorSet *ValueSource `json:"descriptorSet,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Responses are truncated to this many bytes before they are passed to the model (default 1048576)
+	MaxResponseBytes int `json:"maxResponseBytes,omitempty"`
+}
+
+// GRPCTLS configures the TLS connection to a gRPC server
+type GRPCTLS struct {
+	// +kubebuilder:validation:Optional
+	// Name the server certificate is verified against (default the host of the address)
+	ServerName string `json:"serverName,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Skips the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// +kubebuilder:validation:Optional
+	// PEM encoded CA certificates that verify the server, instead of the system roots
+	CA *ValueSource `json:"ca,omitempty"`
+	// +kubebuilder:validation:Optional
+	// PEM encoded client certificate for mutual TLS, set together with key
+	Cert *ValueSource `json:"cert,omitempty"`
+	// +kubebuilder:validation:Optional
+	// PEM encoded private key of the client certificate
+	Key *ValueSource `json:"key,omitempty"`
 }
 
 // Tool type constants
 const (
-	ToolTypeHTTP    = "http"
-	ToolTypeMCP     = "mcp"
-	ToolTypeAgent   = "agent"
-	ToolTypeTeam    = "team"
-	ToolTypeBuiltin = "builtin"
+	ToolTypeHTTP       = "http"
+	ToolTypeMCP        = "mcp"
+	ToolTypeAgent      = "agent"
+	ToolTypeTeam       = "team"
+	ToolTypeBuiltin    = "builtin"
+	ToolTypeKubernetes = "kubernetes"
+	ToolTypeGRPC       = "grpc"
 )
 
 // Tool state constants
 const (
 	ToolStateReady = "Ready"
+	ToolStateError = "Error"
 )
 
 type ToolStatus struct {
 	State   string `json:"state,omitempty"`
 	Message string `json:"message,omitempty"`
+	// Input schema derived from the tool's target, such as the request message of a gRPC
+	// method. Used when the spec does not set one.
+	InputSchema *runtime.RawExtension `json:"inputSchema,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -205,6 +415,16 @@ func (in *ToolSpec) DeepCopyInto(out *ToolSpec) {
 		*out = new(BuiltinToolRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesToolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 func (in *MCPServerRef) DeepCopyInto(out *MCPServerRef) {
@@ -237,4 +457,34 @@ func (in *HTTPSpec) DeepCopyInto(out *HTTPSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryParameters != nil {
+		in, out := &in.QueryParameters, &out.QueryParameters
+		*out = make([]HTTPQueryParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Form != nil {
+		in, out := &in.Form, &out.Form
+		*out = make([]HTTPFormField, len(*in))
+		copy(*out, *in)
+	}
+	if in.Multipart != nil {
+		in, out := &in.Multipart, &out.Multipart
+		*out = make([]HTTPFormField, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuccessStatusCodes != nil {
+		in, out := &in.SuccessStatusCodes, &out.SuccessStatusCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(HTTPRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
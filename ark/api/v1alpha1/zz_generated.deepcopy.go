@@ -374,6 +374,11 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(AgentModelRef)
 		**out = **in
 	}
+	if in.FallbackModels != nil {
+		in, out := &in.FallbackModels, &out.FallbackModels
+		*out = make([]AgentModelRef, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExecutionEngine != nil {
 		in, out := &in.ExecutionEngine, &out.ExecutionEngine
 		*out = new(ExecutionEngineRef)
@@ -398,6 +403,11 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OutputSchemaRepairAttempts != nil {
+		in, out := &in.OutputSchemaRepairAttempts, &out.OutputSchemaRepairAttempts
+		*out = new(int)
+		**out = **in
+	}
 	if in.Overrides != nil {
 		in, out := &in.Overrides, &out.Overrides
 		*out = make([]Override, len(*in))
@@ -405,6 +415,31 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxIterations != nil {
+		in, out := &in.MaxIterations, &out.MaxIterations
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxParallelToolCalls != nil {
+		in, out := &in.MaxParallelToolCalls, &out.MaxParallelToolCalls
+		*out = new(int)
+		**out = **in
+	}
+	if in.ContextPolicy != nil {
+		in, out := &in.ContextPolicy, &out.ContextPolicy
+		*out = new(ContextPolicy)
+		**out = **in
+	}
+	if in.ToolApproval != nil {
+		in, out := &in.ToolApproval, &out.ToolApproval
+		*out = new(ToolApprovalPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]GuardrailRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -481,6 +516,46 @@ func (in *AgentToolRef) DeepCopy() *AgentToolRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnthropicModelConfig) DeepCopyInto(out *AnthropicModelConfig) {
+	*out = *in
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.APIVersion != nil {
+		in, out := &in.APIVersion, &out.APIVersion
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnthropicModelConfig.
+func (in *AnthropicModelConfig) DeepCopy() *AnthropicModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AnthropicModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureModelConfig) DeepCopyInto(out *AzureModelConfig) {
 	*out = *in
@@ -532,6 +607,23 @@ func (in *BaselineEvaluationConfig) DeepCopy() *BaselineEvaluationConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BatchEvaluationConfig) DeepCopyInto(out *BatchEvaluationConfig) {
 	*out = *in
@@ -732,6 +824,21 @@ func (in *ChildEvaluationStatus) DeepCopy() *ChildEvaluationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextPolicy) DeepCopyInto(out *ContextPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextPolicy.
+func (in *ContextPolicy) DeepCopy() *ContextPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectEvaluationConfig) DeepCopyInto(out *DirectEvaluationConfig) {
 	*out = *in
@@ -916,7 +1023,7 @@ func (in *EvaluationStatus) DeepCopyInto(out *EvaluationStatus) {
 	if in.TokenUsage != nil {
 		in, out := &in.TokenUsage, &out.TokenUsage
 		*out = new(TokenUsage)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Duration != nil {
 		in, out := &in.Duration, &out.Duration
@@ -1099,103 +1206,143 @@ func (in *ExpressionRule) DeepCopy() *ExpressionRule {
 	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSpec.
-func (in *HTTPSpec) DeepCopy() *HTTPSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(HTTPSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Header) DeepCopyInto(out *Header) {
+func (in *GRPCSpec) DeepCopyInto(out *GRPCSpec) {
 	*out = *in
-	in.Value.DeepCopyInto(&out.Value)
+	in.Address.DeepCopyInto(&out.Address)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(GRPCTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DescriptorSet != nil {
+		in, out := &in.DescriptorSet, &out.DescriptorSet
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Header.
-func (in *Header) DeepCopy() *Header {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCSpec.
+func (in *GRPCSpec) DeepCopy() *GRPCSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Header)
+	out := new(GRPCSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HeaderValue) DeepCopyInto(out *HeaderValue) {
+func (in *GRPCTLS) DeepCopyInto(out *GRPCTLS) {
 	*out = *in
-	if in.ValueFrom != nil {
-		in, out := &in.ValueFrom, &out.ValueFrom
-		*out = new(HeaderValueSource)
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(ValueSource)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderValue.
-func (in *HeaderValue) DeepCopy() *HeaderValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCTLS.
+func (in *GRPCTLS) DeepCopy() *GRPCTLS {
 	if in == nil {
 		return nil
 	}
-	out := new(HeaderValue)
+	out := new(GRPCTLS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HeaderValueSource) DeepCopyInto(out *HeaderValueSource) {
+func (in *GeminiModelConfig) DeepCopyInto(out *GeminiModelConfig) {
 	*out = *in
-	if in.SecretKeyRef != nil {
-		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(corev1.SecretKeySelector)
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(ValueSource)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ConfigMapKeyRef != nil {
-		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
-		*out = new(corev1.ConfigMapKeySelector)
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueSource)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.QueryParameterRef != nil {
-		in, out := &in.QueryParameterRef, &out.QueryParameterRef
-		*out = new(QueryParameterReference)
-		**out = **in
+	if in.ServiceAccountKey != nil {
+		in, out := &in.ServiceAccountKey, &out.ServiceAccountKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderValueSource.
-func (in *HeaderValueSource) DeepCopy() *HeaderValueSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeminiModelConfig.
+func (in *GeminiModelConfig) DeepCopy() *GeminiModelConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(HeaderValueSource)
+	out := new(GeminiModelConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+func (in *Guardrail) DeepCopyInto(out *Guardrail) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
-func (in *MCPServer) DeepCopy() *MCPServer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Guardrail.
+func (in *Guardrail) DeepCopy() *Guardrail {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServer)
+	out := new(Guardrail)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServer) DeepCopyObject() runtime.Object {
+func (in *Guardrail) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1203,99 +1350,446 @@ func (in *MCPServer) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+func (in *GuardrailClassifier) DeepCopyInto(out *GuardrailClassifier) {
+	*out = *in
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ModelRef != nil {
+		in, out := &in.ModelRef, &out.ModelRef
+		*out = new(AgentModelRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailClassifier.
+func (in *GuardrailClassifier) DeepCopy() *GuardrailClassifier {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailClassifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailList) DeepCopyInto(out *GuardrailList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MCPServer, len(*in))
+		*out = make([]Guardrail, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
-func (in *MCPServerList) DeepCopy() *MCPServerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailList.
+func (in *GuardrailList) DeepCopy() *GuardrailList {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerList)
+	out := new(GuardrailList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServerList) DeepCopyObject() runtime.Object {
+func (in *GuardrailList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerRef.
-func (in *MCPServerRef) DeepCopy() *MCPServerRef {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailRef) DeepCopyInto(out *GuardrailRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailRef.
+func (in *GuardrailRef) DeepCopy() *GuardrailRef {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerRef)
+	out := new(GuardrailRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+func (in *GuardrailSpec) DeepCopyInto(out *GuardrailSpec) {
 	*out = *in
-	in.Address.DeepCopyInto(&out.Address)
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make([]Header, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.PollInterval != nil {
-		in, out := &in.PollInterval, &out.PollInterval
-		*out = new(v1.Duration)
+	if in.Denylist != nil {
+		in, out := &in.Denylist, &out.Denylist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PII != nil {
+		in, out := &in.PII, &out.PII
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int)
 		**out = **in
 	}
+	if in.JSONSchema != nil {
+		in, out := &in.JSONSchema, &out.JSONSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Classifier != nil {
+		in, out := &in.Classifier, &out.Classifier
+		*out = new(GuardrailClassifier)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
-func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailSpec.
+func (in *GuardrailSpec) DeepCopy() *GuardrailSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerSpec)
+	out := new(GuardrailSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+func (in *HTTPAuth) DeepCopyInto(out *HTTPAuth) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.OAuth2 != nil {
+		in, out := &in.OAuth2, &out.OAuth2
+		*out = new(OAuth2ClientCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Basic != nil {
+		in, out := &in.Basic, &out.Basic
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(TLSClientCertificate)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
-func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAuth.
+func (in *HTTPAuth) DeepCopy() *HTTPAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerStatus)
+	out := new(HTTPAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPToolRef.
-func (in *MCPToolRef) DeepCopy() *MCPToolRef {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPFormField) DeepCopyInto(out *HTTPFormField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPFormField.
+func (in *HTTPFormField) DeepCopy() *HTTPFormField {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPFormField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPQueryParameter) DeepCopyInto(out *HTTPQueryParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPQueryParameter.
+func (in *HTTPQueryParameter) DeepCopy() *HTTPQueryParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPQueryParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRetryPolicy) DeepCopyInto(out *HTTPRetryPolicy) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRetryPolicy.
+func (in *HTTPRetryPolicy) DeepCopy() *HTTPRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSpec.
+func (in *HTTPSpec) DeepCopy() *HTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Header) DeepCopyInto(out *Header) {
+	*out = *in
+	in.Value.DeepCopyInto(&out.Value)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Header.
+func (in *Header) DeepCopy() *Header {
+	if in == nil {
+		return nil
+	}
+	out := new(Header)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderValue) DeepCopyInto(out *HeaderValue) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(HeaderValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderValue.
+func (in *HeaderValue) DeepCopy() *HeaderValue {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderValueSource) DeepCopyInto(out *HeaderValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryParameterRef != nil {
+		in, out := &in.QueryParameterRef, &out.QueryParameterRef
+		*out = new(QueryParameterReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderValueSource.
+func (in *HeaderValueSource) DeepCopy() *HeaderValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesToolSpec) DeepCopyInto(out *KubernetesToolSpec) {
+	*out = *in
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesToolSpec.
+func (in *KubernetesToolSpec) DeepCopy() *KubernetesToolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesToolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
+func (in *MCPServer) DeepCopy() *MCPServer {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPServer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MCPServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
+func (in *MCPServerList) DeepCopy() *MCPServerList {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPServerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerRef.
+func (in *MCPServerRef) DeepCopy() *MCPServerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+	*out = *in
+	in.Address.DeepCopyInto(&out.Address)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
+func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
+func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPToolRef.
+func (in *MCPToolRef) DeepCopy() *MCPToolRef {
 	if in == nil {
 		return nil
 	}
@@ -1345,43 +1839,515 @@ func (in *MemoryList) DeepCopyInto(out *MemoryList) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryList.
-func (in *MemoryList) DeepCopy() *MemoryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryList.
+func (in *MemoryList) DeepCopy() *MemoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MemoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryRef) DeepCopyInto(out *MemoryRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRef.
+func (in *MemoryRef) DeepCopy() *MemoryRef {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
+	*out = *in
+	in.Address.DeepCopyInto(&out.Address)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemorySpec.
+func (in *MemorySpec) DeepCopy() *MemorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryStatus) DeepCopyInto(out *MemoryStatus) {
+	*out = *in
+	if in.LastResolvedAddress != nil {
+		in, out := &in.LastResolvedAddress, &out.LastResolvedAddress
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryStatus.
+func (in *MemoryStatus) DeepCopy() *MemoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Model) DeepCopyInto(out *Model) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
+func (in *Model) DeepCopy() *Model {
+	if in == nil {
+		return nil
+	}
+	out := new(Model)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Model) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelCache) DeepCopyInto(out *ModelCache) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(ModelCacheHTTP)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelCache.
+func (in *ModelCache) DeepCopy() *ModelCache {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelCacheHTTP) DeepCopyInto(out *ModelCacheHTTP) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelCacheHTTP.
+func (in *ModelCacheHTTP) DeepCopy() *ModelCacheHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelCacheHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelCapabilities) DeepCopyInto(out *ModelCapabilities) {
+	*out = *in
+	if in.ToolCalling != nil {
+		in, out := &in.ToolCalling, &out.ToolCalling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ParallelToolCalls != nil {
+		in, out := &in.ParallelToolCalls, &out.ParallelToolCalls
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StructuredOutput != nil {
+		in, out := &in.StructuredOutput, &out.StructuredOutput
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VisionInput != nil {
+		in, out := &in.VisionInput, &out.VisionInput
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelCapabilities.
+func (in *ModelCapabilities) DeepCopy() *ModelCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
+	*out = *in
+	if in.OpenAI != nil {
+		in, out := &in.OpenAI, &out.OpenAI
+		*out = new(OpenAIModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bedrock != nil {
+		in, out := &in.Bedrock, &out.Bedrock
+		*out = new(BedrockModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Anthropic != nil {
+		in, out := &in.Anthropic, &out.Anthropic
+		*out = new(AnthropicModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gemini != nil {
+		in, out := &in.Gemini, &out.Gemini
+		*out = new(GeminiModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
+func (in *ModelConfig) DeepCopy() *ModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelList) DeepCopyInto(out *ModelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Model, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelList.
+func (in *ModelList) DeepCopy() *ModelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPricing) DeepCopyInto(out *ModelPricing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPricing.
+func (in *ModelPricing) DeepCopy() *ModelPricing {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRateLimits) DeepCopyInto(out *ModelRateLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRateLimits.
+func (in *ModelRateLimits) DeepCopy() *ModelRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelReasoning) DeepCopyInto(out *ModelReasoning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelReasoning.
+func (in *ModelReasoning) DeepCopy() *ModelReasoning {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelReasoning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRetryPolicy) DeepCopyInto(out *ModelRetryPolicy) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRetryPolicy.
+func (in *ModelRetryPolicy) DeepCopy() *ModelRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRoute) DeepCopyInto(out *ModelRoute) {
+	*out = *in
+	out.ModelRef = in.ModelRef
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(ModelRouteCondition)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRoute.
+func (in *ModelRoute) DeepCopy() *ModelRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRouteCondition) DeepCopyInto(out *ModelRouteCondition) {
+	*out = *in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRouteCondition.
+func (in *ModelRouteCondition) DeepCopy() *ModelRouteCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRouteCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRouter) DeepCopyInto(out *ModelRouter) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]ModelRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRouter.
+func (in *ModelRouter) DeepCopy() *ModelRouter {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRouter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+	*out = *in
+	in.Model.DeepCopyInto(&out.Model)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = new(ModelRateLimits)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(ModelRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(ModelCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pricing != nil {
+		in, out := &in.Pricing, &out.Pricing
+		*out = new(ModelPricing)
+		**out = **in
+	}
+	if in.Router != nil {
+		in, out := &in.Router, &out.Router
+		*out = new(ModelRouter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reasoning != nil {
+		in, out := &in.Reasoning, &out.Reasoning
+		*out = new(ModelReasoning)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
+func (in *ModelSpec) DeepCopy() *ModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(ModelCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ProbeLatency != nil {
+		in, out := &in.ProbeLatency, &out.ProbeLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
+func (in *ModelStatus) DeepCopy() *ModelStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryList)
+	out := new(ModelStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MemoryList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryRef) DeepCopyInto(out *MemoryRef) {
+func (in *OAuth2ClientCredentials) DeepCopyInto(out *OAuth2ClientCredentials) {
 	*out = *in
+	in.ClientID.DeepCopyInto(&out.ClientID)
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRef.
-func (in *MemoryRef) DeepCopy() *MemoryRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2ClientCredentials.
+func (in *OAuth2ClientCredentials) DeepCopy() *OAuth2ClientCredentials {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryRef)
+	out := new(OAuth2ClientCredentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
+func (in *OpenAIModelConfig) DeepCopyInto(out *OpenAIModelConfig) {
 	*out = *in
-	in.Address.DeepCopyInto(&out.Address)
+	in.BaseURL.DeepCopyInto(&out.BaseURL)
+	in.APIKey.DeepCopyInto(&out.APIKey)
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = make([]Header, len(*in))
@@ -1389,121 +2355,133 @@ func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemorySpec.
-func (in *MemorySpec) DeepCopy() *MemorySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAIModelConfig.
+func (in *OpenAIModelConfig) DeepCopy() *OpenAIModelConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MemorySpec)
+	out := new(OpenAIModelConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryStatus) DeepCopyInto(out *MemoryStatus) {
+func (in *OpenAPIOperationFilter) DeepCopyInto(out *OpenAPIOperationFilter) {
 	*out = *in
-	if in.LastResolvedAddress != nil {
-		in, out := &in.LastResolvedAddress, &out.LastResolvedAddress
-		*out = new(string)
-		**out = **in
+	if in.OperationIDs != nil {
+		in, out := &in.OperationIDs, &out.OperationIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryStatus.
-func (in *MemoryStatus) DeepCopy() *MemoryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIOperationFilter.
+func (in *OpenAPIOperationFilter) DeepCopy() *OpenAPIOperationFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryStatus)
+	out := new(OpenAPIOperationFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Model) DeepCopyInto(out *Model) {
+func (in *OpenAPISpecSource) DeepCopyInto(out *OpenAPISpecSource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
-func (in *Model) DeepCopy() *Model {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPISpecSource.
+func (in *OpenAPISpecSource) DeepCopy() *OpenAPISpecSource {
 	if in == nil {
 		return nil
 	}
-	out := new(Model)
+	out := new(OpenAPISpecSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Model) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
+func (in *OpenAPIToolSet) DeepCopyInto(out *OpenAPIToolSet) {
 	*out = *in
-	if in.OpenAI != nil {
-		in, out := &in.OpenAI, &out.OpenAI
-		*out = new(OpenAIModelConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Azure != nil {
-		in, out := &in.Azure, &out.Azure
-		*out = new(AzureModelConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Bedrock != nil {
-		in, out := &in.Bedrock, &out.Bedrock
-		*out = new(BedrockModelConfig)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
-func (in *ModelConfig) DeepCopy() *ModelConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIToolSet.
+func (in *OpenAPIToolSet) DeepCopy() *OpenAPIToolSet {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelConfig)
+	out := new(OpenAPIToolSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenAPIToolSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelList) DeepCopyInto(out *ModelList) {
+func (in *OpenAPIToolSetList) DeepCopyInto(out *OpenAPIToolSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Model, len(*in))
+		*out = make([]OpenAPIToolSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelList.
-func (in *ModelList) DeepCopy() *ModelList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIToolSetList.
+func (in *OpenAPIToolSetList) DeepCopy() *OpenAPIToolSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelList)
+	out := new(OpenAPIToolSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ModelList) DeepCopyObject() runtime.Object {
+func (in *OpenAPIToolSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1511,10 +2489,31 @@ func (in *ModelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+func (in *OpenAPIToolSetSpec) DeepCopyInto(out *OpenAPIToolSetSpec) {
 	*out = *in
-	in.Model.DeepCopyInto(&out.Model)
-	in.Config.DeepCopyInto(&out.Config)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = new(OpenAPIOperationFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = new(OpenAPIOperationFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PollInterval != nil {
 		in, out := &in.PollInterval, &out.PollInterval
 		*out = new(v1.Duration)
@@ -1522,19 +2521,24 @@ func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
-func (in *ModelSpec) DeepCopy() *ModelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIToolSetSpec.
+func (in *OpenAPIToolSetSpec) DeepCopy() *OpenAPIToolSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelSpec)
+	out := new(OpenAPIToolSetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+func (in *OpenAPIToolSetStatus) DeepCopyInto(out *OpenAPIToolSetStatus) {
 	*out = *in
+	if in.SkippedOperations != nil {
+		in, out := &in.SkippedOperations, &out.SkippedOperations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -1544,43 +2548,12 @@ func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
-func (in *ModelStatus) DeepCopy() *ModelStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(ModelStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenAIModelConfig) DeepCopyInto(out *OpenAIModelConfig) {
-	*out = *in
-	in.BaseURL.DeepCopyInto(&out.BaseURL)
-	in.APIKey.DeepCopyInto(&out.APIKey)
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make([]Header, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]ValueSource, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAIModelConfig.
-func (in *OpenAIModelConfig) DeepCopy() *OpenAIModelConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPIToolSetStatus.
+func (in *OpenAPIToolSetStatus) DeepCopy() *OpenAPIToolSetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenAIModelConfig)
+	out := new(OpenAPIToolSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1632,6 +2605,22 @@ func (in *Parameter) DeepCopy() *Parameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingToolApproval) DeepCopyInto(out *PendingToolApproval) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingToolApproval.
+func (in *PendingToolApproval) DeepCopy() *PendingToolApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingToolApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Query) DeepCopyInto(out *Query) {
 	*out = *in
@@ -1813,6 +2802,21 @@ func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxIterations != nil {
+		in, out := &in.MaxIterations, &out.MaxIterations
+		*out = new(int)
+		**out = **in
+	}
+	if in.ToolApprovals != nil {
+		in, out := &in.ToolApprovals, &out.ToolApprovals
+		*out = make([]ToolApproval, len(*in))
+		copy(*out, *in)
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]GuardrailRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySpec.
@@ -1840,12 +2844,19 @@ func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
 		*out = new(Response)
 		(*in).DeepCopyInto(*out)
 	}
-	out.TokenUsage = in.TokenUsage
+	in.TokenUsage.DeepCopyInto(&out.TokenUsage)
 	if in.Duration != nil {
 		in, out := &in.Duration, &out.Duration
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.PendingToolApprovals != nil {
+		in, out := &in.PendingToolApprovals, &out.PendingToolApprovals
+		*out = make([]PendingToolApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatus.
@@ -1908,6 +2919,16 @@ func (in *Response) DeepCopyInto(out *Response) {
 		*out = new(A2AMetadata)
 		**out = **in
 	}
+	if in.Structured != nil {
+		in, out := &in.Structured, &out.Structured
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Embeddings != nil {
+		in, out := &in.Embeddings, &out.Embeddings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Response.
@@ -1935,6 +2956,28 @@ func (in *ServiceReference) DeepCopy() *ServiceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSClientCertificate) DeepCopyInto(out *TLSClientCertificate) {
+	*out = *in
+	in.Cert.DeepCopyInto(&out.Cert)
+	in.Key.DeepCopyInto(&out.Key)
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSClientCertificate.
+func (in *TLSClientCertificate) DeepCopy() *TLSClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Team) DeepCopyInto(out *Team) {
 	*out = *in
@@ -2082,6 +3125,11 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = new(TeamGraphSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]GuardrailRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -2129,6 +3177,13 @@ func (in *TeamToolRef) DeepCopy() *TeamToolRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenUsage) DeepCopyInto(out *TokenUsage) {
 	*out = *in
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenUsage.
@@ -2147,7 +3202,7 @@ func (in *Tool) DeepCopyInto(out *Tool) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tool.
@@ -2178,6 +3233,41 @@ func (in *ToolAnnotations) DeepCopy() *ToolAnnotations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApproval) DeepCopyInto(out *ToolApproval) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApproval.
+func (in *ToolApproval) DeepCopy() *ToolApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApprovalPolicy) DeepCopyInto(out *ToolApprovalPolicy) {
+	*out = *in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApprovalPolicy.
+func (in *ToolApprovalPolicy) DeepCopy() *ToolApprovalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApprovalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolFunction) DeepCopyInto(out *ToolFunction) {
 	*out = *in
@@ -2265,6 +3355,11 @@ func (in *ToolSpec) DeepCopy() *ToolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolStatus) DeepCopyInto(out *ToolStatus) {
 	*out = *in
+	if in.InputSchema != nil {
+		in, out := &in.InputSchema, &out.InputSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolStatus.
@@ -286,6 +286,11 @@ func setupControllers(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provi
 			Scheme:   mgr.GetScheme(),
 			Eventing: eventingProvider,
 		}},
+		{"OpenAPIToolSet", &controller.OpenAPIToolSetReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("openapitoolset-controller"),
+		}},
 	}
 
 	for _, reconciler := range controllers {
@@ -313,6 +318,8 @@ func setupWebhooks(mgr ctrl.Manager) {
 		{"MCPServer", webhookv1.SetupMCPServerWebhookWithManager},
 		{"Evaluator", webhookv1.SetupEvaluatorWebhookWithManager},
 		{"Evaluation", webhookv1.SetupEvaluationWebhookWithManager},
+		{"Guardrail", webhookv1.SetupGuardrailWebhookWithManager},
+		{"OpenAPIToolSet", webhookv1.SetupOpenAPIToolSetWebhookWithManager},
 		{"A2AServer", webhookv1prealpha1.SetupA2AServerWebhookWithManager},
 		{"ExecutionEngine", webhookv1prealpha1.SetupExecutionEngineWebhookWithManager},
 	}
@@ -2,16 +2,22 @@ package common
 
 import (
 	"bytes"
+	"encoding/json"
 	"text/template"
 )
 
+// templateFuncs are the functions available to templates
+var templateFuncs = template.FuncMap{
+	"toJson": toJSON,
+}
+
 // ResolveTemplate resolves Go template strings using provided data.
 // Returns the resolved string or the original template if an error occurs.
 func ResolveTemplate(tmpl string, data map[string]any) (string, error) {
 	if tmpl == "" {
 		return "", nil
 	}
-	t, err := template.New("template").Parse(tmpl)
+	t, err := template.New("template").Funcs(templateFuncs).Parse(tmpl)
 	if err != nil {
 		return "", err
 	}
@@ -21,3 +27,12 @@ func ResolveTemplate(tmpl string, data map[string]any) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// toJSON encodes a value as JSON, so templates can embed arguments such as objects in JSON bodies
+func toJSON(value any) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -116,13 +116,31 @@ func (r *AgentReconciler) checkDependencies(ctx context.Context, agent *arkv1alp
 	return true, "Available", "All dependencies are available"
 }
 
-// checkModelDependency validates model dependency
+// checkModelDependency validates the model dependency. An agent with fallback models is
+// available as long as one of its models is.
 func (r *AgentReconciler) checkModelDependency(ctx context.Context, agent *arkv1alpha1.Agent) (bool, string) {
-	modelName := agent.Spec.ModelRef.Name
+	ok, msg := r.checkModelRef(ctx, agent, *agent.Spec.ModelRef)
+	if ok {
+		return true, ""
+	}
+	for _, fallback := range agent.Spec.FallbackModels {
+		if fallbackOK, _ := r.checkModelRef(ctx, agent, fallback); fallbackOK {
+			return true, ""
+		}
+	}
+	if len(agent.Spec.FallbackModels) > 0 {
+		msg += " and none of its fallback models are available"
+	}
+	return false, msg
+}
+
+// checkModelRef checks that a referenced model exists and is available
+func (r *AgentReconciler) checkModelRef(ctx context.Context, agent *arkv1alpha1.Agent, modelRef arkv1alpha1.AgentModelRef) (bool, string) {
+	modelName := modelRef.Name
 	modelNamespace := agent.Namespace
 
-	if agent.Spec.ModelRef.Namespace != "" {
-		modelNamespace = agent.Spec.ModelRef.Namespace
+	if modelRef.Namespace != "" {
+		modelNamespace = modelRef.Namespace
 	}
 
 	var model arkv1alpha1.Model
@@ -347,7 +365,15 @@ func (r *AgentReconciler) agentDependsOnTool(agent *arkv1alpha1.Agent, toolName
 
 // agentDependsOnModel checks if an agent depends on a specific model
 func (r *AgentReconciler) agentDependsOnModel(agent *arkv1alpha1.Agent, modelName string) bool {
-	return agent.Spec.ModelRef != nil && agent.Spec.ModelRef.Name == modelName
+	if agent.Spec.ModelRef != nil && agent.Spec.ModelRef.Name == modelName {
+		return true
+	}
+	for _, fallback := range agent.Spec.FallbackModels {
+		if fallback.Name == modelName {
+			return true
+		}
+	}
+	return false
 }
 
 // findAgentsForA2AServer finds agents owned by the given A2AServer
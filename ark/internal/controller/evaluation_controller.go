@@ -926,9 +926,7 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 
 		// Aggregate token usage
 		if child.Status.TokenUsage != nil {
-			aggregatedTokenUsage.PromptTokens += child.Status.TokenUsage.PromptTokens
-			aggregatedTokenUsage.CompletionTokens += child.Status.TokenUsage.CompletionTokens
-			aggregatedTokenUsage.TotalTokens += child.Status.TokenUsage.TotalTokens
+			aggregatedTokenUsage.Add(*child.Status.TokenUsage)
 		}
 	}
 
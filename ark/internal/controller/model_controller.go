@@ -4,7 +4,9 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,8 +48,11 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if err := r.Get(ctx, req.NamespacedName, &model); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			log.Error(err, "unable to fetch model", "model", req.NamespacedName)
+			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		genai.EvictCompletionCache(req.Namespace, req.Name)
+		genai.EvictGeminiTokenSource(req.Namespace, req.Name)
+		return ctrl.Result{}, nil
 	}
 
 	// Initialize conditions if empty
@@ -57,12 +62,18 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 	}
 
+	// Status updates trigger a reconcile, which waits for the next poll instead of probing again
+	if wait := timeUntilNextProbe(&model); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
 	// Probe the model to test whether it is available.
-	result := r.probeModel(ctx, model)
+	before := model.Status.DeepCopy()
+	result := r.probeModel(ctx, &model)
 
 	if !result.Available {
-		changed, err := r.reconcileCondition(ctx, &model, ModelAvailable, metav1.ConditionFalse, "ModelProbeFailed", result.Message)
-		if err != nil {
+		changed := setModelCondition(&model, ModelAvailable, metav1.ConditionFalse, "ModelProbeFailed", result.Message)
+		if err := r.recordProbe(ctx, &model, before, result); err != nil {
 			return ctrl.Result{}, err
 		}
 		// Log the failure only when condition changes
@@ -73,19 +84,22 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 				"status", result.Message,
 				"details", result.DetailedError)
 		}
-		return ctrl.Result{RequeueAfter: model.Spec.PollInterval.Duration}, nil
+		return ctrl.Result{RequeueAfter: modelPollInterval(&model)}, nil
 	}
 
 	// Success case - model is available
-	if _, err := r.reconcileCondition(ctx, &model, ModelAvailable, metav1.ConditionTrue, "Available", result.Message); err != nil {
+	setModelCondition(&model, ModelAvailable, metav1.ConditionTrue, "Available", result.Message)
+	if err := r.recordProbe(ctx, &model, before, result); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Continue polling at regular interval
-	return ctrl.Result{RequeueAfter: model.Spec.PollInterval.Duration}, nil
+	return ctrl.Result{RequeueAfter: modelPollInterval(&model)}, nil
 }
 
-func (r *ModelReconciler) probeModel(ctx context.Context, model arkv1alpha1.Model) genai.ProbeResult {
+// probeModel probes the model, and detects its capabilities when they have not been detected for
+// the current generation of the model
+func (r *ModelReconciler) probeModel(ctx context.Context, model *arkv1alpha1.Model) genai.ProbeResult {
 	noopTelemetryRecorder := telenoop.NewModelRecorder()
 	noopEventingRecorder := eventnoop.NewModelRecorder()
 	resolvedModel, err := genai.LoadModel(ctx, r.Client, &arkv1alpha1.AgentModelRef{
@@ -101,19 +115,66 @@ func (r *ModelReconciler) probeModel(ctx context.Context, model arkv1alpha1.Mode
 	}
 
 	result := genai.ProbeModel(ctx, resolvedModel)
+	if result.Available && (model.Status.Capabilities == nil || model.Status.Capabilities.ObservedGeneration != model.Generation) {
+		capabilities := genai.DetectCapabilities(ctx, resolvedModel)
+		capabilities.ObservedGeneration = model.Generation
+		model.Status.Capabilities = capabilities
+	}
 	return result
 }
 
-// reconcileCondition updates a condition on the Model and updates status
-// Returns true if the condition changed, false otherwise
-func (r *ModelReconciler) reconcileCondition(ctx context.Context, model *arkv1alpha1.Model, conditionType string, status metav1.ConditionStatus, reason, message string) (bool, error) {
-	changed := meta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+// recordProbe writes the status of the model after a probe. The status is only written when the
+// probe changed the model's conditions or capabilities, so polling a model that stays the same
+// does not write its status on every probe.
+func (r *ModelReconciler) recordProbe(ctx context.Context, model *arkv1alpha1.Model, before *arkv1alpha1.ModelStatus, result genai.ProbeResult) error {
+	if equality.Semantic.DeepEqual(before.Conditions, model.Status.Conditions) && equality.Semantic.DeepEqual(before.Capabilities, model.Status.Capabilities) {
+		return nil
+	}
+
+	now := metav1.Now()
+	model.Status.LastProbeTime = &now
+	model.Status.ProbeLatency = nil
+	if result.Available {
+		model.Status.ProbeLatency = &metav1.Duration{Duration: result.Latency.Round(time.Millisecond)}
+	}
+	return r.updateStatus(ctx, model)
+}
+
+// timeUntilNextProbe returns how long to wait before probing the model again. Models are probed
+// again right away when their spec changed since the latest probe.
+func timeUntilNextProbe(model *arkv1alpha1.Model) time.Duration {
+	if model.Status.LastProbeTime == nil {
+		return 0
+	}
+	condition := meta.FindStatusCondition(model.Status.Conditions, ModelAvailable)
+	if condition == nil || condition.ObservedGeneration != model.Generation {
+		return 0
+	}
+	return time.Until(model.Status.LastProbeTime.Add(modelPollInterval(model)))
+}
+
+func modelPollInterval(model *arkv1alpha1.Model) time.Duration {
+	if model.Spec.PollInterval == nil {
+		return time.Minute
+	}
+	return model.Spec.PollInterval.Duration
+}
+
+// setModelCondition sets a condition on the Model and returns true if the condition changed
+func setModelCondition(model *arkv1alpha1.Model, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
 		Type:               conditionType,
 		Status:             status,
 		Reason:             reason,
 		Message:            message,
 		ObservedGeneration: model.Generation,
 	})
+}
+
+// reconcileCondition updates a condition on the Model and updates status
+// Returns true if the condition changed, false otherwise
+func (r *ModelReconciler) reconcileCondition(ctx context.Context, model *arkv1alpha1.Model, conditionType string, status metav1.ConditionStatus, reason, message string) (bool, error) {
+	changed := setModelCondition(model, conditionType, status, reason, message)
 
 	if !changed {
 		return false, nil
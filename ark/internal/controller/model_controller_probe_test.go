@@ -0,0 +1,93 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	telenoop "mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestModelReconcile_ProbesOncePerPollInterval(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if request["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"id\": \"1\", \"object\": \"chat.completion.chunk\", \"choices\": [{\"index\": 0, \"delta\": {\"content\": \"Hi\"}}]}\n\ndata: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id": "1", "object": "chat.completion", "choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hello"}}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	model := &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt", Namespace: "default", Generation: 1},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:        arkv1alpha1.ValueSource{Value: "gpt-4o"},
+			Provider:     "openai",
+			PollInterval: &metav1.Duration{Duration: time.Minute},
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: server.URL},
+				APIKey:  arkv1alpha1.ValueSource{Value: "key"},
+			}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model).WithStatusSubresource(model).Build()
+	reconciler := &ModelReconciler{Client: k8sClient, Scheme: scheme, Telemetry: telenoop.NewProvider(), Eventing: eventnoop.NewProvider()}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "gpt", Namespace: "default"}}
+
+	result, err := reconciler.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+
+	var probed arkv1alpha1.Model
+	require.NoError(t, k8sClient.Get(context.Background(), request.NamespacedName, &probed))
+	require.NotNil(t, probed.Status.LastProbeTime)
+	require.NotNil(t, probed.Status.ProbeLatency)
+	require.NotNil(t, probed.Status.Capabilities)
+	assert.Equal(t, int64(1), probed.Status.Capabilities.ObservedGeneration)
+	assert.True(t, *probed.Status.Capabilities.ToolCalling)
+	assert.True(t, *probed.Status.Capabilities.Streaming)
+	callsAfterProbe := calls
+
+	// The status update triggers another reconcile, which waits for the next poll
+	result, err = reconciler.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, callsAfterProbe, calls)
+	assert.Greater(t, result.RequeueAfter, 50*time.Second)
+
+	// The next poll probes again, but the status is not written while nothing changed
+	past := metav1.NewTime(probed.Status.LastProbeTime.Add(-2 * time.Minute))
+	probed.Status.LastProbeTime = &past
+	require.NoError(t, k8sClient.Status().Update(context.Background(), &probed))
+	require.NoError(t, k8sClient.Get(context.Background(), request.NamespacedName, &probed))
+
+	_, err = reconciler.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	assert.Greater(t, calls, callsAfterProbe)
+	var polled arkv1alpha1.Model
+	require.NoError(t, k8sClient.Get(context.Background(), request.NamespacedName, &polled))
+	assert.Equal(t, probed.ResourceVersion, polled.ResourceVersion)
+}
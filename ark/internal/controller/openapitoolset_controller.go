@@ -0,0 +1,409 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/labels"
+)
+
+const (
+	// Condition types
+	OpenAPIToolSetAvailable = "Available"
+
+	defaultOpenAPIToolSetPollInterval = 5 * time.Minute
+	// openAPIDocumentMaxBytes bounds the size of documents fetched from a URL
+	openAPIDocumentMaxBytes = 10 << 20
+	// maxOpenAPIToolNameLength keeps tool names within the function name limits of model providers
+	maxOpenAPIToolNameLength = 63
+)
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+type OpenAPIToolSetReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	HTTPClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=openapitoolsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=openapitoolsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=openapitoolsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tools,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *OpenAPIToolSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var toolSet arkv1alpha1.OpenAPIToolSet
+	if err := r.Get(ctx, req.NamespacedName, &toolSet); err != nil {
+		if errors.IsNotFound(err) {
+			// Tools will be garbage collected due to owner references
+			log.Info("OpenAPIToolSet deleted, associated tools will be garbage collected", "toolSet", req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch OpenAPIToolSet")
+		return ctrl.Result{}, err
+	}
+
+	pollInterval := defaultOpenAPIToolSetPollInterval
+	if toolSet.Spec.PollInterval != nil && toolSet.Spec.PollInterval.Duration > 0 {
+		pollInterval = toolSet.Spec.PollInterval.Duration
+	}
+	status := toolSet.Status.DeepCopy()
+
+	document, documentURL, err := r.loadDocument(ctx, &toolSet)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: pollInterval}, r.updateStatus(ctx, &toolSet, status, "DocumentLoadFailed", err)
+	}
+
+	operations, err := genai.ParseOpenAPIOperations(document, toolSet.Spec.BaseURL, documentURL)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: pollInterval}, r.updateStatus(ctx, &toolSet, status, "DocumentInvalid", err)
+	}
+
+	tools, skipped := r.buildTools(&toolSet, operations)
+	toolsChanged, err := r.syncTools(ctx, &toolSet, tools)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: pollInterval}, r.updateStatus(ctx, &toolSet, status, "ToolCreationFailed", err)
+	}
+	if toolsChanged {
+		r.Recorder.Event(&toolSet, corev1.EventTypeNormal, "ToolsSynced", fmt.Sprintf("Synced %d tools from the OpenAPI document", len(tools)))
+	}
+
+	status.ToolCount = len(tools)
+	status.SkippedOperations = skipped
+	return ctrl.Result{RequeueAfter: pollInterval}, r.updateStatus(ctx, &toolSet, status, "ToolsGenerated", nil)
+}
+
+// loadDocument returns the OpenAPI document of the tool set, and the URL it was fetched from if any
+func (r *OpenAPIToolSetReconciler) loadDocument(ctx context.Context, toolSet *arkv1alpha1.OpenAPIToolSet) ([]byte, string, error) {
+	source := toolSet.Spec.Spec
+	switch {
+	case source.Inline != "":
+		return []byte(source.Inline), "", nil
+
+	case source.ConfigMapKeyRef != nil:
+		var configMap corev1.ConfigMap
+		key := client.ObjectKey{Name: source.ConfigMapKeyRef.Name, Namespace: toolSet.Namespace}
+		if err := r.Get(ctx, key, &configMap); err != nil {
+			return nil, "", fmt.Errorf("failed to get ConfigMap %s: %w", source.ConfigMapKeyRef.Name, err)
+		}
+		if value, ok := configMap.Data[source.ConfigMapKeyRef.Key]; ok {
+			return []byte(value), "", nil
+		}
+		if value, ok := configMap.BinaryData[source.ConfigMapKeyRef.Key]; ok {
+			return value, "", nil
+		}
+		return nil, "", fmt.Errorf("key %s not found in ConfigMap %s", source.ConfigMapKeyRef.Key, source.ConfigMapKeyRef.Name)
+
+	case source.URL != "":
+		document, err := r.fetchDocument(ctx, source.URL)
+		return document, source.URL, err
+	}
+	return nil, "", fmt.Errorf("spec requires one of url, configMapKeyRef or inline")
+}
+
+func (r *OpenAPIToolSetReconciler) fetchDocument(ctx context.Context, documentURL string) ([]byte, error) {
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", documentURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch %s: HTTP error %d: %s", documentURL, resp.StatusCode, resp.Status)
+	}
+	document, err := io.ReadAll(io.LimitReader(resp.Body, openAPIDocumentMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", documentURL, err)
+	}
+	if len(document) > openAPIDocumentMaxBytes {
+		return nil, fmt.Errorf("document %s is larger than %d bytes", documentURL, openAPIDocumentMaxBytes)
+	}
+	return document, nil
+}
+
+// buildTools returns the tools of the selected operations, and the selected operations that
+// cannot be generated as tools with the reason
+func (r *OpenAPIToolSetReconciler) buildTools(toolSet *arkv1alpha1.OpenAPIToolSet, operations []genai.OpenAPIOperation) ([]*arkv1alpha1.Tool, []string) {
+	var tools []*arkv1alpha1.Tool
+	var skipped []string
+	operationsByTool := map[string]string{}
+
+	for _, operation := range operations {
+		if (toolSet.Spec.Include != nil && !matchesOperationFilter(toolSet.Spec.Include, operation)) ||
+			(toolSet.Spec.Exclude != nil && matchesOperationFilter(toolSet.Spec.Exclude, operation)) {
+			continue
+		}
+		if operation.Unsupported != "" {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", operation.OperationID, operation.Unsupported))
+			continue
+		}
+		toolName := generateOpenAPIToolName(toolSet.Name, operation.OperationID)
+		if other, ok := operationsByTool[toolName]; ok {
+			skipped = append(skipped, fmt.Sprintf("%s: tool name %s is already used by operation %s", operation.OperationID, toolName, other))
+			continue
+		}
+		operationsByTool[toolName] = operation.OperationID
+		tools = append(tools, r.buildToolCRD(toolSet, operation, toolName))
+	}
+	return tools, skipped
+}
+
+// matchesOperationFilter reports whether the operation matches every field the filter sets
+func matchesOperationFilter(filter *arkv1alpha1.OpenAPIOperationFilter, operation genai.OpenAPIOperation) bool {
+	matchesPattern := func(patterns []string, value string) bool {
+		return len(patterns) == 0 || slices.ContainsFunc(patterns, func(pattern string) bool {
+			matched, _ := path.Match(pattern, value)
+			return matched
+		})
+	}
+	matchesTag := len(filter.Tags) == 0 || slices.ContainsFunc(operation.Tags, func(tag string) bool {
+		return slices.Contains(filter.Tags, tag)
+	})
+	matchesMethod := len(filter.Methods) == 0 || slices.ContainsFunc(filter.Methods, func(method string) bool {
+		return strings.EqualFold(method, operation.Method)
+	})
+	return matchesPattern(filter.OperationIDs, operation.OperationID) && matchesPattern(filter.Paths, operation.Path) && matchesTag && matchesMethod
+}
+
+// generateOpenAPIToolName returns the name of an operation's tool. Operation IDs such as
+// getOrderById become get-order-by-id, so tool names comply with Kubernetes RFC 1123 subdomain
+// rules. Names over the length limit are shortened, with a hash keeping them unique.
+func generateOpenAPIToolName(toolSetName, operationID string) string {
+	var words strings.Builder
+	for i, char := range operationID {
+		if i > 0 && char >= 'A' && char <= 'Z' {
+			previous := operationID[i-1]
+			if (previous >= 'a' && previous <= 'z') || (previous >= '0' && previous <= '9') {
+				words.WriteByte('-')
+			}
+		}
+		words.WriteRune(char)
+	}
+	sanitized := strings.Trim(invalidToolNameChars.ReplaceAllString(strings.ToLower(words.String()), "-"), "-")
+
+	name := fmt.Sprintf("%s-%s", toolSetName, sanitized)
+	if len(name) <= maxOpenAPIToolNameLength {
+		return name
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:8]
+	return strings.TrimRight(name[:maxOpenAPIToolNameLength-len(hash)-1], "-") + "-" + hash
+}
+
+func (r *OpenAPIToolSetReconciler) buildToolCRD(toolSet *arkv1alpha1.OpenAPIToolSet, operation genai.OpenAPIOperation, toolName string) *arkv1alpha1.Tool {
+	toolAnnotations := make(map[string]string)
+	for key, value := range toolSet.Annotations {
+		if strings.HasPrefix(key, annotations.ARKPrefix) {
+			toolAnnotations[key] = value
+		}
+	}
+
+	headers := make([]arkv1alpha1.Header, 0, len(toolSet.Spec.Headers)+1)
+	for _, header := range toolSet.Spec.Headers {
+		headers = append(headers, *header.DeepCopy())
+	}
+	if operation.ContentType != "" {
+		headers = append(headers, arkv1alpha1.Header{Name: "Content-Type", Value: arkv1alpha1.HeaderValue{Value: operation.ContentType}})
+	}
+
+	inputSchema, err := json.Marshal(operation.InputSchema)
+	if err != nil {
+		inputSchema = []byte("{}")
+	}
+
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      toolName,
+			Namespace: toolSet.Namespace,
+			Labels: map[string]string{
+				labels.OpenAPIToolSetLabel: toolSet.Name,
+			},
+			Annotations: toolAnnotations,
+		},
+		Spec: arkv1alpha1.ToolSpec{
+			Type:        genai.ToolTypeHTTP,
+			Description: operation.Description,
+			InputSchema: &runtime.RawExtension{Raw: inputSchema},
+			Annotations: openAPIToolAnnotations(operation.Method),
+			HTTP: &arkv1alpha1.HTTPSpec{
+				URL:             operation.URL,
+				Method:          operation.Method,
+				Headers:         headers,
+				Timeout:         toolSet.Spec.Timeout,
+				Body:            operation.Body,
+				Auth:            toolSet.Spec.Auth.DeepCopy(),
+				QueryParameters: operation.QueryParameters,
+			},
+		},
+	}
+
+	_ = controllerutil.SetControllerReference(toolSet, tool, r.Scheme)
+	return tool
+}
+
+// openAPIToolAnnotations returns the hints of a tool calling an operation with the given method, or
+// nil if the method does not tell whether the operation changes anything
+func openAPIToolAnnotations(method string) *arkv1alpha1.ToolAnnotations {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true}
+	case http.MethodDelete:
+		return &arkv1alpha1.ToolAnnotations{DestructiveHint: true}
+	}
+	return nil
+}
+
+// syncTools creates and updates the tools of the tool set, and deletes its tools for operations
+// that were removed or are no longer selected. It reports whether any tool changed.
+func (r *OpenAPIToolSetReconciler) syncTools(ctx context.Context, toolSet *arkv1alpha1.OpenAPIToolSet, tools []*arkv1alpha1.Tool) (bool, error) {
+	log := logf.FromContext(ctx)
+	changed := false
+
+	var existingTools arkv1alpha1.ToolList
+	if err := r.List(ctx, &existingTools, client.InNamespace(toolSet.Namespace), client.MatchingLabels{labels.OpenAPIToolSetLabel: toolSet.Name}); err != nil {
+		return false, fmt.Errorf("failed to list tools for OpenAPIToolSet %s: %w", toolSet.Name, err)
+	}
+
+	wanted := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		wanted[tool.Name] = true
+		toolChanged, err := r.createOrUpdateTool(ctx, toolSet, tool)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || toolChanged
+	}
+
+	for i := range existingTools.Items {
+		tool := &existingTools.Items[i]
+		if wanted[tool.Name] || !metav1.IsControlledBy(tool, toolSet) {
+			continue
+		}
+		if err := r.Delete(ctx, tool); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete tool %s: %w", tool.Name, err)
+		}
+		log.Info("tool crd deleted", "tool", tool.Name, "toolSet", toolSet.Name, "namespace", toolSet.Namespace)
+		changed = true
+	}
+	return changed, nil
+}
+
+func (r *OpenAPIToolSetReconciler) createOrUpdateTool(ctx context.Context, toolSet *arkv1alpha1.OpenAPIToolSet, tool *arkv1alpha1.Tool) (bool, error) {
+	log := logf.FromContext(ctx)
+	existingTool := &arkv1alpha1.Tool{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(tool), existingTool)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, tool); err != nil {
+			return false, fmt.Errorf("failed to create tool %s: %w", tool.Name, err)
+		}
+		log.Info("tool crd created", "tool", tool.Name, "namespace", tool.Namespace)
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get tool %s: %w", tool.Name, err)
+	}
+	// Tools written by hand are not replaced
+	if !metav1.IsControlledBy(existingTool, toolSet) {
+		return false, fmt.Errorf("tool %s already exists and is not managed by OpenAPIToolSet %s", tool.Name, toolSet.Name)
+	}
+
+	// Check if the spec, labels or annotations actually changed
+	toolSpecJSON, _ := json.Marshal(tool.Spec)
+	existingSpecJSON, _ := json.Marshal(existingTool.Spec)
+	if string(toolSpecJSON) == string(existingSpecJSON) && equality.Semantic.DeepEqual(tool.Labels, existingTool.Labels) &&
+		equality.Semantic.DeepEqual(tool.Annotations, existingTool.Annotations) {
+		return false, nil
+	}
+
+	existingTool.Spec = tool.Spec
+	existingTool.Labels = tool.Labels
+	existingTool.Annotations = tool.Annotations
+	if err := r.Update(ctx, existingTool); err != nil {
+		return false, fmt.Errorf("failed to update tool %s: %w", tool.Name, err)
+	}
+	log.Info("tool crd updated", "tool", tool.Name, "namespace", existingTool.Namespace)
+	return true, nil
+}
+
+// updateStatus sets the Available condition from the reason and error, and updates the status if
+// it changed. Failures are recorded as events and returned only if the status update fails, so
+// the tool set is retried at its poll interval rather than with backoff.
+func (r *OpenAPIToolSetReconciler) updateStatus(ctx context.Context, toolSet *arkv1alpha1.OpenAPIToolSet, status *arkv1alpha1.OpenAPIToolSetStatus, reason string, err error) error {
+	condition := metav1.Condition{
+		Type:               OpenAPIToolSetAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("Generated %d tools", status.ToolCount),
+		ObservedGeneration: toolSet.Generation,
+	}
+	if len(status.SkippedOperations) > 0 {
+		condition.Message += fmt.Sprintf(", skipped %d unsupported operations", len(status.SkippedOperations))
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	conditionChanged := meta.SetStatusCondition(&status.Conditions, condition)
+	if err != nil && conditionChanged {
+		logf.FromContext(ctx).Error(err, "failed to sync OpenAPIToolSet", "toolSet", toolSet.Name, "reason", reason)
+		r.Recorder.Event(toolSet, corev1.EventTypeWarning, reason, err.Error())
+	}
+
+	if equality.Semantic.DeepEqual(status, &toolSet.Status) || ctx.Err() != nil {
+		return nil
+	}
+	toolSet.Status = *status
+	if err := r.Status().Update(ctx, toolSet); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to update OpenAPIToolSet status")
+		return err
+	}
+	return nil
+}
+
+func (r *OpenAPIToolSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.OpenAPIToolSet{}).
+		Owns(&arkv1alpha1.Tool{}).
+		Named("openapitoolset").
+		Complete(r)
+}
@@ -0,0 +1,170 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/labels"
+)
+
+const testOrdersDocument = `
+openapi: 3.0.3
+info: {title: Orders, version: "1.0"}
+servers:
+  - url: https://orders.example.com/v1
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+    post:
+      operationId: createOrder
+      tags: [orders]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {type: object, properties: {customer: {type: string}}}
+  /orders/{id}:
+    get:
+      operationId: getOrderById
+      tags: [orders]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+  /admin/reset:
+    post:
+      operationId: resetAll
+      tags: [admin]
+`
+
+func TestOpenAPIToolSetReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	toolSet := &arkv1alpha1.OpenAPIToolSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default", UID: "toolset-uid", Generation: 1},
+		Spec: arkv1alpha1.OpenAPIToolSetSpec{
+			Spec:         arkv1alpha1.OpenAPISpecSource{Inline: testOrdersDocument},
+			Exclude:      &arkv1alpha1.OpenAPIOperationFilter{Tags: []string{"admin"}},
+			Headers:      []arkv1alpha1.Header{{Name: "X-API-Key", Value: arkv1alpha1.HeaderValue{Value: "key"}}},
+			Timeout:      "10s",
+			PollInterval: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(toolSet).WithStatusSubresource(toolSet).Build()
+	reconciler := &OpenAPIToolSetReconciler{Client: k8sClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "default"}}
+	ctx := context.Background()
+
+	result, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+
+	var tools arkv1alpha1.ToolList
+	require.NoError(t, k8sClient.List(ctx, &tools, client.MatchingLabels{labels.OpenAPIToolSetLabel: "orders"}))
+	names := make([]string, 0, len(tools.Items))
+	for _, tool := range tools.Items {
+		names = append(names, tool.Name)
+	}
+	assert.ElementsMatch(t, []string{"orders-list-orders", "orders-create-order", "orders-get-order-by-id"}, names)
+
+	var create arkv1alpha1.Tool
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "orders-create-order", Namespace: "default"}, &create))
+	assert.Equal(t, genai.ToolTypeHTTP, create.Spec.Type)
+	assert.Equal(t, "https://orders.example.com/v1/orders", create.Spec.HTTP.URL)
+	assert.Equal(t, "POST", create.Spec.HTTP.Method)
+	assert.Equal(t, "10s", create.Spec.HTTP.Timeout)
+	assert.Equal(t, []arkv1alpha1.Header{
+		{Name: "X-API-Key", Value: arkv1alpha1.HeaderValue{Value: "key"}},
+		{Name: "Content-Type", Value: arkv1alpha1.HeaderValue{Value: "application/json"}},
+	}, create.Spec.HTTP.Headers)
+	var inputSchema map[string]any
+	require.NoError(t, json.Unmarshal(create.Spec.InputSchema.Raw, &inputSchema))
+	assert.Equal(t, []any{"body"}, inputSchema["required"])
+	assert.True(t, metav1.IsControlledBy(&create, toolSet))
+	assert.Nil(t, create.Spec.Annotations)
+
+	var list arkv1alpha1.Tool
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "orders-list-orders", Namespace: "default"}, &list))
+	assert.Equal(t, &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true}, list.Spec.Annotations)
+
+	var updated arkv1alpha1.OpenAPIToolSet
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &updated))
+	assert.Equal(t, 3, updated.Status.ToolCount)
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, OpenAPIToolSetAvailable))
+
+	// Annotations set on the tool set are copied to its tools
+	updated.Annotations = map[string]string{"ark.mckinsey.com/dashboard-icon": "orders"}
+	require.NoError(t, k8sClient.Update(ctx, &updated))
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "orders-list-orders", Namespace: "default"}, &list))
+	assert.Equal(t, "orders", list.Annotations["ark.mckinsey.com/dashboard-icon"])
+
+	// Operations removed from the document are garbage collected
+	updated.Spec.Include = &arkv1alpha1.OpenAPIOperationFilter{Methods: []string{"GET"}}
+	require.NoError(t, k8sClient.Update(ctx, &updated))
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.List(ctx, &tools, client.MatchingLabels{labels.OpenAPIToolSetLabel: "orders"}))
+	assert.Len(t, tools.Items, 2)
+
+	// An invalid document keeps the tools and reports the error
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &updated))
+	updated.Spec.Spec.Inline = `{"swagger": "2.0"}`
+	require.NoError(t, k8sClient.Update(ctx, &updated))
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &updated))
+	condition := meta.FindStatusCondition(updated.Status.Conditions, OpenAPIToolSetAvailable)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "DocumentInvalid", condition.Reason)
+	require.NoError(t, k8sClient.List(ctx, &tools, client.MatchingLabels{labels.OpenAPIToolSetLabel: "orders"}))
+	assert.Len(t, tools.Items, 2)
+}
+
+func TestOpenAPIToolAnnotations(t *testing.T) {
+	assert.Equal(t, &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true}, openAPIToolAnnotations("GET"))
+	assert.Equal(t, &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true}, openAPIToolAnnotations("HEAD"))
+	assert.Equal(t, &arkv1alpha1.ToolAnnotations{DestructiveHint: true}, openAPIToolAnnotations("DELETE"))
+	assert.Nil(t, openAPIToolAnnotations("POST"))
+}
+
+func TestGenerateOpenAPIToolName(t *testing.T) {
+	assert.Equal(t, "orders-get-order-by-id", generateOpenAPIToolName("orders", "getOrderByID"))
+	assert.Equal(t, "orders-get-orders-id", generateOpenAPIToolName("orders", "get/orders/{id}"))
+	assert.Equal(t, "orders-list-v2-items", generateOpenAPIToolName("orders", "list_v2Items"))
+
+	long := generateOpenAPIToolName("orders", "searchOrdersByCustomerAndStatusAndCreationDateRangeWithPagination")
+	assert.Len(t, long, maxOpenAPIToolNameLength)
+	assert.NotEqual(t, long, generateOpenAPIToolName("orders", "searchOrdersByCustomerAndStatusAndCreationDateRangeWithPaging"))
+}
+
+func TestMatchesOperationFilter(t *testing.T) {
+	operation := genai.OpenAPIOperation{OperationID: "getOrder", Method: "GET", Path: "/orders/{id}", Tags: []string{"orders", "read"}}
+
+	assert.True(t, matchesOperationFilter(&arkv1alpha1.OpenAPIOperationFilter{}, operation))
+	assert.True(t, matchesOperationFilter(&arkv1alpha1.OpenAPIOperationFilter{OperationIDs: []string{"get*"}, Tags: []string{"read"}}, operation))
+	assert.True(t, matchesOperationFilter(&arkv1alpha1.OpenAPIOperationFilter{Paths: []string{"/orders/*"}, Methods: []string{"GET", "PUT"}}, operation))
+	assert.False(t, matchesOperationFilter(&arkv1alpha1.OpenAPIOperationFilter{Paths: []string{"/*"}}, operation))
+	assert.False(t, matchesOperationFilter(&arkv1alpha1.OpenAPIOperationFilter{OperationIDs: []string{"get*"}, Methods: []string{"DELETE"}}, operation))
+}
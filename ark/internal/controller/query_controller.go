@@ -3,6 +3,7 @@
 package controller
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -34,6 +36,16 @@ const (
 	targetTypeTool  = "tool"
 )
 
+// Completed condition reasons for target outcomes that differ from a plain success or error.
+const (
+	// reasonMaxIterationsReached marks queries whose agent exhausted its iteration budget.
+	reasonMaxIterationsReached = "MaxIterationsReached"
+	// reasonGuardrailBlocked marks queries whose input or output was blocked by a guardrail.
+	reasonGuardrailBlocked = "GuardrailBlocked"
+	// reasonOutputSchemaInvalid marks queries whose agent response never matched its outputSchema.
+	reasonOutputSchemaInvalid = "OutputSchemaInvalid"
+)
+
 // QueryReconciler reconciles a Query object with telemetry abstraction.
 //
 // Telemetry Pattern:
@@ -48,6 +60,9 @@ type QueryReconciler struct {
 	Telemetry  *telemetryconfig.Provider
 	Eventing   *eventingconfig.Provider
 	operations sync.Map
+	// queryWatchers wake tool calls awaiting approval when their query is reconciled
+	queryWatchers   map[types.NamespacedName]chan struct{}
+	queryWatchersMu sync.Mutex
 }
 
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;create;update;patch;delete
@@ -56,6 +71,7 @@ type QueryReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=teams,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=guardrails,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;list;watch;patch
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=impersonate
 
@@ -133,7 +149,7 @@ func (r *QueryReconciler) handleQueryExecution(ctx context.Context, req ctrl.Req
 		return ctrl.Result{
 			RequeueAfter: time.Until(expiry),
 		}, nil
-	case statusRunning:
+	case statusRunning, statusAwaitingApproval:
 		return r.handleRunningPhase(ctx, req, obj)
 	default:
 		if err := r.updateStatus(ctx, &obj, statusRunning); err != nil {
@@ -150,6 +166,8 @@ func (r *QueryReconciler) handleRunningPhase(ctx context.Context, req ctrl.Reque
 
 	if _, exists := r.operations.Load(req.NamespacedName); exists {
 		log.Info("Exists")
+		// The query may have changed for tool calls waiting on spec.toolApprovals
+		r.notifyQueryUpdated(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -207,6 +225,7 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 
 	opCtx = r.Eventing.QueryRecorder().InitializeQueryContext(opCtx, &obj)
 	opCtx = r.Eventing.QueryRecorder().StartTokenCollection(opCtx)
+	opCtx = genai.WithGuardrailCollector(opCtx)
 	opCtx = r.Eventing.QueryRecorder().Start(opCtx, "QueryExecution", fmt.Sprintf("Executing query %s", obj.Name), nil)
 
 	inputMessages, err := genai.GetQueryInputMessages(opCtx, obj, impersonatedClient)
@@ -215,7 +234,18 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 		r.Telemetry.QueryRecorder().RecordRootInput(span, queryInput)
 	}
 
-	response, eventStream, err := r.reconcileQueue(opCtx, obj, impersonatedClient, memory)
+	response, completionReason, eventStream, err := r.reconcileQueue(opCtx, obj, impersonatedClient, memory)
+
+	// Tokens used before a failure are still reported, so failed queries are costed
+	tokenSummary := r.Eventing.QueryRecorder().GetTokenSummary(opCtx)
+	obj.Status.TokenUsage = tokenSummary
+	if tokenSummary.TotalTokens > 0 {
+		r.Telemetry.QueryRecorder().RecordTokenUsage(span, tokenSummary.PromptTokens, tokenSummary.CompletionTokens, tokenSummary.TotalTokens)
+	}
+	if len(tokenSummary.Cost) > 0 {
+		r.Telemetry.QueryRecorder().RecordCost(span, tokenSummary.Cost)
+	}
+
 	if err != nil {
 		genai.StreamError(opCtx, eventStream, err, "query_execution_failed", "query")
 		r.Telemetry.QueryRecorder().RecordError(span, err)
@@ -225,30 +255,24 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 	}
 
 	obj.Status.Response = response
+	r.setConditionGuardrailTriggered(&obj, genai.GetGuardrailViolations(opCtx))
 
 	if response != nil && response.Phase == statusDone {
 		r.Telemetry.QueryRecorder().RecordRootOutput(span, response.Content)
 	}
 
-	tokenSummary := r.Eventing.QueryRecorder().GetTokenSummary(opCtx)
-	obj.Status.TokenUsage = tokenSummary
-
-	if tokenSummary.TotalTokens > 0 {
-		r.Telemetry.QueryRecorder().RecordTokenUsage(span, tokenSummary.PromptTokens, tokenSummary.CompletionTokens, tokenSummary.TotalTokens)
-	}
-
 	queryStatus := r.determineQueryStatus(response)
-	_ = r.updateStatus(opCtx, &obj, queryStatus)
+	_ = r.updateStatusWithReason(opCtx, &obj, queryStatus, nil, completionReason)
 
 	duration := &metav1.Duration{Duration: time.Since(startTime)}
 	r.finalizeEventStream(opCtx, eventStream, &obj)
-	_ = r.updateStatusWithDuration(opCtx, &obj, queryStatus, duration)
+	_ = r.updateStatusWithReason(opCtx, &obj, queryStatus, duration, completionReason)
 
 	r.Telemetry.QueryRecorder().RecordSuccess(span)
-	operationData := map[string]string{
-		"promptTokens":     fmt.Sprintf("%d", tokenSummary.PromptTokens),
-		"completionTokens": fmt.Sprintf("%d", tokenSummary.CompletionTokens),
-		"totalTokens":      fmt.Sprintf("%d", tokenSummary.TotalTokens),
+	operationData := map[string]string{}
+	genai.AddTokenUsageToOperationData(operationData, tokenSummary)
+	if completionReason != "" {
+		operationData["reason"] = completionReason
 	}
 	r.Eventing.QueryRecorder().Complete(opCtx, "QueryExecution", "Query execution completed", operationData)
 }
@@ -386,19 +410,19 @@ func (r *QueryReconciler) resolveSelector(ctx context.Context, selector *metav1.
 	return nil, fmt.Errorf("no matching resources found for selector")
 }
 
-func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface) (*arkv1alpha1.Response, genai.EventStreamInterface, error) {
+func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface) (*arkv1alpha1.Response, string, genai.EventStreamInterface, error) {
 	eventStream, err := r.createEventStreamIfNeeded(ctx, query)
 	if err != nil {
-		return nil, nil, err
+		return nil, "", nil, err
 	}
 
 	target, err := r.resolveTarget(ctx, query, impersonatedClient)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to resolve target: %w", err)
+		return nil, "", nil, fmt.Errorf("failed to resolve target: %w", err)
 	}
 
-	response := r.executeTarget(ctx, query, *target, impersonatedClient, memory, eventStream)
-	return response, eventStream, nil
+	response, completionReason := r.executeTarget(ctx, query, *target, impersonatedClient, memory, eventStream)
+	return response, completionReason, eventStream, nil
 }
 
 func (r *QueryReconciler) createEventStreamIfNeeded(ctx context.Context, query arkv1alpha1.Query) (genai.EventStreamInterface, error) {
@@ -425,18 +449,32 @@ func (r *QueryReconciler) createEventStreamIfNeeded(ctx context.Context, query a
 	return eventStream, nil
 }
 
-func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) *arkv1alpha1.Response {
+// executeTarget runs the target and returns its response together with a condition reason
+// that overrides the default completion reason, or an empty string if none applies.
+func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*arkv1alpha1.Response, string) {
 	executionResult, err := r.performTargetExecution(ctx, query, target, impersonatedClient, memory, eventStream)
+	completionReason := completionReasonFor(executionResult, err)
 	if err != nil {
 		errResponse := r.createErrorResponse(target, err)
-		return &errResponse
+		return &errResponse, completionReason
 	}
 
 	if executionResult == nil || executionResult.Messages == nil {
-		return nil
+		return nil, completionReason
 	}
 
 	response := r.createSuccessResponse(target, executionResult.Messages)
+	if structured := executionResult.StructuredOutput; len(structured) > 0 {
+		response.Structured = &runtime.RawExtension{Raw: structured}
+	}
+	if len(executionResult.Embeddings) > 0 {
+		embeddings, err := json.Marshal(executionResult.Embeddings)
+		if err != nil {
+			errResponse := r.createErrorResponse(target, fmt.Errorf("failed to serialize embeddings: %w", err))
+			return &errResponse, completionReason
+		}
+		response.Embeddings = &runtime.RawExtension{Raw: embeddings}
+	}
 	if executionResult.A2AResponse != nil {
 		response.A2A = &arkv1alpha1.A2AMetadata{
 			ContextID: executionResult.A2AResponse.ContextID,
@@ -444,7 +482,22 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 		}
 	}
 
-	return &response
+	return &response, completionReason
+}
+
+// completionReasonFor returns a distinct Completed condition reason for target outcomes
+// that should be distinguishable from a plain success or error.
+func completionReasonFor(result *genai.ExecutionResult, err error) string {
+	if genai.IsGuardrailBlocked(err) {
+		return reasonGuardrailBlocked
+	}
+	if genai.IsOutputSchemaInvalid(err) {
+		return reasonOutputSchemaInvalid
+	}
+	if genai.IsMaxIterationsReached(err) || (result != nil && result.MaxIterationsReached) {
+		return reasonMaxIterationsReached
+	}
+	return ""
 }
 
 func (r *QueryReconciler) createSuccessResponse(target arkv1alpha1.QueryTarget, messages []genai.Message) arkv1alpha1.Response {
@@ -523,6 +576,12 @@ func (r *QueryReconciler) updateStatus(ctx context.Context, query *arkv1alpha1.Q
 }
 
 func (r *QueryReconciler) updateStatusWithDuration(ctx context.Context, query *arkv1alpha1.Query, status string, duration *metav1.Duration) error {
+	return r.updateStatusWithReason(ctx, query, status, duration, "")
+}
+
+// updateStatusWithReason updates the query phase, using reason (when set) in place of the
+// default Completed condition reason for the done and error phases.
+func (r *QueryReconciler) updateStatusWithReason(ctx context.Context, query *arkv1alpha1.Query, status string, duration *metav1.Duration, reason string) error {
 	if ctx.Err() != nil {
 		return nil
 	}
@@ -531,26 +590,63 @@ func (r *QueryReconciler) updateStatusWithDuration(ctx context.Context, query *a
 	case statusRunning:
 		r.setConditionCompleted(query, metav1.ConditionFalse, "QueryRunning", "Query is running")
 	case statusDone:
-		r.setConditionCompleted(query, metav1.ConditionTrue, "QuerySucceeded", "Query completed successfully")
+		r.setConditionCompleted(query, metav1.ConditionTrue, cmp.Or(reason, "QuerySucceeded"), "Query completed successfully")
 	case statusError:
 		errorMsg := "Query completed with error"
 		if query.Status.Response != nil && query.Status.Response.Phase == statusError && query.Status.Response.Content != "" {
 			errorMsg = query.Status.Response.Content
 		}
-		r.setConditionCompleted(query, metav1.ConditionTrue, "QueryErrored", errorMsg)
+		r.setConditionCompleted(query, metav1.ConditionTrue, cmp.Or(reason, "QueryErrored"), errorMsg)
 	case statusCanceled:
 		r.setConditionCompleted(query, metav1.ConditionTrue, "QueryCanceled", "Query canceled")
 	}
 	if duration != nil {
 		query.Status.Duration = duration
 	}
-	err := r.Status().Update(ctx, query)
+
+	// The execution owns the status apart from the pending tool approvals, which tool calls
+	// awaiting approval keep on the latest version of the query
+	desired := query.Status.DeepCopy()
+	err := r.updateQueryStatus(ctx, query, func(latest *arkv1alpha1.Query) {
+		pending, phase, conditions := latest.Status.PendingToolApprovals, latest.Status.Phase, latest.Status.Conditions
+		desired.DeepCopyInto(&latest.Status)
+		latest.Status.PendingToolApprovals = nil
+		if status == statusRunning && len(pending) > 0 {
+			latest.Status.PendingToolApprovals = pending
+			latest.Status.Phase = phase
+			latest.Status.Conditions = conditions
+		}
+	})
 	if err != nil {
 		logf.FromContext(ctx).Error(err, "failed to update query status", "status", status)
 	}
 	return err
 }
 
+// updateQueryStatus applies mutate to query and writes its status. On a conflict the latest
+// version of the query is fetched and mutate is applied to it again, since the query changes
+// while it runs, e.g. when approvers add spec.toolApprovals. query is left at the written version.
+func (r *QueryReconciler) updateQueryStatus(ctx context.Context, query *arkv1alpha1.Query, mutate func(*arkv1alpha1.Query)) error {
+	refetch := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := query.DeepCopy()
+		if refetch {
+			latest = &arkv1alpha1.Query{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(query), latest); err != nil {
+				return err
+			}
+		}
+		refetch = true
+
+		mutate(latest)
+		if err := r.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		latest.DeepCopyInto(query)
+		return nil
+	})
+}
+
 // determineQueryStatus checks if any responses have error phase and returns appropriate query status
 func (r *QueryReconciler) determineQueryStatus(response *arkv1alpha1.Response) string {
 	if response != nil && response.Phase == statusError {
@@ -586,6 +682,7 @@ func (r *QueryReconciler) finalize(ctx context.Context, query *arkv1alpha1.Query
 			cancelFunc()
 		}
 		r.operations.Delete(nsName)
+		r.notifyQueryUpdated(nsName)
 		log.Info("cancelled running operation for query", "name", query.Name, "namespace", query.Namespace)
 	}
 }
@@ -598,6 +695,7 @@ func (r *QueryReconciler) handleTargetExecutionError(ctx context.Context, err er
 func (r *QueryReconciler) performTargetExecution(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*genai.ExecutionResult, error) {
 	// Store query in context for access in deeper call stacks
 	ctx = context.WithValue(ctx, genai.QueryContextKey, &query)
+	ctx = genai.WithToolApprover(ctx, r.newToolApprover(&query))
 
 	ctx, span := r.Telemetry.QueryRecorder().StartTarget(ctx, target.Type, target.Name)
 	defer span.End()
@@ -635,6 +733,12 @@ func (r *QueryReconciler) performTargetExecution(ctx context.Context, query arkv
 	userContent := genai.ExtractUserMessageContent(inputMessages)
 	r.Telemetry.QueryRecorder().RecordInput(span, userContent)
 
+	guardrails, err := genai.LoadGuardrails(ctx, impersonatedClient, query.Spec.Guardrails, query.Namespace, r.Telemetry, r.Eventing)
+	if err != nil {
+		r.Telemetry.QueryRecorder().RecordError(span, err)
+		return nil, err
+	}
+
 	timeout := 5 * time.Minute
 	if query.Spec.Timeout != nil {
 		timeout = query.Spec.Timeout.Duration
@@ -642,23 +746,7 @@ func (r *QueryReconciler) performTargetExecution(ctx context.Context, query arkv
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	var result *genai.ExecutionResult
-	switch target.Type {
-	case targetTypeAgent:
-		result, err = r.executeAgent(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
-	case targetTypeTeam:
-		result, err = r.executeTeam(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
-	case targetTypeModel:
-		var messages []genai.Message
-		messages, err = r.executeModel(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
-		result = &genai.ExecutionResult{Messages: messages}
-	case targetTypeTool:
-		var messages []genai.Message
-		messages, err = r.executeTool(execCtx, query, inputMessages, target.Name, impersonatedClient)
-		result = &genai.ExecutionResult{Messages: messages}
-	default:
-		panic(fmt.Errorf("unknown query target type:%s", target.Type))
-	}
+	result, err := r.executeGuardedTarget(execCtx, query, target, guardrails, inputMessages, impersonatedClient, memory, eventStream)
 
 	if err != nil {
 		r.Telemetry.QueryRecorder().RecordError(span, err)
@@ -680,6 +768,27 @@ func (r *QueryReconciler) performTargetExecution(ctx context.Context, query arkv
 	return result, nil
 }
 
+// dispatchTarget executes the target according to its type
+func (r *QueryReconciler) dispatchTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, inputMessages []genai.Message, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*genai.ExecutionResult, error) {
+	var result *genai.ExecutionResult
+	var err error
+	switch target.Type {
+	case targetTypeAgent:
+		result, err = r.executeAgent(ctx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
+	case targetTypeTeam:
+		result, err = r.executeTeam(ctx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
+	case targetTypeModel:
+		result, err = r.executeModel(ctx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream)
+	case targetTypeTool:
+		var messages []genai.Message
+		messages, err = r.executeTool(ctx, query, inputMessages, target.Name, impersonatedClient)
+		result = &genai.ExecutionResult{Messages: messages}
+	default:
+		panic(fmt.Errorf("unknown query target type:%s", target.Type))
+	}
+	return result, err
+}
+
 func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, agentName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*genai.ExecutionResult, error) {
 	var agentCRD arkv1alpha1.Agent
 	agentKey := types.NamespacedName{Name: agentName, Namespace: query.Namespace}
@@ -713,13 +822,6 @@ func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Qu
 	if err != nil {
 		return nil, err
 	}
-
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, result.Messages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
-	}
-
 	return result, nil
 }
 
@@ -748,17 +850,10 @@ func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Que
 	if err != nil {
 		return nil, err
 	}
-
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, result.Messages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
-	}
-
 	return result, nil
 }
 
-func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, modelName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) ([]genai.Message, error) {
+func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, modelName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*genai.ExecutionResult, error) {
 	var modelCRD arkv1alpha1.Model
 	modelKey := types.NamespacedName{Name: modelName, Namespace: query.Namespace}
 
@@ -771,6 +866,10 @@ func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Qu
 		return nil, fmt.Errorf("unable to load model %v, error:%w", modelKey, err)
 	}
 
+	if model.IsEmbeddingsModel() {
+		return executeEmbeddingModel(ctx, model, inputMessages)
+	}
+
 	historyMessages, err := r.loadInitialMessages(ctx, memory)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load initial messages: %w", err)
@@ -802,13 +901,42 @@ func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Qu
 		responseMessages = []genai.Message{assistantMessage}
 	}
 
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, responseMessages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
+	return &genai.ExecutionResult{Messages: responseMessages}, nil
+}
+
+// executeEmbeddingModel embeds the text of each input user message. Memory is not used, as
+// embeddings are not part of a conversation.
+func executeEmbeddingModel(ctx context.Context, model *genai.Model, inputMessages []genai.Message) (*genai.ExecutionResult, error) {
+	inputs := genai.EmbeddingInputs(inputMessages)
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("query has no text input to embed")
 	}
 
-	return responseMessages, nil
+	response, err := model.Embed(ctx, inputs)
+	if err != nil {
+		return nil, fmt.Errorf("model embedding failed: %w", err)
+	}
+
+	if len(response.Data) != len(inputs) {
+		return nil, fmt.Errorf("model returned %d embeddings for %d inputs", len(response.Data), len(inputs))
+	}
+	// Every input must get exactly one embedding, so no vector is left empty
+	embeddings := make([][]float64, len(inputs))
+	for _, embedding := range response.Data {
+		if embedding.Index < 0 || int(embedding.Index) >= len(embeddings) {
+			return nil, fmt.Errorf("model returned an embedding with invalid index %d", embedding.Index)
+		}
+		if embeddings[embedding.Index] != nil {
+			return nil, fmt.Errorf("model returned more than one embedding with index %d", embedding.Index)
+		}
+		embeddings[embedding.Index] = embedding.Embedding
+	}
+
+	summary := fmt.Sprintf("Created %d embeddings with %d dimensions", len(embeddings), len(embeddings[0]))
+	return &genai.ExecutionResult{
+		Messages:   []genai.Message{genai.NewAssistantMessage(summary)},
+		Embeddings: embeddings,
+	}, nil
 }
 
 func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query, inputMessages []genai.Message, toolName string, impersonatedClient client.Client) ([]genai.Message, error) {
@@ -922,14 +1050,11 @@ func (r *QueryReconciler) getClientForQuery(query arkv1alpha1.Query) (client.Cli
 		return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
 	}
 
-	cfg.Impersonate = rest.ImpersonationConfig{
-		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", query.Namespace, serviceAccount),
-	}
-
-	impersonatedClient, err := client.New(cfg, client.Options{
+	// The client also reads pod logs, so kubernetes tools have the permissions of the service account
+	impersonatedClient, err := genai.NewImpersonatingClient(cfg, client.Options{
 		Scheme: r.Scheme,
 		Mapper: r.RESTMapper(),
-	})
+	}, query.Namespace, serviceAccount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create impersonated client for service account %s/%s: %w", query.Namespace, serviceAccount, err)
 	}
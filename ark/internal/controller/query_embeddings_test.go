@@ -0,0 +1,103 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/genai"
+	telenoop "mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func newEmbeddingTestModel(t *testing.T, response string) *genai.Model {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "embedder", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "text-embedding-3-small"},
+			Type:     genai.ModelTypeEmbeddings,
+			Provider: genai.ProviderOpenAI,
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: server.URL},
+				APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}).Build()
+	model, err := genai.LoadModel(context.Background(), k8sClient, "embedder", "default", nil, telenoop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	return model
+}
+
+func TestExecuteEmbeddingModel(t *testing.T) {
+	model := newEmbeddingTestModel(t, `{"object": "list", "data": [
+		{"object": "embedding", "index": 1, "embedding": [0.3, 0.4]},
+		{"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}
+	], "usage": {"prompt_tokens": 4, "total_tokens": 4}}`)
+
+	result, err := executeEmbeddingModel(context.Background(), model, []genai.Message{genai.NewUserMessage("first"), genai.NewUserMessage("second")})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]float64{{0.1, 0.2}, {0.3, 0.4}}, result.Embeddings)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "Created 2 embeddings with 2 dimensions", result.Messages[0].OfAssistant.Content.OfString.Value)
+}
+
+func TestExecuteEmbeddingModel_InvalidResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "duplicate index",
+			data:     `{"index": 0, "embedding": [0.1]}, {"index": 0, "embedding": [0.2]}`,
+			expected: "more than one embedding with index 0",
+		},
+		{
+			name:     "index out of range",
+			data:     `{"index": 0, "embedding": [0.1]}, {"index": 2, "embedding": [0.2]}`,
+			expected: "invalid index 2",
+		},
+		{
+			name:     "missing embedding",
+			data:     `{"index": 0, "embedding": [0.1]}`,
+			expected: "1 embeddings for 2 inputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newEmbeddingTestModel(t, `{"object": "list", "data": [`+tt.data+`]}`)
+
+			_, err := executeEmbeddingModel(context.Background(), model, []genai.Message{genai.NewUserMessage("first"), genai.NewUserMessage("second")})
+
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestExecuteEmbeddingModel_NoInput(t *testing.T) {
+	_, err := executeEmbeddingModel(context.Background(), &genai.Model{Type: genai.ModelTypeEmbeddings}, []genai.Message{genai.NewSystemMessage("system")})
+
+	assert.ErrorContains(t, err, "no text input to embed")
+}
@@ -0,0 +1,95 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// executeGuardedTarget applies the query's guardrails to the input messages, executes the
+// target and then applies them to the response. The new messages are saved to memory once the
+// response has been checked, and when output guardrails are configured the response is streamed
+// only after the check.
+func (r *QueryReconciler) executeGuardedTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, guardrails []*genai.Guardrail, inputMessages []genai.Message, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface) (*genai.ExecutionResult, error) {
+	if len(guardrails) > 0 {
+		guardedMessages := make([]genai.Message, len(inputMessages))
+		for i, msg := range inputMessages {
+			guarded, err := genai.ApplyGuardrailsToMessage(ctx, guardrails, genai.GuardrailStageInput, msg)
+			if err != nil {
+				return nil, err
+			}
+			guardedMessages[i] = guarded
+		}
+		inputMessages = guardedMessages
+	}
+
+	executionStream := eventStream
+	if genai.HasOutputGuardrails(guardrails) {
+		executionStream = nil
+	}
+
+	result, err := r.dispatchTarget(ctx, query, target, inputMessages, impersonatedClient, memory, executionStream)
+	if err != nil {
+		return result, err
+	}
+
+	if err := genai.ApplyOutputGuardrails(ctx, guardrails, result); err != nil {
+		return nil, err
+	}
+	if executionStream == nil {
+		modelName := ""
+		if target.Type == targetTypeModel {
+			modelName = target.Name
+		}
+		genai.StreamCheckedResult(ctx, eventStream, result, modelName)
+	}
+
+	// Tool targets and embeddings are not part of a conversation
+	if target.Type != targetTypeTool && result.Embeddings == nil {
+		newMessages := genai.PrepareNewMessagesForMemory(inputMessages, result.Messages)
+		if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
+			return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// setConditionGuardrailTriggered records the guardrails that triggered during execution. The
+// reason reflects the most severe action taken: blocked, then redacted, then warned.
+func (r *QueryReconciler) setConditionGuardrailTriggered(query *arkv1alpha1.Query, violations []genai.GuardrailViolation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	reason := "GuardrailWarned"
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		switch v.Action {
+		case genai.GuardrailActionBlock:
+			reason = reasonGuardrailBlocked
+		case genai.GuardrailActionRedact:
+			if reason != reasonGuardrailBlocked {
+				reason = "GuardrailRedacted"
+			}
+		}
+		messages = append(messages, fmt.Sprintf("%s (%s, %s): %s", v.Guardrail, v.Stage, v.Action, v.Reason))
+	}
+
+	meta.SetStatusCondition(&query.Status.Conditions, metav1.Condition{
+		Type:               string(arkv1alpha1.QueryGuardrailTriggered),
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            strings.Join(messages, "; "),
+		LastTransitionTime: metav1.Now(),
+		ObservedGeneration: query.Generation,
+	})
+}
@@ -0,0 +1,67 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+func TestSetConditionGuardrailTriggered(t *testing.T) {
+	tests := []struct {
+		name       string
+		violations []genai.GuardrailViolation
+		reason     string
+	}{
+		{
+			name:       "warned",
+			violations: []genai.GuardrailViolation{{Guardrail: "default/a", Stage: "input", Action: genai.GuardrailActionWarn, Reason: "contains email"}},
+			reason:     "GuardrailWarned",
+		},
+		{
+			name: "redacted",
+			violations: []genai.GuardrailViolation{
+				{Guardrail: "default/a", Stage: "input", Action: genai.GuardrailActionWarn, Reason: "contains email"},
+				{Guardrail: "default/b", Stage: "output", Action: genai.GuardrailActionRedact, Reason: "contains ssn"},
+			},
+			reason: "GuardrailRedacted",
+		},
+		{
+			name: "blocked",
+			violations: []genai.GuardrailViolation{
+				{Guardrail: "default/a", Stage: "input", Action: genai.GuardrailActionBlock, Reason: "contains denied term"},
+				{Guardrail: "default/b", Stage: "output", Action: genai.GuardrailActionRedact, Reason: "contains ssn"},
+			},
+			reason: reasonGuardrailBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &arkv1alpha1.Query{}
+			(&QueryReconciler{}).setConditionGuardrailTriggered(query, tt.violations)
+
+			condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryGuardrailTriggered))
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.reason, condition.Reason)
+			assert.Contains(t, condition.Message, "default/a (input, ")
+		})
+	}
+}
+
+func TestSetConditionGuardrailTriggered_NoViolations(t *testing.T) {
+	query := &arkv1alpha1.Query{}
+	(&QueryReconciler{}).setConditionGuardrailTriggered(query, nil)
+	assert.Empty(t, query.Status.Conditions)
+}
+
+func TestCompletionReasonFor_GuardrailBlocked(t *testing.T) {
+	err := &genai.GuardrailBlocked{Violation: genai.GuardrailViolation{Guardrail: "default/a", Stage: "input", Action: genai.GuardrailActionBlock}}
+	assert.Equal(t, reasonGuardrailBlocked, completionReasonFor(nil, err))
+}
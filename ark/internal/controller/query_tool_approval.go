@@ -0,0 +1,168 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const toolApprovalApprove = "approve"
+
+// queryToolApprover implements genai.ToolApprover for a query. Pending calls are recorded in
+// status.pendingToolApprovals with the query in the awaiting-approval phase, and decisions are
+// read from spec.toolApprovals, which approvers patch onto the query.
+type queryToolApprover struct {
+	reconciler *QueryReconciler
+	key        types.NamespacedName
+	// mu serializes status updates from tool calls awaiting approval in parallel
+	mu sync.Mutex
+}
+
+func (r *QueryReconciler) newToolApprover(query *arkv1alpha1.Query) *queryToolApprover {
+	return &queryToolApprover{
+		reconciler: r,
+		key:        types.NamespacedName{Name: query.Name, Namespace: query.Namespace},
+	}
+}
+
+func (a *queryToolApprover) RequestApproval(ctx context.Context, request genai.ToolApprovalRequest) (genai.ToolApprovalDecision, error) {
+	log := logf.FromContext(ctx)
+
+	if err := a.addPending(ctx, request); err != nil {
+		return genai.ToolApprovalDecision{}, fmt.Errorf("failed to record pending approval for tool %s: %w", request.ToolName, err)
+	}
+	defer func() {
+		// Clear the pending entry even when the query was canceled or timed out
+		if err := a.removePending(context.WithoutCancel(ctx), request.ToolCallID); err != nil {
+			log.Error(err, "failed to clear pending tool approval", "toolCallId", request.ToolCallID)
+		}
+	}()
+	log.Info("waiting for tool approval", "tool", request.ToolName, "toolCallId", request.ToolCallID, "agent", request.Agent)
+
+	for {
+		// Taken before reading the query, so a decision added after the read still wakes the call
+		updated := a.reconciler.queryUpdated(a.key)
+
+		var query arkv1alpha1.Query
+		if err := a.reconciler.Get(ctx, a.key, &query); err != nil {
+			return genai.ToolApprovalDecision{}, fmt.Errorf("failed to get query %s: %w", a.key, err)
+		}
+		if approval := findToolApproval(query.Spec.ToolApprovals, request.ToolCallID); approval != nil {
+			log.Info("tool approval decided", "tool", request.ToolName, "toolCallId", request.ToolCallID, "decision", approval.Decision)
+			return genai.ToolApprovalDecision{
+				Approved: approval.Decision == toolApprovalApprove,
+				Reason:   approval.Reason,
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return genai.ToolApprovalDecision{}, fmt.Errorf("query timed out while tool %s was awaiting approval, the query timeout includes the time spent awaiting approval: %w", request.ToolName, ctx.Err())
+			}
+			return genai.ToolApprovalDecision{}, ctx.Err()
+		case <-updated:
+		}
+	}
+}
+
+// queryUpdated returns a channel that is closed the next time the query is reconciled, which
+// happens when approvers add decisions to spec.toolApprovals
+func (r *QueryReconciler) queryUpdated(key types.NamespacedName) <-chan struct{} {
+	r.queryWatchersMu.Lock()
+	defer r.queryWatchersMu.Unlock()
+
+	if r.queryWatchers == nil {
+		r.queryWatchers = make(map[types.NamespacedName]chan struct{})
+	}
+	updated, exists := r.queryWatchers[key]
+	if !exists {
+		updated = make(chan struct{})
+		r.queryWatchers[key] = updated
+	}
+	return updated
+}
+
+// notifyQueryUpdated wakes the tool calls waiting for a change to the query
+func (r *QueryReconciler) notifyQueryUpdated(key types.NamespacedName) {
+	r.queryWatchersMu.Lock()
+	defer r.queryWatchersMu.Unlock()
+
+	if updated, exists := r.queryWatchers[key]; exists {
+		close(updated)
+		delete(r.queryWatchers, key)
+	}
+}
+
+func findToolApproval(approvals []arkv1alpha1.ToolApproval, toolCallID string) *arkv1alpha1.ToolApproval {
+	for i := range approvals {
+		if approvals[i].ToolCallID == toolCallID {
+			return &approvals[i]
+		}
+	}
+	return nil
+}
+
+func (a *queryToolApprover) addPending(ctx context.Context, request genai.ToolApprovalRequest) error {
+	return a.updatePending(ctx, func(pending []arkv1alpha1.PendingToolApproval) []arkv1alpha1.PendingToolApproval {
+		return append(pending, arkv1alpha1.PendingToolApproval{
+			ToolCallID:  request.ToolCallID,
+			ToolName:    request.ToolName,
+			Agent:       request.Agent,
+			Arguments:   request.Arguments,
+			RequestedAt: metav1.Now(),
+		})
+	})
+}
+
+func (a *queryToolApprover) removePending(ctx context.Context, toolCallID string) error {
+	return a.updatePending(ctx, func(pending []arkv1alpha1.PendingToolApproval) []arkv1alpha1.PendingToolApproval {
+		remaining := pending[:0]
+		for _, p := range pending {
+			if p.ToolCallID != toolCallID {
+				remaining = append(remaining, p)
+			}
+		}
+		return remaining
+	})
+}
+
+// updatePending applies update to the pending approvals of the latest query and moves it between
+// the running and awaiting-approval phases. Queries that have already finished are left untouched.
+func (a *queryToolApprover) updatePending(ctx context.Context, update func([]arkv1alpha1.PendingToolApproval) []arkv1alpha1.PendingToolApproval) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var query arkv1alpha1.Query
+		if err := a.reconciler.Get(ctx, a.key, &query); err != nil {
+			return err
+		}
+		if query.Status.Phase != statusRunning && query.Status.Phase != statusAwaitingApproval {
+			return nil
+		}
+
+		query.Status.PendingToolApprovals = update(query.Status.PendingToolApprovals)
+		if len(query.Status.PendingToolApprovals) > 0 {
+			query.Status.Phase = statusAwaitingApproval
+			a.reconciler.setConditionCompleted(&query, metav1.ConditionFalse, "QueryAwaitingApproval",
+				fmt.Sprintf("Waiting for approval of %d tool call(s)", len(query.Status.PendingToolApprovals)))
+		} else {
+			query.Status.PendingToolApprovals = nil
+			query.Status.Phase = statusRunning
+			a.reconciler.setConditionCompleted(&query, metav1.ConditionFalse, "QueryRunning", "Query is running")
+		}
+		return a.reconciler.Status().Update(ctx, &query)
+	})
+}
@@ -0,0 +1,168 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+func newApprovalTestReconciler(t *testing.T, query *arkv1alpha1.Query) *QueryReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(query).
+		WithStatusSubresource(&arkv1alpha1.Query{}).
+		Build()
+	return &QueryReconciler{Client: k8sClient, Scheme: scheme}
+}
+
+func TestQueryToolApprover(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision string
+		reason   string
+		approved bool
+	}{
+		{name: "approve", decision: "approve", approved: true},
+		{name: "reject", decision: "reject", reason: "not in production"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &arkv1alpha1.Query{
+				ObjectMeta: metav1.ObjectMeta{Name: testQueryName, Namespace: testNamespace},
+				Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+			}
+			reconciler := newApprovalTestReconciler(t, query)
+			approver := reconciler.newToolApprover(query)
+			ctx := context.Background()
+			key := client.ObjectKeyFromObject(query)
+
+			type outcome struct {
+				decision genai.ToolApprovalDecision
+				err      error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				decision, err := approver.RequestApproval(ctx, genai.ToolApprovalRequest{
+					ToolCallID: "call-1",
+					ToolName:   "delete-records",
+					Arguments:  `{"table":"users"}`,
+					Agent:      "default/ops",
+				})
+				done <- outcome{decision, err}
+			}()
+
+			var current arkv1alpha1.Query
+			require.Eventually(t, func() bool {
+				require.NoError(t, reconciler.Get(ctx, key, &current))
+				return current.Status.Phase == statusAwaitingApproval
+			}, time.Second, 5*time.Millisecond)
+			require.Len(t, current.Status.PendingToolApprovals, 1)
+			assert.Equal(t, "call-1", current.Status.PendingToolApprovals[0].ToolCallID)
+			assert.Equal(t, "default/ops", current.Status.PendingToolApprovals[0].Agent)
+
+			current.Spec.ToolApprovals = []arkv1alpha1.ToolApproval{{ToolCallID: "call-1", Decision: tt.decision, Reason: tt.reason}}
+			require.NoError(t, reconciler.Update(ctx, &current))
+
+			// The update reconciles the query, which wakes the waiting call while its execution runs
+			reconciler.operations.Store(key, context.CancelFunc(func() {}))
+			_, err := reconciler.handleRunningPhase(ctx, ctrl.Request{NamespacedName: key}, current)
+			require.NoError(t, err)
+
+			select {
+			case result := <-done:
+				require.NoError(t, result.err)
+				assert.Equal(t, tt.approved, result.decision.Approved)
+				assert.Equal(t, tt.reason, result.decision.Reason)
+			case <-time.After(time.Second):
+				t.Fatal("approval decision was not picked up")
+			}
+
+			require.NoError(t, reconciler.Get(ctx, key, &current))
+			assert.Equal(t, statusRunning, current.Status.Phase)
+			assert.Empty(t, current.Status.PendingToolApprovals)
+		})
+	}
+}
+
+func TestQueryToolApprover_Canceled(t *testing.T) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: testQueryName, Namespace: testNamespace},
+		Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+	}
+	reconciler := newApprovalTestReconciler(t, query)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := reconciler.newToolApprover(query).RequestApproval(ctx, genai.ToolApprovalRequest{ToolCallID: "call-1", ToolName: "delete-records"})
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueryToolApprover_TimedOut(t *testing.T) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: testQueryName, Namespace: testNamespace},
+		Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+	}
+	reconciler := newApprovalTestReconciler(t, query)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := reconciler.newToolApprover(query).RequestApproval(ctx, genai.ToolApprovalRequest{ToolCallID: "call-1", ToolName: "delete-records"})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "query timed out while tool delete-records was awaiting approval")
+
+	var current arkv1alpha1.Query
+	require.NoError(t, reconciler.Get(context.Background(), client.ObjectKeyFromObject(query), &current))
+	assert.Empty(t, current.Status.PendingToolApprovals)
+}
+
+func TestQueryStatusUpdate_KeepsConcurrentChanges(t *testing.T) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: testQueryName, Namespace: testNamespace},
+		Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+	}
+	reconciler := newApprovalTestReconciler(t, query)
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(query)
+
+	// The execution holds a copy of the query read before a tool call started awaiting approval
+	var stale arkv1alpha1.Query
+	require.NoError(t, reconciler.Get(ctx, key, &stale))
+	require.NoError(t, reconciler.newToolApprover(query).addPending(ctx, genai.ToolApprovalRequest{ToolCallID: "call-1", ToolName: "delete-records"}))
+
+	stale.Status.ConversationId = "conversation-1"
+	require.NoError(t, reconciler.updateStatus(ctx, &stale, statusRunning))
+
+	var current arkv1alpha1.Query
+	require.NoError(t, reconciler.Get(ctx, key, &current))
+	assert.Equal(t, "conversation-1", current.Status.ConversationId)
+	assert.Equal(t, statusAwaitingApproval, current.Status.Phase, "the pending approval is kept")
+	require.Len(t, current.Status.PendingToolApprovals, 1)
+	assert.Equal(t, current.ResourceVersion, stale.ResourceVersion)
+
+	stale.Status.Response = &arkv1alpha1.Response{Content: "done", Phase: statusDone}
+	require.NoError(t, reconciler.updateStatus(ctx, &stale, statusDone))
+
+	require.NoError(t, reconciler.Get(ctx, key, &current))
+	assert.Equal(t, statusDone, current.Status.Phase)
+	assert.Equal(t, "done", current.Status.Response.Content)
+	assert.Empty(t, current.Status.PendingToolApprovals)
+}
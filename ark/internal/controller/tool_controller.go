@@ -3,14 +3,27 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const (
+	// grpcToolSchemaRefreshInterval is how often the input schema and the method of gRPC tools are
+	// loaded again, so they follow changes of the server
+	grpcToolSchemaRefreshInterval = genai.GRPCToolRefreshInterval
+	grpcToolSchemaRetryInterval   = 30 * time.Second
 )
 
 type ToolReconciler struct {
@@ -25,9 +38,16 @@ type ToolReconciler struct {
 func (r *ToolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	tool := &arkv1alpha1.Tool{}
 	if err := r.Get(ctx, req.NamespacedName, tool); err != nil {
+		if errors.IsNotFound(err) {
+			genai.EvictGRPCToolClient(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if tool.Spec.Type == arkv1alpha1.ToolTypeGRPC && tool.Spec.GRPC != nil {
+		return r.reconcileGRPCTool(ctx, tool)
+	}
+
 	if tool.Status.State == arkv1alpha1.ToolStateReady {
 		return ctrl.Result{}, nil
 	}
@@ -35,6 +55,48 @@ func (r *ToolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	return r.updateToolStatus(ctx, tool, arkv1alpha1.ToolStateReady, "Tool configuration is valid")
 }
 
+// reconcileGRPCTool derives the input schema of a gRPC tool from the request message of its
+// method. The last schema is kept while the method cannot be loaded.
+func (r *ToolReconciler) reconcileGRPCTool(ctx context.Context, tool *arkv1alpha1.Tool) (ctrl.Result, error) {
+	inputSchema := tool.Status.InputSchema
+	state, message := arkv1alpha1.ToolStateReady, "Tool configuration is valid"
+	requeueAfter := grpcToolSchemaRefreshInterval
+
+	schema, err := genai.ResolveGRPCToolSchema(ctx, r.Client, tool)
+	if err != nil {
+		state, message = arkv1alpha1.ToolStateError, fmt.Sprintf("Failed to load gRPC method: %v", err)
+		requeueAfter = grpcToolSchemaRetryInterval
+	} else {
+		raw, err := json.Marshal(schema)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to marshal input schema: %w", err)
+		}
+		inputSchema = &runtime.RawExtension{Raw: raw}
+	}
+
+	if tool.Status.State != state || tool.Status.Message != message || !sameRawExtension(tool.Status.InputSchema, inputSchema) {
+		tool.Status.InputSchema = inputSchema
+		if _, err := r.updateToolStatus(ctx, tool, state, message); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// sameRawExtension compares the JSON of two raw extensions, as the API server may encode the
+// stored schema differently
+func sameRawExtension(a, b *runtime.RawExtension) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	var aValue, bValue any
+	if json.Unmarshal(a.Raw, &aValue) != nil || json.Unmarshal(b.Raw, &bValue) != nil {
+		return bytes.Equal(a.Raw, b.Raw)
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
 func (r *ToolReconciler) updateToolStatus(ctx context.Context, tool *arkv1alpha1.Tool, state, message string) (ctrl.Result, error) {
 	tool.Status.State = state
 	tool.Status.Message = message
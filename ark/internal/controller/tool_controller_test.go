@@ -4,11 +4,22 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"net"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -79,3 +90,53 @@ var _ = Describe("Tool Controller", func() {
 		})
 	})
 })
+
+func TestToolReconcile_GRPCInputSchema(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "health", Namespace: "default"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type: arkv1alpha1.ToolTypeGRPC,
+			GRPC: &arkv1alpha1.GRPCSpec{
+				Address: arkv1alpha1.ValueSource{Value: listener.Addr().String()},
+				Service: "grpc.health.v1.Health",
+				Method:  "Check",
+			},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tool).WithStatusSubresource(tool).Build()
+	reconciler := &ToolReconciler{Client: k8sClient, Scheme: scheme}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "health", Namespace: "default"}}
+	ctx := context.Background()
+
+	result, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, grpcToolSchemaRefreshInterval, result.RequeueAfter)
+
+	var updated arkv1alpha1.Tool
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &updated))
+	assert.Equal(t, arkv1alpha1.ToolStateReady, updated.Status.State)
+	var inputSchema map[string]any
+	require.NoError(t, json.Unmarshal(updated.Status.InputSchema.Raw, &inputSchema))
+	assert.Equal(t, map[string]any{"type": "string"}, inputSchema["properties"].(map[string]any)["service"])
+
+	updated.Spec.GRPC.Method = "Missing"
+	require.NoError(t, k8sClient.Update(ctx, &updated))
+	result, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, grpcToolSchemaRetryInterval, result.RequeueAfter)
+
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, &updated))
+	assert.Equal(t, arkv1alpha1.ToolStateError, updated.Status.State)
+	assert.Contains(t, updated.Status.Message, "method Missing not found in service grpc.health.v1.Health")
+	assert.NotNil(t, updated.Status.InputSchema, "the last schema is kept")
+}
@@ -5,12 +5,13 @@ package controller
 import "mckinsey.com/ark/internal/annotations"
 
 const (
-	statusPending  = "pending"
-	statusRunning  = "running"
-	statusDone     = "done"
-	statusError    = "error"
-	statusCanceled = "canceled"
-	statusReady    = "ready"
+	statusPending          = "pending"
+	statusRunning          = "running"
+	statusAwaitingApproval = "awaiting-approval"
+	statusDone             = "done"
+	statusError            = "error"
+	statusCanceled         = "canceled"
+	statusReady            = "ready"
 
 	finalizer = annotations.Finalizer
 )
@@ -26,6 +26,7 @@ type Provider struct {
 	queryRecorder           eventing.QueryRecorder
 	toolRecorder            eventing.ToolRecorder
 	memoryRecorder          eventing.MemoryRecorder
+	guardrailRecorder       eventing.GuardrailRecorder
 }
 
 func NewProvider(mgr ctrl.Manager, k8sClient client.Client) *Provider {
@@ -65,6 +66,7 @@ func NewProvider(mgr ctrl.Manager, k8sClient client.Client) *Provider {
 		queryRecorder:           recorders.NewQueryRecorder(k8sEmitter, operationEmitter),
 		toolRecorder:            recorders.NewToolRecorder(k8sEmitter, operationEmitter),
 		memoryRecorder:          recorders.NewMemoryRecorder(k8sEmitter, operationEmitter),
+		guardrailRecorder:       recorders.NewGuardrailRecorder(k8sEmitter, operationEmitter),
 	}
 }
 
@@ -103,3 +105,7 @@ func (p *Provider) ToolRecorder() eventing.ToolRecorder {
 func (p *Provider) MemoryRecorder() eventing.MemoryRecorder {
 	return p.memoryRecorder
 }
+
+func (p *Provider) GuardrailRecorder() eventing.GuardrailRecorder {
+	return p.guardrailRecorder
+}
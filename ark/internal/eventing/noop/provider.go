@@ -6,21 +6,23 @@ import (
 )
 
 type noopProvider struct {
-	queryRecorder eventing.QueryRecorder
-	modelRecorder eventing.ModelRecorder
-	agentRecorder eventing.AgentRecorder
-	teamRecorder  eventing.TeamRecorder
-	toolRecorder  eventing.ToolRecorder
+	queryRecorder     eventing.QueryRecorder
+	modelRecorder     eventing.ModelRecorder
+	agentRecorder     eventing.AgentRecorder
+	teamRecorder      eventing.TeamRecorder
+	toolRecorder      eventing.ToolRecorder
+	guardrailRecorder eventing.GuardrailRecorder
 }
 
 func NewProvider() eventing.Provider {
 	emitter := NewNoopEventEmitter()
 	return &noopProvider{
-		queryRecorder: NewQueryRecorder(),
-		modelRecorder: recorder.NewModelRecorder(emitter, emitter),
-		agentRecorder: recorder.NewAgentRecorder(emitter, emitter),
-		teamRecorder:  recorder.NewTeamRecorder(emitter, emitter),
-		toolRecorder:  recorder.NewToolRecorder(emitter, emitter),
+		queryRecorder:     NewQueryRecorder(),
+		modelRecorder:     recorder.NewModelRecorder(emitter, emitter),
+		agentRecorder:     recorder.NewAgentRecorder(emitter, emitter),
+		teamRecorder:      recorder.NewTeamRecorder(emitter, emitter),
+		toolRecorder:      recorder.NewToolRecorder(emitter, emitter),
+		guardrailRecorder: recorder.NewGuardrailRecorder(emitter, emitter),
 	}
 }
 
@@ -60,6 +62,10 @@ func (p *noopProvider) MemoryRecorder() eventing.MemoryRecorder {
 	return nil
 }
 
+func (p *noopProvider) GuardrailRecorder() eventing.GuardrailRecorder {
+	return p.guardrailRecorder
+}
+
 func NewModelRecorder() eventing.ModelRecorder {
 	emitter := NewNoopEventEmitter()
 	return recorder.NewModelRecorder(emitter, emitter)
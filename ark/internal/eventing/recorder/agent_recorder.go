@@ -2,21 +2,25 @@ package recorder
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"mckinsey.com/ark/internal/eventing"
 	"mckinsey.com/ark/internal/eventing/recorder/operations"
+	"mckinsey.com/ark/internal/eventing/recorder/tokens"
 )
 
 type agentRecorder struct {
 	emitter eventing.EventEmitter
+	tokens.TokenCollector
 	operations.OperationTracker
 }
 
 func NewAgentRecorder(emitter, operationEmitter eventing.EventEmitter) eventing.AgentRecorder {
 	return &agentRecorder{
 		emitter:          emitter,
+		TokenCollector:   tokens.NewTokenCollector(),
 		OperationTracker: operations.NewOperationTracker(operationEmitter),
 	}
 }
@@ -24,3 +28,11 @@ func NewAgentRecorder(emitter, operationEmitter eventing.EventEmitter) eventing.
 func (t *agentRecorder) DependencyUnavailable(ctx context.Context, obj runtime.Object, reason string) {
 	t.emitter.EmitWarning(ctx, obj, "DependencyUnavailable", reason)
 }
+
+func (t *agentRecorder) MaxIterationsReached(ctx context.Context, agentName string, maxIterations int) {
+	queryDetails := t.GetQueryDetails(ctx)
+	if queryDetails == nil {
+		return
+	}
+	t.emitter.EmitWarning(ctx, queryDetails.Query, "MaxIterationsReached", fmt.Sprintf("Agent %s reached the maximum of %d iterations", agentName, maxIterations))
+}
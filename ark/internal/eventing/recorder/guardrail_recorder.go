@@ -0,0 +1,29 @@
+package recorder
+
+import (
+	"context"
+	"fmt"
+
+	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/eventing/recorder/operations"
+)
+
+type guardrailRecorder struct {
+	operations.OperationTracker
+	emitter eventing.EventEmitter
+}
+
+func NewGuardrailRecorder(emitter, operationEmitter eventing.EventEmitter) eventing.GuardrailRecorder {
+	return &guardrailRecorder{
+		OperationTracker: operations.NewOperationTracker(operationEmitter),
+		emitter:          emitter,
+	}
+}
+
+func (t *guardrailRecorder) GuardrailTriggered(ctx context.Context, guardrail, stage, action, reason string) {
+	queryDetails := t.GetQueryDetails(ctx)
+	if queryDetails == nil {
+		return
+	}
+	t.emitter.EmitWarning(ctx, queryDetails.Query, "GuardrailTriggered", fmt.Sprintf("Guardrail %s triggered on %s (%s): %s", guardrail, stage, action, reason))
+}
@@ -2,6 +2,7 @@ package recorder
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -27,3 +28,19 @@ func NewModelRecorder(emitter, operationEmitter eventing.EventEmitter) eventing.
 func (t *modelRecorder) ModelUnavailable(ctx context.Context, model runtime.Object, reason string) {
 	t.emitter.EmitWarning(ctx, model, "ModelUnavailable", reason)
 }
+
+func (t *modelRecorder) ModelFallback(ctx context.Context, failedModel, fallbackModel, reason string) {
+	queryDetails := t.GetQueryDetails(ctx)
+	if queryDetails == nil {
+		return
+	}
+	t.emitter.EmitWarning(ctx, queryDetails.Query, "ModelFallback", fmt.Sprintf("Model %s failed, falling back to %s: %s", failedModel, fallbackModel, reason))
+}
+
+func (t *modelRecorder) ModelRouted(ctx context.Context, router, model, reason string) {
+	queryDetails := t.GetQueryDetails(ctx)
+	if queryDetails == nil {
+		return
+	}
+	t.emitter.EmitNormal(ctx, queryDetails.Query, "ModelRouted", fmt.Sprintf("Router %s dispatched the call to model %s: %s", router, model, reason))
+}
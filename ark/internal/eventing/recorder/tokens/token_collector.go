@@ -2,6 +2,7 @@ package tokens
 
 import (
 	"context"
+	"sync"
 
 	"github.com/openai/openai-go"
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
@@ -11,6 +12,13 @@ type tokenUsageKeyType struct{}
 
 var tokenUsageKey = tokenUsageKeyType{}
 
+// collectedUsage is the usage of one collection. It is updated from concurrently executing
+// tool calls (e.g. agents as tools), so it carries its own lock.
+type collectedUsage struct {
+	mu    sync.Mutex
+	usage arkv1alpha1.TokenUsage
+}
+
 type TokenCollector struct{}
 
 func NewTokenCollector() TokenCollector {
@@ -18,34 +26,50 @@ func NewTokenCollector() TokenCollector {
 }
 
 func (tc *TokenCollector) StartTokenCollection(ctx context.Context) context.Context {
-	usage := &arkv1alpha1.TokenUsage{}
-	return context.WithValue(ctx, tokenUsageKey, usage)
+	return context.WithValue(ctx, tokenUsageKey, &collectedUsage{})
 }
 
 func (tc *TokenCollector) AddTokens(ctx context.Context, promptTokens, completionTokens, totalTokens int64) {
-	usage, ok := ctx.Value(tokenUsageKey).(*arkv1alpha1.TokenUsage)
-	if !ok || usage == nil {
+	collected, ok := ctx.Value(tokenUsageKey).(*collectedUsage)
+	if !ok || collected == nil {
 		return
 	}
 
-	usage.PromptTokens += promptTokens
-	usage.CompletionTokens += completionTokens
-	usage.TotalTokens += totalTokens
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	collected.usage.PromptTokens += promptTokens
+	collected.usage.CompletionTokens += completionTokens
+	collected.usage.TotalTokens += totalTokens
 }
 
 func (tc *TokenCollector) AddTokenUsage(ctx context.Context, usage arkv1alpha1.TokenUsage) {
-	tc.AddTokens(ctx, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	collected, ok := ctx.Value(tokenUsageKey).(*collectedUsage)
+	if !ok || collected == nil {
+		return
+	}
+
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	collected.usage.Add(usage)
 }
 
 func (tc *TokenCollector) AddCompletionUsage(ctx context.Context, usage openai.CompletionUsage) {
-	tc.AddTokens(ctx, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	tc.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		ReasoningTokens:  usage.CompletionTokensDetails.ReasoningTokens,
+		CachedTokens:     usage.PromptTokensDetails.CachedTokens,
+	})
 }
 
 func (tc *TokenCollector) GetTokenSummary(ctx context.Context) arkv1alpha1.TokenUsage {
-	usage, ok := ctx.Value(tokenUsageKey).(*arkv1alpha1.TokenUsage)
-	if !ok || usage == nil {
+	collected, ok := ctx.Value(tokenUsageKey).(*collectedUsage)
+	if !ok || collected == nil {
 		return arkv1alpha1.TokenUsage{}
 	}
 
-	return *usage
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	return *collected.usage.DeepCopy()
 }
@@ -15,9 +15,10 @@ func TestTokenCollector_StartTokenCollection(t *testing.T) {
 
 	ctx = tc.StartTokenCollection(ctx)
 
-	usage, ok := ctx.Value(tokenUsageKey).(*arkv1alpha1.TokenUsage)
+	collected, ok := ctx.Value(tokenUsageKey).(*collectedUsage)
 	assert.True(t, ok, "Expected tokenUsageKey to be set in context")
-	assert.NotNil(t, usage, "Expected usage to be initialized")
+	assert.NotNil(t, collected, "Expected usage to be initialized")
+	usage := collected.usage
 	assert.Equal(t, int64(0), usage.PromptTokens)
 	assert.Equal(t, int64(0), usage.CompletionTokens)
 	assert.Equal(t, int64(0), usage.TotalTokens)
@@ -77,6 +78,20 @@ func TestTokenCollector_AddTokenUsage(t *testing.T) {
 	assert.Equal(t, int64(150), usage.TotalTokens)
 }
 
+func TestTokenCollector_AddTokenUsage_Cost(t *testing.T) {
+	tc := NewTokenCollector()
+	ctx := tc.StartTokenCollection(context.Background())
+
+	tc.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{TotalTokens: 150, Cost: map[string]string{"USD": "0.25"}})
+	tc.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{TotalTokens: 50, Cost: map[string]string{"USD": "0.5"}})
+	summary := tc.GetTokenSummary(ctx)
+	tc.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{TotalTokens: 10, Cost: map[string]string{"USD": "1"}})
+
+	assert.Equal(t, int64(200), summary.TotalTokens)
+	assert.Equal(t, map[string]string{"USD": "0.75"}, summary.Cost, "summaries are not changed by later usage")
+	assert.Equal(t, map[string]string{"USD": "1.75"}, tc.GetTokenSummary(ctx).Cost)
+}
+
 func TestTokenCollector_AddCompletionUsage(t *testing.T) {
 	tc := NewTokenCollector()
 	ctx := tc.StartTokenCollection(context.Background())
@@ -94,6 +109,28 @@ func TestTokenCollector_AddCompletionUsage(t *testing.T) {
 	assert.Equal(t, int64(150), usage.TotalTokens)
 }
 
+func TestTokenCollector_AddCompletionUsage_ReasoningAndCachedTokens(t *testing.T) {
+	tc := NewTokenCollector()
+	ctx := tc.StartTokenCollection(context.Background())
+
+	completionUsage := openai.CompletionUsage{
+		PromptTokens:            100,
+		CompletionTokens:        50,
+		TotalTokens:             150,
+		PromptTokensDetails:     openai.CompletionUsagePromptTokensDetails{CachedTokens: 80},
+		CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{ReasoningTokens: 30},
+	}
+	tc.AddCompletionUsage(ctx, completionUsage)
+	tc.AddCompletionUsage(ctx, completionUsage)
+
+	usage := tc.GetTokenSummary(ctx)
+	assert.Equal(t, int64(200), usage.PromptTokens)
+	assert.Equal(t, int64(100), usage.CompletionTokens)
+	assert.Equal(t, int64(300), usage.TotalTokens)
+	assert.Equal(t, int64(60), usage.ReasoningTokens)
+	assert.Equal(t, int64(160), usage.CachedTokens)
+}
+
 func TestTokenCollector_GetTokenSummary_NoCollection(t *testing.T) {
 	tc := NewTokenCollector()
 	ctx := context.Background()
@@ -27,6 +27,8 @@ type ModelRecorder interface {
 	OperationTracker
 	TokenCollector
 	ModelUnavailable(ctx context.Context, model runtime.Object, reason string)
+	ModelFallback(ctx context.Context, failedModel, fallbackModel, reason string)
+	ModelRouted(ctx context.Context, router, model, reason string)
 }
 
 type A2aRecorder interface {
@@ -43,7 +45,9 @@ type A2aRecorder interface {
 
 type AgentRecorder interface {
 	OperationTracker
+	TokenCollector
 	DependencyUnavailable(ctx context.Context, obj runtime.Object, reason string)
+	MaxIterationsReached(ctx context.Context, agentName string, maxIterations int)
 }
 
 type ExecutionEngineRecorder interface {
@@ -78,6 +82,11 @@ type MemoryRecorder interface {
 	OperationTracker
 }
 
+type GuardrailRecorder interface {
+	OperationTracker
+	GuardrailTriggered(ctx context.Context, guardrail, stage, action, reason string)
+}
+
 type Provider interface {
 	ModelRecorder() ModelRecorder
 	A2aRecorder() A2aRecorder
@@ -88,4 +97,5 @@ type Provider interface {
 	QueryRecorder() QueryRecorder
 	ToolRecorder() ToolRecorder
 	MemoryRecorder() MemoryRecorder
+	GuardrailRecorder() GuardrailRecorder
 }
@@ -3,6 +3,7 @@ package genai
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
@@ -31,7 +32,22 @@ type Agent struct {
 	ExecutionEngine   *arkv1alpha1.ExecutionEngineRef
 	Annotations       map[string]string
 	OutputSchema      *runtime.RawExtension
-	client            client.Client
+	// OutputSchemaRepairAttempts bounds the re-prompts for responses that do not match OutputSchema;
+	// nil uses the default
+	OutputSchemaRepairAttempts *int
+	// MaxIterations bounds the number of model calls in the tool-calling loop; nil means unbounded
+	MaxIterations                *int
+	FinalResponseOnMaxIterations bool
+	// MaxParallelToolCalls bounds concurrent tool calls per model response; nil or 1 runs them sequentially
+	MaxParallelToolCalls *int
+	// ContextPolicy trims the messages sent on each model call to a token budget; nil disables trimming
+	ContextPolicy *arkv1alpha1.ContextPolicy
+	// Guardrails check the agent's input before the model is called and its final response
+	Guardrails []*Guardrail
+	// summaryModel summarizes removed messages for the summarize context strategy. It is loaded
+	// separately from Model so the summary call does not share the output schema set on Model.
+	summaryModel *Model
+	client       client.Client
 }
 
 // FullName returns the namespace/name format for the agent
@@ -44,35 +60,73 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 	ctx, span := a.telemetryRecorder.StartAgentExecution(ctx, a.Name, a.Namespace)
 	defer span.End()
 
+	// The agent's tokens are collected separately to report its cost, then added to the caller's
+	agentctx := a.eventingRecorder.StartTokenCollection(ctx)
 	operationData := map[string]string{
 		"agent": a.FullName(),
 	}
-	ctx = a.eventingRecorder.Start(ctx, "AgentExecution", fmt.Sprintf("Executing agent %s", a.FullName()), operationData)
+	agentctx = a.eventingRecorder.Start(agentctx, "AgentExecution", fmt.Sprintf("Executing agent %s", a.FullName()), operationData)
+
+	result, err := a.executeAgent(agentctx, userInput, history, memory, eventStream)
+
+	usage := a.eventingRecorder.GetTokenSummary(agentctx)
+	a.eventingRecorder.AddTokenUsage(ctx, usage)
+	AddTokenUsageToOperationData(operationData, usage)
+	a.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	if len(usage.Cost) > 0 {
+		a.telemetryRecorder.RecordCost(span, usage.Cost)
+	}
 
-	result, err := a.executeAgent(ctx, userInput, history, memory, eventStream)
 	if err != nil {
 		a.telemetryRecorder.RecordError(span, err)
 		if !IsTerminateTeam(err) {
-			a.eventingRecorder.Fail(ctx, "AgentExecution", fmt.Sprintf("Agent execution failed: %v", err), err, operationData)
+			a.eventingRecorder.Fail(agentctx, "AgentExecution", fmt.Sprintf("Agent execution failed: %v", err), err, operationData)
 		}
 		return nil, err
 	}
 
 	a.telemetryRecorder.RecordSuccess(span)
-	a.eventingRecorder.Complete(ctx, "AgentExecution", "Agent execution completed successfully", operationData)
+	a.eventingRecorder.Complete(agentctx, "AgentExecution", "Agent execution completed successfully", operationData)
 	return result, nil
 }
 
 func (a *Agent) executeAgent(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
-	if a.ExecutionEngine != nil {
-		return a.executeWithExecutionEngineRouter(ctx, userInput, history, eventStream)
+	userInput, err := ApplyGuardrailsToMessage(ctx, a.Guardrails, GuardrailStageInput, userInput)
+	if err != nil {
+		return nil, err
+	}
+
+	// Output that guardrails check, or that may be repaired to match the outputSchema, is streamed
+	// once it has been checked
+	executionStream := eventStream
+	if HasOutputGuardrails(a.Guardrails) || a.OutputSchema != nil {
+		executionStream = nil
 	}
 
-	messages, err := a.executeLocally(ctx, userInput, history, memory, eventStream)
+	var result *ExecutionResult
+	if a.ExecutionEngine != nil {
+		result, err = a.executeWithExecutionEngineRouter(ctx, userInput, history, executionStream)
+	} else {
+		result, err = a.executeLocally(ctx, userInput, history, memory, executionStream)
+	}
 	if err != nil {
+		return result, err
+	}
+
+	if err := ApplyOutputGuardrails(ctx, a.Guardrails, result); err != nil {
 		return nil, err
 	}
-	return &ExecutionResult{Messages: messages}, nil
+	if executionStream == nil {
+		StreamCheckedResult(ctx, eventStream, result, a.modelName())
+	}
+	return result, nil
+}
+
+func (a *Agent) modelName() string {
+	if a.Model == nil {
+		return ""
+	}
+	return a.Model.Model
 }
 
 func (a *Agent) executeWithExecutionEngineRouter(ctx context.Context, userInput Message, history []Message, eventStream EventStreamInterface) (*ExecutionResult, error) {
@@ -131,6 +185,11 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 	// Truncate schema name to 64 chars for OpenAI API compatibility - name is purely an identifier
 	a.Model.SchemaName = fmt.Sprintf("%.64s", fmt.Sprintf("namespace-%s-agent-%s", a.Namespace, a.Name))
 
+	// The history sent is trimmed to the context policy, which a chained Responses API call
+	// would bypass
+	if a.ContextPolicy != nil {
+		ctx = withoutResponseChaining(ctx)
+	}
 	response, err := a.Model.ChatCompletion(ctx, agentMessages, eventStream, 1, tools)
 	if err != nil {
 		return nil, fmt.Errorf("agent %s execution failed: %w", a.FullName(), err)
@@ -144,7 +203,7 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 }
 
 func (a *Agent) processAssistantMessage(choice openai.ChatCompletionChoice) Message {
-	assistantMessage := Message(choice.Message.ToParam())
+	assistantMessage := assistantMessageFromCompletion(choice.Message)
 
 	if m := assistantMessage.OfAssistant; m != nil {
 		m.Name = param.Opt[string]{Value: a.Name}
@@ -154,6 +213,16 @@ func (a *Agent) processAssistantMessage(choice openai.ChatCompletionChoice) Mess
 }
 
 func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) (Message, error) {
+	if a.Tools.RequiresApproval(toolCall.Function.Name) {
+		decision, err := a.requestToolApproval(ctx, ToolCall(toolCall))
+		if err != nil {
+			return ToolMessage(fmt.Sprintf("Approval of tool %s failed: %v", toolCall.Function.Name, err), toolCall.ID), err
+		}
+		if !decision.Approved {
+			return ToolMessage(rejectedToolMessage(toolCall.Function.Name, decision.Reason), toolCall.ID), nil
+		}
+	}
+
 	result, err := a.Tools.ExecuteTool(ctx, ToolCall(toolCall))
 	toolMessage := ToolMessage(result.Content, result.ID)
 
@@ -165,6 +234,10 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 }
 
 func (a *Agent) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, agentMessages, newMessages *[]Message) error {
+	if a.MaxParallelToolCalls != nil && *a.MaxParallelToolCalls > 1 && len(toolCalls) > 1 {
+		return a.executeToolCallsParallel(ctx, toolCalls, *a.MaxParallelToolCalls, agentMessages, newMessages)
+	}
+
 	for _, tc := range toolCalls {
 		if ctx.Err() != nil {
 			return ctx.Err()
@@ -181,8 +254,50 @@ func (a *Agent) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCom
 	return nil
 }
 
+// executeToolCallsParallel runs up to limit tool calls at once. Every call is given the
+// agent context so tool spans share the agent span as parent, and results are appended
+// in the original tool-call order to keep message history deterministic.
+func (a *Agent) executeToolCallsParallel(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, limit int, agentMessages, newMessages *[]Message) error {
+	toolMessages := make([]Message, len(toolCalls))
+	errs := make([]error, len(toolCalls))
+	semaphore := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, tc := range toolCalls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+			}
+
+			if ctx.Err() != nil {
+				toolMessages[i] = ToolMessage(ctx.Err().Error(), tc.ID)
+				errs[i] = ctx.Err()
+				return
+			}
+
+			toolMessages[i], errs[i] = a.executeToolCall(ctx, tc)
+		}()
+	}
+	wg.Wait()
+
+	*agentMessages = append(*agentMessages, toolMessages...)
+	*newMessages = append(*newMessages, toolMessages...)
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // executeLocally executes the agent using the built-in OpenAI-compatible engine
-func (a *Agent) executeLocally(ctx context.Context, userInput Message, history []Message, _ MemoryInterface, eventStream EventStreamInterface) ([]Message, error) {
+func (a *Agent) executeLocally(ctx context.Context, userInput Message, history []Message, _ MemoryInterface, eventStream EventStreamInterface) (*ExecutionResult, error) {
 	var tools []openai.ChatCompletionToolParam
 	if a.Tools != nil {
 		tools = a.Tools.ToOpenAITools()
@@ -198,12 +313,19 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 	}
 
 	newMessages := []Message{}
+	iterations := 0
 
 	for {
 		if ctx.Err() != nil {
-			return newMessages, ctx.Err()
+			return &ExecutionResult{Messages: newMessages}, ctx.Err()
+		}
+
+		if a.MaxIterations != nil && iterations >= *a.MaxIterations {
+			return a.handleMaxIterationsReached(ctx, agentMessages, newMessages, eventStream)
 		}
+		iterations++
 
+		agentMessages = a.fitContextWindow(ctx, agentMessages, tools)
 		response, err := a.executeModelCall(ctx, agentMessages, tools, eventStream)
 		if err != nil {
 			return nil, err
@@ -216,7 +338,7 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 		newMessages = append(newMessages, assistantMessage)
 
 		if len(choice.Message.ToolCalls) == 0 {
-			return newMessages, nil
+			return a.enforceOutputSchema(ctx, agentMessages, &ExecutionResult{Messages: newMessages}, eventStream)
 		}
 
 		if err := a.executeToolCalls(ctx, choice.Message.ToolCalls, &agentMessages, &newMessages); err != nil {
@@ -224,11 +346,34 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 			if !IsTerminateTeam(err) {
 				logger.Error(err, "Tool execution failed", "agent", a.FullName())
 			}
-			return newMessages, err
+			return &ExecutionResult{Messages: newMessages}, err
 		}
 	}
 }
 
+// handleMaxIterationsReached stops the tool-calling loop once the iteration budget is spent.
+// If configured, the model gets one last call without tools so it can answer with what it has.
+func (a *Agent) handleMaxIterationsReached(ctx context.Context, agentMessages, newMessages []Message, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	maxIterations := *a.MaxIterations
+	a.eventingRecorder.MaxIterationsReached(ctx, a.FullName(), maxIterations)
+	logf.FromContext(ctx).Info("Agent reached maximum iterations", "agent", a.FullName(), "maxIterations", maxIterations)
+
+	if !a.FinalResponseOnMaxIterations {
+		return &ExecutionResult{Messages: newMessages}, &MaxIterationsReached{Agent: a.FullName(), MaxIterations: maxIterations}
+	}
+
+	agentMessages = a.fitContextWindow(ctx, agentMessages, nil)
+	response, err := a.executeModelCall(ctx, agentMessages, nil, eventStream)
+	if err != nil {
+		return nil, err
+	}
+
+	assistantMessage := a.processAssistantMessage(response.Choices[0])
+	agentMessages = append(agentMessages, assistantMessage)
+	newMessages = append(newMessages, assistantMessage)
+	return a.enforceOutputSchema(ctx, agentMessages, &ExecutionResult{Messages: newMessages, MaxIterationsReached: true}, eventStream)
+}
+
 func (a *Agent) GetName() string {
 	return a.Name
 }
@@ -349,12 +494,20 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 	// A2A agents don't need models - they delegate to external A2A servers
 	if crd.Spec.ExecutionEngine == nil || crd.Spec.ExecutionEngine.Name != ExecutionEngineA2A {
 		var err error
-		resolvedModel, err = LoadModel(ctx, k8sClient, crd.Spec.ModelRef, crd.Namespace, modelHeaders, telemetryProvider.ModelRecorder(), eventingProvider.ModelRecorder())
+		resolvedModel, err = loadAgentModels(ctx, k8sClient, crd, modelHeaders, telemetryProvider.ModelRecorder(), eventingProvider.ModelRecorder())
 		if err != nil {
 			return nil, fmt.Errorf("failed to load model for agent %s/%s: %w", crd.Namespace, crd.Name, err)
 		}
 	}
 
+	var summaryModel *Model
+	if resolvedModel != nil && crd.Spec.ContextPolicy != nil && crd.Spec.ContextPolicy.Strategy == ContextStrategySummarize {
+		summaryModel, err = loadAgentModels(ctx, k8sClient, crd, modelHeaders, telemetryProvider.ModelRecorder(), eventingProvider.ModelRecorder())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load summary model for agent %s/%s: %w", crd.Namespace, crd.Name, err)
+		}
+	}
+
 	if crd.Spec.ExecutionEngine != nil {
 		err := ValidateExecutionEngine(ctx, k8sClient, crd.Spec.ExecutionEngine, crd.Namespace)
 		if err != nil {
@@ -375,24 +528,49 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 
 	tools := NewToolRegistry(mcpSettings, telemetryProvider.ToolRecorder(), eventingProvider.ToolRecorder())
 
+	toolErrorPolicy, err := ParseToolErrorPolicy(crd.Spec.ToolErrorPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("agent %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+	tools.SetErrorPolicy(toolErrorPolicy)
+	tools.SetApprovalPolicy(crd.Spec.ToolApproval)
+
+	// A query-level limit takes precedence over the agent's own limit
+	maxIterations := crd.Spec.MaxIterations
+	if queryCrd.Spec.MaxIterations != nil {
+		maxIterations = queryCrd.Spec.MaxIterations
+	}
+
 	if err := tools.registerTools(ctx, k8sClient, crd, telemetryProvider, eventingProvider); err != nil {
 		return nil, err
 	}
 
+	guardrails, err := LoadGuardrails(ctx, k8sClient, crd.Spec.Guardrails, crd.Namespace, telemetryProvider, eventingProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guardrails for agent %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+
 	return &Agent{
-		Name:              crd.Name,
-		Namespace:         crd.Namespace,
-		Prompt:            crd.Spec.Prompt,
-		Description:       crd.Spec.Description,
-		Parameters:        crd.Spec.Parameters,
-		Model:             resolvedModel,
-		Tools:             tools,
-		telemetryRecorder: telemetryProvider.AgentRecorder(),
-		eventingRecorder:  eventingProvider.AgentRecorder(),
-		eventing:          eventingProvider,
-		ExecutionEngine:   crd.Spec.ExecutionEngine,
-		Annotations:       crd.Annotations,
-		OutputSchema:      crd.Spec.OutputSchema,
-		client:            k8sClient,
+		Name:                         crd.Name,
+		Namespace:                    crd.Namespace,
+		Prompt:                       crd.Spec.Prompt,
+		Description:                  crd.Spec.Description,
+		Parameters:                   crd.Spec.Parameters,
+		Model:                        resolvedModel,
+		Tools:                        tools,
+		telemetryRecorder:            telemetryProvider.AgentRecorder(),
+		eventingRecorder:             eventingProvider.AgentRecorder(),
+		eventing:                     eventingProvider,
+		ExecutionEngine:              crd.Spec.ExecutionEngine,
+		Annotations:                  crd.Annotations,
+		OutputSchema:                 crd.Spec.OutputSchema,
+		OutputSchemaRepairAttempts:   crd.Spec.OutputSchemaRepairAttempts,
+		MaxIterations:                maxIterations,
+		FinalResponseOnMaxIterations: crd.Spec.FinalResponseOnMaxIterations,
+		MaxParallelToolCalls:         crd.Spec.MaxParallelToolCalls,
+		ContextPolicy:                contextPolicyWithDefaults(crd.Spec.ContextPolicy),
+		Guardrails:                   guardrails,
+		summaryModel:                 summaryModel,
+		client:                       k8sClient,
 	}, nil
 }
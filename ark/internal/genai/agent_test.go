@@ -0,0 +1,229 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// scriptedProvider returns a tool call for every request that offers tools and a
+// plain answer otherwise, recording how many tools each call received.
+type scriptedProvider struct {
+	toolCounts []int
+}
+
+func (p *scriptedProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	var offered []openai.ChatCompletionToolParam
+	if len(tools) > 0 {
+		offered = tools[0]
+	}
+	p.toolCounts = append(p.toolCounts, len(offered))
+
+	message := openai.ChatCompletionMessage{Role: "assistant", Content: "final answer"}
+	if len(offered) > 0 {
+		message = openai.ChatCompletionMessage{
+			Role: "assistant",
+			ToolCalls: []openai.ChatCompletionMessageToolCall{{
+				ID:       "call-1",
+				Type:     "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{Name: "noop", Arguments: `{"message":"again"}`},
+			}},
+		}
+	}
+	return &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: message}}}, nil
+}
+
+func (p *scriptedProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *scriptedProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func newLoopTestAgent(provider ChatCompletionProvider, maxIterations *int, finalResponse bool) *Agent {
+	eventing := eventnoop.NewProvider()
+	tools := NewToolRegistry(nil, noop.NewToolRecorder(), eventing.ToolRecorder())
+	tools.RegisterTool(GetNoopTool(), &NoopExecutor{})
+
+	return &Agent{
+		Name:      "looping-agent",
+		Namespace: "default",
+		Prompt:    "You are a test agent.",
+		Model: &Model{
+			Model:             "test-model",
+			Provider:          provider,
+			telemetryRecorder: noop.NewModelRecorder(),
+			eventingRecorder:  eventnoop.NewModelRecorder(),
+		},
+		Tools:                        tools,
+		telemetryRecorder:            noop.NewAgentRecorder(),
+		eventingRecorder:             eventing.AgentRecorder(),
+		eventing:                     eventing,
+		MaxIterations:                maxIterations,
+		FinalResponseOnMaxIterations: finalResponse,
+	}
+}
+
+func TestExecuteLocally_MaxIterationsReached(t *testing.T) {
+	provider := &scriptedProvider{}
+	maxIterations := 3
+	agent := newLoopTestAgent(provider, &maxIterations, false)
+
+	result, err := agent.executeLocally(context.Background(), NewUserMessage("hello"), nil, nil, nil)
+
+	require.Error(t, err)
+	require.True(t, IsMaxIterationsReached(err))
+	require.Len(t, provider.toolCounts, 3)
+	// Every model call is followed by its tool result so the history stays valid
+	require.Len(t, result.Messages, 6)
+	require.NotNil(t, result.Messages[len(result.Messages)-1].OfTool)
+}
+
+func TestExecuteLocally_FinalResponseOnMaxIterations(t *testing.T) {
+	provider := &scriptedProvider{}
+	maxIterations := 2
+	agent := newLoopTestAgent(provider, &maxIterations, true)
+
+	result, err := agent.executeLocally(context.Background(), NewUserMessage("hello"), nil, nil, nil)
+
+	require.NoError(t, err)
+	require.True(t, result.MaxIterationsReached)
+	require.Equal(t, []int{1, 1, 0}, provider.toolCounts)
+	last := result.Messages[len(result.Messages)-1]
+	require.NotNil(t, last.OfAssistant)
+	require.Equal(t, "final answer", last.OfAssistant.Content.OfString.Value)
+}
+
+func TestTeamExecute_PropagatesMaxIterationsReached(t *testing.T) {
+	maxIterations := 1
+	team := &Team{
+		Name:              "team",
+		Namespace:         "default",
+		Strategy:          "sequential",
+		Members:           []TeamMember{newLoopTestAgent(&scriptedProvider{}, &maxIterations, true)},
+		telemetryRecorder: noop.NewTeamRecorder(),
+		eventingRecorder:  eventnoop.NewProvider().TeamRecorder(),
+	}
+
+	result, err := team.Execute(context.Background(), NewUserMessage("hello"), nil, nil, nil)
+
+	require.NoError(t, err)
+	require.True(t, result.MaxIterationsReached)
+
+	// The agent fails without a final response, and the error reaches the team's caller
+	team.Members = []TeamMember{newLoopTestAgent(&scriptedProvider{}, &maxIterations, false)}
+	_, err = team.Execute(context.Background(), NewUserMessage("hello"), nil, nil, nil)
+	require.True(t, IsMaxIterationsReached(err))
+}
+
+func TestIsMaxIterationsReached(t *testing.T) {
+	require.False(t, IsMaxIterationsReached(nil))
+	require.False(t, IsMaxIterationsReached(&TerminateTeam{}))
+	require.True(t, IsMaxIterationsReached(&MaxIterationsReached{Agent: "default/a", MaxIterations: 1}))
+}
+
+// concurrencyExecutor records the peak number of simultaneous executions. Earlier
+// calls sleep longer so that completion order differs from call order.
+type concurrencyExecutor struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+	failOn  string
+}
+
+func (e *concurrencyExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	e.mu.Lock()
+	e.running++
+	e.peak = max(e.peak, e.running)
+	e.mu.Unlock()
+
+	var index int
+	_, _ = fmt.Sscanf(call.ID, "call-%d", &index)
+	time.Sleep(time.Duration(5-index) * 10 * time.Millisecond)
+
+	e.mu.Lock()
+	e.running--
+	e.mu.Unlock()
+
+	result := ToolResult{ID: call.ID, Name: call.Function.Name, Content: "result " + call.ID}
+	if call.ID == e.failOn {
+		return result, errors.New("tool failed")
+	}
+	return result, nil
+}
+
+func parallelToolCalls(count int) []openai.ChatCompletionMessageToolCall {
+	calls := make([]openai.ChatCompletionMessageToolCall, count)
+	for i := range calls {
+		calls[i] = openai.ChatCompletionMessageToolCall{
+			ID:       fmt.Sprintf("call-%d", i),
+			Type:     "function",
+			Function: openai.ChatCompletionMessageToolCallFunction{Name: "lookup", Arguments: "{}"},
+		}
+	}
+	return calls
+}
+
+func newParallelTestAgent(executor ToolExecutor, maxParallel int) *Agent {
+	eventing := eventnoop.NewProvider()
+	tools := NewToolRegistry(nil, noop.NewToolRecorder(), eventing.ToolRecorder())
+	tools.RegisterTool(ToolDefinition{Name: "lookup"}, executor)
+
+	return &Agent{
+		Name:                 "parallel-agent",
+		Namespace:            "default",
+		Tools:                tools,
+		MaxParallelToolCalls: &maxParallel,
+	}
+}
+
+func TestExecuteToolCalls_ParallelPreservesOrder(t *testing.T) {
+	executor := &concurrencyExecutor{}
+	agent := newParallelTestAgent(executor, 3)
+
+	var agentMessages, newMessages []Message
+	err := agent.executeToolCalls(context.Background(), parallelToolCalls(5), &agentMessages, &newMessages)
+
+	require.NoError(t, err)
+	require.Equal(t, 3, executor.peak)
+	require.Len(t, newMessages, 5)
+	require.Equal(t, newMessages, agentMessages)
+	for i, msg := range newMessages {
+		require.Equal(t, fmt.Sprintf("call-%d", i), msg.OfTool.ToolCallID)
+		require.Equal(t, fmt.Sprintf("result call-%d", i), msg.OfTool.Content.OfString.Value)
+	}
+}
+
+func TestExecuteToolCalls_SequentialByDefault(t *testing.T) {
+	executor := &concurrencyExecutor{}
+	agent := newParallelTestAgent(executor, 1)
+	agent.MaxParallelToolCalls = nil
+
+	var agentMessages, newMessages []Message
+	err := agent.executeToolCalls(context.Background(), parallelToolCalls(3), &agentMessages, &newMessages)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, executor.peak)
+	require.Len(t, newMessages, 3)
+}
+
+func TestExecuteToolCalls_ParallelReturnsFirstErrorInOrder(t *testing.T) {
+	executor := &concurrencyExecutor{failOn: "call-2"}
+	agent := newParallelTestAgent(executor, 4)
+
+	var agentMessages, newMessages []Message
+	err := agent.executeToolCalls(context.Background(), parallelToolCalls(4), &agentMessages, &newMessages)
+
+	require.EqualError(t, err, "tool failed")
+	// All calls were executed, so every tool call keeps its result in history
+	require.Len(t, newMessages, 4)
+}
@@ -81,6 +81,10 @@ func CreateToolExecutor(ctx context.Context, k8sClient client.Client, tool *arkv
 		return createTeamExecutor(ctx, k8sClient, tool, namespace, telemetryProvider, eventingProvider)
 	case ToolTypeBuiltin:
 		return createBuiltinExecutor(tool)
+	case ToolTypeKubernetes:
+		return createKubernetesExecutor(k8sClient, tool, namespace), nil
+	case ToolTypeGRPC:
+		return createGRPCExecutor(k8sClient, tool, namespace)
 	default:
 		return nil, fmt.Errorf("unsupported tool type %s for tool %s", tool.Spec.Type, tool.Name)
 	}
@@ -139,6 +143,32 @@ func createBuiltinExecutor(tool *arkv1alpha1.Tool) (ToolExecutor, error) {
 	}
 }
 
+func createKubernetesExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) ToolExecutor {
+	spec := tool.Spec.Kubernetes
+	if spec == nil {
+		spec = &arkv1alpha1.KubernetesToolSpec{}
+	}
+	return &KubernetesExecutor{
+		K8sClient:   k8sClient,
+		Spec:        spec,
+		Namespace:   namespace,
+		AllowWrites: KubernetesToolAllowsWrites(tool.Spec.Annotations),
+	}
+}
+
+func createGRPCExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
+	if tool.Spec.GRPC == nil {
+		return nil, fmt.Errorf("grpc spec is required for tool %s", tool.Name)
+	}
+	return &GRPCExecutor{
+		K8sClient:  k8sClient,
+		ToolName:   tool.Name,
+		Generation: tool.Generation,
+		Spec:       tool.Spec.GRPC,
+		Namespace:  namespace,
+	}, nil
+}
+
 func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
 	if tool.Spec.HTTP == nil {
 		return nil, fmt.Errorf("http spec is required for tool %s", tool.Name)
@@ -265,6 +295,18 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 		}
 	}
 
+	if agentTool.ErrorPolicy != "" {
+		policy, err := ParseToolErrorPolicy(agentTool.ErrorPolicy)
+		if err != nil {
+			return fmt.Errorf("tool %s: %w", toolDef.Name, err)
+		}
+		r.SetToolErrorPolicy(toolDef.Name, policy)
+	}
+
+	if toolRequiresApproval(r.approvalPolicy, toolDef.Name, tool) {
+		r.approvalRequired[toolDef.Name] = true
+	}
+
 	r.RegisterTool(toolDef, executor)
 	return nil
 }
@@ -7,16 +7,22 @@ const (
 
 // Provider constants - specifies which AI provider client to use.
 const (
-	ProviderAzure   = "azure"
-	ProviderOpenAI  = "openai"
-	ProviderBedrock = "bedrock"
+	ProviderAzure     = "azure"
+	ProviderOpenAI    = "openai"
+	ProviderBedrock   = "bedrock"
+	ProviderAnthropic = "anthropic"
+	ProviderGemini    = "gemini"
 )
 
 // Model type constants - specifies the API capability of the model.
-// New types can be added in the future (e.g., embeddings, responses).
 // See: https://github.com/mckinsey/agents-at-scale-ark/issues/37
 const (
 	ModelTypeCompletions = "completions"
+	ModelTypeEmbeddings  = "embeddings"
+	// ModelTypeResponses calls OpenAI and Azure OpenAI models through the Responses API
+	ModelTypeResponses = "responses"
+	// ModelTypeRouter dispatches each call to one of the models of the router's routes
+	ModelTypeRouter = "router"
 )
 
 // Deprecated: Ark < 0.50 used spec.type for provider selection.
@@ -49,11 +55,13 @@ const (
 
 // Tool type constants
 const (
-	ToolTypeHTTP    = "http"
-	ToolTypeMCP     = "mcp"
-	ToolTypeAgent   = "agent"
-	ToolTypeTeam    = "team"
-	ToolTypeBuiltin = "builtin"
+	ToolTypeHTTP       = "http"
+	ToolTypeMCP        = "mcp"
+	ToolTypeAgent      = "agent"
+	ToolTypeTeam       = "team"
+	ToolTypeBuiltin    = "builtin"
+	ToolTypeKubernetes = "kubernetes"
+	ToolTypeGRPC       = "grpc"
 )
 
 // Team member type constants
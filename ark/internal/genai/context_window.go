@@ -0,0 +1,234 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	ContextStrategyDropOldest    = "drop-oldest"
+	ContextStrategyKeepFirstLast = "keep-first-last"
+	ContextStrategySummarize     = "summarize"
+)
+
+const (
+	// Rough average for English text and JSON with common tokenizers
+	charsPerToken = 4
+	// Per-message framing added by chat templates (role, separators)
+	messageTokenOverhead = 4
+)
+
+// contextSummaryHeading separates the agent's prompt from the summary of removed messages in the
+// system message
+const contextSummaryHeading = "Summary of earlier conversation:\n"
+
+const contextSummaryPrompt = `You compress conversation history for an AI agent whose context window is full.
+Summarize the conversation messages you are given (one JSON message per line) in a few short paragraphs.
+Preserve facts, decisions, open questions, tool results and anything the agent will need to continue. Do not invent details.`
+
+// EstimateTokens approximates the number of tokens the messages use without calling a tokenizer
+func EstimateTokens(messages []Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += estimateMessageTokens(msg)
+	}
+	return total
+}
+
+func estimateMessageTokens(msg Message) int {
+	data, err := json.Marshal(openai.ChatCompletionMessageParamUnion(msg))
+	if err != nil {
+		return messageTokenOverhead
+	}
+	return len(data)/charsPerToken + messageTokenOverhead
+}
+
+func estimateToolTokens(tools []openai.ChatCompletionToolParam) int {
+	if len(tools) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return 0
+	}
+	return len(data) / charsPerToken
+}
+
+// groupMessages splits messages into units that are kept or dropped together. An assistant
+// message requesting tool calls and the tool results that follow it form one unit, so trimming
+// never leaves a tool result without its call or a call without its results.
+func groupMessages(messages []Message) [][]Message {
+	var groups [][]Message
+	for _, msg := range messages {
+		if msg.OfTool != nil && len(groups) > 0 {
+			last := groups[len(groups)-1]
+			if first := last[0].OfAssistant; first != nil && len(first.ToolCalls) > 0 {
+				groups[len(groups)-1] = append(last, msg)
+				continue
+			}
+		}
+		groups = append(groups, []Message{msg})
+	}
+	return groups
+}
+
+func flattenGroups(groups [][]Message) []Message {
+	var messages []Message
+	for _, group := range groups {
+		messages = append(messages, group...)
+	}
+	return messages
+}
+
+func estimateGroupTokens(groups [][]Message) int {
+	total := 0
+	for _, group := range groups {
+		total += EstimateTokens(group)
+	}
+	return total
+}
+
+// dropOldest removes the oldest groups after the first keepFirst until the messages fit the budget.
+// The most recent group is always kept. It returns the remaining and the removed groups.
+func dropOldest(groups [][]Message, budget, keepFirst int) ([][]Message, [][]Message) {
+	var dropped [][]Message
+	for estimateGroupTokens(groups) > budget && len(groups) > keepFirst+1 {
+		dropped = append(dropped, groups[keepFirst])
+		groups = append(groups[:keepFirst:keepFirst], groups[keepFirst+1:]...)
+	}
+	return groups, dropped
+}
+
+// keepFirstLast keeps the first and last groups and removes everything in between. The webhook
+// requires last to be at least 1; it is raised to 1 here for agents admitted before that check.
+func keepFirstLast(groups [][]Message, first, last int) [][]Message {
+	last = max(last, 1)
+	if len(groups) <= first+last {
+		return groups
+	}
+	kept := append([][]Message{}, groups[:first]...)
+	return append(kept, groups[len(groups)-last:]...)
+}
+
+func splitSystemMessage(messages []Message) (*Message, []Message) {
+	if len(messages) > 0 && messages[0].OfSystem != nil {
+		return &messages[0], messages[1:]
+	}
+	return nil, messages
+}
+
+func joinSystemMessage(system *Message, messages []Message) []Message {
+	if system == nil {
+		return messages
+	}
+	return append([]Message{*system}, messages...)
+}
+
+// fitContextWindow applies the agent's context policy so the messages and tool definitions
+// stay within the configured token budget. The system prompt is never removed.
+func (a *Agent) fitContextWindow(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) []Message {
+	policy := a.ContextPolicy
+	if policy == nil {
+		return messages
+	}
+
+	budget := policy.MaxTokens - estimateToolTokens(tools)
+	before := EstimateTokens(messages)
+	if before <= budget {
+		return messages
+	}
+
+	system, rest := splitSystemMessage(messages)
+	if system != nil {
+		budget -= estimateMessageTokens(*system)
+	}
+	groups := groupMessages(rest)
+
+	switch policy.Strategy {
+	case ContextStrategyKeepFirstLast:
+		groups = keepFirstLast(groups, policy.KeepFirst, policy.KeepLast)
+		groups, _ = dropOldest(groups, budget, min(policy.KeepFirst, len(groups)-1))
+	case ContextStrategySummarize:
+		var dropped [][]Message
+		groups, dropped = dropOldest(groups, budget, 0)
+		if len(dropped) > 0 {
+			summarized, err := a.summarizeMessages(ctx, system, flattenGroups(dropped))
+			if err != nil {
+				logf.FromContext(ctx).Error(err, "failed to summarize context, dropping oldest messages instead", "agent", a.FullName())
+			} else {
+				system = summarized
+				budget = policy.MaxTokens - estimateToolTokens(tools) - estimateMessageTokens(*system)
+			}
+		}
+	}
+	groups, _ = dropOldest(groups, budget, 0)
+
+	fitted := joinSystemMessage(system, flattenGroups(groups))
+	after := EstimateTokens(fitted)
+	logf.FromContext(ctx).Info("trimmed agent context to fit token budget", "agent", a.FullName(),
+		"strategy", policy.Strategy, "maxTokens", policy.MaxTokens, "estimatedTokensBefore", before, "estimatedTokensAfter", after,
+		"messagesBefore", len(messages), "messagesAfter", len(fitted))
+	return fitted
+}
+
+// summarizeMessages asks the agent's summary model to summarize the removed messages and returns
+// a system message with the summary after the original system prompt. A summary from an earlier
+// call is summarized along with the removed messages and replaced, so summaries do not stack up.
+func (a *Agent) summarizeMessages(ctx context.Context, system *Message, dropped []Message) (*Message, error) {
+	if a.summaryModel == nil {
+		return nil, fmt.Errorf("agent %s has no model to summarize context with", a.FullName())
+	}
+
+	prompt := ""
+	var transcript strings.Builder
+	if system != nil {
+		var previous string
+		prompt, previous, _ = strings.Cut(system.OfSystem.Content.OfString.Value, contextSummaryHeading)
+		prompt = strings.TrimSuffix(prompt, "\n\n")
+		if previous != "" {
+			dropped = append([]Message{NewSystemMessage(contextSummaryHeading + previous)}, dropped...)
+		}
+	}
+	for _, msg := range dropped {
+		data, err := json.Marshal(openai.ChatCompletionMessageParamUnion(msg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize message for summary: %w", err)
+		}
+		transcript.Write(data)
+		transcript.WriteString("\n")
+	}
+
+	response, err := a.summaryModel.ChatCompletion(ctx, []Message{NewSystemMessage(contextSummaryPrompt), NewUserMessage(transcript.String())}, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("agent %s context summary failed: %w", a.FullName(), err)
+	}
+	if response == nil || len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("agent %s context summary was empty", a.FullName())
+	}
+
+	summary := contextSummaryHeading + response.Choices[0].Message.Content
+	if prompt != "" {
+		summary = prompt + "\n\n" + summary
+	}
+	summaryMessage := NewSystemMessage(summary)
+	return &summaryMessage, nil
+}
+
+// contextPolicyWithDefaults returns a copy of the policy with the strategy defaulted to drop-oldest
+func contextPolicyWithDefaults(policy *arkv1alpha1.ContextPolicy) *arkv1alpha1.ContextPolicy {
+	if policy == nil {
+		return nil
+	}
+	resolved := policy.DeepCopy()
+	if resolved.Strategy == "" {
+		resolved.Strategy = ContextStrategyDropOldest
+	}
+	return resolved
+}
@@ -0,0 +1,205 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func toolCallMessage(id string) Message {
+	return Message(openai.ChatCompletionMessage{
+		Role: "assistant",
+		ToolCalls: []openai.ChatCompletionMessageToolCall{{
+			ID:       id,
+			Type:     "function",
+			Function: openai.ChatCompletionMessageToolCallFunction{Name: "lookup", Arguments: "{}"},
+		}},
+	}.ToParam())
+}
+
+// longConversation builds a system prompt followed by turns user/assistant-tool-call/tool/assistant
+func longConversation(turns int) []Message {
+	padding := strings.Repeat("x", 200)
+	messages := []Message{NewSystemMessage("You are a test agent.")}
+	for i := range turns {
+		id := fmt.Sprintf("call-%d", i)
+		messages = append(messages,
+			NewUserMessage(fmt.Sprintf("question %d %s", i, padding)),
+			toolCallMessage(id),
+			ToolMessage(fmt.Sprintf("result %d %s", i, padding), id),
+			NewAssistantMessage(fmt.Sprintf("answer %d %s", i, padding)),
+		)
+	}
+	return messages
+}
+
+func newContextTestAgent(policy *arkv1alpha1.ContextPolicy, provider ChatCompletionProvider) *Agent {
+	newModel := func() *Model {
+		return &Model{
+			Model:             "test-model",
+			Provider:          provider,
+			telemetryRecorder: noop.NewModelRecorder(),
+			eventingRecorder:  eventnoop.NewModelRecorder(),
+		}
+	}
+	return &Agent{
+		Name:          "context-agent",
+		Namespace:     "default",
+		ContextPolicy: contextPolicyWithDefaults(policy),
+		Model:         newModel(),
+		summaryModel:  newModel(),
+	}
+}
+
+// transcriptRecordingProvider records the transcripts it is asked to summarize
+type transcriptRecordingProvider struct {
+	scriptedProvider
+	transcripts []string
+}
+
+func (p *transcriptRecordingProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.transcripts = append(p.transcripts, messages[len(messages)-1].OfUser.Content.OfString.Value)
+	return p.scriptedProvider.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *transcriptRecordingProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+// requireToolPairsIntact checks that every tool result directly follows the call that requested it
+func requireToolPairsIntact(t *testing.T, messages []Message) {
+	t.Helper()
+	pending := map[string]bool{}
+	for _, msg := range messages {
+		switch {
+		case msg.OfAssistant != nil:
+			for id := range pending {
+				require.Failf(t, "orphaned tool call", "tool call %s has no result", id)
+			}
+			for _, tc := range msg.OfAssistant.ToolCalls {
+				pending[tc.ID] = true
+			}
+		case msg.OfTool != nil:
+			require.Truef(t, pending[msg.OfTool.ToolCallID], "orphaned tool result %s", msg.OfTool.ToolCallID)
+			delete(pending, msg.OfTool.ToolCallID)
+		}
+	}
+}
+
+func TestGroupMessages(t *testing.T) {
+	groups := groupMessages(longConversation(2)[1:])
+
+	require.Len(t, groups, 6)
+	assert.Len(t, groups[1], 2, "tool call and its result are grouped")
+	assert.NotNil(t, groups[1][0].OfAssistant)
+	assert.NotNil(t, groups[1][1].OfTool)
+}
+
+func TestFitContextWindow_NoPolicy(t *testing.T) {
+	agent := newContextTestAgent(nil, &scriptedProvider{})
+	messages := longConversation(10)
+
+	assert.Equal(t, messages, agent.fitContextWindow(context.Background(), messages, nil))
+}
+
+func TestFitContextWindow_WithinBudget(t *testing.T) {
+	agent := newContextTestAgent(&arkv1alpha1.ContextPolicy{MaxTokens: 100000}, &scriptedProvider{})
+	messages := longConversation(10)
+
+	assert.Equal(t, messages, agent.fitContextWindow(context.Background(), messages, nil))
+}
+
+func TestFitContextWindow_DropOldest(t *testing.T) {
+	messages := longConversation(10)
+	agent := newContextTestAgent(&arkv1alpha1.ContextPolicy{MaxTokens: EstimateTokens(messages) / 3}, &scriptedProvider{})
+
+	fitted := agent.fitContextWindow(context.Background(), messages, nil)
+
+	assert.LessOrEqual(t, EstimateTokens(fitted), agent.ContextPolicy.MaxTokens)
+	assert.Less(t, len(fitted), len(messages))
+	assert.Equal(t, messages[0], fitted[0], "system prompt is kept")
+	assert.Equal(t, messages[len(messages)-1], fitted[len(fitted)-1], "latest message is kept")
+	requireToolPairsIntact(t, fitted)
+}
+
+func TestFitContextWindow_KeepFirstLast(t *testing.T) {
+	messages := longConversation(10)
+	policy := &arkv1alpha1.ContextPolicy{
+		MaxTokens: EstimateTokens(messages) / 2,
+		Strategy:  ContextStrategyKeepFirstLast,
+		KeepFirst: 2,
+		KeepLast:  3,
+	}
+	agent := newContextTestAgent(policy, &scriptedProvider{})
+
+	fitted := agent.fitContextWindow(context.Background(), messages, nil)
+
+	// system + first user message + first tool exchange, then the last three units
+	require.Len(t, fitted, 1+3+4)
+	assert.Equal(t, messages[:4], fitted[:4])
+	assert.Equal(t, messages[len(messages)-4:], fitted[len(fitted)-4:])
+	requireToolPairsIntact(t, fitted)
+}
+
+func TestFitContextWindow_Summarize(t *testing.T) {
+	messages := longConversation(10)
+	provider := &scriptedProvider{}
+	policy := &arkv1alpha1.ContextPolicy{MaxTokens: EstimateTokens(messages) / 3, Strategy: ContextStrategySummarize}
+	agent := newContextTestAgent(policy, provider)
+
+	fitted := agent.fitContextWindow(context.Background(), messages, nil)
+
+	require.Len(t, provider.toolCounts, 1, "one summarization call")
+	system := fitted[0].OfSystem.Content.OfString.Value
+	assert.True(t, strings.HasPrefix(system, "You are a test agent."))
+	assert.Contains(t, system, "Summary of earlier conversation:\nfinal answer")
+	assert.LessOrEqual(t, EstimateTokens(fitted), policy.MaxTokens)
+	requireToolPairsIntact(t, fitted)
+}
+
+func TestFitContextWindow_SummarizeReplacesEarlierSummary(t *testing.T) {
+	messages := longConversation(10)
+	provider := &transcriptRecordingProvider{}
+	policy := &arkv1alpha1.ContextPolicy{MaxTokens: EstimateTokens(messages) / 3, Strategy: ContextStrategySummarize}
+	agent := newContextTestAgent(policy, provider)
+
+	fitted := agent.fitContextWindow(context.Background(), messages, nil)
+	fitted = agent.fitContextWindow(context.Background(), append(fitted, longConversation(10)[1:]...), nil)
+
+	require.Len(t, provider.transcripts, 2)
+	assert.Contains(t, provider.transcripts[1], `"content":"Summary of earlier conversation:\nfinal answer"`, "the earlier summary is summarized again")
+	system := fitted[0].OfSystem.Content.OfString.Value
+	assert.Equal(t, "You are a test agent.\n\nSummary of earlier conversation:\nfinal answer", system)
+	assert.LessOrEqual(t, EstimateTokens(fitted), policy.MaxTokens)
+}
+
+func TestFitContextWindow_SummarizeWithoutSummaryModel(t *testing.T) {
+	messages := longConversation(10)
+	policy := &arkv1alpha1.ContextPolicy{MaxTokens: EstimateTokens(messages) / 3, Strategy: ContextStrategySummarize}
+	agent := newContextTestAgent(policy, &scriptedProvider{})
+	agent.summaryModel = nil
+
+	fitted := agent.fitContextWindow(context.Background(), messages, nil)
+
+	assert.Equal(t, messages[0], fitted[0], "the oldest messages are dropped instead")
+	assert.LessOrEqual(t, EstimateTokens(fitted), policy.MaxTokens)
+}
+
+func TestFitContextWindow_CountsToolDefinitions(t *testing.T) {
+	messages := longConversation(3)
+	agent := newContextTestAgent(&arkv1alpha1.ContextPolicy{MaxTokens: EstimateTokens(messages)}, &scriptedProvider{})
+	tools := []openai.ChatCompletionToolParam{{Type: "function", Function: openai.FunctionDefinitionParam{Name: "lookup", Description: openai.String(strings.Repeat("d", 400))}}}
+
+	fitted := agent.fitContextWindow(context.Background(), messages, tools)
+
+	assert.Less(t, len(fitted), len(messages))
+}
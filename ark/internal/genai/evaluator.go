@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -151,10 +152,35 @@ func CallUnifiedEvaluator(ctx context.Context, k8sClient client.Client, evaluato
 		return nil, err
 	}
 
+	addEvaluatorModelCost(ctx, k8sClient, request.Parameters, namespace, response.TokenUsage)
+
 	log.Info("Unified evaluator call completed successfully", "response", response)
 	return response, nil
 }
 
+// addEvaluatorModelCost prices the tokens an evaluator reports with the pricing of the model named
+// by the model.name and model.namespace parameters, unless the evaluator reported their cost
+func addEvaluatorModelCost(ctx context.Context, k8sClient client.Client, parameters map[string]string, namespace string, usage *arkv1alpha1.TokenUsage) {
+	if usage == nil || len(usage.Cost) > 0 || parameters["model.name"] == "" {
+		return
+	}
+	modelNamespace := parameters["model.namespace"]
+	if modelNamespace == "" {
+		modelNamespace = namespace
+	}
+
+	modelCRD, err := loadModelCRD(ctx, k8sClient, parameters["model.name"], modelNamespace)
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("cannot price evaluator tokens", "error", err.Error())
+		return
+	}
+	pricing, err := pricingFromSpec(modelCRD.Spec.Pricing)
+	if err != nil || pricing == nil {
+		return
+	}
+	usage.AddCost(pricing.currency, pricing.cost(openai.CompletionUsage{PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}))
+}
+
 func callUnifiedEvaluatorHTTP(ctx context.Context, address string, request UnifiedEvaluationRequest, configuredTimeout time.Duration) (*EvaluationResponse, error) {
 	// Use configured timeout, with type-specific adjustments if needed
 	timeout := configuredTimeout
@@ -1,6 +1,16 @@
 package genai
 
+import "encoding/json"
+
 type ExecutionResult struct {
 	Messages    []Message
 	A2AResponse *A2AResponse
+	// MaxIterationsReached is set when the final message was produced by the
+	// forced no-tools completion after the agent exhausted its iterations.
+	MaxIterationsReached bool
+	// StructuredOutput is the final message parsed as JSON once it has been validated
+	// against the agent's outputSchema.
+	StructuredOutput json.RawMessage
+	// Embeddings holds one vector per input when the target is an embeddings model.
+	Embeddings [][]float64
 }
@@ -0,0 +1,579 @@
+package genai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// Guardrail stages and actions
+const (
+	GuardrailStageInput  = "input"
+	GuardrailStageOutput = "output"
+
+	GuardrailActionBlock  = "block"
+	GuardrailActionRedact = "redact"
+	GuardrailActionWarn   = "warn"
+)
+
+const (
+	guardrailCollectorKey contextKey = "guardrailCollector"
+	redactedText                     = "[REDACTED]"
+)
+
+// piiPatterns detect the kinds of personally identifiable information a guardrail can look for
+var piiPatterns = map[string]*regexp.Regexp{
+	"email":      regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	"phone":      regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?\(?\d{3}\)?[\s.\-]\d{3}[\s.\-]\d{4}\b`),
+	"creditCard": regexp.MustCompile(`\b(?:\d{4}[\s\-]?){3}\d{1,4}\b`),
+	"ssn":        regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	"ipAddress":  regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+}
+
+// ContentClassifier decides whether content should be flagged by a guardrail
+type ContentClassifier interface {
+	Classify(ctx context.Context, stage, content string) (flagged bool, reason string, err error)
+}
+
+// guardrailMatcher is a redactable check: a pattern that must not match the content
+type guardrailMatcher struct {
+	pattern *regexp.Regexp
+	reason  string
+}
+
+// Guardrail checks content entering or leaving an agent, team or query
+type Guardrail struct {
+	Name             string
+	Namespace        string
+	Stages           []string
+	Action           string
+	Message          string
+	MaxLength        *int
+	matchers         []guardrailMatcher
+	schema           *jsonschema.Resolved
+	classifier       ContentClassifier
+	eventingRecorder eventing.GuardrailRecorder
+}
+
+// FullName returns the namespace/name format for the guardrail
+func (g *Guardrail) FullName() string {
+	return g.Namespace + "/" + g.Name
+}
+
+func (g *Guardrail) appliesTo(stage string) bool {
+	return len(g.Stages) == 0 || slices.Contains(g.Stages, stage)
+}
+
+// withoutAnswerChecks returns the guardrail without its maximum length and JSON schema, which
+// describe the final answer rather than the intermediate messages and tool results before it
+func (g *Guardrail) withoutAnswerChecks() *Guardrail {
+	if g.MaxLength == nil && g.schema == nil {
+		return g
+	}
+	checks := *g
+	checks.MaxLength, checks.schema = nil, nil
+	return &checks
+}
+
+// Check runs the guardrail's checks against content. It returns the content to continue with,
+// which differs from the input only when matches were redacted, and the violation if any check
+// matched. Violations that cannot be redacted are reported with the block action.
+func (g *Guardrail) Check(ctx context.Context, stage, content string) (string, *GuardrailViolation, error) {
+	if !g.appliesTo(stage) {
+		return content, nil, nil
+	}
+
+	redact := g.Action == GuardrailActionRedact
+	result := content
+	var reasons []string
+
+	for _, m := range g.matchers {
+		if !m.pattern.MatchString(result) {
+			continue
+		}
+		reasons = append(reasons, m.reason)
+		if redact {
+			result = m.pattern.ReplaceAllLiteralString(result, redactedText)
+		}
+	}
+
+	if g.MaxLength != nil && utf8.RuneCountInString(result) > *g.MaxLength {
+		reasons = append(reasons, fmt.Sprintf("exceeds the maximum length of %d characters", *g.MaxLength))
+		if redact {
+			result = string([]rune(result)[:*g.MaxLength])
+		}
+	}
+
+	unredactable := false
+	if g.schema != nil {
+		if err := validateJSONContent(g.schema, result); err != nil {
+			reasons = append(reasons, fmt.Sprintf("does not match the JSON schema: %v", err))
+			unredactable = true
+		}
+	}
+
+	if g.classifier != nil {
+		flagged, reason, err := g.classifier.Classify(ctx, stage, result)
+		if err != nil {
+			return content, nil, fmt.Errorf("guardrail %s classifier failed: %w", g.FullName(), err)
+		}
+		if flagged {
+			reasons = append(reasons, "flagged by classifier: "+cmp.Or(reason, "no reason given"))
+			unredactable = true
+		}
+	}
+
+	if len(reasons) == 0 {
+		return content, nil, nil
+	}
+
+	action := g.Action
+	if redact && unredactable {
+		action = GuardrailActionBlock
+	}
+	if action != GuardrailActionRedact {
+		result = content
+	}
+
+	return result, &GuardrailViolation{
+		Guardrail: g.FullName(),
+		Stage:     stage,
+		Action:    action,
+		Reason:    strings.Join(reasons, "; "),
+	}, nil
+}
+
+// ApplyGuardrails runs content through each guardrail in order, passing redacted content on to
+// the next one. Violations are recorded in ctx and emitted as events. A *GuardrailBlocked error
+// is returned as soon as a guardrail blocks the content. Empty content is not checked.
+func ApplyGuardrails(ctx context.Context, guardrails []*Guardrail, stage, content string) (string, error) {
+	if content == "" {
+		return content, nil
+	}
+	for _, g := range guardrails {
+		checked, violation, err := g.Check(ctx, stage, content)
+		if err != nil {
+			return content, err
+		}
+		if violation == nil {
+			continue
+		}
+
+		logf.FromContext(ctx).Info("guardrail triggered", "guardrail", violation.Guardrail, "stage", stage, "action", violation.Action, "reason", violation.Reason)
+		recordGuardrailViolation(ctx, *violation)
+		if g.eventingRecorder != nil {
+			g.eventingRecorder.GuardrailTriggered(ctx, violation.Guardrail, stage, violation.Action, violation.Reason)
+		}
+
+		if violation.Action == GuardrailActionBlock {
+			return content, &GuardrailBlocked{Violation: *violation, Message: g.Message}
+		}
+		content = checked
+	}
+	return content, nil
+}
+
+// ApplyGuardrailsToMessage applies guardrails to the text of a user, assistant or tool message and
+// returns the message with any redactions. Other messages are returned unchanged.
+func ApplyGuardrailsToMessage(ctx context.Context, guardrails []*Guardrail, stage string, msg Message) (Message, error) {
+	if len(guardrails) == 0 {
+		return msg, nil
+	}
+
+	switch {
+	case msg.OfUser != nil:
+		user := *msg.OfUser
+		if parts := user.Content.OfArrayOfContentParts; parts != nil {
+			user.Content.OfArrayOfContentParts = slices.Clone(parts)
+			for i, part := range user.Content.OfArrayOfContentParts {
+				if part.OfText == nil {
+					continue
+				}
+				text, err := ApplyGuardrails(ctx, guardrails, stage, part.OfText.Text)
+				if err != nil {
+					return msg, err
+				}
+				textPart := *part.OfText
+				textPart.Text = text
+				user.Content.OfArrayOfContentParts[i].OfText = &textPart
+			}
+		} else {
+			text, err := ApplyGuardrails(ctx, guardrails, stage, user.Content.OfString.Value)
+			if err != nil {
+				return msg, err
+			}
+			user.Content.OfString = openai.String(text)
+		}
+		return Message{OfUser: &user}, nil
+	case msg.OfAssistant != nil:
+		assistant := *msg.OfAssistant
+		text, err := ApplyGuardrails(ctx, guardrails, stage, assistant.Content.OfString.Value)
+		if err != nil {
+			return msg, err
+		}
+		if text != assistant.Content.OfString.Value {
+			assistant.Content.OfString = openai.String(text)
+		}
+		return Message{OfAssistant: &assistant}, nil
+	case msg.OfTool != nil:
+		tool := *msg.OfTool
+		if parts := tool.Content.OfArrayOfContentParts; parts != nil {
+			tool.Content.OfArrayOfContentParts = slices.Clone(parts)
+			for i, part := range tool.Content.OfArrayOfContentParts {
+				text, err := ApplyGuardrails(ctx, guardrails, stage, part.Text)
+				if err != nil {
+					return msg, err
+				}
+				tool.Content.OfArrayOfContentParts[i].Text = text
+			}
+		} else {
+			text, err := ApplyGuardrails(ctx, guardrails, stage, tool.Content.OfString.Value)
+			if err != nil {
+				return msg, err
+			}
+			if text != tool.Content.OfString.Value {
+				tool.Content.OfString = openai.String(text)
+			}
+		}
+		return Message{OfTool: &tool}, nil
+	default:
+		return msg, nil
+	}
+}
+
+// ApplyOutputGuardrails applies guardrails to every message of an execution result, including
+// intermediate assistant messages and tool results, which are persisted to memory with the final
+// answer. The maximum length and JSON schema are only checked against the final assistant
+// message. Structured output is re-parsed from the redacted final message, and dropped if
+// redaction left it invalid JSON.
+func ApplyOutputGuardrails(ctx context.Context, guardrails []*Guardrail, result *ExecutionResult) error {
+	if len(guardrails) == 0 || result == nil || len(result.Messages) == 0 {
+		return nil
+	}
+	intermediate := make([]*Guardrail, len(guardrails))
+	for i, g := range guardrails {
+		intermediate[i] = g.withoutAnswerChecks()
+	}
+	last := len(result.Messages) - 1
+	for i, msg := range result.Messages {
+		checks := intermediate
+		if i == last && msg.OfAssistant != nil {
+			checks = guardrails
+		}
+		checked, err := ApplyGuardrailsToMessage(ctx, checks, GuardrailStageOutput, msg)
+		if err != nil {
+			return err
+		}
+		result.Messages[i] = checked
+	}
+
+	msg := result.Messages[last]
+	if result.StructuredOutput != nil && msg.OfAssistant != nil {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(extractJSONContent(msg.OfAssistant.Content.OfString.Value))); err != nil {
+			result.StructuredOutput = nil
+		} else {
+			result.StructuredOutput = compact.Bytes()
+		}
+	}
+	return nil
+}
+
+// HasOutputGuardrails reports whether any of the guardrails checks output. Checked output is not
+// streamed while it is generated, since a client that received it could not be made to forget a
+// redaction or a block.
+func HasOutputGuardrails(guardrails []*Guardrail) bool {
+	for _, g := range guardrails {
+		if g.appliesTo(GuardrailStageOutput) {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamCheckedResult streams the final message of a result whose streaming was held back until
+// its output had been checked
+func StreamCheckedResult(ctx context.Context, eventStream EventStreamInterface, result *ExecutionResult, modelName string) {
+	if eventStream == nil || result == nil {
+		return
+	}
+	content := ExtractLastAssistantMessageContent(result.Messages)
+	if content == "" {
+		return
+	}
+	chunk := NewContentChunk("chatcmpl-checked", modelName, content)
+	chunk.Choices[0].FinishReason = "stop"
+	if err := eventStream.StreamChunk(ctx, WrapChunkWithMetadata(ctx, chunk, modelName, nil)); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to stream checked response")
+	}
+}
+
+type guardrailCollector struct {
+	mu         sync.Mutex
+	violations []GuardrailViolation
+}
+
+// WithGuardrailCollector returns a context in which guardrail violations are recorded,
+// so they can be read back with GetGuardrailViolations once execution finishes
+func WithGuardrailCollector(ctx context.Context) context.Context {
+	return context.WithValue(ctx, guardrailCollectorKey, &guardrailCollector{})
+}
+
+// GetGuardrailViolations returns the violations recorded in ctx, in the order they occurred
+func GetGuardrailViolations(ctx context.Context) []GuardrailViolation {
+	collector, ok := ctx.Value(guardrailCollectorKey).(*guardrailCollector)
+	if !ok {
+		return nil
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	return slices.Clone(collector.violations)
+}
+
+func recordGuardrailViolation(ctx context.Context, violation GuardrailViolation) {
+	collector, ok := ctx.Value(guardrailCollectorKey).(*guardrailCollector)
+	if !ok {
+		return
+	}
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.violations = append(collector.violations, violation)
+}
+
+// ValidateGuardrailSpec checks that the patterns, PII kinds, JSON schema and classifier of a
+// guardrail are usable and that at least one check is configured
+func ValidateGuardrailSpec(spec *arkv1alpha1.GuardrailSpec) error {
+	if _, err := compileGuardrailMatchers(spec); err != nil {
+		return err
+	}
+	if _, err := compileGuardrailSchema(spec.JSONSchema); err != nil {
+		return err
+	}
+	if c := spec.Classifier; c != nil && (c.Address == nil) == (c.ModelRef == nil) {
+		return fmt.Errorf("classifier must set exactly one of address and modelRef")
+	}
+	if len(spec.Denylist) == 0 && len(spec.Patterns) == 0 && len(spec.PII) == 0 &&
+		spec.MaxLength == nil && spec.JSONSchema == nil && spec.Classifier == nil {
+		return fmt.Errorf("guardrail must configure at least one of denylist, patterns, pii, maxLength, jsonSchema or classifier")
+	}
+	return nil
+}
+
+func compileGuardrailMatchers(spec *arkv1alpha1.GuardrailSpec) ([]guardrailMatcher, error) {
+	var matchers []guardrailMatcher
+	for _, term := range spec.Denylist {
+		if term == "" {
+			return nil, fmt.Errorf("denylist terms must not be empty")
+		}
+		matchers = append(matchers, guardrailMatcher{
+			pattern: regexp.MustCompile("(?i)" + regexp.QuoteMeta(term)),
+			reason:  fmt.Sprintf("contains denied term %q", term),
+		})
+	}
+	for _, expr := range spec.Patterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+		}
+		matchers = append(matchers, guardrailMatcher{pattern: pattern, reason: fmt.Sprintf("matches pattern %q", expr)})
+	}
+	for _, kind := range spec.PII {
+		pattern, ok := piiPatterns[kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported pii kind %q", kind)
+		}
+		matchers = append(matchers, guardrailMatcher{pattern: pattern, reason: "contains " + kind})
+	}
+	return matchers, nil
+}
+
+func compileGuardrailSchema(raw *runtime.RawExtension) (*jsonschema.Resolved, error) {
+	resolved, err := compileJSONSchema(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonSchema: %w", err)
+	}
+	return resolved, nil
+}
+
+// LoadGuardrails loads the referenced guardrails, resolving references without a namespace
+// in defaultNamespace
+func LoadGuardrails(ctx context.Context, k8sClient client.Client, refs []arkv1alpha1.GuardrailRef, defaultNamespace string, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) ([]*Guardrail, error) {
+	guardrails := make([]*Guardrail, 0, len(refs))
+	for _, ref := range refs {
+		var crd arkv1alpha1.Guardrail
+		key := types.NamespacedName{Name: ref.Name, Namespace: cmp.Or(ref.Namespace, defaultNamespace)}
+		if err := k8sClient.Get(ctx, key, &crd); err != nil {
+			return nil, fmt.Errorf("failed to get guardrail %s: %w", key, err)
+		}
+
+		guardrail, err := MakeGuardrail(ctx, k8sClient, &crd, telemetryProvider, eventingProvider)
+		if err != nil {
+			return nil, err
+		}
+		guardrails = append(guardrails, guardrail)
+	}
+	return guardrails, nil
+}
+
+func MakeGuardrail(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Guardrail, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (*Guardrail, error) {
+	matchers, err := compileGuardrailMatchers(&crd.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("guardrail %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+	schema, err := compileGuardrailSchema(crd.Spec.JSONSchema)
+	if err != nil {
+		return nil, fmt.Errorf("guardrail %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+
+	var classifier ContentClassifier
+	if crd.Spec.Classifier != nil {
+		classifier, err = makeContentClassifier(ctx, k8sClient, crd.Spec.Classifier, crd.Namespace, telemetryProvider, eventingProvider)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %s/%s: %w", crd.Namespace, crd.Name, err)
+		}
+	}
+
+	return &Guardrail{
+		Name:             crd.Name,
+		Namespace:        crd.Namespace,
+		Stages:           crd.Spec.Stages,
+		Action:           cmp.Or(crd.Spec.Action, GuardrailActionBlock),
+		Message:          crd.Spec.Message,
+		MaxLength:        crd.Spec.MaxLength,
+		matchers:         matchers,
+		schema:           schema,
+		classifier:       classifier,
+		eventingRecorder: eventingProvider.GuardrailRecorder(),
+	}, nil
+}
+
+func makeContentClassifier(ctx context.Context, k8sClient client.Client, spec *arkv1alpha1.GuardrailClassifier, namespace string, telemetryProvider telemetry.Provider, eventingProvider eventing.Provider) (ContentClassifier, error) {
+	if spec.ModelRef != nil {
+		model, err := LoadModel(ctx, k8sClient, spec.ModelRef, namespace, nil, telemetryProvider.ModelRecorder(), eventingProvider.ModelRecorder())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load classifier model: %w", err)
+		}
+		return &modelContentClassifier{model: model, prompt: spec.Prompt}, nil
+	}
+
+	if spec.Address == nil {
+		return nil, fmt.Errorf("classifier must set address or modelRef")
+	}
+	address, err := common.NewValueSourceResolver(k8sClient).ResolveValueSource(ctx, *spec.Address, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve classifier address: %w", err)
+	}
+	headers, err := ResolveHeaders(ctx, k8sClient, spec.Headers, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve classifier headers: %w", err)
+	}
+	return &httpContentClassifier{
+		address:    address,
+		headers:    headers,
+		httpClient: common.NewHTTPClientWithLogging(ctx),
+	}, nil
+}
+
+type classifierRequest struct {
+	Stage   string `json:"stage"`
+	Content string `json:"content"`
+}
+
+type classifierResponse struct {
+	Flagged bool   `json:"flagged"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// httpContentClassifier posts content to an external classification service
+type httpContentClassifier struct {
+	address    string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func (c *httpContentClassifier) Classify(ctx context.Context, stage, content string) (bool, string, error) {
+	body, err := json.Marshal(classifierRequest{Stage: stage, Content: content})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.address, bytes.NewReader(body))
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("User-Agent", UserAgent)
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, "", fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+
+	var response classifierResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return false, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return response.Flagged, response.Reason, nil
+}
+
+const modelClassifierInstructions = `Decide whether the following %s should be flagged. Respond only with a JSON object of the form {"flagged": true or false, "reason": "<short explanation>"}.`
+
+// modelContentClassifier asks a model whether content should be flagged
+type modelContentClassifier struct {
+	model  *Model
+	prompt string
+}
+
+func (c *modelContentClassifier) Classify(ctx context.Context, stage, content string) (bool, string, error) {
+	instructions := fmt.Sprintf(modelClassifierInstructions, stage)
+	if c.prompt != "" {
+		instructions = c.prompt + "\n\n" + instructions
+	}
+
+	response, err := c.model.ChatCompletion(ctx, []Message{NewSystemMessage(instructions), NewUserMessage(content)}, nil, 1)
+	if err != nil {
+		return false, "", err
+	}
+	if len(response.Choices) == 0 {
+		return false, "", fmt.Errorf("classifier model returned no choices")
+	}
+
+	answer := response.Choices[0].Message.Content
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return false, "", fmt.Errorf("classifier model did not return JSON: %q", answer)
+	}
+	var result classifierResponse
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &result); err != nil {
+		return false, "", fmt.Errorf("failed to parse classifier model response: %w", err)
+	}
+	return result.Flagged, result.Reason, nil
+}
@@ -0,0 +1,275 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newTestGuardrail(t *testing.T, name string, spec arkv1alpha1.GuardrailSpec) *Guardrail {
+	t.Helper()
+	require.NoError(t, ValidateGuardrailSpec(&spec))
+	matchers, err := compileGuardrailMatchers(&spec)
+	require.NoError(t, err)
+	schema, err := compileGuardrailSchema(spec.JSONSchema)
+	require.NoError(t, err)
+	return &Guardrail{
+		Name:      name,
+		Namespace: "default",
+		Stages:    spec.Stages,
+		Action:    spec.Action,
+		Message:   spec.Message,
+		MaxLength: spec.MaxLength,
+		matchers:  matchers,
+		schema:    schema,
+	}
+}
+
+func TestGuardrailCheck_RedactsMatches(t *testing.T) {
+	g := newTestGuardrail(t, "pii", arkv1alpha1.GuardrailSpec{
+		Action:   GuardrailActionRedact,
+		PII:      []string{"email", "ssn"},
+		Denylist: []string{"Project Falcon"},
+	})
+
+	content, violation, err := g.Check(context.Background(), GuardrailStageOutput, "Mail jane@example.com about project falcon, SSN 123-45-6789")
+
+	require.NoError(t, err)
+	require.NotNil(t, violation)
+	assert.Equal(t, GuardrailActionRedact, violation.Action)
+	assert.Equal(t, "default/pii", violation.Guardrail)
+	assert.Equal(t, "Mail [REDACTED] about [REDACTED], SSN [REDACTED]", content)
+	assert.Contains(t, violation.Reason, "contains email")
+	assert.Contains(t, violation.Reason, `contains denied term "Project Falcon"`)
+}
+
+func TestGuardrailCheck_WarnKeepsContent(t *testing.T) {
+	g := newTestGuardrail(t, "secrets", arkv1alpha1.GuardrailSpec{
+		Action:   GuardrailActionWarn,
+		Patterns: []string{`sk-[A-Za-z0-9]{8,}`},
+	})
+
+	content, violation, err := g.Check(context.Background(), GuardrailStageInput, "key sk-abcdefgh123")
+
+	require.NoError(t, err)
+	require.NotNil(t, violation)
+	assert.Equal(t, GuardrailActionWarn, violation.Action)
+	assert.Equal(t, "key sk-abcdefgh123", content)
+}
+
+func TestGuardrailCheck_MaxLength(t *testing.T) {
+	maxLength := 5
+	g := newTestGuardrail(t, "short", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, MaxLength: &maxLength})
+
+	content, violation, err := g.Check(context.Background(), GuardrailStageOutput, "héllo world")
+
+	require.NoError(t, err)
+	require.NotNil(t, violation)
+	assert.Equal(t, "héllo", content)
+}
+
+func TestGuardrailCheck_JSONSchemaBlocksWhenRedacting(t *testing.T) {
+	g := newTestGuardrail(t, "json", arkv1alpha1.GuardrailSpec{
+		Action:     GuardrailActionRedact,
+		JSONSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","required":["answer"]}`)},
+	})
+
+	_, violation, err := g.Check(context.Background(), GuardrailStageOutput, `{"answer": 42}`)
+	require.NoError(t, err)
+	assert.Nil(t, violation)
+
+	content, violation, err := g.Check(context.Background(), GuardrailStageOutput, "not json")
+	require.NoError(t, err)
+	require.NotNil(t, violation)
+	assert.Equal(t, GuardrailActionBlock, violation.Action)
+	assert.Equal(t, "not json", content)
+}
+
+func TestGuardrailCheck_SkipsOtherStages(t *testing.T) {
+	g := newTestGuardrail(t, "input-only", arkv1alpha1.GuardrailSpec{
+		Stages:   []string{GuardrailStageInput},
+		Action:   GuardrailActionBlock,
+		Denylist: []string{"secret"},
+	})
+
+	_, violation, err := g.Check(context.Background(), GuardrailStageOutput, "a secret")
+
+	require.NoError(t, err)
+	assert.Nil(t, violation)
+}
+
+func TestApplyGuardrails_BlockRecordsViolations(t *testing.T) {
+	ctx := WithGuardrailCollector(context.Background())
+	redact := newTestGuardrail(t, "redact", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, PII: []string{"email"}})
+	block := newTestGuardrail(t, "block", arkv1alpha1.GuardrailSpec{
+		Action:   GuardrailActionBlock,
+		Denylist: []string{"password"},
+		Message:  "Request contains credentials",
+	})
+
+	_, err := ApplyGuardrails(ctx, []*Guardrail{redact, block}, GuardrailStageInput, "my password for a@b.io")
+
+	require.Error(t, err)
+	require.True(t, IsGuardrailBlocked(err))
+	assert.Equal(t, "guardrail default/block blocked input: Request contains credentials", err.Error())
+
+	violations := GetGuardrailViolations(ctx)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "default/redact", violations[0].Guardrail)
+	assert.Equal(t, GuardrailActionBlock, violations[1].Action)
+}
+
+func TestApplyGuardrailsToMessage_UserContentParts(t *testing.T) {
+	g := newTestGuardrail(t, "pii", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, PII: []string{"phone"}})
+	original := Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("call me on 555-123-4567"),
+	}))
+
+	guarded, err := ApplyGuardrailsToMessage(context.Background(), []*Guardrail{g}, GuardrailStageInput, original)
+
+	require.NoError(t, err)
+	assert.Equal(t, "call me on [REDACTED]", guarded.OfUser.Content.OfArrayOfContentParts[0].OfText.Text)
+	assert.Equal(t, "call me on 555-123-4567", original.OfUser.Content.OfArrayOfContentParts[0].OfText.Text)
+}
+
+func TestHTTPContentClassifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req classifierRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "secret-token", r.Header.Get("X-Api-Key"))
+		_ = json.NewEncoder(w).Encode(classifierResponse{Flagged: req.Stage == GuardrailStageOutput, Reason: "toxic"})
+	}))
+	defer server.Close()
+
+	g := newTestGuardrail(t, "toxicity", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, Classifier: &arkv1alpha1.GuardrailClassifier{
+		Address: &arkv1alpha1.ValueSource{Value: server.URL},
+	}})
+	g.classifier = &httpContentClassifier{
+		address:    server.URL,
+		headers:    map[string]string{"X-Api-Key": "secret-token"},
+		httpClient: server.Client(),
+	}
+
+	_, violation, err := g.Check(context.Background(), GuardrailStageInput, "hello")
+	require.NoError(t, err)
+	assert.Nil(t, violation)
+
+	_, violation, err = g.Check(context.Background(), GuardrailStageOutput, "hello")
+	require.NoError(t, err)
+	require.NotNil(t, violation)
+	assert.Equal(t, GuardrailActionBlock, violation.Action)
+	assert.Equal(t, "flagged by classifier: toxic", violation.Reason)
+}
+
+func TestAgentExecute_GuardrailBlocksInput(t *testing.T) {
+	provider := &scriptedProvider{}
+	agent := newLoopTestAgent(provider, nil, false)
+	agent.Guardrails = []*Guardrail{newTestGuardrail(t, "no-secrets", arkv1alpha1.GuardrailSpec{
+		Action:   GuardrailActionBlock,
+		Denylist: []string{"password"},
+	})}
+
+	_, err := agent.Execute(context.Background(), NewUserMessage("my password is hunter2"), nil, nil, nil)
+
+	require.True(t, IsGuardrailBlocked(err))
+	assert.Empty(t, provider.toolCounts)
+}
+
+func TestAgentExecute_GuardrailRedactsOutput(t *testing.T) {
+	maxIterations := 1
+	agent := newLoopTestAgent(&scriptedProvider{}, &maxIterations, true)
+	agent.Guardrails = []*Guardrail{newTestGuardrail(t, "redact", arkv1alpha1.GuardrailSpec{
+		Stages:   []string{GuardrailStageOutput},
+		Action:   GuardrailActionRedact,
+		Denylist: []string{"final"},
+	})}
+
+	result, err := agent.Execute(context.Background(), NewUserMessage("hello"), nil, nil, nil)
+
+	require.NoError(t, err)
+	last := result.Messages[len(result.Messages)-1]
+	assert.Equal(t, "[REDACTED] answer", last.OfAssistant.Content.OfString.Value)
+}
+
+// chunkStreamingProvider streams its answer as a single chunk before returning it
+type chunkStreamingProvider struct {
+	scriptedProvider
+}
+
+func (p *chunkStreamingProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	response, err := p.ChatCompletion(ctx, messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+	if err := streamFunc(completionChunk(response)); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func TestAgentExecute_OutputGuardrailsHoldBackStreaming(t *testing.T) {
+	maxIterations := 1
+	agent := newLoopTestAgent(&chunkStreamingProvider{}, &maxIterations, true)
+	agent.Guardrails = []*Guardrail{newTestGuardrail(t, "redact", arkv1alpha1.GuardrailSpec{
+		Stages:   []string{GuardrailStageOutput},
+		Action:   GuardrailActionRedact,
+		Denylist: []string{"final"},
+	})}
+	stream := &recordingStream{}
+
+	_, err := agent.Execute(context.Background(), NewUserMessage("hello"), nil, nil, stream)
+
+	require.NoError(t, err)
+	require.Len(t, stream.chunks, 1, "only the checked answer is streamed")
+	chunk := stream.chunks[0].(ChunkWithMetadata)
+	assert.Equal(t, "[REDACTED] answer", chunk.Choices[0].Delta.Content)
+}
+
+func TestApplyOutputGuardrails_ChecksEveryMessage(t *testing.T) {
+	guardrail := newTestGuardrail(t, "pii", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, PII: []string{"email"}})
+	result := &ExecutionResult{Messages: []Message{
+		NewAssistantMessage("Looking up jane@example.com"),
+		ToolMessage("owner: jane@example.com", "call-1"),
+		NewAssistantMessage("The owner is jane@example.com"),
+	}}
+
+	require.NoError(t, ApplyOutputGuardrails(context.Background(), []*Guardrail{guardrail}, result))
+
+	assert.Equal(t, "Looking up [REDACTED]", result.Messages[0].OfAssistant.Content.OfString.Value)
+	assert.Equal(t, "owner: [REDACTED]", result.Messages[1].OfTool.Content.OfString.Value)
+	assert.Equal(t, "The owner is [REDACTED]", result.Messages[2].OfAssistant.Content.OfString.Value)
+}
+
+func TestApplyOutputGuardrails_ChecksAnswerOnlyOnFinalMessage(t *testing.T) {
+	maxLength := 20
+	guardrail := newTestGuardrail(t, "answer", arkv1alpha1.GuardrailSpec{
+		Action:     GuardrailActionRedact,
+		PII:        []string{"email"},
+		MaxLength:  &maxLength,
+		JSONSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object","required":["owner"]}`)},
+	})
+	result := &ExecutionResult{Messages: []Message{
+		NewAssistantMessage("Looking up the owner of the repository"),
+		ToolMessage("owner: jane@example.com, team: platform engineering", "call-1"),
+		NewAssistantMessage(`{"owner": "jane"}`),
+	}}
+
+	require.NoError(t, ApplyOutputGuardrails(context.Background(), []*Guardrail{guardrail}, result))
+
+	assert.Equal(t, "Looking up the owner of the repository", result.Messages[0].OfAssistant.Content.OfString.Value)
+	assert.Equal(t, "owner: [REDACTED], team: platform engineering", result.Messages[1].OfTool.Content.OfString.Value)
+	assert.Equal(t, `{"owner": "jane"}`, result.Messages[2].OfAssistant.Content.OfString.Value)
+
+	result.Messages[2] = NewAssistantMessage("The owner is jane")
+	var blocked *GuardrailBlocked
+	require.ErrorAs(t, ApplyOutputGuardrails(context.Background(), []*Guardrail{guardrail}, result), &blocked)
+}
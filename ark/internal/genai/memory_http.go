@@ -175,7 +175,7 @@ func (m *HTTPMemory) AddMessages(ctx context.Context, queryID string, messages [
 	// Convert messages to the request format
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+		openaiMessages[i] = withoutThinkingBlocks(msg)
 	}
 
 	reqBody, err := json.Marshal(MessagesRequest{
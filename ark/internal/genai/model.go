@@ -55,6 +55,9 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 	if err != nil {
 		return nil, fmt.Errorf("failed to load model CRD %s in namespace %s: %w", modelName, namespace, err)
 	}
+	if modelCRD.Spec.Type == ModelTypeRouter {
+		return loadRouterModel(ctx, k8sClient, modelCRD, additionalHeaders, telemetryRecorder, eventingRecorder)
+	}
 
 	resolver := common.NewValueSourceResolver(k8sClient)
 	model, err := resolver.ResolveValueSource(ctx, modelCRD.Spec.Model, namespace)
@@ -62,9 +65,24 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		return nil, fmt.Errorf("failed to resolve model: %w", err)
 	}
 
+	cache, err := loadCompletionCache(ctx, resolver, namespace, modelName, modelCRD.Generation, modelCRD.Spec.Cache)
+	if err != nil {
+		return nil, err
+	}
+	pricing, err := pricingFromSpec(modelCRD.Spec.Pricing)
+	if err != nil {
+		return nil, fmt.Errorf("model %s: %w", modelName, err)
+	}
+
 	modelInstance := &Model{
+		Name:              modelName,
 		Model:             model,
 		Type:              modelCRD.Spec.Type,
+		rateLimiter:       rateLimiterFor(namespace, modelName, modelCRD.Spec.RateLimits),
+		retryPolicy:       retryPolicyFromSpec(modelCRD.Spec.RetryPolicy),
+		cache:             cache,
+		pricing:           pricing,
+		reasoning:         modelCRD.Spec.Reasoning,
 		telemetryRecorder: telemetryRecorder,
 		eventingRecorder:  eventingRecorder,
 	}
@@ -82,6 +100,14 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		if err := loadBedrockConfig(ctx, resolver, modelCRD.Spec.Config.Bedrock, namespace, model, modelInstance); err != nil {
 			return nil, err
 		}
+	case ProviderAnthropic:
+		if err := loadAnthropicConfig(ctx, resolver, modelCRD.Spec.Config.Anthropic, namespace, modelInstance, additionalHeaders); err != nil {
+			return nil, err
+		}
+	case ProviderGemini:
+		if err := loadGeminiConfig(ctx, resolver, modelCRD.Spec.Config.Gemini, namespace, modelInstance, additionalHeaders); err != nil {
+			return nil, err
+		}
 	default:
 		if modelCRD.Spec.Provider == "" {
 			if IsDeprecatedProviderInType(modelCRD.Spec.Type) {
@@ -92,6 +118,18 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		return nil, fmt.Errorf("unsupported provider: %s", modelCRD.Spec.Provider)
 	}
 
+	if modelCRD.Spec.Type == ModelTypeResponses {
+		responsesProvider, err := newResponsesProvider(modelInstance.Provider)
+		if err != nil {
+			return nil, err
+		}
+		modelInstance.Provider = responsesProvider
+	}
+
+	if reasoningProvider, ok := modelInstance.Provider.(ReasoningProvider); ok && modelCRD.Spec.Reasoning != nil {
+		reasoningProvider.SetReasoning(modelCRD.Spec.Reasoning)
+	}
+
 	return modelInstance, nil
 }
 
@@ -0,0 +1,365 @@
+package genai
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	defaultCacheTTL           = time.Hour
+	defaultCacheMaxEntries    = 1000
+	defaultCacheMaxEntryBytes = 1 << 20
+	completionCacheKeyPrefix  = "ark:completion:"
+)
+
+// CompletionCacheBackend stores cached completions by key
+type CompletionCacheBackend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// completionCache serves repeated identical calls to a model from a backend
+type completionCache struct {
+	// scope is the namespace and name of the Model, so models sharing a backend do not share entries
+	scope         string
+	backend       CompletionCacheBackend
+	ttl           time.Duration
+	maxEntryBytes int
+	// fingerprint identifies the model generation and configuration the cache was created from
+	fingerprint string
+}
+
+var modelCaches = struct {
+	sync.Mutex
+	caches map[string]*completionCache
+}{caches: map[string]*completionCache{}}
+
+// loadCompletionCache returns the completion cache of a model, or nil if the model has none. The
+// cache is shared by every query and recreated empty when the model's spec changes, so calls are
+// not served completions from an earlier configuration of the model.
+func loadCompletionCache(ctx context.Context, resolver *common.ValueSourceResolver, namespace, name string, generation int64, spec *arkv1alpha1.ModelCache) (*completionCache, error) {
+	scope := namespace + "/" + name
+	if spec == nil {
+		EvictCompletionCache(namespace, name)
+		return nil, nil
+	}
+
+	var cacheURL, token string
+	if spec.HTTP != nil {
+		var err error
+		if cacheURL, err = resolver.ResolveValueSource(ctx, spec.HTTP.URL, namespace); err != nil {
+			return nil, fmt.Errorf("failed to resolve cache URL: %w", err)
+		}
+		if spec.HTTP.Token != nil {
+			if token, err = resolver.ResolveValueSource(ctx, *spec.HTTP.Token, namespace); err != nil {
+				return nil, fmt.Errorf("failed to resolve cache token: %w", err)
+			}
+		}
+	}
+
+	cache := &completionCache{scope: scope, ttl: defaultCacheTTL, maxEntryBytes: defaultCacheMaxEntryBytes}
+	if spec.TTL != nil {
+		cache.ttl = spec.TTL.Duration
+	}
+	if spec.MaxEntryBytes > 0 {
+		cache.maxEntryBytes = spec.MaxEntryBytes
+	}
+	maxEntries := defaultCacheMaxEntries
+	if spec.MaxEntries > 0 {
+		maxEntries = spec.MaxEntries
+	}
+	tokenHash := sha256.Sum256([]byte(token))
+	cache.fingerprint = fmt.Sprintf("%d|%s|%d|%d|%s|%x", generation, cache.ttl, cache.maxEntryBytes, maxEntries, cacheURL, tokenHash)
+
+	modelCaches.Lock()
+	defer modelCaches.Unlock()
+	if existing, ok := modelCaches.caches[scope]; ok && existing.fingerprint == cache.fingerprint {
+		return existing, nil
+	}
+
+	if cacheURL != "" {
+		cache.backend = &httpCacheBackend{baseURL: strings.TrimSuffix(cacheURL, "/"), token: token}
+	} else {
+		cache.backend = newMemoryCacheBackend(maxEntries)
+	}
+	modelCaches.caches[scope] = cache
+	return cache, nil
+}
+
+// EvictCompletionCache drops the completion cache of a model, such as when the model is deleted
+func EvictCompletionCache(namespace, name string) {
+	modelCaches.Lock()
+	defer modelCaches.Unlock()
+	delete(modelCaches.caches, namespace+"/"+name)
+}
+
+// key hashes everything that affects the completion of a call
+func (c *completionCache) key(m *Model, messages []Message, n int64, tools [][]openai.ChatCompletionToolParam) (string, error) {
+	params := make([]openai.ChatCompletionMessageParamUnion, len(messages))
+	for i, msg := range messages {
+		params[i] = openai.ChatCompletionMessageParamUnion(msg)
+	}
+	var toolParams []openai.ChatCompletionToolParam
+	for _, toolSet := range tools {
+		toolParams = append(toolParams, toolSet...)
+	}
+	var outputSchema json.RawMessage
+	if m.OutputSchema != nil {
+		outputSchema = m.OutputSchema.Raw
+	}
+
+	data, err := json.Marshal(struct {
+		Scope        string                                   `json:"scope"`
+		Model        string                                   `json:"model"`
+		Properties   map[string]string                        `json:"properties"`
+		Messages     []openai.ChatCompletionMessageParamUnion `json:"messages"`
+		N            int64                                    `json:"n"`
+		Tools        []openai.ChatCompletionToolParam         `json:"tools"`
+		OutputSchema json.RawMessage                          `json:"outputSchema"`
+		SchemaName   string                                   `json:"schemaName"`
+		Reasoning    *arkv1alpha1.ModelReasoning              `json:"reasoning,omitempty"`
+	}{c.scope, m.Model, m.Properties, params, n, toolParams, outputSchema, m.SchemaName, m.reasoning})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return completionCacheKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// get returns the cached completion for key, or nil. Backend failures are logged and treated as
+// misses so the model is called instead.
+func (c *completionCache) get(ctx context.Context, key string) *openai.ChatCompletion {
+	data, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to read completion cache", "model", c.scope)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	var response openai.ChatCompletion
+	if err := json.Unmarshal(data, &response); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to decode cached completion", "model", c.scope)
+		return nil
+	}
+	return &response
+}
+
+func (c *completionCache) set(ctx context.Context, key string, response *openai.ChatCompletion) {
+	if len(response.Choices) == 0 {
+		return
+	}
+	data, err := json.Marshal(response)
+	if err != nil || len(data) > c.maxEntryBytes {
+		return
+	}
+	if err := c.backend.Set(ctx, key, data, c.ttl); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to write completion cache", "model", c.scope)
+	}
+}
+
+// completionCacheKey returns the cache key of a call, or "" if the call does not use the cache.
+// Probes and queries with bypassCache set do not use it.
+func (m *Model) completionCacheKey(ctx context.Context, messages []Message, n int64, tools [][]openai.ChatCompletionToolParam) string {
+	if m.cache == nil || IsProbeContext(ctx) {
+		return ""
+	}
+	if query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query); ok && query.Spec.BypassCache {
+		return ""
+	}
+	key, err := m.cache.key(m, messages, n, tools)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to compute completion cache key", "model", m.Name)
+		return ""
+	}
+	return key
+}
+
+// completeFromCache finishes a call served from the cache. The completion is reported with the
+// token usage of the cached call at zero cost, since the model was not called.
+func (m *Model) completeFromCache(ctx context.Context, span telemetry.Span, response *openai.ChatCompletion, eventStream EventStreamInterface, operationData map[string]string) (*openai.ChatCompletion, error) {
+	if eventStream != nil {
+		if err := eventStream.StreamChunk(ctx, WrapChunkWithMetadata(ctx, completionChunk(response), m.Model, nil)); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to stream cached completion", "model", m.Name)
+		}
+	}
+
+	if len(response.Choices) > 0 {
+		m.telemetryRecorder.RecordOutput(span, response.Choices[0].Message)
+	}
+	usage := m.tokenUsage(response.Usage)
+	usage.Cost = nil
+	if m.pricing != nil {
+		usage.AddCost(m.pricing.currency, 0)
+	}
+	m.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	if usage.ReasoningTokens > 0 || usage.CachedTokens > 0 {
+		m.telemetryRecorder.RecordTokenDetails(span, usage.ReasoningTokens, usage.CachedTokens)
+	}
+	AddTokenUsageToOperationData(operationData, usage)
+	m.telemetryRecorder.RecordSuccess(span)
+	m.eventingRecorder.Complete(ctx, "LLMCall", "Model call served from cache", operationData)
+	m.eventingRecorder.AddTokenUsage(ctx, usage)
+	return response, nil
+}
+
+// completionChunk converts a completion to a single chunk carrying all of its content
+func completionChunk(response *openai.ChatCompletion) *openai.ChatCompletionChunk {
+	chunk := &openai.ChatCompletionChunk{
+		ID:      response.ID,
+		Object:  "chat.completion.chunk",
+		Created: response.Created,
+		Model:   response.Model,
+	}
+	for _, choice := range response.Choices {
+		delta := openai.ChatCompletionChunkChoiceDelta{Role: "assistant", Content: choice.Message.Content}
+		for i, toolCall := range choice.Message.ToolCalls {
+			delta.ToolCalls = append(delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+				Index: int64(i),
+				ID:    toolCall.ID,
+				Type:  "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{
+					Name:      toolCall.Function.Name,
+					Arguments: toolCall.Function.Arguments,
+				},
+			})
+		}
+		chunk.Choices = append(chunk.Choices, openai.ChatCompletionChunkChoice{
+			Index:        choice.Index,
+			Delta:        delta,
+			FinishReason: choice.FinishReason,
+		})
+	}
+	return chunk
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// memoryCacheBackend keeps completions in the controller process, evicting the least recently
+// used entry once maxEntries is reached
+type memoryCacheBackend struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	now        func() time.Time
+}
+
+func newMemoryCacheBackend(maxEntries int) *memoryCacheBackend {
+	return &memoryCacheBackend{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+func (b *memoryCacheBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	element, ok := b.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryCacheEntry)
+	if !b.now().Before(entry.expires) {
+		b.order.Remove(element)
+		delete(b.entries, key)
+		return nil, false, nil
+	}
+	b.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (b *memoryCacheBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if element, ok := b.entries[key]; ok {
+		b.order.Remove(element)
+	}
+	b.entries[key] = b.order.PushFront(&memoryCacheEntry{key: key, value: value, expires: b.now().Add(ttl)})
+	for b.order.Len() > b.maxEntries {
+		oldest := b.order.Back()
+		b.order.Remove(oldest)
+		delete(b.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+// httpCacheBackend stores completions in a service implementing the GET and SET commands of the
+// Redis REST API, such as Upstash or a Redis REST proxy
+type httpCacheBackend struct {
+	baseURL string
+	token   string
+}
+
+type redisRESTResponse struct {
+	Result *string `json:"result"`
+	Error  string  `json:"error"`
+}
+
+func (b *httpCacheBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	response, err := b.do(ctx, http.MethodGet, "/get/"+url.PathEscape(key), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if response.Result == nil {
+		return nil, false, nil
+	}
+	return []byte(*response.Result), true, nil
+}
+
+func (b *httpCacheBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	path := fmt.Sprintf("/set/%s?EX=%d", url.PathEscape(key), max(int64(ttl.Seconds()), 1))
+	_, err := b.do(ctx, http.MethodPost, path, value)
+	return err
+}
+
+func (b *httpCacheBackend) do(ctx context.Context, method, path string, body []byte) (*redisRESTResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+
+	resp, err := common.NewHTTPClientWithLogging(ctx).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cache request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response redisRESTResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil && resp.StatusCode < 300 {
+		return nil, fmt.Errorf("failed to decode cache response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || response.Error != "" {
+		return nil, fmt.Errorf("cache request failed with HTTP status %d: %s", resp.StatusCode, response.Error)
+	}
+	return &response, nil
+}
@@ -0,0 +1,259 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// cacheRecorder records the results reported by RecordCacheResult
+type cacheRecorder struct {
+	telemetry.ModelRecorder
+	hits []bool
+}
+
+func (r *cacheRecorder) RecordCacheResult(span telemetry.Span, hit bool) {
+	r.hits = append(r.hits, hit)
+}
+
+// recordingStream records every chunk it is sent
+type recordingStream struct {
+	discardStream
+	chunks []interface{}
+}
+
+func (s *recordingStream) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+// newTestCachedModel returns a model with an in-memory cache that calls a fake server replying
+// with a tool call, and a counter of the calls the server received
+func newTestCachedModel(t *testing.T) (*Model, *cacheRecorder, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"id": "chatcmpl-1", "object": "chat.completion", "model": "gpt-4o", "choices": [
+			{"index": 0, "finish_reason": "tool_calls", "message": {"role": "assistant", "content": "Checking.", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}
+			]}}
+		], "usage": {"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := &cacheRecorder{ModelRecorder: noop.NewModelRecorder()}
+	model := newTestRetryModel(&OpenAIProvider{Model: "gpt-4o", BaseURL: server.URL, APIKey: "test-key"}, recorder)
+	model.cache = &completionCache{scope: "default/gpt-4o", backend: newMemoryCacheBackend(10), ttl: time.Hour, maxEntryBytes: defaultCacheMaxEntryBytes}
+	return model, recorder, &calls
+}
+
+func TestModelChatCompletion_Cache(t *testing.T) {
+	model, recorder, calls := newTestCachedModel(t)
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+
+	first, err := model.ChatCompletion(context.Background(), messages, nil, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	second, err := model.ChatCompletion(context.Background(), messages, nil, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, []bool{false, true}, recorder.hits)
+	assert.Equal(t, int64(12), first.Usage.TotalTokens)
+	assert.Equal(t, int64(12), second.Usage.TotalTokens, "cache hits report the usage of the cached call")
+	assert.Equal(t, first.Choices[0].Message.Content, second.Choices[0].Message.Content)
+	assert.Equal(t, "call_1", second.Choices[0].Message.ToolCalls[0].ID)
+}
+
+func TestModelChatCompletion_CacheKey(t *testing.T) {
+	model, _, calls := newTestCachedModel(t)
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+
+	_, err := model.ChatCompletion(context.Background(), messages, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(context.Background(), []Message{NewUserMessage("Weather in London?")}, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(context.Background(), messages, nil, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	model.Properties = map[string]string{"temperature": "0.5"}
+	_, err = model.ChatCompletion(context.Background(), messages, nil, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, *calls, "messages, tools and properties are part of the key")
+}
+
+func TestModelChatCompletion_CacheBypass(t *testing.T) {
+	model, recorder, calls := newTestCachedModel(t)
+	query := &arkv1alpha1.Query{Spec: arkv1alpha1.QuerySpec{BypassCache: true}}
+	ctx := context.WithValue(context.Background(), QueryContextKey, query)
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+
+	_, err := model.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(context.Background(), messages, nil, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, *calls, "bypassed calls are not stored")
+	assert.Equal(t, []bool{false}, recorder.hits)
+}
+
+func TestModelChatCompletion_CacheHitStreams(t *testing.T) {
+	model, _, calls := newTestCachedModel(t)
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+	_, err := model.ChatCompletion(context.Background(), messages, nil, 1)
+	require.NoError(t, err)
+
+	stream := &recordingStream{}
+	_, err = model.ChatCompletion(context.Background(), messages, stream, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, *calls)
+	require.Len(t, stream.chunks, 1)
+	chunk := stream.chunks[0].(ChunkWithMetadata).ChatCompletionChunk
+	assert.Equal(t, "Checking.", chunk.Choices[0].Delta.Content)
+	assert.Equal(t, "tool_calls", chunk.Choices[0].FinishReason)
+	require.Len(t, chunk.Choices[0].Delta.ToolCalls, 1)
+	assert.Equal(t, `{"city":"Paris"}`, chunk.Choices[0].Delta.ToolCalls[0].Function.Arguments)
+}
+
+func TestModelChatCompletion_CacheSkipsLargeEntries(t *testing.T) {
+	model, _, calls := newTestCachedModel(t)
+	model.cache.maxEntryBytes = 10
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+
+	for range 2 {
+		_, err := model.ChatCompletion(context.Background(), messages, nil, 1)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, *calls)
+}
+
+func TestMemoryCacheBackend(t *testing.T) {
+	now := time.Now()
+	backend := newMemoryCacheBackend(2)
+	backend.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, backend.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, backend.Set(ctx, "b", []byte("2"), time.Hour))
+	_, ok, _ := backend.Get(ctx, "a")
+	require.True(t, ok)
+	// b is now the least recently used entry and is evicted
+	require.NoError(t, backend.Set(ctx, "c", []byte("3"), time.Hour))
+
+	_, ok, _ = backend.Get(ctx, "b")
+	assert.False(t, ok)
+	value, ok, _ := backend.Get(ctx, "c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("3"), value)
+
+	now = now.Add(time.Minute)
+	_, ok, _ = backend.Get(ctx, "a")
+	assert.False(t, ok, "expired entries are not served")
+	assert.Equal(t, 1, backend.order.Len())
+}
+
+func TestHTTPCacheBackend(t *testing.T) {
+	var mu sync.Mutex
+	store := map[string]string{}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error": "Unauthorized"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && len(r.URL.Path) > len("/set/"):
+			body, _ := io.ReadAll(r.Body)
+			store[r.URL.Path[len("/set/"):]] = string(body)
+			_, _ = fmt.Fprint(w, `{"result": "OK"}`)
+		case r.Method == http.MethodGet:
+			value, ok := store[r.URL.Path[len("/get/"):]]
+			if !ok {
+				_, _ = fmt.Fprint(w, `{"result": null}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"result": %q}`, value)
+		}
+	}))
+	t.Cleanup(server.Close)
+	backend := &httpCacheBackend{baseURL: server.URL, token: "test-token"}
+	ctx := context.Background()
+
+	_, ok, err := backend.Get(ctx, "ark:completion:abc")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, backend.Set(ctx, "ark:completion:abc", []byte(`{"id":"chatcmpl-1"}`), 90*time.Minute))
+	value, ok, err := backend.Get(ctx, "ark:completion:abc")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `{"id":"chatcmpl-1"}`, string(value))
+	assert.Equal(t, "POST /set/ark:completion:abc?EX=5400", requests[1])
+
+	backend.token = "wrong"
+	_, _, err = backend.Get(ctx, "ark:completion:abc")
+	assert.ErrorContains(t, err, "HTTP status 401: Unauthorized")
+}
+
+func TestLoadCompletionCache(t *testing.T) {
+	resolver := common.NewValueSourceResolver(setupModelTestClient(nil))
+	ctx := context.Background()
+	t.Cleanup(func() { _, _ = loadCompletionCache(ctx, resolver, "default", "cached", 1, nil) })
+
+	cache, err := loadCompletionCache(ctx, resolver, "default", "cached", 1, &arkv1alpha1.ModelCache{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultCacheTTL, cache.ttl)
+	assert.IsType(t, &memoryCacheBackend{}, cache.backend)
+
+	same, err := loadCompletionCache(ctx, resolver, "default", "cached", 1, &arkv1alpha1.ModelCache{})
+	require.NoError(t, err)
+	assert.Same(t, cache, same, "the cache is shared by every query")
+
+	nextGeneration, err := loadCompletionCache(ctx, resolver, "default", "cached", 2, &arkv1alpha1.ModelCache{})
+	require.NoError(t, err)
+	assert.NotSame(t, cache, nextGeneration, "a change to the model's spec empties the cache")
+	cache = nextGeneration
+
+	changed, err := loadCompletionCache(ctx, resolver, "default", "cached", 2, &arkv1alpha1.ModelCache{
+		TTL:  &metav1.Duration{Duration: time.Minute},
+		HTTP: &arkv1alpha1.ModelCacheHTTP{URL: arkv1alpha1.ValueSource{Value: "http://cache:8080/"}},
+	})
+	require.NoError(t, err)
+	assert.NotSame(t, cache, changed)
+	assert.Equal(t, time.Minute, changed.ttl)
+	assert.Equal(t, "http://cache:8080", changed.backend.(*httpCacheBackend).baseURL)
+
+	EvictCompletionCache("default", "cached")
+	reloaded, err := loadCompletionCache(ctx, resolver, "default", "cached", 2, &arkv1alpha1.ModelCache{
+		TTL:  &metav1.Duration{Duration: time.Minute},
+		HTTP: &arkv1alpha1.ModelCacheHTTP{URL: arkv1alpha1.ValueSource{Value: "http://cache:8080/"}},
+	})
+	require.NoError(t, err)
+	assert.NotSame(t, changed, reloaded, "an evicted cache is not reused")
+
+	none, err := loadCompletionCache(ctx, resolver, "default", "cached", 1, nil)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
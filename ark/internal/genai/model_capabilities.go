@@ -0,0 +1,175 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ContextWindowProvider is implemented by providers that can look up the context window the
+// provider advertises for the model
+type ContextWindowProvider interface {
+	ContextWindow(ctx context.Context) (int64, error)
+}
+
+const capabilityProbeTimeout = 30 * time.Second
+
+// capabilityProbeImage is a 1x1 PNG sent to detect vision input
+const capabilityProbeImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8BQDwAEhQGAhKmMIQAAAABJRU5ErkJggg=="
+
+var capabilityProbeTools = []openai.ChatCompletionToolParam{
+	{Function: openai.FunctionDefinitionParam{
+		Name:        "get_time",
+		Description: openai.String("Get the current time in a city"),
+		Parameters:  openai.FunctionParameters{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}, "required": []string{"city"}},
+	}},
+	{Function: openai.FunctionDefinitionParam{
+		Name:        "get_weather",
+		Description: openai.String("Get the current weather in a city"),
+		Parameters:  openai.FunctionParameters{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}, "required": []string{"city"}},
+	}},
+}
+
+var capabilityProbeSchema = &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"answer": {"type": "string"}}, "required": ["answer"], "additionalProperties": false}`)}
+
+// DetectCapabilities detects the features of a model with a few small calls. A feature is
+// unsupported when the provider rejects the call using it as invalid, and is left unset when the
+// calls cannot tell. Routers and embeddings models have no detected capabilities.
+func DetectCapabilities(ctx context.Context, model *Model) *arkv1alpha1.ModelCapabilities {
+	capabilities := &arkv1alpha1.ModelCapabilities{}
+	if model.router != nil || model.IsEmbeddingsModel() || model.Provider == nil {
+		return capabilities
+	}
+	log := logf.FromContext(ctx)
+
+	call := func(feature string, probe func(ctx context.Context) error) error {
+		probeCtx, cancel := context.WithTimeout(contextWithProbeMode(ctx), capabilityProbeTimeout)
+		defer cancel()
+		err := probe(probeCtx)
+		if err != nil {
+			log.V(1).Info("capability probe failed", "model", model.Name, "capability", feature, "error", err.Error())
+		}
+		return err
+	}
+
+	var response *openai.ChatCompletion
+	err := call("toolCalling", func(ctx context.Context) error {
+		var err error
+		response, err = model.ChatCompletion(ctx, []Message{NewUserMessage("What are the time and the weather in Paris? Call both tools at once.")}, nil, 1, capabilityProbeTools)
+		return err
+	})
+	capabilities.ToolCalling = capabilitySupported(err)
+	if err == nil && response != nil && len(response.Choices) > 0 && len(response.Choices[0].Message.ToolCalls) > 1 {
+		capabilities.ParallelToolCalls = ptrBool(true)
+	}
+
+	chunks := &countingEventStream{}
+	err = call("streaming", func(ctx context.Context) error {
+		_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("Hello")}, chunks, 1)
+		return err
+	})
+	capabilities.Streaming = capabilitySupported(err)
+	if err == nil && chunks.chunks == 0 {
+		capabilities.Streaming = ptrBool(false)
+	}
+
+	err = call("visionInput", func(ctx context.Context) error {
+		_, err := model.ChatCompletion(ctx, []Message{Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+			openai.TextContentPart("What color is this image?"),
+			openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: capabilityProbeImage}),
+		}))}, nil, 1)
+		return err
+	})
+	capabilities.VisionInput = capabilitySupported(err)
+
+	structured := *model
+	structured.OutputSchema = capabilityProbeSchema
+	structured.SchemaName = "capability_probe"
+	err = call("structuredOutput", func(ctx context.Context) error {
+		var err error
+		response, err = structured.ChatCompletion(ctx, []Message{NewUserMessage("Say hello in the answer field.")}, nil, 1)
+		return err
+	})
+	model.Provider.SetOutputSchema(model.OutputSchema, model.SchemaName)
+	capabilities.StructuredOutput = capabilitySupported(err)
+	if err == nil && response != nil && len(response.Choices) > 0 {
+		capabilities.StructuredOutput = ptrBool(json.Valid([]byte(response.Choices[0].Message.Content)))
+	}
+
+	if provider, ok := model.Provider.(ContextWindowProvider); ok {
+		_ = call("contextWindow", func(ctx context.Context) error {
+			var err error
+			capabilities.ContextWindow, err = provider.ContextWindow(ctx)
+			return err
+		})
+	}
+
+	return capabilities
+}
+
+// capabilitySupported returns whether a call using a feature shows the feature is supported.
+// Calls rejected as invalid show it is not, while other failures cannot tell.
+func capabilitySupported(err error) *bool {
+	if err == nil {
+		return ptrBool(true)
+	}
+	switch modelErrorStatusCode(err) {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return ptrBool(false)
+	}
+	return nil
+}
+
+// modelErrorStatusCode returns the HTTP status code of a failed model call, or 0 if the call did
+// not get a response
+func modelErrorStatusCode(err error) int {
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return openaiErr.StatusCode
+	}
+	var anthropicErr *AnthropicError
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode
+	}
+	var geminiErr *GeminiError
+	if errors.As(err, &geminiErr) {
+		return geminiErr.StatusCode
+	}
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return httpErr.HTTPStatusCode()
+	}
+	return 0
+}
+
+func ptrBool(value bool) *bool {
+	return &value
+}
+
+// countingEventStream counts the chunks streamed by a capability probe
+type countingEventStream struct {
+	chunks int
+}
+
+func (s *countingEventStream) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.chunks++
+	return nil
+}
+
+func (s *countingEventStream) NotifyCompletion(ctx context.Context) error {
+	return nil
+}
+
+func (s *countingEventStream) Close() error {
+	return nil
+}
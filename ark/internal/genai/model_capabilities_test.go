@@ -0,0 +1,110 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// newCapabilitiesTestServer fakes an OpenAI-compatible server that calls tools in parallel,
+// streams, follows response formats and advertises its context window, but rejects images
+func newCapabilitiesTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id": "llama", "object": "model", "created": 0, "owned_by": "vllm", "max_model_len": 32768}`)
+			return
+		}
+
+		var request map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		body, _ := json.Marshal(request)
+		switch {
+		case strings.Contains(string(body), "image_url"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"error": {"message": "images are not supported", "type": "invalid_request_error"}}`)
+		case request["stream"] == true:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: {\"id\": \"1\", \"object\": \"chat.completion.chunk\", \"choices\": [{\"index\": 0, \"delta\": {\"role\": \"assistant\", \"content\": \"Hi\"}}]}\n\n")
+			_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+		case request["tools"] != nil:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id": "1", "object": "chat.completion", "choices": [{"index": 0, "finish_reason": "tool_calls", "message": {"role": "assistant", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_time", "arguments": "{\"city\":\"Paris\"}"}},
+				{"id": "call_2", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}
+			]}}]}`)
+		case request["response_format"] != nil:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id": "1", "object": "chat.completion", "choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "{\"answer\": \"hello\"}"}}]}`)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"id": "1", "object": "chat.completion", "choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "Hello"}}]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	server := newCapabilitiesTestServer(t)
+	model := newTestRetryModel(&OpenAIProvider{Model: "llama", BaseURL: server.URL, APIKey: "test-key"}, noop.NewModelRecorder())
+
+	capabilities := DetectCapabilities(context.Background(), model)
+
+	assert.Equal(t, &arkv1alpha1.ModelCapabilities{
+		ToolCalling:       ptrBool(true),
+		ParallelToolCalls: ptrBool(true),
+		Streaming:         ptrBool(true),
+		StructuredOutput:  ptrBool(true),
+		VisionInput:       ptrBool(false),
+		ContextWindow:     32768,
+	}, capabilities)
+	assert.Nil(t, model.OutputSchema, "the probe schema is not left on the model")
+}
+
+func TestDetectCapabilities_UnknownOnServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	model := newTestRetryModel(&OpenAIProvider{Model: "llama", BaseURL: server.URL, APIKey: "test-key"}, noop.NewModelRecorder())
+	model.retryPolicy = retryPolicy{maxAttempts: 1}
+
+	capabilities := DetectCapabilities(context.Background(), model)
+
+	assert.Equal(t, &arkv1alpha1.ModelCapabilities{}, capabilities, "failures that do not reject a feature leave it unset")
+}
+
+func TestDetectCapabilities_CanceledContext(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	t.Cleanup(server.Close)
+	model := newTestRetryModel(&OpenAIProvider{Model: "llama", BaseURL: server.URL, APIKey: "test-key"}, noop.NewModelRecorder())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	capabilities := DetectCapabilities(ctx, model)
+
+	assert.Equal(t, &arkv1alpha1.ModelCapabilities{}, capabilities)
+	assert.Equal(t, 0, calls, "the probes stop with the reconcile that runs them")
+}
+
+func TestCapabilitySupported(t *testing.T) {
+	assert.Equal(t, ptrBool(true), capabilitySupported(nil))
+	assert.Equal(t, ptrBool(false), capabilitySupported(openAIStatusError(http.StatusBadRequest)))
+	assert.Nil(t, capabilitySupported(openAIStatusError(http.StatusTooManyRequests)))
+	assert.Nil(t, capabilitySupported(context.DeadlineExceeded))
+}
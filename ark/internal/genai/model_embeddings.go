@@ -0,0 +1,119 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go"
+)
+
+// EmbeddingProvider is implemented by providers that can create embeddings for models of type
+// embeddings
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error)
+}
+
+// IsEmbeddingsModel returns true if the model was declared with type embeddings
+func (m *Model) IsEmbeddingsModel() bool {
+	return m.Type == ModelTypeEmbeddings
+}
+
+// Embed creates an embedding for each input. The embeddings in the response are in input order.
+func (m *Model) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	provider, ok := m.Provider.(EmbeddingProvider)
+	if !ok {
+		return nil, fmt.Errorf("model %s does not support embeddings", m.Name)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no input to embed")
+	}
+
+	ctx, span := m.telemetryRecorder.StartModelExecution(ctx, m.Model, m.Type)
+	defer span.End()
+
+	operationData := map[string]string{
+		"model":     m.Model,
+		"modelType": m.Type,
+	}
+	if m.Name != "" {
+		operationData["modelName"] = m.Name
+	}
+	ctx = m.eventingRecorder.Start(ctx, "EmbeddingCall", fmt.Sprintf("Creating embeddings with model %s", m.Model), operationData)
+
+	m.telemetryRecorder.RecordInput(span, inputs)
+	m.telemetryRecorder.RecordModelDetails(span, m.Model, m.Type)
+
+	var estimatedTokens int64
+	for _, input := range inputs {
+		estimatedTokens += int64(len(input) / charsPerToken)
+	}
+	var response *openai.CreateEmbeddingResponse
+	err := m.callWithRetry(ctx, span, func() bool { return true }, func() error {
+		release, err := m.waitForRateLimits(ctx, span, estimatedTokens)
+		if err != nil {
+			return err
+		}
+		response, err = provider.Embed(ctx, inputs)
+		if response != nil {
+			release(response.Usage.TotalTokens)
+		} else {
+			release(0)
+		}
+		return err
+	})
+	if err == nil && len(response.Data) != len(inputs) {
+		err = fmt.Errorf("model returned %d embeddings for %d inputs", len(response.Data), len(inputs))
+	}
+	if err != nil {
+		m.telemetryRecorder.RecordError(span, err)
+		m.eventingRecorder.Fail(ctx, "EmbeddingCall", fmt.Sprintf("Embedding call failed: %v", err), err, operationData)
+		return nil, err
+	}
+
+	usage := m.tokenUsage(openai.CompletionUsage{PromptTokens: response.Usage.PromptTokens, TotalTokens: response.Usage.TotalTokens})
+	m.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, 0, usage.TotalTokens)
+	if len(usage.Cost) > 0 {
+		m.telemetryRecorder.RecordCost(span, usage.Cost)
+	}
+	AddTokenUsageToOperationData(operationData, usage)
+	m.telemetryRecorder.RecordSuccess(span)
+	m.eventingRecorder.Complete(ctx, "EmbeddingCall", "Embedding call completed successfully", operationData)
+	m.eventingRecorder.AddTokenUsage(ctx, usage)
+
+	return response, nil
+}
+
+// EmbeddingInputs returns the text of each user message, which are the inputs of a query that
+// targets an embeddings model
+func EmbeddingInputs(messages []Message) []string {
+	var inputs []string
+	for _, msg := range messages {
+		user := openai.ChatCompletionMessageParamUnion(msg).OfUser
+		if user == nil {
+			continue
+		}
+		text := user.Content.OfString.Value
+		for _, part := range user.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				text += part.OfText.Text
+			}
+		}
+		if text != "" {
+			inputs = append(inputs, text)
+		}
+	}
+	return inputs
+}
+
+// newEmbeddingParams builds the parameters of an OpenAI compatible embeddings request. The
+// dimensions property sets the size of the embeddings for models that support it.
+func newEmbeddingParams(model string, properties map[string]string, inputs []string) openai.EmbeddingNewParams {
+	params := openai.EmbeddingNewParams{
+		Model: model,
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+	}
+	if dimensions := getIntProperty(properties, "dimensions", 0); dimensions > 0 {
+		params.Dimensions = openai.Int(int64(dimensions))
+	}
+	return params
+}
@@ -0,0 +1,174 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+const testEmbeddingResponse = `{
+	"object": "list",
+	"model": "text-embedding-3-small",
+	"data": [
+		{"object": "embedding", "index": 0, "embedding": [0.1, 0.2, 0.3]},
+		{"object": "embedding", "index": 1, "embedding": [0.4, 0.5, 0.6]}
+	],
+	"usage": {"prompt_tokens": 8, "total_tokens": 8}
+}`
+
+// newFakeEmbeddingServer replies to every request with response and records the last request
+func newFakeEmbeddingServer(t *testing.T, response string, request *map[string]any, path *string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(request)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestEmbeddingsModel(baseURL string, properties map[string]string) *Model {
+	return &Model{
+		Name:              "embedder",
+		Model:             "text-embedding-3-small",
+		Type:              ModelTypeEmbeddings,
+		Provider:          &OpenAIProvider{Model: "text-embedding-3-small", BaseURL: baseURL, APIKey: "test-key", Properties: properties},
+		telemetryRecorder: noop.NewModelRecorder(),
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+}
+
+func TestModelEmbed_OpenAI(t *testing.T) {
+	var request map[string]any
+	var path string
+	server := newFakeEmbeddingServer(t, testEmbeddingResponse, &request, &path)
+	model := newTestEmbeddingsModel(server.URL, map[string]string{"dimensions": "3"})
+
+	response, err := model.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/embeddings", path)
+	assert.Equal(t, "text-embedding-3-small", request["model"])
+	assert.Equal(t, []any{"first", "second"}, request["input"])
+	assert.Equal(t, 3.0, request["dimensions"])
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, []float64{0.4, 0.5, 0.6}, response.Data[1].Embedding)
+	assert.Equal(t, int64(8), response.Usage.TotalTokens)
+}
+
+func TestModelEmbed_InputCountMismatch(t *testing.T) {
+	var request map[string]any
+	var path string
+	server := newFakeEmbeddingServer(t, testEmbeddingResponse, &request, &path)
+	model := newTestEmbeddingsModel(server.URL, nil)
+
+	_, err := model.Embed(context.Background(), []string{"only one"})
+
+	assert.ErrorContains(t, err, "returned 2 embeddings for 1 inputs")
+}
+
+func TestModelEmbed_UnsupportedProvider(t *testing.T) {
+	model := newTestEmbeddingsModel("", nil)
+	model.Provider = &AnthropicProvider{Model: "claude-sonnet-4-5"}
+
+	_, err := model.Embed(context.Background(), []string{"hello"})
+
+	assert.ErrorContains(t, err, "does not support embeddings")
+}
+
+func TestModelChatCompletion_EmbeddingsModel(t *testing.T) {
+	model := newTestEmbeddingsModel("http://unused", nil)
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hello")}, nil, 1)
+
+	assert.ErrorContains(t, err, "is an embeddings model")
+}
+
+func TestBedrockEmbed_Titan(t *testing.T) {
+	server := newFakeBedrockServer(t, `{"embedding": [0.1, 0.2], "inputTextTokenCount": 3}`)
+	model := newTestBedrockModel("amazon.titan-embed-text-v2:0", server.URL)
+	model.Properties = map[string]string{"dimensions": "256"}
+
+	response, err := model.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/model/amazon.titan-embed-text-v2:0/invoke", server.path)
+	assert.Equal(t, map[string]any{"inputText": "second", "dimensions": 256.0}, server.request)
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, int64(1), response.Data[1].Index)
+	assert.Equal(t, []float64{0.1, 0.2}, response.Data[1].Embedding)
+	assert.Equal(t, int64(6), response.Usage.PromptTokens)
+}
+
+func TestBedrockEmbed_Cohere(t *testing.T) {
+	server := newFakeBedrockServer(t, `{"id": "1", "embeddings": [[0.1, 0.2], [0.3, 0.4]], "texts": ["first", "second"]}`)
+	model := newTestBedrockModel("cohere.embed-english-v3", server.URL)
+
+	response, err := model.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"texts": []any{"first", "second"}, "input_type": "search_document"}, server.request)
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, []float64{0.3, 0.4}, response.Data[1].Embedding)
+}
+
+func TestBedrockEmbed_UnsupportedModel(t *testing.T) {
+	model := newTestBedrockModel("anthropic.claude-3-haiku-20240307-v1:0", "http://unused")
+
+	_, err := model.Embed(context.Background(), []string{"hello"})
+
+	assert.ErrorContains(t, err, "not a supported embedding model")
+}
+
+func TestEmbeddingInputs(t *testing.T) {
+	messages := []Message{
+		NewSystemMessage("ignored"),
+		NewUserMessage("first"),
+		Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{openai.TextContentPart("second")})),
+		NewAssistantMessage("ignored"),
+		NewUserMessage(""),
+	}
+
+	assert.Equal(t, []string{"first", "second"}, EmbeddingInputs(messages))
+}
+
+func TestProbeModel_Embeddings(t *testing.T) {
+	var request map[string]any
+	var path string
+	server := newFakeEmbeddingServer(t, `{"object": "list", "data": [{"object": "embedding", "index": 0, "embedding": [0.1]}], "usage": {"prompt_tokens": 1, "total_tokens": 1}}`, &request, &path)
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "embedder", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "text-embedding-3-small"},
+			Type:     ModelTypeEmbeddings,
+			Provider: ProviderOpenAI,
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: server.URL},
+				APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}})
+
+	model, err := LoadModel(context.Background(), k8sClient, "embedder", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+
+	result := ProbeModel(context.Background(), model)
+
+	assert.True(t, result.Available, result.Message)
+	assert.Equal(t, "/embeddings", path)
+	assert.Equal(t, []any{"Hello"}, request["input"])
+}
@@ -0,0 +1,196 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// modelAvailableCondition is the condition the model controller sets from its probes
+const modelAvailableCondition = "ModelAvailable"
+
+// IsRetryableModelError reports whether a model call failed with a rate limit, a server error or
+// a connection failure, which another model or a later attempt may not hit
+func IsRetryableModelError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var openaiErr *openai.Error
+	if errors.As(err, &openaiErr) {
+		return isRetryableStatusCode(openaiErr.StatusCode)
+	}
+
+	var anthropicErr *AnthropicError
+	if errors.As(err, &anthropicErr) {
+		return isRetryableStatusCode(anthropicErr.StatusCode)
+	}
+
+	var geminiErr *GeminiError
+	if errors.As(err, &geminiErr) {
+		return isRetryableStatusCode(geminiErr.StatusCode)
+	}
+
+	if isRetryableBedrockError(err) {
+		return true
+	}
+
+	// Matches both smithy and AWS response errors, whose Unwrap skips the smithy error
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return isRetryableStatusCode(httpErr.HTTPStatusCode())
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Connections closed by the provider in the middle of a response
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return isRetryableError(err)
+}
+
+func isRetryableStatusCode(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// isRetryableBedrockError reports whether Bedrock throttled the call or was temporarily unable
+// to serve it, which it can also report in the middle of a stream
+func isRetryableBedrockError(err error) bool {
+	var throttling *types.ThrottlingException
+	var unavailable *types.ServiceUnavailableException
+	var notReady *types.ModelNotReadyException
+	var internal *types.InternalServerException
+	return errors.As(err, &throttling) || errors.As(err, &unavailable) || errors.As(err, &notReady) || errors.As(err, &internal)
+}
+
+// modelFallback is a fallback model that is loaded the first time a call fails over to it
+type modelFallback struct {
+	name  string
+	load  func(ctx context.Context) (*Model, error)
+	mu    sync.Mutex
+	model *Model
+	err   error
+}
+
+func (f *modelFallback) get(ctx context.Context) (*Model, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.model == nil && f.err == nil {
+		f.model, f.err = f.load(ctx)
+	}
+	return f.model, f.err
+}
+
+// streamTracker records whether a model call has streamed any chunks
+type streamTracker struct {
+	EventStreamInterface
+	streamed atomic.Bool
+}
+
+func (s *streamTracker) StreamChunk(ctx context.Context, chunk interface{}) error {
+	s.streamed.Store(true)
+	return s.EventStreamInterface.StreamChunk(ctx, chunk)
+}
+
+// loadAgentModels loads the agent's model with its fallback models. Models whose ModelAvailable
+// condition is False are tried last. The first model that loads is returned, and the models after
+// it are loaded only when a call fails over to them.
+func loadAgentModels(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Agent, additionalHeaders map[string]string, telemetryRecorder telemetry.ModelRecorder, eventingRecorder eventing.ModelRecorder) (*Model, error) {
+	// Agents without a model use the default model, as the agent webhook sets it
+	modelRef := crd.Spec.ModelRef
+	if modelRef == nil {
+		modelRef = &arkv1alpha1.AgentModelRef{Name: defaultModelName}
+	}
+	if len(crd.Spec.FallbackModels) == 0 {
+		return LoadModel(ctx, k8sClient, modelRef, crd.Namespace, additionalHeaders, telemetryRecorder, eventingRecorder)
+	}
+
+	refs := make([]*arkv1alpha1.AgentModelRef, 0, len(crd.Spec.FallbackModels)+1)
+	refs = append(refs, modelRef)
+	for i := range crd.Spec.FallbackModels {
+		refs = append(refs, &crd.Spec.FallbackModels[i])
+	}
+
+	var available, unavailable []*arkv1alpha1.AgentModelRef
+	for _, ref := range refs {
+		if isModelUnavailable(ctx, k8sClient, ref, crd.Namespace) {
+			unavailable = append(unavailable, ref)
+			continue
+		}
+		available = append(available, ref)
+	}
+	ordered := append(available, unavailable...)
+
+	log := logf.FromContext(ctx)
+	var loadErrs []error
+	for i, ref := range ordered {
+		model, err := LoadModel(ctx, k8sClient, ref, crd.Namespace, additionalHeaders, telemetryRecorder, eventingRecorder)
+		if err != nil {
+			log.Info("skipping model that failed to load", "agent", crd.Name, "model", ref.Name, "error", err.Error())
+			loadErrs = append(loadErrs, fmt.Errorf("model %s: %w", ref.Name, err))
+			continue
+		}
+
+		if model.Name != refs[0].Name {
+			reason := "model is not available"
+			if len(loadErrs) > 0 {
+				reason = loadErrs[0].Error()
+			}
+			eventingRecorder.ModelFallback(ctx, refs[0].Name, model.Name, reason)
+		}
+
+		for _, fallbackRef := range ordered[i+1:] {
+			model.fallbacks = append(model.fallbacks, &modelFallback{
+				name: fallbackRef.Name,
+				load: func(ctx context.Context) (*Model, error) {
+					return LoadModel(ctx, k8sClient, fallbackRef, crd.Namespace, additionalHeaders, telemetryRecorder, eventingRecorder)
+				},
+			})
+		}
+		return model, nil
+	}
+	return nil, errors.Join(loadErrs...)
+}
+
+// isModelUnavailable reports whether the model's latest probe failed. Models that have not been
+// probed yet are treated as available.
+func isModelUnavailable(ctx context.Context, k8sClient client.Client, ref *arkv1alpha1.AgentModelRef, defaultNamespace string) bool {
+	modelName, namespace, err := ResolveModelSpec(ref, defaultNamespace)
+	if err != nil {
+		return false
+	}
+	modelCRD, err := loadModelCRD(ctx, k8sClient, modelName, namespace)
+	if err != nil {
+		return false
+	}
+	return modelProbeFailed(modelCRD)
+}
+
+// modelProbeFailed reports whether the ModelAvailable condition of the model is False
+func modelProbeFailed(modelCRD *arkv1alpha1.Model) bool {
+	condition := meta.FindStatusCondition(modelCRD.Status.Conditions, modelAvailableCondition)
+	return condition != nil && condition.Status == metav1.ConditionFalse
+}
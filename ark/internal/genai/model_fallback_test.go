@@ -0,0 +1,233 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// failingProvider fails every call with err and counts the calls it received
+type failingProvider struct {
+	err   error
+	calls int
+}
+
+func (p *failingProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func (p *failingProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *failingProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func newFallbackTestModel(name string, provider ChatCompletionProvider, fallbacks ...*Model) *Model {
+	model := &Model{
+		Name:              name,
+		Model:             name,
+		Provider:          provider,
+		telemetryRecorder: noop.NewModelRecorder(),
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+	for _, fallback := range fallbacks {
+		model.fallbacks = append(model.fallbacks, &modelFallback{name: fallback.Name, model: fallback})
+	}
+	return model
+}
+
+func openAIStatusError(statusCode int) error {
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/chat/completions", nil)
+	return &openai.Error{StatusCode: statusCode, Request: req, Response: &http.Response{StatusCode: statusCode}}
+}
+
+func TestIsRetryableModelError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "nil", err: nil, retryable: false},
+		{name: "rate limited", err: fmt.Errorf("call failed: %w", openAIStatusError(http.StatusTooManyRequests)), retryable: true},
+		{name: "server error", err: openAIStatusError(http.StatusServiceUnavailable), retryable: true},
+		{name: "bad request", err: openAIStatusError(http.StatusBadRequest), retryable: false},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, retryable: true},
+		{name: "canceled", err: context.Canceled, retryable: false},
+		{name: "other", err: errors.New("invalid model"), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryableModelError(tt.err))
+		})
+	}
+}
+
+func TestModelChatCompletion_FallsBackOnRetryableError(t *testing.T) {
+	primary := &failingProvider{err: openAIStatusError(http.StatusTooManyRequests)}
+	secondary := &failingProvider{err: openAIStatusError(http.StatusBadGateway)}
+	answer := &answerProvider{answers: []string{"served by fallback"}}
+	model := newFallbackTestModel("azure", primary,
+		newFallbackTestModel("openai", secondary),
+		newFallbackTestModel("openai-backup", answer),
+	)
+
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "served by fallback", response.Choices[0].Message.Content)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, 1, secondary.calls)
+	assert.Equal(t, 1, answer.calls)
+}
+
+// failAfterChunkProvider streams a chunk and then fails with err
+type failAfterChunkProvider struct {
+	failingProvider
+}
+
+func (p *failAfterChunkProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.calls++
+	if err := streamFunc(&openai.ChatCompletionChunk{Choices: []openai.ChatCompletionChunkChoice{{Delta: openai.ChatCompletionChunkChoiceDelta{Content: "partial"}}}}); err != nil {
+		return nil, err
+	}
+	return nil, p.err
+}
+
+func TestModelChatCompletion_NoFallbackAfterStreaming(t *testing.T) {
+	primary := &failAfterChunkProvider{failingProvider{err: openAIStatusError(http.StatusBadGateway)}}
+	answer := &answerProvider{answers: []string{"unused"}}
+	model := newFallbackTestModel("azure", primary, newFallbackTestModel("openai", answer))
+	stream := &recordingStream{}
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, stream, 1)
+
+	require.Error(t, err)
+	assert.Equal(t, 0, answer.calls)
+	assert.Len(t, stream.chunks, 1)
+}
+
+func TestModelChatCompletion_NoFallbackOnClientError(t *testing.T) {
+	primary := &failingProvider{err: openAIStatusError(http.StatusBadRequest)}
+	answer := &answerProvider{answers: []string{"unused"}}
+	model := newFallbackTestModel("azure", primary, newFallbackTestModel("openai", answer))
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil, 1)
+
+	require.Error(t, err)
+	assert.Equal(t, 0, answer.calls)
+}
+
+func newFallbackTestModelCRD(name string, available metav1.ConditionStatus) *arkv1alpha1.Model {
+	model := &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "gpt-4o"},
+			Provider: ProviderOpenAI,
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: "https://" + name + ".example.com"},
+				APIKey:  arkv1alpha1.ValueSource{Value: "key"},
+			}},
+		},
+	}
+	if available != "" {
+		meta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{Type: modelAvailableCondition, Status: available, Reason: "Probe"})
+	}
+	return model
+}
+
+func TestLoadAgentModels(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{
+		newFallbackTestModelCRD("azure", metav1.ConditionFalse),
+		newFallbackTestModelCRD("openai", metav1.ConditionTrue),
+		newFallbackTestModelCRD("openai-backup", ""),
+	})
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: arkv1alpha1.AgentSpec{
+			ModelRef: &arkv1alpha1.AgentModelRef{Name: "azure"},
+			FallbackModels: []arkv1alpha1.AgentModelRef{
+				{Name: "missing"},
+				{Name: "openai"},
+				{Name: "openai-backup"},
+			},
+		},
+	}
+
+	model, err := loadAgentModels(context.Background(), k8sClient, agent, nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+
+	require.NoError(t, err)
+	names := []string{model.Name}
+	for _, fallback := range model.fallbacks {
+		names = append(names, fallback.name)
+		assert.Nil(t, fallback.model, "fallback models are loaded when a call fails over to them")
+	}
+	// The missing model is skipped and the unavailable primary is tried last
+	assert.Equal(t, []string{"openai", "openai-backup", "azure"}, names)
+
+	fallback, err := model.fallbacks[0].get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "openai-backup", fallback.Name)
+}
+
+// fallbackRecorder records the models of each ModelFallback event
+type fallbackRecorder struct {
+	eventing.ModelRecorder
+	fallbacks [][2]string
+}
+
+func (r *fallbackRecorder) ModelFallback(_ context.Context, failedModel, fallbackModel, _ string) {
+	r.fallbacks = append(r.fallbacks, [2]string{failedModel, fallbackModel})
+}
+
+func TestLoadAgentModels_DefaultModel(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{
+		newFallbackTestModelCRD(defaultModelName, metav1.ConditionFalse),
+		newFallbackTestModelCRD("openai", metav1.ConditionTrue),
+	})
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec:       arkv1alpha1.AgentSpec{FallbackModels: []arkv1alpha1.AgentModelRef{{Name: "openai"}}},
+	}
+	recorder := &fallbackRecorder{ModelRecorder: eventnoop.NewModelRecorder()}
+
+	model, err := loadAgentModels(context.Background(), k8sClient, agent, nil, noop.NewModelRecorder(), recorder)
+
+	require.NoError(t, err)
+	assert.Equal(t, "openai", model.Name)
+	require.Len(t, model.fallbacks, 1)
+	assert.Equal(t, defaultModelName, model.fallbacks[0].name, "an agent without a model falls back from the default model")
+	assert.Equal(t, [][2]string{{defaultModelName, "openai"}}, recorder.fallbacks)
+}
+
+func TestLoadAgentModels_NoneLoadable(t *testing.T) {
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: arkv1alpha1.AgentSpec{
+			ModelRef:       &arkv1alpha1.AgentModelRef{Name: "missing"},
+			FallbackModels: []arkv1alpha1.AgentModelRef{{Name: "also-missing"}},
+		},
+	}
+
+	_, err := loadAgentModels(context.Background(), setupModelTestClient(nil), agent, nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+
+	require.ErrorContains(t, err, "model missing")
+	require.ErrorContains(t, err, "model also-missing")
+}
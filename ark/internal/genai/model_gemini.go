@@ -0,0 +1,107 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadGeminiConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.GeminiModelConfig, namespace string, model *Model, additionalHeaders map[string]string) error {
+	if config == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+	if (config.APIKey == nil) == (config.ServiceAccountKey == nil) {
+		return fmt.Errorf("gemini configuration requires exactly one of apiKey and serviceAccountKey")
+	}
+
+	resolveOptional := func(source *arkv1alpha1.ValueSource, name string) (string, error) {
+		if source == nil {
+			return "", nil
+		}
+		value, err := resolver.ResolveValueSource(ctx, *source, namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve Gemini %s: %w", name, err)
+		}
+		return value, nil
+	}
+
+	baseURL, err := resolveOptional(config.BaseURL, "baseUrl")
+	if err != nil {
+		return err
+	}
+	apiKey, err := resolveOptional(config.APIKey, "apiKey")
+	if err != nil {
+		return err
+	}
+	serviceAccountKey, err := resolveOptional(config.ServiceAccountKey, "serviceAccountKey")
+	if err != nil {
+		return err
+	}
+	project, err := resolveOptional(config.Project, "project")
+	if err != nil {
+		return err
+	}
+	location, err := resolveOptional(config.Location, "location")
+	if err != nil {
+		return err
+	}
+
+	var tokenSource *geminiTokenSource
+	if serviceAccountKey != "" {
+		key, err := parseGeminiServiceAccountKey(serviceAccountKey)
+		if err != nil {
+			return err
+		}
+		tokenSource, err = loadGeminiTokenSource(namespace, model.Name, serviceAccountKey)
+		if err != nil {
+			return err
+		}
+		if project == "" {
+			project = key.ProjectID
+		}
+		if project == "" {
+			return fmt.Errorf("gemini project is required when the service account key has no project_id")
+		}
+		if location == "" {
+			location = geminiDefaultLocation
+		}
+	}
+
+	headers, err := resolveModelHeaders(ctx, resolver.Client, config.Headers, namespace)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range additionalHeaders {
+		headers[k] = v
+	}
+
+	var properties map[string]string
+	if config.Properties != nil {
+		properties = make(map[string]string)
+		for key, valueSource := range config.Properties {
+			value, err := resolver.ResolveValueSource(ctx, valueSource, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve Gemini property %s: %w", key, err)
+			}
+			properties[key] = value
+		}
+	}
+
+	model.Provider = &GeminiProvider{
+		Model:             model.Model,
+		BaseURL:           baseURL,
+		APIKey:            apiKey,
+		ServiceAccountKey: serviceAccountKey,
+		Project:           project,
+		Location:          location,
+		Headers:           headers,
+		Properties:        properties,
+		tokenSource:       tokenSource,
+	}
+	model.Properties = properties
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/eventing"
 	"mckinsey.com/ark/internal/telemetry"
@@ -22,28 +24,89 @@ type ConfigProvider interface {
 }
 
 type Model struct {
-	Model             string
-	Type              string
-	Properties        map[string]string
-	Provider          ChatCompletionProvider
-	OutputSchema      *runtime.RawExtension
-	SchemaName        string
+	// Name of the Model resource the model was loaded from
+	Name         string
+	Model        string
+	Type         string
+	Properties   map[string]string
+	Provider     ChatCompletionProvider
+	OutputSchema *runtime.RawExtension
+	SchemaName   string
+	// fallbacks are tried in order when a call fails with a retryable error
+	fallbacks         []*modelFallback
+	router            *modelRouter
+	rateLimiter       *rateLimiter
+	retryPolicy       retryPolicy
+	cache             *completionCache
+	pricing           *modelPricing
+	reasoning         *arkv1alpha1.ModelReasoning
 	telemetryRecorder telemetry.ModelRecorder
 	eventingRecorder  eventing.ModelRecorder
 }
 
+// ChatCompletion calls the model, failing over to the next fallback model while calls fail
+// with a retryable error. A call that has already streamed chunks is not failed over, since the
+// client could not tell the chunks of the two models apart.
 func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStream EventStreamInterface, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	var tracked *streamTracker
+	if eventStream != nil {
+		tracked = &streamTracker{EventStreamInterface: eventStream}
+		eventStream = tracked
+	}
+	response, err := m.chatCompletion(ctx, messages, eventStream, n, "", tools...)
+
+	log := logf.FromContext(ctx)
+	current := m.Name
+	for _, fallback := range m.fallbacks {
+		if err == nil || ctx.Err() != nil || !IsRetryableModelError(err) {
+			break
+		}
+		if tracked != nil && tracked.streamed.Load() {
+			log.Info("model call failed after streaming, not trying fallback models", "model", current, "error", err.Error())
+			break
+		}
+		model, loadErr := fallback.get(ctx)
+		if loadErr != nil {
+			log.Info("skipping fallback model that failed to load", "model", fallback.name, "error", loadErr.Error())
+			continue
+		}
+		log.Info("model call failed, trying fallback model", "model", current, "fallback", model.Name, "error", err.Error())
+		m.eventingRecorder.ModelFallback(ctx, current, model.Name, err.Error())
+
+		model.OutputSchema = m.OutputSchema
+		model.SchemaName = m.SchemaName
+		response, err = model.chatCompletion(ctx, messages, eventStream, n, current, tools...)
+		current = model.Name
+	}
+	return response, err
+}
+
+// chatCompletion makes a single model call. fallbackFor names the model that failed before
+// this call, if any.
+func (m *Model) chatCompletion(ctx context.Context, messages []Message, eventStream EventStreamInterface, n int64, fallbackFor string, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if m.router != nil {
+		return m.routeChatCompletion(ctx, messages, eventStream, n, fallbackFor, tools...)
+	}
 	if m.Provider == nil {
 		return nil, nil
 	}
+	if m.IsEmbeddingsModel() {
+		return nil, fmt.Errorf("model %s is an embeddings model and does not support chat completions", m.Name)
+	}
 
 	ctx, span := m.telemetryRecorder.StartModelExecution(ctx, m.Model, m.Type)
 	defer span.End()
+	if fallbackFor != "" {
+		m.telemetryRecorder.RecordFallback(span, fallbackFor)
+	}
 
 	operationData := map[string]string{
 		"model":     m.Model,
 		"modelType": m.Type,
 	}
+	if m.Name != "" {
+		operationData["modelName"] = m.Name
+	}
 	ctx = m.eventingRecorder.Start(ctx, "LLMCall", fmt.Sprintf("Calling model %s", m.Model), operationData)
 
 	otelMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
@@ -58,18 +121,43 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 		m.Provider.SetOutputSchema(m.OutputSchema, m.SchemaName)
 	}
 
-	var response *openai.ChatCompletion
-	var err error
-
-	if eventStream != nil {
-		response, err = m.Provider.ChatCompletionStream(ctx, messages, n, func(chunk *openai.ChatCompletionChunk) error {
-			chunkWithMeta := WrapChunkWithMetadata(ctx, chunk, m.Model, nil)
-			return eventStream.StreamChunk(ctx, chunkWithMeta)
-		}, tools...)
-	} else {
-		response, err = m.Provider.ChatCompletion(ctx, messages, n, tools...)
+	cacheKey := m.completionCacheKey(ctx, messages, n, tools)
+	if cacheKey != "" {
+		cached := m.cache.get(ctx, cacheKey)
+		m.telemetryRecorder.RecordCacheResult(span, cached != nil)
+		if cached != nil {
+			return m.completeFromCache(ctx, span, cached, eventStream, operationData)
+		}
 	}
 
+	estimatedTokens := estimateCallTokens(messages, tools)
+	ctx, reasoningSummary := contextWithReasoningSummary(ctx)
+	var response *openai.ChatCompletion
+	streamed := false
+	err := m.callWithRetry(ctx, span, func() bool { return !streamed }, func() error {
+		reasoningSummary.reset()
+		release, err := m.waitForRateLimits(ctx, span, estimatedTokens)
+		if err != nil {
+			return err
+		}
+
+		if eventStream != nil {
+			response, err = m.Provider.ChatCompletionStream(ctx, messages, n, func(chunk *openai.ChatCompletionChunk) error {
+				streamed = true
+				chunkWithMeta := WrapChunkWithMetadata(ctx, chunk, m.Model, nil)
+				return eventStream.StreamChunk(ctx, chunkWithMeta)
+			}, tools...)
+		} else {
+			response, err = m.Provider.ChatCompletion(ctx, messages, n, tools...)
+		}
+		if response != nil {
+			release(response.Usage.TotalTokens)
+		} else {
+			release(0)
+		}
+		return err
+	})
+
 	if err != nil {
 		m.telemetryRecorder.RecordError(span, err)
 		m.eventingRecorder.Fail(ctx, "LLMCall", fmt.Sprintf("Model call failed: %v", err), err, operationData)
@@ -83,18 +171,30 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 		return nil, err
 	}
 
+	if cacheKey != "" {
+		m.cache.set(ctx, cacheKey, response)
+	}
+
 	if len(response.Choices) > 0 {
 		m.telemetryRecorder.RecordOutput(span, response.Choices[0].Message)
 	}
 
-	m.telemetryRecorder.RecordTokenUsage(span, response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Usage.TotalTokens)
+	if summary := reasoningSummary.String(); summary != "" {
+		m.telemetryRecorder.RecordReasoningSummary(span, summary)
+	}
+
+	usage := m.tokenUsage(response.Usage)
+	m.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	if usage.ReasoningTokens > 0 || usage.CachedTokens > 0 {
+		m.telemetryRecorder.RecordTokenDetails(span, usage.ReasoningTokens, usage.CachedTokens)
+	}
+	if len(usage.Cost) > 0 {
+		m.telemetryRecorder.RecordCost(span, usage.Cost)
+	}
+	AddTokenUsageToOperationData(operationData, usage)
 	m.telemetryRecorder.RecordSuccess(span)
 	m.eventingRecorder.Complete(ctx, "LLMCall", "Model call completed successfully", operationData)
-	m.eventingRecorder.AddTokenUsage(ctx, arkv1alpha1.TokenUsage{
-		PromptTokens:     response.Usage.PromptTokens,
-		CompletionTokens: response.Usage.CompletionTokens,
-		TotalTokens:      response.Usage.TotalTokens,
-	})
+	m.eventingRecorder.AddTokenUsage(ctx, usage)
 
 	return response, nil
 }
@@ -0,0 +1,103 @@
+package genai
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const defaultPricingCurrency = "USD"
+
+// modelPricing is the price of a million tokens of a model
+type modelPricing struct {
+	input       float64
+	cachedInput float64
+	output      float64
+	currency    string
+}
+
+// pricingFromSpec parses the pricing of a model, returning nil if the model has none
+func pricingFromSpec(spec *arkv1alpha1.ModelPricing) (*modelPricing, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	input, err := parsePrice("inputPerMillion", spec.InputPerMillion)
+	if err != nil {
+		return nil, err
+	}
+	output, err := parsePrice("outputPerMillion", spec.OutputPerMillion)
+	if err != nil {
+		return nil, err
+	}
+	cachedInput := input
+	if spec.CachedInputPerMillion != "" {
+		if cachedInput, err = parsePrice("cachedInputPerMillion", spec.CachedInputPerMillion); err != nil {
+			return nil, err
+		}
+	}
+	currency := spec.Currency
+	if currency == "" {
+		currency = defaultPricingCurrency
+	}
+	return &modelPricing{input: input, cachedInput: cachedInput, output: output, currency: currency}, nil
+}
+
+func parsePrice(field, value string) (float64, error) {
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 {
+		return 0, fmt.Errorf("invalid pricing.%s %q: must be a non-negative decimal", field, value)
+	}
+	return price, nil
+}
+
+// cost returns the cost of the tokens used by a call. Prompt tokens read from the provider's
+// prompt cache are charged at the cached input price.
+func (p *modelPricing) cost(usage openai.CompletionUsage) float64 {
+	cached := min(max(usage.PromptTokensDetails.CachedTokens, 0), usage.PromptTokens)
+	tokens := float64(usage.PromptTokens-cached)*p.input + float64(cached)*p.cachedInput + float64(usage.CompletionTokens)*p.output
+	return tokens / 1e6
+}
+
+// tokenUsage returns the usage of a call with its cost, if the model has pricing
+func (m *Model) tokenUsage(usage openai.CompletionUsage) arkv1alpha1.TokenUsage {
+	tokenUsage := arkv1alpha1.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		ReasoningTokens:  usage.CompletionTokensDetails.ReasoningTokens,
+		CachedTokens:     usage.PromptTokensDetails.CachedTokens,
+	}
+	if m.pricing != nil {
+		tokenUsage.AddCost(m.pricing.currency, m.pricing.cost(usage))
+	}
+	return tokenUsage
+}
+
+// AddTokenUsageToOperationData adds the tokens and the cost of usage to the data of an operation
+// event. The cost is reported with its currency, or per currency when usage is priced in several.
+func AddTokenUsageToOperationData(operationData map[string]string, usage arkv1alpha1.TokenUsage) {
+	operationData["promptTokens"] = fmt.Sprintf("%d", usage.PromptTokens)
+	operationData["completionTokens"] = fmt.Sprintf("%d", usage.CompletionTokens)
+	operationData["totalTokens"] = fmt.Sprintf("%d", usage.TotalTokens)
+	if usage.ReasoningTokens > 0 {
+		operationData["reasoningTokens"] = fmt.Sprintf("%d", usage.ReasoningTokens)
+	}
+	if usage.CachedTokens > 0 {
+		operationData["cachedTokens"] = fmt.Sprintf("%d", usage.CachedTokens)
+	}
+
+	if len(usage.Cost) == 1 {
+		for currency, amount := range usage.Cost {
+			operationData["cost"] = amount
+			operationData["currency"] = currency
+		}
+		return
+	}
+	for currency, amount := range usage.Cost {
+		operationData["cost."+currency] = amount
+	}
+}
@@ -0,0 +1,150 @@
+package genai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// pricedProvider answers every call with the given usage
+type pricedProvider struct {
+	usage openai.CompletionUsage
+}
+
+func (p *pricedProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "hi"}}},
+		Usage:   p.usage,
+	}, nil
+}
+
+func (p *pricedProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *pricedProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+var testPricing = &modelPricing{input: 2.5, cachedInput: 1.25, output: 10, currency: "USD"}
+
+func TestPricingFromSpec(t *testing.T) {
+	pricing, err := pricingFromSpec(&arkv1alpha1.ModelPricing{InputPerMillion: "2.5", OutputPerMillion: "10"})
+	require.NoError(t, err)
+	assert.Equal(t, &modelPricing{input: 2.5, cachedInput: 2.5, output: 10, currency: "USD"}, pricing)
+
+	pricing, err = pricingFromSpec(&arkv1alpha1.ModelPricing{InputPerMillion: "3", OutputPerMillion: "15", CachedInputPerMillion: "0.3", Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, &modelPricing{input: 3, cachedInput: 0.3, output: 15, currency: "EUR"}, pricing)
+
+	_, err = pricingFromSpec(&arkv1alpha1.ModelPricing{InputPerMillion: "free", OutputPerMillion: "10"})
+	assert.ErrorContains(t, err, `invalid pricing.inputPerMillion "free"`)
+
+	pricing, err = pricingFromSpec(nil)
+	require.NoError(t, err)
+	assert.Nil(t, pricing)
+}
+
+func TestModelPricingCost(t *testing.T) {
+	assert.InDelta(t, 0.000045, testPricing.cost(openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 2}), 1e-12)
+	assert.InDelta(t, 0.00004, testPricing.cost(openai.CompletionUsage{
+		PromptTokens:        10,
+		CompletionTokens:    2,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{CachedTokens: 4},
+	}), 1e-12, "cached prompt tokens are charged at the cached input price")
+}
+
+func TestModelChatCompletion_Cost(t *testing.T) {
+	model := newTestRetryModel(&pricedProvider{usage: openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}}, noop.NewModelRecorder())
+	ctx := model.eventingRecorder.StartTokenCollection(context.Background())
+
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+	assert.Empty(t, model.eventingRecorder.GetTokenSummary(ctx).Cost, "models without pricing have no cost")
+
+	model.pricing = testPricing
+	ctx = model.eventingRecorder.StartTokenCollection(context.Background())
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+
+	usage := model.eventingRecorder.GetTokenSummary(ctx)
+	assert.Equal(t, int64(24), usage.TotalTokens)
+	assert.Equal(t, map[string]string{"USD": "0.00009"}, usage.Cost)
+}
+
+func TestModelChatCompletion_CachedCallCost(t *testing.T) {
+	model := newTestRetryModel(&pricedProvider{usage: openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}}, noop.NewModelRecorder())
+	model.pricing = testPricing
+	model.cache = &completionCache{scope: "default/gpt-4o", backend: newMemoryCacheBackend(10), ttl: time.Hour, maxEntryBytes: defaultCacheMaxEntryBytes}
+	ctx := model.eventingRecorder.StartTokenCollection(context.Background())
+
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+
+	usage := model.eventingRecorder.GetTokenSummary(ctx)
+	assert.Equal(t, int64(24), usage.TotalTokens, "cache hits report the tokens of the cached call")
+	assert.Equal(t, map[string]string{"USD": "0.000045"}, usage.Cost, "cache hits cost nothing")
+}
+
+func TestAgentExecute_Cost(t *testing.T) {
+	agent := newLoopTestAgent(&pricedProvider{usage: openai.CompletionUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}}, nil, false)
+	agent.Model.pricing = testPricing
+	queryRecorder := eventnoop.NewProvider().QueryRecorder()
+	ctx := queryRecorder.StartTokenCollection(context.Background())
+
+	_, err := agent.Execute(ctx, NewUserMessage("hello"), nil, nil, nil)
+	require.NoError(t, err)
+
+	usage := queryRecorder.GetTokenSummary(ctx)
+	assert.Equal(t, int64(120), usage.TotalTokens, "the agent's tokens are added to the query's")
+	assert.Equal(t, map[string]string{"USD": "0.00045"}, usage.Cost)
+}
+
+func TestAddTokenUsageToOperationData(t *testing.T) {
+	operationData := map[string]string{}
+	AddTokenUsageToOperationData(operationData, arkv1alpha1.TokenUsage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12, Cost: map[string]string{"USD": "0.5"}})
+	assert.Equal(t, map[string]string{
+		"promptTokens":     "10",
+		"completionTokens": "2",
+		"totalTokens":      "12",
+		"cost":             "0.5",
+		"currency":         "USD",
+	}, operationData)
+
+	operationData = map[string]string{}
+	AddTokenUsageToOperationData(operationData, arkv1alpha1.TokenUsage{Cost: map[string]string{"USD": "0.5", "EUR": "0.25"}})
+	assert.Equal(t, "0.5", operationData["cost.USD"])
+	assert.Equal(t, "0.25", operationData["cost.EUR"])
+	assert.NotContains(t, operationData, "currency")
+}
+
+func TestAddEvaluatorModelCost(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "judge", Namespace: "evals"},
+		Spec:       arkv1alpha1.ModelSpec{Pricing: &arkv1alpha1.ModelPricing{InputPerMillion: "1", OutputPerMillion: "4"}},
+	}})
+	parameters := map[string]string{"model.name": "judge", "model.namespace": "evals"}
+
+	usage := &arkv1alpha1.TokenUsage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500}
+	addEvaluatorModelCost(context.Background(), k8sClient, parameters, "default", usage)
+	assert.Equal(t, map[string]string{"USD": "0.003"}, usage.Cost)
+
+	reported := &arkv1alpha1.TokenUsage{PromptTokens: 1000, Cost: map[string]string{"USD": "0.1"}}
+	addEvaluatorModelCost(context.Background(), k8sClient, parameters, "default", reported)
+	assert.Equal(t, map[string]string{"USD": "0.1"}, reported.Cost, "costs reported by the evaluator are kept")
+}
@@ -29,18 +29,29 @@ type ProbeResult struct {
 	Available     bool
 	Message       string // Stable message for status condition
 	DetailedError error  // Full error for logging
+	Latency       time.Duration
 }
 
 // ProbeModel tests if a model is available
 func ProbeModel(ctx context.Context, model *Model) ProbeResult {
+	if model.router != nil {
+		return probeRouter(model.router)
+	}
+
 	timeout := 30 * time.Second
 	probeCtx := contextWithProbeMode(context.Background())
 	probeCtx, cancel := context.WithTimeout(probeCtx, timeout)
 	defer cancel()
 
-	testMessages := []Message{NewUserMessage("Hello")}
-
-	_, err := model.ChatCompletion(probeCtx, testMessages, nil, 1)
+	var err error
+	start := time.Now()
+	if model.IsEmbeddingsModel() {
+		_, err = model.Embed(probeCtx, []string{"Hello"})
+	} else {
+		testMessages := []Message{NewUserMessage("Hello")}
+		_, err = model.ChatCompletion(probeCtx, testMessages, nil, 1)
+	}
+	latency := time.Since(start)
 	if err != nil {
 		return ProbeResult{
 			Available:     false,
@@ -53,6 +64,26 @@ func ProbeModel(ctx context.Context, model *Model) ProbeResult {
 		Available:     true,
 		Message:       "Model is available",
 		DetailedError: nil,
+		Latency:       latency,
+	}
+}
+
+// probeRouter reports a router as available when any of its routes is, from the routes' own
+// probes rather than with a call
+func probeRouter(router *modelRouter) ProbeResult {
+	available := 0
+	for _, route := range router.routes {
+		if route.available {
+			available++
+		}
+	}
+	if available == 0 {
+		err := fmt.Errorf("none of the %d routes is available", len(router.routes))
+		return ProbeResult{Available: false, Message: "No route is available", DetailedError: err}
+	}
+	return ProbeResult{
+		Available: true,
+		Message:   fmt.Sprintf("%d of %d routes are available", available, len(router.routes)),
 	}
 }
 
@@ -72,6 +103,18 @@ func extractStableError(err error, timeout time.Duration) string {
 		return fmt.Sprintf("%s (%d)", openaiErr.Message, openaiErr.StatusCode)
 	}
 
+	// Anthropic API error
+	var anthropicErr *AnthropicError
+	if errors.As(err, &anthropicErr) {
+		return fmt.Sprintf("%s (%d)", anthropicErr.Message, anthropicErr.StatusCode)
+	}
+
+	// Gemini or Vertex AI API error
+	var geminiErr *GeminiError
+	if errors.As(err, &geminiErr) {
+		return fmt.Sprintf("%s (%d)", geminiErr.Message, geminiErr.StatusCode)
+	}
+
 	// AWS Smithy API error with HTTP response
 	var httpErr *smithyhttp.ResponseError
 	if errors.As(err, &httpErr) {
@@ -7,11 +7,14 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/shared"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
+// applyPropertiesToParams sets the model properties on the parameters of a call. The temperature
+// is left to the provider's default unless set, as reasoning models reject any other than 1.
 func applyPropertiesToParams(properties map[string]string, params *openai.ChatCompletionNewParams) {
 	setDefaults := func() {
-		params.Temperature = openai.Float(1.0)
 		params.N = openai.Int(1)
 	}
 
@@ -39,9 +42,6 @@ func applyPropertiesToParams(properties map[string]string, params *openai.ChatCo
 		paramsMap[key] = value
 	}
 
-	if _, exists := properties["temperature"]; !exists {
-		paramsMap["temperature"] = 1.0
-	}
 	if _, exists := properties["n"]; !exists {
 		paramsMap["n"] = 1
 	}
@@ -54,6 +54,14 @@ func applyPropertiesToParams(properties map[string]string, params *openai.ChatCo
 	_ = json.Unmarshal(updatedJSON, params)
 }
 
+// applyReasoningToParams sets the reasoning effort of a model on the parameters of a call
+func applyReasoningToParams(reasoning *arkv1alpha1.ModelReasoning, params *openai.ChatCompletionNewParams) {
+	if reasoning == nil {
+		return
+	}
+	params.ReasoningEffort = shared.ReasoningEffort(reasoning.Effort)
+}
+
 // getFloatProperty extracts a float property with a default value
 func getFloatProperty(properties map[string]string, key string, defaultValue float64) float64 {
 	if value, exists := properties[key]; exists {
@@ -91,3 +99,16 @@ func applyStructuredOutputToParams(outputSchema *runtime.RawExtension, schemaNam
 		}
 	}
 }
+
+// withOutputSchemaInstructions appends the output schema to a system prompt, for providers that
+// have no native structured output parameter
+func withOutputSchemaInstructions(systemPrompt string, outputSchema *runtime.RawExtension) string {
+	if outputSchema == nil || len(outputSchema.Raw) == 0 {
+		return systemPrompt
+	}
+	instructions := "Respond only with a JSON document, without surrounding text or code fences, that matches this JSON schema:\n" + string(outputSchema.Raw)
+	if systemPrompt == "" {
+		return instructions
+	}
+	return systemPrompt + "\n\n" + instructions
+}
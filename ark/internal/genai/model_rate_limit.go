@@ -0,0 +1,233 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// rateLimitWindow is the period requestsPerMinute and tokensPerMinute are counted over
+const rateLimitWindow = time.Minute
+
+// RateLimitWaitError is returned when a call cannot start within the deadline of its context
+// because of the model's rate limits
+type RateLimitWaitError struct {
+	Model  string
+	Waited time.Duration
+}
+
+func (e *RateLimitWaitError) Error() string {
+	return fmt.Sprintf("rate limit queue wait for model %s exceeds the query timeout (waited %s)", e.Model, e.Waited.Round(time.Millisecond))
+}
+
+// rateLimitUsage is a call counted against the limits of the current window
+type rateLimitUsage struct {
+	at     time.Time
+	tokens int64
+}
+
+// rateLimiter enforces the rate limits of a model across every query in the process. Calls that
+// are over a limit wait in a first-come, first-served queue, so a burst of calls from one query
+// cannot overtake calls that were already waiting.
+type rateLimiter struct {
+	mu       sync.Mutex
+	limits   arkv1alpha1.ModelRateLimits
+	queue    []chan struct{}
+	inFlight int
+	window   []*rateLimitUsage
+	now      func() time.Time
+}
+
+var modelRateLimiters = struct {
+	sync.Mutex
+	limiters map[string]*rateLimiter
+}{limiters: map[string]*rateLimiter{}}
+
+// rateLimiterFor returns the shared rate limiter of a model, updated with its current limits, or
+// nil if the model has no limits
+func rateLimiterFor(namespace, name string, limits *arkv1alpha1.ModelRateLimits) *rateLimiter {
+	key := namespace + "/" + name
+
+	modelRateLimiters.Lock()
+	defer modelRateLimiters.Unlock()
+
+	if limits == nil || (limits.RequestsPerMinute == 0 && limits.TokensPerMinute == 0 && limits.MaxConcurrent == 0) {
+		delete(modelRateLimiters.limiters, key)
+		return nil
+	}
+
+	limiter, ok := modelRateLimiters.limiters[key]
+	if !ok {
+		limiter = &rateLimiter{now: time.Now}
+		modelRateLimiters.limiters[key] = limiter
+	}
+
+	limiter.mu.Lock()
+	limiter.limits = *limits
+	limiter.notifyHead()
+	limiter.mu.Unlock()
+	return limiter
+}
+
+// acquire waits until a call using an estimated number of tokens is allowed. It fails without
+// waiting further once the wait is certain to pass the deadline of ctx.
+func (l *rateLimiter) acquire(ctx context.Context, model string, tokens int64) (*rateLimitUsage, time.Duration, error) {
+	start := l.now()
+	ready := make(chan struct{}, 1)
+
+	l.mu.Lock()
+	l.queue = append(l.queue, ready)
+	for {
+		retryAfter, ok := l.admit(ready, tokens)
+		if ok {
+			usage := &rateLimitUsage{at: l.now(), tokens: tokens}
+			l.window = append(l.window, usage)
+			l.inFlight++
+			l.queue = l.queue[1:]
+			l.notifyHead()
+			l.mu.Unlock()
+			return usage, l.now().Sub(start), nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && retryAfter > 0 && l.now().Add(retryAfter).After(deadline) {
+			l.leave(ready)
+			l.mu.Unlock()
+			return nil, l.now().Sub(start), &RateLimitWaitError{Model: model, Waited: l.now().Sub(start)}
+		}
+		l.mu.Unlock()
+
+		var timer *time.Timer
+		var retry <-chan time.Time
+		if retryAfter > 0 {
+			timer = time.NewTimer(retryAfter)
+			retry = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.leave(ready)
+			l.mu.Unlock()
+			waited := l.now().Sub(start)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, waited, &RateLimitWaitError{Model: model, Waited: waited}
+			}
+			return nil, waited, ctx.Err()
+		case <-ready:
+		case <-retry:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		l.mu.Lock()
+	}
+}
+
+// release ends a call, replacing its estimated tokens with the tokens it used when known
+func (l *rateLimiter) release(usage *rateLimitUsage, usedTokens int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if usedTokens > 0 {
+		usage.tokens = usedTokens
+	}
+	l.notifyHead()
+}
+
+// admit reports whether the waiter can start now. If it cannot, retryAfter is how long until a
+// per-minute limit allows it, or zero when it has to wait for another call to finish or leave
+// the queue. Must be called with l.mu held.
+func (l *rateLimiter) admit(ready chan struct{}, tokens int64) (time.Duration, bool) {
+	now := l.now()
+	expired := 0
+	for expired < len(l.window) && now.Sub(l.window[expired].at) >= rateLimitWindow {
+		expired++
+	}
+	l.window = l.window[expired:]
+
+	if l.queue[0] != ready {
+		return 0, false
+	}
+	if l.limits.MaxConcurrent > 0 && l.inFlight >= l.limits.MaxConcurrent {
+		return 0, false
+	}
+
+	var retryAfter time.Duration
+	if rpm := l.limits.RequestsPerMinute; rpm > 0 && len(l.window) >= rpm {
+		retryAfter = l.window[len(l.window)-rpm].at.Add(rateLimitWindow).Sub(now)
+	}
+	if tpm := int64(l.limits.TokensPerMinute); tpm > 0 {
+		var used int64
+		for _, usage := range l.window {
+			used += usage.tokens
+		}
+		// A call estimated above the limit on its own runs once the window is empty
+		for i := 0; used > 0 && used+tokens > tpm; i++ {
+			used -= l.window[i].tokens
+			retryAfter = max(retryAfter, l.window[i].at.Add(rateLimitWindow).Sub(now))
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter, false
+	}
+	return 0, true
+}
+
+// leave removes a waiter that gave up from the queue. Must be called with l.mu held.
+func (l *rateLimiter) leave(ready chan struct{}) {
+	for i, waiter := range l.queue {
+		if waiter == ready {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			break
+		}
+	}
+	l.notifyHead()
+}
+
+// notifyHead wakes the first waiter so it checks the limits again. Must be called with l.mu held.
+func (l *rateLimiter) notifyHead() {
+	if len(l.queue) == 0 {
+		return
+	}
+	select {
+	case l.queue[0] <- struct{}{}:
+	default:
+	}
+}
+
+// waitForRateLimits waits until the model's rate limits allow a call using an estimated number
+// of tokens. The returned function must be called with the tokens the call used once it ends.
+func (m *Model) waitForRateLimits(ctx context.Context, span telemetry.Span, tokens int64) (func(usedTokens int64), error) {
+	if m.rateLimiter == nil {
+		return func(int64) {}, nil
+	}
+
+	usage, waited, err := m.rateLimiter.acquire(ctx, m.Name, tokens)
+	m.telemetryRecorder.RecordRateLimitWait(span, waited)
+	if err != nil {
+		return nil, err
+	}
+	if waited >= time.Second {
+		logf.FromContext(ctx).Info("model call waited for rate limits", "model", m.Name, "waited", waited.String())
+	}
+	return func(usedTokens int64) {
+		m.rateLimiter.release(usage, usedTokens)
+	}, nil
+}
+
+// estimateCallTokens approximates the tokens a chat completion call sends to the model
+func estimateCallTokens(messages []Message, tools [][]openai.ChatCompletionToolParam) int64 {
+	tokens := EstimateTokens(messages)
+	for _, toolSet := range tools {
+		tokens += estimateToolTokens(toolSet)
+	}
+	return int64(tokens)
+}
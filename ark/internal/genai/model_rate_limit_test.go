@@ -0,0 +1,175 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newTestRateLimiter(limits arkv1alpha1.ModelRateLimits) *rateLimiter {
+	return &rateLimiter{limits: limits, now: time.Now}
+}
+
+func TestRateLimiter_RequestsPerMinuteFailsPastDeadline(t *testing.T) {
+	limiter := newTestRateLimiter(arkv1alpha1.ModelRateLimits{RequestsPerMinute: 1})
+	usage, _, err := limiter.acquire(context.Background(), "gpt-4o", 10)
+	require.NoError(t, err)
+	limiter.release(usage, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	started := time.Now()
+	_, _, err = limiter.acquire(ctx, "gpt-4o", 10)
+
+	var waitErr *RateLimitWaitError
+	require.ErrorAs(t, err, &waitErr)
+	assert.Equal(t, "gpt-4o", waitErr.Model)
+	assert.Contains(t, err.Error(), "exceeds the query timeout")
+	assert.Less(t, time.Since(started), 500*time.Millisecond, "should fail without waiting for the deadline")
+	assert.Empty(t, limiter.queue)
+}
+
+func TestRateLimiter_TokensPerMinute(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(arkv1alpha1.ModelRateLimits{TokensPerMinute: 100})
+	limiter.now = func() time.Time { return now }
+
+	usage, _, err := limiter.acquire(context.Background(), "gpt-4o", 80)
+	require.NoError(t, err)
+	// The call used fewer tokens than estimated, which leaves room for the next one
+	limiter.release(usage, 40)
+
+	_, _, err = limiter.acquire(context.Background(), "gpt-4o", 50)
+	require.NoError(t, err)
+
+	retryAfter, ok := limiter.admitNext(20)
+	assert.False(t, ok)
+	assert.Equal(t, rateLimitWindow, retryAfter)
+
+	now = now.Add(rateLimitWindow)
+	_, ok = limiter.admitNext(20)
+	assert.True(t, ok)
+}
+
+func TestRateLimiter_OversizedCallRunsInEmptyWindow(t *testing.T) {
+	limiter := newTestRateLimiter(arkv1alpha1.ModelRateLimits{TokensPerMinute: 100})
+
+	_, _, err := limiter.acquire(context.Background(), "gpt-4o", 500)
+
+	assert.NoError(t, err)
+}
+
+func TestRateLimiter_MaxConcurrentQueuesInOrder(t *testing.T) {
+	limiter := newTestRateLimiter(arkv1alpha1.ModelRateLimits{MaxConcurrent: 1})
+	first, _, err := limiter.acquire(context.Background(), "gpt-4o", 1)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			usage, _, err := limiter.acquire(context.Background(), "gpt-4o", 1)
+			assert.NoError(t, err)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			limiter.release(usage, 1)
+		}()
+		// Join the queue one at a time so the expected order is known
+		require.Eventually(t, func() bool {
+			limiter.mu.Lock()
+			defer limiter.mu.Unlock()
+			return len(limiter.queue) == i
+		}, time.Second, time.Millisecond)
+	}
+
+	limiter.release(first, 1)
+	wg.Wait()
+
+	assert.Equal(t, []int{1, 2, 3}, order)
+	assert.Zero(t, limiter.inFlight)
+}
+
+func TestRateLimiter_CanceledWaiterLeavesQueue(t *testing.T) {
+	limiter := newTestRateLimiter(arkv1alpha1.ModelRateLimits{MaxConcurrent: 1})
+	first, _, err := limiter.acquire(context.Background(), "gpt-4o", 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, _, err := limiter.acquire(ctx, "gpt-4o", 1)
+		done <- err
+	}()
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.queue) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	err = <-done
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Empty(t, limiter.queue)
+
+	limiter.release(first, 1)
+	_, _, err = limiter.acquire(context.Background(), "gpt-4o", 1)
+	assert.NoError(t, err)
+}
+
+func TestRateLimiterFor(t *testing.T) {
+	limiter := rateLimiterFor("default", "limited", &arkv1alpha1.ModelRateLimits{RequestsPerMinute: 10})
+	require.NotNil(t, limiter)
+	t.Cleanup(func() { rateLimiterFor("default", "limited", nil) })
+
+	updated := rateLimiterFor("default", "limited", &arkv1alpha1.ModelRateLimits{RequestsPerMinute: 20})
+	assert.Same(t, limiter, updated)
+	assert.Equal(t, 20, updated.limits.RequestsPerMinute)
+
+	assert.NotSame(t, limiter, rateLimiterFor("other", "limited", &arkv1alpha1.ModelRateLimits{RequestsPerMinute: 20}))
+	t.Cleanup(func() { rateLimiterFor("other", "limited", nil) })
+
+	assert.Nil(t, rateLimiterFor("default", "unlimited", nil))
+	assert.Nil(t, rateLimiterFor("default", "unlimited", &arkv1alpha1.ModelRateLimits{}))
+}
+
+func TestModelChatCompletion_RateLimitWait(t *testing.T) {
+	server := newFakeResponsesServer(t, 200, `{"id": "chatcmpl-1", "object": "chat.completion", "choices": [
+		{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "hi"}}
+	], "usage": {"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12}}`)
+	model := newTestEmbeddingsModel(server.URL, nil)
+	model.Type = ModelTypeCompletions
+	model.rateLimiter = newTestRateLimiter(arkv1alpha1.ModelRateLimits{RequestsPerMinute: 1})
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), model.rateLimiter.window[0].tokens)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+
+	var waitErr *RateLimitWaitError
+	assert.ErrorAs(t, err, &waitErr)
+	assert.Len(t, server.requests, 1)
+}
+
+// admitNext checks the limits for a call at the head of an empty queue
+func (l *rateLimiter) admitNext(tokens int64) (time.Duration, bool) {
+	ready := make(chan struct{}, 1)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue = append(l.queue, ready)
+	defer func() { l.queue = l.queue[:len(l.queue)-1] }()
+	return l.admit(ready, tokens)
+}
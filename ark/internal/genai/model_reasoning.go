@@ -0,0 +1,160 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ReasoningProvider is implemented by providers that can make reasoning models reason before
+// answering
+type ReasoningProvider interface {
+	SetReasoning(reasoning *arkv1alpha1.ModelReasoning)
+}
+
+// reasoningBudgets are the default thinking budgets of the reasoning efforts, for providers that
+// take a budget instead of an effort
+var reasoningBudgets = map[string]int{
+	"low":    1024,
+	"medium": 4096,
+	"high":   16384,
+}
+
+// reasoningBudget returns the number of tokens a model may spend reasoning
+func reasoningBudget(reasoning *arkv1alpha1.ModelReasoning) int {
+	if reasoning.BudgetTokens > 0 {
+		return reasoning.BudgetTokens
+	}
+	if budget, ok := reasoningBudgets[reasoning.Effort]; ok {
+		return budget
+	}
+	return reasoningBudgets["medium"]
+}
+
+// reasoningMaxTokens returns the maximum number of tokens of a response with thinking enabled,
+// which must leave room for the answer after the thinking budget
+func reasoningMaxTokens(maxTokens, budget int) int {
+	return max(maxTokens, budget+anthropicDefaultMaxTokens)
+}
+
+// Extra fields of assistant messages that hold the thinking blocks of the response the message was
+// built from. Anthropic and Bedrock require the blocks back with the message when the model
+// continues after calling tools, and carrying them on the message keeps them with the conversation
+// they belong to.
+const (
+	anthropicThinkingField = "anthropic_thinking"
+	bedrockReasoningField  = "bedrock_reasoning"
+)
+
+var thinkingFields = []string{anthropicThinkingField, bedrockReasoningField}
+
+// withThinkingBlocks returns message with blocks stored in the extra field name, which is kept on
+// the assistant message built from it by assistantMessageFromCompletion
+func withThinkingBlocks(message openai.ChatCompletionMessage, name string, blocks any) openai.ChatCompletionMessage {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return message
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return message
+	}
+	if fields[name], err = json.Marshal(blocks); err != nil {
+		return message
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return message
+	}
+	var withBlocks openai.ChatCompletionMessage
+	if err := json.Unmarshal(data, &withBlocks); err != nil {
+		return message
+	}
+	return withBlocks
+}
+
+// assistantMessageFromCompletion converts a response message to an assistant message, keeping the
+// thinking blocks a provider stored on it
+func assistantMessageFromCompletion(message openai.ChatCompletionMessage) Message {
+	assistant := message.ToAssistantMessageParam()
+	extras := map[string]any{}
+	for _, name := range thinkingFields {
+		if raw := message.JSON.ExtraFields[name].Raw(); raw != "" {
+			extras[name] = json.RawMessage(raw)
+		}
+	}
+	if len(extras) > 0 {
+		assistant.SetExtraFields(extras)
+	}
+	return Message{OfAssistant: &assistant}
+}
+
+// thinkingBlocks decodes the thinking blocks stored in the extra field name of an assistant
+// message into blocks, reporting whether there were any
+func thinkingBlocks(assistant *openai.ChatCompletionAssistantMessageParam, name string, blocks any) bool {
+	value, ok := assistant.ExtraFields()[name]
+	if !ok {
+		return false
+	}
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(raw, blocks) == nil
+}
+
+// withoutThinkingBlocks returns msg without the thinking blocks of any provider, for APIs and
+// stores that do not take them
+func withoutThinkingBlocks(msg Message) openai.ChatCompletionMessageParamUnion {
+	union := openai.ChatCompletionMessageParamUnion(msg)
+	if union.OfAssistant == nil || len(union.OfAssistant.ExtraFields()) == 0 {
+		return union
+	}
+	assistant := *union.OfAssistant
+	assistant.SetExtraFields(nil)
+	union.OfAssistant = &assistant
+	return union
+}
+
+type reasoningSummaryKey struct{}
+
+// reasoningSummary collects the reasoning summaries a provider returns during a model call
+type reasoningSummary struct {
+	mu    sync.Mutex
+	parts []string
+}
+
+// contextWithReasoningSummary returns a context in which providers report the summaries of the
+// model's reasoning to the returned collector
+func contextWithReasoningSummary(ctx context.Context) (context.Context, *reasoningSummary) {
+	summary := &reasoningSummary{}
+	return context.WithValue(ctx, reasoningSummaryKey{}, summary), summary
+}
+
+// addReasoningSummary reports a summary of the model's reasoning for the current model call
+func addReasoningSummary(ctx context.Context, text string) {
+	summary, ok := ctx.Value(reasoningSummaryKey{}).(*reasoningSummary)
+	if !ok || strings.TrimSpace(text) == "" {
+		return
+	}
+	summary.mu.Lock()
+	defer summary.mu.Unlock()
+	summary.parts = append(summary.parts, strings.TrimSpace(text))
+}
+
+func (s *reasoningSummary) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.parts, "\n\n")
+}
+
+// reset discards the summaries of a failed attempt before the call is retried
+func (s *reasoningSummary) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parts = nil
+}
@@ -0,0 +1,97 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// reasoningRecorder records the reasoning summaries and token details of model calls
+type reasoningRecorder struct {
+	telemetry.ModelRecorder
+	summaries       []string
+	reasoningTokens int64
+	cachedTokens    int64
+}
+
+func (r *reasoningRecorder) RecordReasoningSummary(span telemetry.Span, summary string) {
+	r.summaries = append(r.summaries, summary)
+}
+
+func (r *reasoningRecorder) RecordTokenDetails(span telemetry.Span, reasoningTokens, cachedTokens int64) {
+	r.reasoningTokens += reasoningTokens
+	r.cachedTokens += cachedTokens
+}
+
+// thinkingProvider reports a reasoning summary on every call and fails the first call
+type thinkingProvider struct {
+	calls int
+}
+
+func (p *thinkingProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.calls++
+	if p.calls == 1 {
+		addReasoningSummary(ctx, "discarded")
+		return nil, openAIStatusError(http.StatusServiceUnavailable)
+	}
+	addReasoningSummary(ctx, "The user greets me.")
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: "assistant", Content: "hi"}}},
+		Usage: openai.CompletionUsage{
+			PromptTokens:            10,
+			CompletionTokens:        50,
+			TotalTokens:             60,
+			CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{ReasoningTokens: 40},
+			PromptTokensDetails:     openai.CompletionUsagePromptTokensDetails{CachedTokens: 8},
+		},
+	}, nil
+}
+
+func (p *thinkingProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *thinkingProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func TestModelChatCompletion_RecordsReasoning(t *testing.T) {
+	recorder := &reasoningRecorder{ModelRecorder: noop.NewModelRecorder()}
+	model := newTestRetryModel(&thinkingProvider{}, recorder)
+	ctx := model.eventingRecorder.StartTokenCollection(context.Background())
+
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"The user greets me."}, recorder.summaries, "summaries of failed attempts are discarded")
+	assert.Equal(t, int64(40), recorder.reasoningTokens)
+	assert.Equal(t, int64(8), recorder.cachedTokens)
+	usage := model.eventingRecorder.GetTokenSummary(ctx)
+	assert.Equal(t, int64(40), usage.ReasoningTokens)
+	assert.Equal(t, int64(8), usage.CachedTokens)
+}
+
+func TestReasoningBudget(t *testing.T) {
+	assert.Equal(t, 1024, reasoningBudget(&arkv1alpha1.ModelReasoning{Effort: "low"}))
+	assert.Equal(t, 16384, reasoningBudget(&arkv1alpha1.ModelReasoning{Effort: "high"}))
+	assert.Equal(t, 2000, reasoningBudget(&arkv1alpha1.ModelReasoning{Effort: "high", BudgetTokens: 2000}))
+	assert.Equal(t, 1024+anthropicDefaultMaxTokens, reasoningMaxTokens(0, 1024))
+	assert.Equal(t, 64000, reasoningMaxTokens(64000, 1024))
+}
+
+func TestApplyReasoningToParams(t *testing.T) {
+	params := openai.ChatCompletionNewParams{}
+	applyPropertiesToParams(map[string]string{"max_tokens": "100"}, &params)
+	applyReasoningToParams(&arkv1alpha1.ModelReasoning{Effort: "high"}, &params)
+
+	assert.False(t, params.Temperature.Valid(), "reasoning models reject temperatures other than the default")
+	assert.Equal(t, shared.ReasoningEffortHigh, params.ReasoningEffort)
+}
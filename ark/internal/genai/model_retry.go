@@ -0,0 +1,202 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// retryPolicy is a model's retry policy with defaults applied. The zero value makes a single
+// attempt.
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// retryPolicyFromSpec applies the defaults to a model's retry policy. Models without one are retried
+// with the defaults.
+func retryPolicyFromSpec(spec *arkv1alpha1.ModelRetryPolicy) retryPolicy {
+	policy := retryPolicy{
+		maxAttempts:    defaultRetryMaxAttempts,
+		initialBackoff: defaultRetryInitialBackoff,
+		maxBackoff:     defaultRetryMaxBackoff,
+	}
+	if spec == nil {
+		return policy
+	}
+	if spec.MaxAttempts > 0 {
+		policy.maxAttempts = spec.MaxAttempts
+	}
+	if spec.InitialBackoff != nil {
+		policy.initialBackoff = spec.InitialBackoff.Duration
+	}
+	if spec.MaxBackoff != nil {
+		policy.maxBackoff = spec.MaxBackoff.Duration
+	}
+	return policy
+}
+
+// delay returns how long to wait before the attempt after the given one. The provider's
+// Retry-After headers take precedence over the backoff, up to the maximum backoff.
+func (p retryPolicy) delay(attempt int, err error) time.Duration {
+	if retryAfter, ok := retryAfterFromError(err); ok {
+		return min(retryAfter, p.maxBackoff)
+	}
+
+	backoff := p.initialBackoff
+	for i := 1; i < attempt && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, p.maxBackoff)
+	// Half of the backoff is fixed and half is random, so calls that failed together spread out
+	return backoff/2 + rand.N(backoff/2+1)
+}
+
+// Failed calls are retried by the model with its retry policy, so the provider clients must not
+// retry them as well
+var (
+	openAIClientWithoutRetries  = option.WithMaxRetries(0)
+	bedrockClientWithoutRetries = func(o *bedrockruntime.Options) { o.Retryer = aws.NopRetryer{} }
+)
+
+// callWithRetry makes a model call, repeating it while it fails with a retryable error, attempts
+// remain and the delay before the next attempt ends before the deadline of ctx. canRetry reports
+// whether the failed call may be repeated, which a streaming call that sent chunks may not.
+// Probes make a single attempt so they report the model's current state.
+func (m *Model) callWithRetry(ctx context.Context, span telemetry.Span, canRetry func() bool, call func() error) error {
+	maxAttempts := max(m.retryPolicy.maxAttempts, 1)
+	if IsProbeContext(ctx) {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= maxAttempts || ctx.Err() != nil || !isRetryableAttempt(err) || !canRetry() {
+			return err
+		}
+
+		delay := m.retryPolicy.delay(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		logf.FromContext(ctx).Info("model call failed, retrying", "model", m.Name, "attempt", attempt, "delay", delay.String(), "error", err.Error())
+		m.telemetryRecorder.RecordRetry(span, attempt, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryableAttempt reports whether a failed call can be made again to the same model. Calls
+// that could not get past the model's rate limits in time are not, though a fallback model may
+// still serve them.
+func isRetryableAttempt(err error) bool {
+	var waitErr *RateLimitWaitError
+	if errors.As(err, &waitErr) {
+		return false
+	}
+	return IsRetryableModelError(err)
+}
+
+// retryAfterFromError returns the delay the provider asked for in the headers of a failed call
+func retryAfterFromError(err error) (time.Duration, bool) {
+	var header http.Header
+	var statusCode int
+
+	var openaiErr *openai.Error
+	var anthropicErr *AnthropicError
+	var geminiErr *GeminiError
+	var smithyErr interface{ HTTPResponse() *smithyhttp.Response }
+	switch {
+	case errors.As(err, &openaiErr) && openaiErr.Response != nil:
+		header, statusCode = openaiErr.Response.Header, openaiErr.StatusCode
+	case errors.As(err, &anthropicErr):
+		header, statusCode = anthropicErr.Header, anthropicErr.StatusCode
+	case errors.As(err, &geminiErr):
+		header, statusCode = geminiErr.Header, geminiErr.StatusCode
+	case errors.As(err, &smithyErr) && smithyErr.HTTPResponse() != nil:
+		header, statusCode = smithyErr.HTTPResponse().Header, smithyErr.HTTPResponse().StatusCode
+	}
+	return retryAfterFromHeader(header, statusCode == http.StatusTooManyRequests)
+}
+
+// retryAfterFromHeader reads the retry-after-ms and Retry-After headers. For rate limited calls
+// it falls back to the x-ratelimit-reset headers of the exhausted limits, which OpenAI sends as
+// durations such as "6m0s" and other providers as seconds.
+func retryAfterFromHeader(header http.Header, rateLimited bool) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+
+	if value := header.Get("retry-after-ms"); value != "" {
+		if ms, err := strconv.ParseFloat(value, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+	if !rateLimited {
+		return 0, false
+	}
+
+	if reset, ok := parseRateLimitReset(header.Get("x-ratelimit-reset")); ok {
+		return reset, true
+	}
+	var reset time.Duration
+	found := false
+	for _, limit := range []string{"requests", "tokens"} {
+		if header.Get("x-ratelimit-remaining-"+limit) != "0" {
+			continue
+		}
+		if duration, ok := parseRateLimitReset(header.Get("x-ratelimit-reset-" + limit)); ok {
+			reset = max(reset, duration)
+			found = true
+		}
+	}
+	return reset, found
+}
+
+func parseRateLimitReset(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration, true
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	return 0, false
+}
@@ -0,0 +1,246 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+const testChatCompletion = `{"id": "chatcmpl-1", "object": "chat.completion", "choices": [
+	{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "hi"}}
+], "usage": {"prompt_tokens": 10, "completion_tokens": 2, "total_tokens": 12}}`
+
+// retryRecorder records the attempts reported by RecordRetry
+type retryRecorder struct {
+	telemetry.ModelRecorder
+	attempts []int
+}
+
+func (r *retryRecorder) RecordRetry(span telemetry.Span, attempt int, delay time.Duration, err error) {
+	r.attempts = append(r.attempts, attempt)
+}
+
+// flakyProvider fails with the given errors in turn, sending a chunk before failing if
+// chunkFirst is set, then succeeds
+type flakyProvider struct {
+	errs       []error
+	chunkFirst bool
+	calls      int
+}
+
+func (p *flakyProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "hi"}}}}, nil
+}
+
+func (p *flakyProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if p.chunkFirst {
+		if err := streamFunc(&openai.ChatCompletionChunk{Choices: []openai.ChatCompletionChunkChoice{{Delta: openai.ChatCompletionChunkChoiceDelta{Content: "h"}}}}); err != nil {
+			return nil, err
+		}
+	}
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *flakyProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func newTestRetryModel(provider ChatCompletionProvider, recorder telemetry.ModelRecorder) *Model {
+	return &Model{
+		Name:              "gpt-4o",
+		Model:             "gpt-4o",
+		Type:              ModelTypeCompletions,
+		Provider:          provider,
+		retryPolicy:       retryPolicy{maxAttempts: 3, initialBackoff: time.Millisecond, maxBackoff: 10 * time.Millisecond},
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+}
+
+// discardStream accepts every chunk
+type discardStream struct{}
+
+func (discardStream) StreamChunk(ctx context.Context, chunk interface{}) error { return nil }
+func (discardStream) NotifyCompletion(ctx context.Context) error               { return nil }
+func (discardStream) Close() error                                             { return nil }
+
+func TestModelChatCompletion_RetriesWithRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("retry-after-ms", "20")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "code": "rate_limit_exceeded"}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, testChatCompletion)
+	}))
+	t.Cleanup(server.Close)
+	recorder := &retryRecorder{ModelRecorder: noop.NewModelRecorder()}
+	model := newTestRetryModel(&OpenAIProvider{Model: "gpt-4o", BaseURL: server.URL, APIKey: "test-key"}, recorder)
+	model.retryPolicy.initialBackoff = time.Hour
+	model.retryPolicy.maxBackoff = time.Hour
+
+	started := time.Now()
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi", response.Choices[0].Message.Content)
+	assert.Equal(t, 2, calls, "the client must not retry on its own")
+	assert.Equal(t, []int{1}, recorder.attempts)
+	assert.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+}
+
+func TestModelChatCompletion_RetryAttempts(t *testing.T) {
+	serverErr := &AnthropicError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+
+	tests := []struct {
+		name          string
+		provider      *flakyProvider
+		stream        bool
+		ctx           context.Context
+		expectedCalls int
+		expectedErr   bool
+	}{
+		{name: "retries server errors", provider: &flakyProvider{errs: []error{serverErr, serverErr}}, expectedCalls: 3},
+		{name: "stops after max attempts", provider: &flakyProvider{errs: []error{serverErr, serverErr, serverErr}}, expectedCalls: 3, expectedErr: true},
+		{name: "does not retry client errors", provider: &flakyProvider{errs: []error{&AnthropicError{StatusCode: http.StatusBadRequest}}}, expectedCalls: 1, expectedErr: true},
+		{name: "retries streams before the first chunk", provider: &flakyProvider{errs: []error{serverErr}}, stream: true, expectedCalls: 2},
+		{name: "does not retry streams after a chunk", provider: &flakyProvider{errs: []error{serverErr}, chunkFirst: true}, stream: true, expectedCalls: 1, expectedErr: true},
+		{name: "does not retry probes", provider: &flakyProvider{errs: []error{serverErr}}, ctx: contextWithProbeMode(context.Background()), expectedCalls: 1, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newTestRetryModel(tt.provider, noop.NewModelRecorder())
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			var stream EventStreamInterface
+			if tt.stream {
+				stream = discardStream{}
+			}
+
+			_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, stream, 1)
+
+			assert.Equal(t, tt.expectedCalls, tt.provider.calls)
+			assert.Equal(t, tt.expectedErr, err != nil)
+		})
+	}
+}
+
+func TestModelChatCompletion_NoRetryPastDeadline(t *testing.T) {
+	provider := &flakyProvider{errs: []error{&GeminiError{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}}}}
+	model := newTestRetryModel(provider, noop.NewModelRecorder())
+	model.retryPolicy.maxBackoff = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := model.ChatCompletion(ctx, []Message{NewUserMessage("hello")}, nil, 1)
+
+	var geminiErr *GeminiError
+	assert.ErrorAs(t, err, &geminiErr)
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestBedrockChatCompletion_RetriesThrottling(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("X-Amzn-Errortype", "ThrottlingException")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"message": "Too many requests, please wait before trying again."}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{
+			"output": {"message": {"role": "assistant", "content": [{"text": "hi"}]}},
+			"stopReason": "end_turn",
+			"usage": {"inputTokens": 5, "outputTokens": 1, "totalTokens": 6}
+		}`)
+	}))
+	t.Cleanup(server.Close)
+	model := newTestRetryModel(newTestBedrockModel("meta.llama3-1-70b-instruct-v1:0", server.URL), noop.NewModelRecorder())
+
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hello")}, nil, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi", response.Choices[0].Message.Content)
+	assert.Equal(t, 2, calls, "the SDK must not retry on its own")
+}
+
+func TestRetryAfterFromHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		rateLimited bool
+		expected    time.Duration
+		found       bool
+	}{
+		{name: "no headers", header: http.Header{}, rateLimited: true},
+		{name: "retry-after-ms", header: http.Header{"Retry-After-Ms": {"1500"}}, expected: 1500 * time.Millisecond, found: true},
+		{name: "retry-after seconds", header: http.Header{"Retry-After": {"2"}}, expected: 2 * time.Second, found: true},
+		{name: "retry-after-ms takes precedence", header: http.Header{"Retry-After-Ms": {"100"}, "Retry-After": {"2"}}, expected: 100 * time.Millisecond, found: true},
+		{name: "exhausted limit reset", header: http.Header{
+			"X-Ratelimit-Remaining-Requests": {"10"}, "X-Ratelimit-Reset-Requests": {"1s"},
+			"X-Ratelimit-Remaining-Tokens": {"0"}, "X-Ratelimit-Reset-Tokens": {"6m0s"},
+		}, rateLimited: true, expected: 6 * time.Minute, found: true},
+		{name: "reset in seconds", header: http.Header{"X-Ratelimit-Reset": {"3"}}, rateLimited: true, expected: 3 * time.Second, found: true},
+		{name: "reset ignored without rate limit", header: http.Header{"X-Ratelimit-Remaining-Tokens": {"0"}, "X-Ratelimit-Reset-Tokens": {"6m0s"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, found := retryAfterFromHeader(tt.header, tt.rateLimited)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, delay)
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicyFromSpec(&arkv1alpha1.ModelRetryPolicy{
+		InitialBackoff: &metav1.Duration{Duration: time.Second},
+		MaxBackoff:     &metav1.Duration{Duration: 4 * time.Second},
+	})
+	assert.Equal(t, defaultRetryMaxAttempts, policy.maxAttempts)
+
+	err := errors.New("connection refused")
+	for attempt, backoff := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 4 * time.Second} {
+		delay := policy.delay(attempt, err)
+		assert.GreaterOrEqual(t, delay, backoff/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, backoff, "attempt %d", attempt)
+	}
+
+	rateLimited := openAIStatusError(http.StatusTooManyRequests).(*openai.Error)
+	rateLimited.Response.Header = http.Header{"Retry-After": []string{"60"}}
+	assert.Equal(t, 4*time.Second, policy.delay(1, rateLimited), "the provider's delay is capped at the maximum backoff")
+}
+
+func TestRetryPolicyFromSpec_NoPolicy(t *testing.T) {
+	policy := retryPolicyFromSpec(nil)
+	assert.Equal(t, defaultRetryMaxAttempts, policy.maxAttempts)
+	assert.Equal(t, defaultRetryInitialBackoff, policy.initialBackoff)
+	assert.Equal(t, defaultRetryMaxBackoff, policy.maxBackoff)
+}
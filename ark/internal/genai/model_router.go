@@ -0,0 +1,238 @@
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/openai/openai-go"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/eventing"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// modelRoute is a model a router dispatches calls to
+type modelRoute struct {
+	model     *Model
+	weight    int
+	when      *arkv1alpha1.ModelRouteCondition
+	available bool
+}
+
+// modelRouter dispatches the calls of a router model to its routes
+type modelRouter struct {
+	routes []modelRoute
+	// intN returns a random number in [0, n), for calls made outside of a query
+	intN func(n int) int
+}
+
+// loadRouterModel loads a router model and the models of its routes. Routes that fail to load
+// are skipped, and loading fails when none is left.
+func loadRouterModel(ctx context.Context, k8sClient client.Client, modelCRD *arkv1alpha1.Model, additionalHeaders map[string]string, telemetryRecorder telemetry.ModelRecorder, eventingRecorder eventing.ModelRecorder) (*Model, error) {
+	if modelCRD.Spec.Router == nil || len(modelCRD.Spec.Router.Routes) == 0 {
+		return nil, fmt.Errorf("router model %s has no routes", modelCRD.Name)
+	}
+
+	log := logf.FromContext(ctx)
+	router := &modelRouter{intN: rand.IntN}
+	var loadErrs []error
+	for i := range modelCRD.Spec.Router.Routes {
+		spec := &modelCRD.Spec.Router.Routes[i]
+		route, err := loadModelRoute(ctx, k8sClient, spec, modelCRD.Namespace, additionalHeaders, telemetryRecorder, eventingRecorder)
+		if err != nil {
+			log.Info("skipping route that failed to load", "router", modelCRD.Name, "model", spec.ModelRef.Name, "error", err.Error())
+			loadErrs = append(loadErrs, fmt.Errorf("route to model %s: %w", spec.ModelRef.Name, err))
+			continue
+		}
+		router.routes = append(router.routes, route)
+	}
+	if len(router.routes) == 0 {
+		return nil, fmt.Errorf("router model %s has no route that loads: %w", modelCRD.Name, errors.Join(loadErrs...))
+	}
+
+	return &Model{
+		Name:              modelCRD.Name,
+		Model:             modelCRD.Name,
+		Type:              ModelTypeRouter,
+		router:            router,
+		telemetryRecorder: telemetryRecorder,
+		eventingRecorder:  eventingRecorder,
+	}, nil
+}
+
+func loadModelRoute(ctx context.Context, k8sClient client.Client, spec *arkv1alpha1.ModelRoute, namespace string, additionalHeaders map[string]string, telemetryRecorder telemetry.ModelRecorder, eventingRecorder eventing.ModelRecorder) (modelRoute, error) {
+	modelName, modelNamespace, err := ResolveModelSpec(&spec.ModelRef, namespace)
+	if err != nil {
+		return modelRoute{}, err
+	}
+	modelCRD, err := loadModelCRD(ctx, k8sClient, modelName, modelNamespace)
+	if err != nil {
+		return modelRoute{}, err
+	}
+	switch modelCRD.Spec.Type {
+	case ModelTypeRouter:
+		return modelRoute{}, fmt.Errorf("model %s is a router and cannot be routed to", modelName)
+	case ModelTypeEmbeddings:
+		return modelRoute{}, fmt.Errorf("model %s is an embeddings model and does not support chat completions", modelName)
+	}
+
+	model, err := LoadModel(ctx, k8sClient, &spec.ModelRef, namespace, additionalHeaders, telemetryRecorder, eventingRecorder)
+	if err != nil {
+		return modelRoute{}, err
+	}
+	return modelRoute{
+		model:     model,
+		weight:    max(spec.Weight, 1),
+		when:      spec.When,
+		available: !modelProbeFailed(modelCRD),
+	}, nil
+}
+
+// matches reports whether a call with the estimated number of prompt tokens meets the route's
+// conditions
+func (r modelRoute) matches(promptTokens int, hasTools bool) bool {
+	if r.when == nil {
+		return true
+	}
+	if promptTokens < r.when.MinPromptTokens {
+		return false
+	}
+	if r.when.MaxPromptTokens > 0 && promptTokens > r.when.MaxPromptTokens {
+		return false
+	}
+	return r.when.Tools == nil || *r.when.Tools == hasTools
+}
+
+// routingKey identifies the conversation a call belongs to: the session of its query, or the
+// query itself. Calls with the same key are routed the same way, so a conversation is not split
+// across models. It returns "" for calls made outside of a query.
+func routingKey(ctx context.Context) string {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok || query == nil {
+		return ""
+	}
+	if query.Spec.SessionId != "" {
+		return query.Namespace + "/session/" + query.Spec.SessionId
+	}
+	return query.Namespace + "/query/" + query.Name + "/" + string(query.UID)
+}
+
+// routerFor returns the router to use for calls with the given routing key, which orders the
+// routes the same way for every call with that key
+func (r *modelRouter) routerFor(routerName, key string) *modelRouter {
+	if key == "" {
+		return r
+	}
+	seed := sha256.Sum256([]byte(routerName + "|" + key))
+	random := rand.New(rand.NewPCG(binary.BigEndian.Uint64(seed[:8]), binary.BigEndian.Uint64(seed[8:16])))
+	return &modelRouter{routes: r.routes, intN: random.IntN}
+}
+
+// candidates returns the routes matching a call in the order they are tried: available routes
+// first, each group in a random order weighted by the routes' weights
+func (r *modelRouter) candidates(promptTokens int, hasTools bool) []modelRoute {
+	var available, unavailable []modelRoute
+	for _, route := range r.routes {
+		if !route.matches(promptTokens, hasTools) {
+			continue
+		}
+		if route.available {
+			available = append(available, route)
+		} else {
+			unavailable = append(unavailable, route)
+		}
+	}
+	return append(r.shuffle(available), r.shuffle(unavailable)...)
+}
+
+// shuffle orders routes by repeatedly picking one of the remaining routes, with a probability
+// proportional to its weight
+func (r *modelRouter) shuffle(routes []modelRoute) []modelRoute {
+	ordered := make([]modelRoute, 0, len(routes))
+	for len(routes) > 0 {
+		total := 0
+		for _, route := range routes {
+			total += route.weight
+		}
+		pick := r.intN(total)
+		i := 0
+		for pick >= routes[i].weight {
+			pick -= routes[i].weight
+			i++
+		}
+		ordered = append(ordered, routes[i])
+		routes = slices.Delete(routes, i, i+1)
+	}
+	return ordered
+}
+
+// routeChatCompletion dispatches a call of a router model to the first of the routes matching
+// the call, moving on to the next while calls fail with a retryable error. The routes are picked
+// once per query, or per session for queries that have one. fallbackFor names the model that
+// failed before this call, if any.
+func (m *Model) routeChatCompletion(ctx context.Context, messages []Message, eventStream EventStreamInterface, n int64, fallbackFor string, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	ctx, span := m.telemetryRecorder.StartModelExecution(ctx, m.Name, m.Type)
+	defer span.End()
+	if fallbackFor != "" {
+		m.telemetryRecorder.RecordFallback(span, fallbackFor)
+	}
+
+	promptTokens := 0
+	for _, msg := range messages {
+		promptTokens += estimateMessageTokens(msg)
+	}
+	hasTools := slices.ContainsFunc(tools, func(t []openai.ChatCompletionToolParam) bool { return len(t) > 0 })
+
+	candidates := m.router.routerFor(m.Name, routingKey(ctx)).candidates(promptTokens, hasTools)
+	if len(candidates) == 0 {
+		err := fmt.Errorf("router %s has no route matching a call with about %d prompt tokens (tools: %t)", m.Name, promptTokens, hasTools)
+		m.telemetryRecorder.RecordError(span, err)
+		return nil, err
+	}
+
+	totalWeight := 0
+	for _, route := range candidates {
+		totalWeight += route.weight
+	}
+
+	var response *openai.ChatCompletion
+	var err error
+	for i, route := range candidates {
+		reason := fmt.Sprintf("weight %d of %d across %d matching routes", route.weight, totalWeight, len(candidates))
+		previous := ""
+		if i > 0 {
+			if err == nil || ctx.Err() != nil || !IsRetryableModelError(err) {
+				break
+			}
+			previous = candidates[i-1].model.Name
+			logf.FromContext(ctx).Info("routed model call failed, trying next route", "router", m.Name, "model", previous, "next", route.model.Name, "error", err.Error())
+			m.eventingRecorder.ModelFallback(ctx, previous, route.model.Name, err.Error())
+			reason = fmt.Sprintf("model %s failed", previous)
+		}
+		if !route.available {
+			reason += ", no available model matches the call"
+		}
+
+		m.telemetryRecorder.RecordRoute(span, route.model.Name)
+		m.eventingRecorder.ModelRouted(ctx, m.Name, route.model.Name, reason)
+
+		route.model.OutputSchema = m.OutputSchema
+		route.model.SchemaName = m.SchemaName
+		route.model.Provider.SetOutputSchema(m.OutputSchema, m.SchemaName)
+		response, err = route.model.chatCompletion(ctx, messages, eventStream, n, previous, tools...)
+	}
+
+	if err != nil {
+		m.telemetryRecorder.RecordError(span, err)
+		return nil, err
+	}
+	m.telemetryRecorder.RecordSuccess(span)
+	return response, nil
+}
@@ -0,0 +1,220 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// routeRecorder records the models reported by RecordRoute
+type routeRecorder struct {
+	telemetry.ModelRecorder
+	routes []string
+}
+
+func (r *routeRecorder) RecordRoute(span telemetry.Span, model string) {
+	r.routes = append(r.routes, model)
+}
+
+func newTestRouterModel(recorder telemetry.ModelRecorder, intN func(int) int, routes ...modelRoute) *Model {
+	return &Model{
+		Name:              "router",
+		Model:             "router",
+		Type:              ModelTypeRouter,
+		router:            &modelRouter{routes: routes, intN: intN},
+		telemetryRecorder: recorder,
+		eventingRecorder:  eventnoop.NewModelRecorder(),
+	}
+}
+
+func newTestRoute(name string, provider ChatCompletionProvider, weight int) modelRoute {
+	return modelRoute{model: newFallbackTestModel(name, provider), weight: weight, available: true}
+}
+
+func routeNames(routes []modelRoute) []string {
+	names := make([]string, len(routes))
+	for i, route := range routes {
+		names[i] = route.model.Name
+	}
+	return names
+}
+
+func TestModelRouteMatches(t *testing.T) {
+	withTools, withoutTools := true, false
+	tests := []struct {
+		name         string
+		when         *arkv1alpha1.ModelRouteCondition
+		promptTokens int
+		hasTools     bool
+		matches      bool
+	}{
+		{name: "no conditions", when: nil, promptTokens: 5000, hasTools: true, matches: true},
+		{name: "short prompt under max", when: &arkv1alpha1.ModelRouteCondition{MaxPromptTokens: 1000}, promptTokens: 200, matches: true},
+		{name: "long prompt over max", when: &arkv1alpha1.ModelRouteCondition{MaxPromptTokens: 1000}, promptTokens: 1500, matches: false},
+		{name: "long prompt over min", when: &arkv1alpha1.ModelRouteCondition{MinPromptTokens: 1000}, promptTokens: 1500, matches: true},
+		{name: "short prompt under min", when: &arkv1alpha1.ModelRouteCondition{MinPromptTokens: 1000}, promptTokens: 200, matches: false},
+		{name: "tools required", when: &arkv1alpha1.ModelRouteCondition{Tools: &withTools}, hasTools: false, matches: false},
+		{name: "tools offered", when: &arkv1alpha1.ModelRouteCondition{Tools: &withTools}, hasTools: true, matches: true},
+		{name: "tools excluded", when: &arkv1alpha1.ModelRouteCondition{Tools: &withoutTools}, hasTools: true, matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := modelRoute{when: tt.when}
+			assert.Equal(t, tt.matches, route.matches(tt.promptTokens, tt.hasTools))
+		})
+	}
+}
+
+func TestModelRouterCandidates(t *testing.T) {
+	withTools := true
+	routes := []modelRoute{
+		newTestRoute("a", nil, 1),
+		newTestRoute("b", nil, 3),
+		newTestRoute("c", nil, 1),
+		{model: newFallbackTestModel("tools-only", nil), weight: 1, available: true, when: &arkv1alpha1.ModelRouteCondition{Tools: &withTools}},
+	}
+	routes[2].available = false
+
+	// Picks of 0 select the first remaining route and picks of 1 to 3 the route of weight 3
+	router := &modelRouter{routes: routes, intN: func(int) int { return 0 }}
+	assert.Equal(t, []string{"a", "b", "c"}, routeNames(router.candidates(10, false)))
+
+	router.intN = func(n int) int { return min(1, n-1) }
+	assert.Equal(t, []string{"b", "a", "c"}, routeNames(router.candidates(10, false)),
+		"unavailable routes are tried after the available ones")
+
+	router.intN = func(n int) int { return n - 1 }
+	assert.Equal(t, []string{"tools-only", "b", "a", "c"}, routeNames(router.candidates(10, true)))
+}
+
+func TestModelRouterCandidates_WeightedShares(t *testing.T) {
+	router := &modelRouter{routes: []modelRoute{newTestRoute("a", nil, 1), newTestRoute("b", nil, 3)}}
+	picks := map[string]int{}
+	for pick := range 4 {
+		router.intN = func(n int) int { return min(pick, n-1) }
+		picks[router.candidates(0, false)[0].model.Name]++
+	}
+	assert.Equal(t, map[string]int{"a": 1, "b": 3}, picks)
+}
+
+func TestModelRouterRoutingKey(t *testing.T) {
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "default", UID: "uid-1"}}
+	followUp := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q2", Namespace: "default", UID: "uid-2"}}
+	assert.Empty(t, routingKey(context.Background()))
+	assert.NotEqual(t, routingKey(context.WithValue(context.Background(), QueryContextKey, query)),
+		routingKey(context.WithValue(context.Background(), QueryContextKey, followUp)))
+
+	query.Spec.SessionId = "session-1"
+	followUp.Spec.SessionId = "session-1"
+	assert.Equal(t, routingKey(context.WithValue(context.Background(), QueryContextKey, query)),
+		routingKey(context.WithValue(context.Background(), QueryContextKey, followUp)), "the queries of a session are routed alike")
+
+	var routes []modelRoute
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		routes = append(routes, newTestRoute(name, nil, 1))
+	}
+	router := &modelRouter{routes: routes}
+	firstRoutes := map[string]bool{}
+	for _, key := range []string{"default/query/q1", "default/query/q2", "default/query/q3", "default/query/q4", "default/query/q5"} {
+		order := routeNames(router.routerFor("router", key).candidates(0, false))
+		assert.Equal(t, order, routeNames(router.routerFor("router", key).candidates(0, false)), "every call with key %s is routed the same way", key)
+		firstRoutes[order[0]] = true
+	}
+	assert.Greater(t, len(firstRoutes), 1, "different queries are spread across the routes")
+}
+
+func TestModelChatCompletion_Router(t *testing.T) {
+	limited := &failingProvider{err: openAIStatusError(http.StatusTooManyRequests)}
+	answer := &answerProvider{answers: []string{"served by b"}}
+	recorder := &routeRecorder{ModelRecorder: noop.NewModelRecorder()}
+	model := newTestRouterModel(recorder, func(int) int { return 0 },
+		newTestRoute("a", limited, 1),
+		newTestRoute("b", answer, 1),
+	)
+
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "served by b", response.Choices[0].Message.Content)
+	assert.Equal(t, 1, limited.calls)
+	assert.Equal(t, []string{"a", "b"}, recorder.routes, "the route that served the call is recorded last")
+}
+
+func TestModelChatCompletion_RouterNoMatchingRoute(t *testing.T) {
+	short := &answerProvider{answers: []string{"unused"}}
+	route := newTestRoute("short", short, 1)
+	route.when = &arkv1alpha1.ModelRouteCondition{MaxPromptTokens: 10}
+	model := newTestRouterModel(noop.NewModelRecorder(), func(int) int { return 0 }, route)
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage(strings.Repeat("long prompt ", 100))}, nil, 1,
+		[]openai.ChatCompletionToolParam{weatherTool})
+
+	require.ErrorContains(t, err, "router router has no route matching a call")
+	assert.Equal(t, 0, short.calls)
+}
+
+func TestLoadModel_Router(t *testing.T) {
+	router := &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "router", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type: ModelTypeRouter,
+			Router: &arkv1alpha1.ModelRouter{Routes: []arkv1alpha1.ModelRoute{
+				{ModelRef: arkv1alpha1.AgentModelRef{Name: "openai"}, Weight: 9},
+				{ModelRef: arkv1alpha1.AgentModelRef{Name: "azure"}},
+				{ModelRef: arkv1alpha1.AgentModelRef{Name: "missing"}},
+				{ModelRef: arkv1alpha1.AgentModelRef{Name: "nested"}},
+			}},
+		},
+	}
+	nested := router.DeepCopy()
+	nested.Name = "nested"
+	k8sClient := setupModelTestClient([]client.Object{
+		router,
+		nested,
+		newFallbackTestModelCRD("openai", metav1.ConditionTrue),
+		newFallbackTestModelCRD("azure", metav1.ConditionFalse),
+	})
+
+	model, err := LoadModel(context.Background(), k8sClient, "router", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+
+	require.NoError(t, err)
+	assert.Equal(t, ModelTypeRouter, model.Type)
+	require.Len(t, model.router.routes, 2, "missing models and routers are skipped")
+	assert.Equal(t, "openai", model.router.routes[0].model.Name)
+	assert.Equal(t, 9, model.router.routes[0].weight)
+	assert.True(t, model.router.routes[0].available)
+	assert.Equal(t, 1, model.router.routes[1].weight)
+	assert.False(t, model.router.routes[1].available)
+
+	result := ProbeModel(context.Background(), model)
+	assert.True(t, result.Available)
+	assert.Equal(t, "1 of 2 routes are available", result.Message)
+}
+
+func TestLoadModel_RouterWithoutLoadableRoutes(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "router", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:   ModelTypeRouter,
+			Router: &arkv1alpha1.ModelRouter{Routes: []arkv1alpha1.ModelRoute{{ModelRef: arkv1alpha1.AgentModelRef{Name: "missing"}}}},
+		},
+	}})
+
+	_, err := LoadModel(context.Background(), k8sClient, "router", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+
+	require.ErrorContains(t, err, "router model router has no route that loads")
+	require.ErrorContains(t, err, "route to model missing")
+}
@@ -0,0 +1,388 @@
+package genai
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// openAPIMethods are the methods of OpenAPI operations that http tools can call
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// openAPISchemaKeywords are OpenAPI keywords of schemas that are not JSON Schema, and are left out
+// of the input schemas of tools
+var openAPISchemaKeywords = []string{"discriminator", "example", "externalDocs", "nullable", "xml"}
+
+// OpenAPIOperation is an operation of an OpenAPI document, with the configuration of an http tool
+// that calls it
+type OpenAPIOperation struct {
+	// OperationID is the operation's operationId, or one derived from its method and path
+	OperationID string
+	Method      string
+	Path        string
+	Tags        []string
+	Description string
+	InputSchema map[string]any
+	// URL has the path parameters as {name} placeholders, which http tools substitute
+	URL             string
+	QueryParameters []arkv1alpha1.HTTPQueryParameter
+	Body            string
+	ContentType     string
+	// Unsupported is why no http tool can call the operation, or empty if one can
+	Unsupported string
+}
+
+// openAPIDocument resolves the local references of an OpenAPI document
+type openAPIDocument struct {
+	root map[string]any
+}
+
+// ParseOpenAPIOperations returns the operations of an OpenAPI 3 document in JSON or YAML, ordered
+// by path and method. Their URLs start with baseURL, or if it is empty with the first server of
+// the document, which may be relative to documentURL.
+func ParseOpenAPIOperations(data []byte, baseURL, documentURL string) ([]OpenAPIOperation, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	var root map[string]any
+	if err := json.Unmarshal(jsonData, &root); err != nil || root == nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: expected an object")
+	}
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI document: only OpenAPI 3 documents are supported")
+	}
+	doc := &openAPIDocument{root: root}
+
+	if baseURL == "" {
+		baseURL, err = doc.serverURL(documentURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	paths, _ := root["paths"].(map[string]any)
+	var operations []OpenAPIOperation
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		pathItem, err := doc.resolveObject(paths[path])
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		}
+		for _, method := range openAPIMethods {
+			operation, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			operations = append(operations, doc.operation(baseURL, path, method, pathItem, operation))
+		}
+	}
+	return operations, nil
+}
+
+// serverURL returns the URL of the document's first server, with its variables set to their defaults
+func (d *openAPIDocument) serverURL(documentURL string) (string, error) {
+	servers, _ := d.root["servers"].([]any)
+	serverURL := ""
+	if len(servers) > 0 {
+		server, _ := servers[0].(map[string]any)
+		serverURL, _ = server["url"].(string)
+		variables, _ := server["variables"].(map[string]any)
+		for name, variable := range variables {
+			if variable, ok := variable.(map[string]any); ok {
+				serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", fmt.Sprint(variable["default"]))
+			}
+		}
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err == nil && !parsed.IsAbs() && documentURL != "" {
+		var base *url.URL
+		if base, err = url.Parse(documentURL); err == nil {
+			parsed = base.ResolveReference(parsed)
+		}
+	}
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", fmt.Errorf("the document has no absolute http server URL: set baseUrl")
+	}
+	return parsed.String(), nil
+}
+
+// operation converts an operation of a path item
+func (d *openAPIDocument) operation(baseURL, path, method string, pathItem, spec map[string]any) OpenAPIOperation {
+	operation := OpenAPIOperation{
+		Method: strings.ToUpper(method),
+		Path:   path,
+		URL:    baseURL + path,
+	}
+	operation.OperationID, _ = spec["operationId"].(string)
+	if operation.OperationID == "" {
+		operation.OperationID = method + path
+	}
+	for _, tag := range asSlice(spec["tags"]) {
+		if tag, ok := tag.(string); ok {
+			operation.Tags = append(operation.Tags, tag)
+		}
+	}
+	summary, _ := spec["summary"].(string)
+	description, _ := spec["description"].(string)
+	operation.Description = strings.TrimSpace(summary + "\n\n" + description)
+	if operation.Description == "" {
+		operation.Description = operation.Method + " " + path
+	}
+
+	inputSchema, err := d.inputSchema(&operation, pathItem, spec)
+	if err != nil {
+		operation.Unsupported = err.Error()
+		return operation
+	}
+	operation.InputSchema = inputSchema
+	return operation
+}
+
+// inputSchema returns the input schema of the operation's tool, with a property per path and query
+// parameter and a body property for a JSON request body, and sets the query parameters and body
+func (d *openAPIDocument) inputSchema(operation *OpenAPIOperation, pathItem, spec map[string]any) (map[string]any, error) {
+	properties := map[string]any{}
+	var required []string
+
+	// Parameters of the operation override those of the path with the same name and location
+	parameters := map[string]map[string]any{}
+	var order []string
+	for _, source := range [][]any{asSlice(pathItem["parameters"]), asSlice(spec["parameters"])} {
+		for _, item := range source {
+			parameter, err := d.resolveObject(item)
+			if err != nil {
+				return nil, err
+			}
+			key := fmt.Sprintf("%v/%v", parameter["in"], parameter["name"])
+			if _, ok := parameters[key]; !ok {
+				order = append(order, key)
+			}
+			parameters[key] = parameter
+		}
+	}
+
+	for _, key := range order {
+		parameter := parameters[key]
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		isRequired, _ := parameter["required"].(bool)
+		if in != "path" && in != "query" {
+			if isRequired {
+				return nil, fmt.Errorf("required %s parameter %s is not supported", in, name)
+			}
+			continue
+		}
+
+		schema, err := d.schema(parameter["schema"])
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		if description, ok := parameter["description"].(string); ok && schema["description"] == nil {
+			schema["description"] = description
+		}
+		properties[name] = schema
+		if isRequired || in == "path" {
+			required = append(required, name)
+		}
+
+		if in == "query" {
+			value := fmt.Sprintf("{{ index .input %q }}", name)
+			if schema["type"] == "array" {
+				value = fmt.Sprintf(`{{ range $i, $v := index .input %q }}{{ if $i }},{{ end }}{{ $v }}{{ end }}`, name)
+			}
+			operation.QueryParameters = append(operation.QueryParameters, arkv1alpha1.HTTPQueryParameter{Name: name, Value: value})
+		}
+	}
+
+	if spec["requestBody"] != nil {
+		requestBody, err := d.resolveObject(spec["requestBody"])
+		if err != nil {
+			return nil, err
+		}
+		isRequired, _ := requestBody["required"].(bool)
+		content, _ := requestBody["content"].(map[string]any)
+		contentType, media := jsonMediaType(content)
+		switch {
+		case media != nil:
+			schema, err := d.schema(media["schema"])
+			if err != nil {
+				return nil, fmt.Errorf("request body: %w", err)
+			}
+			if description, ok := requestBody["description"].(string); ok && schema["description"] == nil {
+				schema["description"] = description
+			}
+			properties["body"] = schema
+			if isRequired {
+				required = append(required, "body")
+			}
+			operation.Body = "{{ with .input.body }}{{ toJson . }}{{ end }}"
+			operation.ContentType = contentType
+		case isRequired:
+			return nil, fmt.Errorf("request bodies of type %s are not supported", strings.Join(slices.Sorted(maps.Keys(content)), ", "))
+		}
+	}
+
+	inputSchema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		inputSchema["required"] = required
+	}
+	return inputSchema, nil
+}
+
+// jsonMediaType returns the JSON media type of request body content, preferring application/json
+func jsonMediaType(content map[string]any) (string, map[string]any) {
+	found := ""
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" || (found == "" && strings.HasSuffix(mediaType, "+json")) {
+			found = contentType
+		}
+	}
+	if found == "" {
+		return "", nil
+	}
+	media, _ := content[found].(map[string]any)
+	if media == nil {
+		media = map[string]any{}
+	}
+	return found, media
+}
+
+// schema returns a copy of a schema with its references inlined and the OpenAPI keywords removed
+func (d *openAPIDocument) schema(node any) (map[string]any, error) {
+	if node == nil {
+		return map[string]any{}, nil
+	}
+	resolved, err := d.inline(node, nil)
+	if err != nil {
+		return nil, err
+	}
+	schema, ok := resolved.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema")
+	}
+	return schema, nil
+}
+
+// inline copies a schema, replacing references with what they point to. A reference within
+// itself is replaced by an empty schema, as input schemas cannot be recursive.
+func (d *openAPIDocument) inline(node any, refs []string) (any, error) {
+	switch node := node.(type) {
+	case map[string]any:
+		if ref, ok := node["$ref"].(string); ok {
+			if slices.Contains(refs, ref) {
+				return map[string]any{}, nil
+			}
+			target, err := d.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			return d.inline(target, slices.Concat(refs, []string{ref}))
+		}
+		copied := make(map[string]any, len(node))
+		for key, value := range node {
+			if slices.Contains(openAPISchemaKeywords, key) {
+				continue
+			}
+			var inlined any
+			var err error
+			if properties, ok := value.(map[string]any); ok && key == "properties" {
+				// Properties may have the names of keywords, so only their schemas are cleaned
+				inlined, err = d.inlineProperties(properties, refs)
+			} else {
+				inlined, err = d.inline(value, refs)
+			}
+			if err != nil {
+				return nil, err
+			}
+			copied[key] = inlined
+		}
+		return copied, nil
+	case []any:
+		copied := make([]any, len(node))
+		for i, value := range node {
+			inlined, err := d.inline(value, refs)
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = inlined
+		}
+		return copied, nil
+	}
+	return node, nil
+}
+
+// inlineProperties inlines the schemas of an object's properties
+func (d *openAPIDocument) inlineProperties(properties map[string]any, refs []string) (map[string]any, error) {
+	copied := make(map[string]any, len(properties))
+	for name, property := range properties {
+		inlined, err := d.inline(property, refs)
+		if err != nil {
+			return nil, err
+		}
+		copied[name] = inlined
+	}
+	return copied, nil
+}
+
+// resolveObject follows the reference of a node, if it is one, and returns the object it points to
+func (d *openAPIDocument) resolveObject(node any) (map[string]any, error) {
+	for range 32 {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object")
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, nil
+		}
+		target, err := d.lookup(ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+	return nil, fmt.Errorf("too many nested references")
+}
+
+// lookup returns the node a local reference such as #/components/schemas/Order points to
+func (d *openAPIDocument) lookup(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("reference %s is not supported: only references within the document are", ref)
+	}
+	var node any = d.root
+	for token := range strings.SplitSeq(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("reference %s not found", ref)
+		}
+		if node, ok = object[token]; !ok {
+			return nil, fmt.Errorf("reference %s not found", ref)
+		}
+	}
+	return node, nil
+}
+
+func asSlice(node any) []any {
+	items, _ := node.([]any)
+	return items
+}
@@ -0,0 +1,192 @@
+package genai
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const testOpenAPIDocument = `
+openapi: 3.0.3
+info:
+  title: Orders
+  version: "1.0"
+servers:
+  - url: /api/v1
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      summary: List orders
+      tags: [orders]
+      parameters:
+        - name: limit
+          in: query
+          description: Maximum number of orders
+          schema: {type: integer}
+        - name: status
+          in: query
+          schema: {type: array, items: {type: string}}
+        - name: X-Trace
+          in: header
+          schema: {type: string}
+    post:
+      operationId: createOrder
+      tags: [orders]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+  /orders/{id}:
+    parameters:
+      - $ref: '#/components/parameters/OrderID'
+    get:
+      summary: Get an order
+      description: Returns the order with its items.
+    delete:
+      operationId: deleteOrder
+      parameters:
+        - name: If-Match
+          in: header
+          required: true
+          schema: {type: string}
+  /orders/{id}/attachments:
+    post:
+      operationId: uploadAttachment
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema: {type: object}
+components:
+  parameters:
+    OrderID:
+      name: id
+      in: path
+      required: true
+      schema: {type: string}
+  schemas:
+    Order:
+      type: object
+      required: [customer]
+      properties:
+        customer: {type: string, example: acme}
+        example: {type: string, nullable: true}
+        parent:
+          $ref: '#/components/schemas/Order'
+`
+
+func TestParseOpenAPIOperations(t *testing.T) {
+	operations, err := ParseOpenAPIOperations([]byte(testOpenAPIDocument), "", "https://docs.example.com/specs/openapi.yaml")
+	require.NoError(t, err)
+	require.Len(t, operations, 5)
+
+	list := operations[0]
+	assert.Equal(t, "listOrders", list.OperationID)
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "https://docs.example.com/api/v1/orders", list.URL)
+	assert.Equal(t, []string{"orders"}, list.Tags)
+	assert.Equal(t, "List orders", list.Description)
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"limit":  map[string]any{"type": "integer", "description": "Maximum number of orders"},
+			"status": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+	}, list.InputSchema)
+	assert.Equal(t, []arkv1alpha1.HTTPQueryParameter{
+		{Name: "limit", Value: `{{ index .input "limit" }}`},
+		{Name: "status", Value: `{{ range $i, $v := index .input "status" }}{{ if $i }},{{ end }}{{ $v }}{{ end }}`},
+	}, list.QueryParameters)
+
+	create := operations[1]
+	assert.Equal(t, "createOrder", create.OperationID)
+	assert.Equal(t, "application/json", create.ContentType)
+	assert.Equal(t, "{{ with .input.body }}{{ toJson . }}{{ end }}", create.Body)
+	// The OpenAPI keywords are left out, but not properties named like them, and the recursive
+	// reference is cut
+	assert.Equal(t, map[string]any{
+		"type":     "object",
+		"required": []any{"customer"},
+		"properties": map[string]any{
+			"customer": map[string]any{"type": "string"},
+			"example":  map[string]any{"type": "string"},
+			"parent":   map[string]any{},
+		},
+	}, create.InputSchema["properties"].(map[string]any)["body"])
+	assert.Equal(t, []string{"body"}, create.InputSchema["required"])
+
+	get := operations[2]
+	assert.Equal(t, "get/orders/{id}", get.OperationID)
+	assert.Equal(t, "https://docs.example.com/api/v1/orders/{id}", get.URL)
+	assert.Equal(t, "Get an order\n\nReturns the order with its items.", get.Description)
+	assert.Equal(t, []string{"id"}, get.InputSchema["required"])
+	assert.Empty(t, get.Unsupported)
+
+	assert.Equal(t, "deleteOrder", operations[3].OperationID)
+	assert.Equal(t, "required header parameter If-Match is not supported", operations[3].Unsupported)
+	assert.Equal(t, "request bodies of type multipart/form-data are not supported", operations[4].Unsupported)
+}
+
+func TestParseOpenAPIOperations_BaseURL(t *testing.T) {
+	operations, err := ParseOpenAPIOperations([]byte(testOpenAPIDocument), "https://orders.internal/", "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://orders.internal/orders", operations[0].URL)
+
+	_, err = ParseOpenAPIOperations([]byte(testOpenAPIDocument), "", "")
+	assert.ErrorContains(t, err, "set baseUrl")
+}
+
+func TestParseOpenAPIOperations_InvalidDocuments(t *testing.T) {
+	_, err := ParseOpenAPIOperations([]byte(`{"swagger": "2.0", "paths": {}}`), "https://api.example.com", "")
+	assert.ErrorContains(t, err, "only OpenAPI 3 documents are supported")
+
+	_, err = ParseOpenAPIOperations([]byte("- not\n- an object"), "https://api.example.com", "")
+	assert.ErrorContains(t, err, "invalid OpenAPI document")
+
+	operations, err := ParseOpenAPIOperations([]byte(`
+openapi: 3.1.0
+paths:
+  /a:
+    get:
+      parameters:
+        - $ref: 'common.yaml#/components/parameters/Page'
+`), "https://api.example.com", "")
+	require.NoError(t, err)
+	assert.Contains(t, operations[0].Unsupported, "only references within the document are")
+}
+
+func TestParseOpenAPIOperations_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.RequestURI(), body)
+	}))
+	t.Cleanup(server.Close)
+
+	operations, err := ParseOpenAPIOperations([]byte(testOpenAPIDocument), server.URL, "")
+	require.NoError(t, err)
+	httpSpec := func(operation OpenAPIOperation) *arkv1alpha1.HTTPSpec {
+		return &arkv1alpha1.HTTPSpec{URL: operation.URL, Method: operation.Method, QueryParameters: operation.QueryParameters, Body: operation.Body}
+	}
+
+	result, err := executeHTTPSpec(t, httpSpec(operations[0]), `{"limit": 5, "status": ["open", "paid"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "GET /orders?limit=5&status=open%2Cpaid ", result.Content)
+
+	result, err = executeHTTPSpec(t, httpSpec(operations[1]), `{"body": {"customer": "acme", "lines": [1, 2]}}`)
+	require.NoError(t, err)
+	assert.Equal(t, `POST /orders {"customer":"acme","lines":[1,2]}`, result.Content)
+
+	result, err = executeHTTPSpec(t, httpSpec(operations[2]), `{"id": "42"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "GET /orders/42 ", result.Content)
+}
@@ -0,0 +1,143 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultOutputSchemaRepairAttempts = 2
+	// structuredOutputToolName is the tool that providers without a native response format force
+	// the model to call, with the output schema as its input schema
+	structuredOutputToolName = "structured_output"
+	outputSchemaRepairPrompt = "Your previous response does not match the required JSON schema: %v\n\nRespond again with only a JSON document that matches the schema."
+)
+
+// ValidateOutputSchema checks that an agent's outputSchema is a usable JSON schema. Its root must
+// describe an object, which response formats and tool input schemas require and which the query
+// stores in status.response.structured.
+func ValidateOutputSchema(raw *runtime.RawExtension) error {
+	resolved, err := compileJSONSchema(raw)
+	if err != nil || resolved == nil {
+		return err
+	}
+	if resolved.Schema().Type != "object" {
+		return fmt.Errorf("the schema must have type object at its root")
+	}
+	return nil
+}
+
+func compileJSONSchema(raw *runtime.RawExtension) (*jsonschema.Resolved, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(raw.Raw, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+func validateJSONContent(schema *jsonschema.Resolved, content string) error {
+	var instance any
+	if err := json.Unmarshal([]byte(content), &instance); err != nil {
+		return fmt.Errorf("content is not valid JSON")
+	}
+	return schema.Validate(instance)
+}
+
+// extractJSONContent trims whitespace and a surrounding Markdown code fence, which models
+// without native structured output often add around JSON responses
+func extractJSONContent(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") || !strings.HasSuffix(content, "```") || len(content) < 6 {
+		return content
+	}
+	content = strings.TrimSuffix(strings.TrimPrefix(content, "```"), "```")
+	if newline := strings.IndexByte(content, '\n'); newline >= 0 && !strings.ContainsAny(content[:newline], "{[") {
+		content = content[newline+1:]
+	}
+	return strings.TrimSpace(content)
+}
+
+// parseStructuredOutput validates content against schema and returns it as compact JSON
+func parseStructuredOutput(schema *jsonschema.Resolved, content string) (json.RawMessage, error) {
+	content = extractJSONContent(content)
+	if err := validateJSONContent(schema, content); err != nil {
+		return nil, err
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(content)); err != nil {
+		return nil, err
+	}
+	return compact.Bytes(), nil
+}
+
+func (a *Agent) outputSchemaRepairAttempts() int {
+	if a.OutputSchemaRepairAttempts != nil {
+		return *a.OutputSchemaRepairAttempts
+	}
+	return defaultOutputSchemaRepairAttempts
+}
+
+// enforceOutputSchema validates the final message of result against the agent's outputSchema.
+// While attempts remain, the model is re-prompted with the validation errors and its new answer
+// replaces the final message. Repair prompts and rejected answers are kept out of result.Messages,
+// so only the last answer is persisted. The parsed JSON is stored on the result once it validates.
+func (a *Agent) enforceOutputSchema(ctx context.Context, agentMessages []Message, result *ExecutionResult, eventStream EventStreamInterface) (*ExecutionResult, error) {
+	schema, err := compileJSONSchema(a.OutputSchema)
+	if err != nil {
+		return result, fmt.Errorf("agent %s has an invalid outputSchema: %w", a.FullName(), err)
+	}
+	if schema == nil || len(result.Messages) == 0 {
+		return result, nil
+	}
+
+	// Repair turns run on a copy, so they never reach the caller's history
+	agentMessages = slices.Clone(agentMessages)
+	maxAttempts := a.outputSchemaRepairAttempts()
+	for attempt := 0; ; attempt++ {
+		final := result.Messages[len(result.Messages)-1]
+		var content string
+		if final.OfAssistant != nil {
+			content = final.OfAssistant.Content.OfString.Value
+		}
+
+		structured, validationErr := parseStructuredOutput(schema, content)
+		if validationErr == nil {
+			result.StructuredOutput = structured
+			return result, nil
+		}
+		if attempt >= maxAttempts {
+			return result, &OutputSchemaInvalid{Agent: a.FullName(), Attempts: attempt, Reason: validationErr.Error()}
+		}
+
+		logf.FromContext(ctx).Info("Agent response does not match outputSchema, requesting repair",
+			"agent", a.FullName(), "attempt", attempt+1, "error", validationErr.Error())
+
+		repairMessage := NewUserMessage(fmt.Sprintf(outputSchemaRepairPrompt, validationErr))
+		agentMessages = append(agentMessages, repairMessage)
+
+		agentMessages = a.fitContextWindow(ctx, agentMessages, nil)
+		response, err := a.executeModelCall(ctx, agentMessages, nil, eventStream)
+		if err != nil {
+			return result, err
+		}
+
+		assistantMessage := a.processAssistantMessage(response.Choices[0])
+		agentMessages = append(agentMessages, assistantMessage)
+		result.Messages[len(result.Messages)-1] = assistantMessage
+	}
+}
@@ -0,0 +1,147 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// answerProvider replies with the given answers in order, repeating the last one, streams
+// each answer as a single chunk, and records the messages of the most recent call.
+type answerProvider struct {
+	answers      []string
+	calls        int
+	lastMessages []Message
+}
+
+func (p *answerProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	answer := p.answers[min(p.calls, len(p.answers)-1)]
+	p.calls++
+	p.lastMessages = messages
+	message := openai.ChatCompletionMessage{Role: "assistant", Content: answer}
+	return &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: message}}}, nil
+}
+
+func (p *answerProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	response, err := p.ChatCompletion(ctx, messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+	if err := streamFunc(completionChunk(response)); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (p *answerProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+const testOutputSchema = `{"type":"object","properties":{"city":{"type":"string"},"temperature":{"type":"number"}},"required":["city","temperature"]}`
+
+func newSchemaTestAgent(provider ChatCompletionProvider, repairAttempts *int) *Agent {
+	agent := newLoopTestAgent(provider, nil, false)
+	agent.Tools = nil
+	agent.OutputSchema = &runtime.RawExtension{Raw: []byte(testOutputSchema)}
+	agent.OutputSchemaRepairAttempts = repairAttempts
+	return agent
+}
+
+func TestExecuteLocally_ValidStructuredOutput(t *testing.T) {
+	provider := &answerProvider{answers: []string{"```json\n{\"city\": \"Paris\", \"temperature\": 21.5}\n```"}}
+	agent := newSchemaTestAgent(provider, nil)
+
+	result, err := agent.executeLocally(context.Background(), NewUserMessage("weather?"), nil, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, provider.calls)
+	assert.JSONEq(t, `{"city":"Paris","temperature":21.5}`, string(result.StructuredOutput))
+}
+
+func TestExecuteLocally_RepairsStructuredOutput(t *testing.T) {
+	provider := &answerProvider{answers: []string{
+		"It is 21 degrees in Paris",
+		`{"city": "Paris"}`,
+		`{"city": "Paris", "temperature": 21}`,
+	}}
+	agent := newSchemaTestAgent(provider, nil)
+
+	result, err := agent.executeLocally(context.Background(), NewUserMessage("weather?"), nil, nil, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.calls)
+	assert.JSONEq(t, `{"city":"Paris","temperature":21}`, string(result.StructuredOutput))
+
+	// The last repair prompt carries the validation error for the previous answer
+	repair := provider.lastMessages[len(provider.lastMessages)-1]
+	require.NotNil(t, repair.OfUser)
+	assert.Contains(t, repair.OfUser.Content.OfString.Value, "temperature")
+
+	// Only the valid answer is kept, without the repair prompts and rejected answers
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, `{"city": "Paris", "temperature": 21}`, result.Messages[0].OfAssistant.Content.OfString.Value)
+}
+
+func TestAgentExecute_OutputSchemaStreamsOnlyValidAnswer(t *testing.T) {
+	provider := &answerProvider{answers: []string{"It is 21 degrees in Paris", `{"city": "Paris", "temperature": 21}`}}
+	agent := newSchemaTestAgent(provider, nil)
+	stream := &recordingStream{}
+
+	_, err := agent.Execute(context.Background(), NewUserMessage("weather?"), nil, nil, stream)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+	require.Len(t, stream.chunks, 1, "the rejected answer is not streamed")
+	chunk := stream.chunks[0].(ChunkWithMetadata)
+	assert.Equal(t, `{"city": "Paris", "temperature": 21}`, chunk.Choices[0].Delta.Content)
+}
+
+func TestValidateOutputSchema(t *testing.T) {
+	require.NoError(t, ValidateOutputSchema(nil))
+	require.NoError(t, ValidateOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}))
+	require.Error(t, ValidateOutputSchema(&runtime.RawExtension{Raw: []byte(`{"type":"array","items":{"type":"string"}}`)}))
+}
+
+func TestExecuteLocally_OutputSchemaInvalid(t *testing.T) {
+	provider := &answerProvider{answers: []string{"not json"}}
+	repairAttempts := 1
+	agent := newSchemaTestAgent(provider, &repairAttempts)
+
+	result, err := agent.executeLocally(context.Background(), NewUserMessage("weather?"), nil, nil, nil)
+
+	require.True(t, IsOutputSchemaInvalid(err))
+	assert.Equal(t, 2, provider.calls)
+	assert.Nil(t, result.StructuredOutput)
+}
+
+func TestApplyOutputGuardrails_UpdatesStructuredOutput(t *testing.T) {
+	guardrail := newTestGuardrail(t, "pii", arkv1alpha1.GuardrailSpec{Action: GuardrailActionRedact, PII: []string{"email"}})
+	result := &ExecutionResult{
+		Messages:         []Message{NewAssistantMessage(`{"contact": "jane@example.com"}`)},
+		StructuredOutput: []byte(`{"contact":"jane@example.com"}`),
+	}
+
+	require.NoError(t, ApplyOutputGuardrails(context.Background(), []*Guardrail{guardrail}, result))
+
+	assert.JSONEq(t, `{"contact":"[REDACTED]"}`, string(result.StructuredOutput))
+}
+
+func TestExtractJSONContent(t *testing.T) {
+	assert.Equal(t, `{"a":1}`, extractJSONContent("  {\"a\":1}\n"))
+	assert.Equal(t, `{"a":1}`, extractJSONContent("```json\n{\"a\":1}\n```"))
+	assert.Equal(t, `{"a":1}`, extractJSONContent("```{\"a\":1}```"))
+	assert.Equal(t, "```", extractJSONContent("```"))
+}
+
+func TestWithOutputSchemaInstructions(t *testing.T) {
+	schema := &runtime.RawExtension{Raw: []byte(testOutputSchema)}
+
+	assert.Equal(t, "prompt", withOutputSchemaInstructions("prompt", nil))
+	instructions := withOutputSchemaInstructions("prompt", schema)
+	assert.Contains(t, instructions, "prompt\n\n")
+	assert.Contains(t, instructions, testOutputSchema)
+}
@@ -0,0 +1,703 @@
+package genai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	anthropicDefaultBaseURL    = "https://api.anthropic.com"
+	anthropicDefaultAPIVersion = "2023-06-01"
+
+	anthropicDefaultMaxTokens = 4096
+)
+
+// AnthropicProvider calls the Anthropic Messages API
+type AnthropicProvider struct {
+	Model         string
+	BaseURL       string
+	APIKey        string
+	APIVersion    string
+	Headers       map[string]string
+	Properties    map[string]string
+	PromptCaching bool
+	outputSchema  *runtime.RawExtension
+	schemaName    string
+	reasoning     *arkv1alpha1.ModelReasoning
+}
+
+// AnthropicError is an error response from the Messages API
+type AnthropicError struct {
+	StatusCode int
+	Type       string
+	Message    string
+	// Header holds the response headers, which may ask for a delay before retrying
+	Header http.Header
+}
+
+func (e *AnthropicError) Error() string {
+	return fmt.Sprintf("anthropic API error %d (%s): %s", e.StatusCode, e.Type, e.Message)
+}
+
+type anthropicCacheControl struct {
+	Type string `json:"type"`
+}
+
+var anthropicEphemeralCache = &anthropicCacheControl{Type: "ephemeral"}
+
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type anthropicContent struct {
+	Type         string                 `json:"type"`
+	Text         string                 `json:"text,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Input        json.RawMessage        `json:"input,omitempty"`
+	ToolUseID    string                 `json:"tool_use_id,omitempty"`
+	Content      string                 `json:"content,omitempty"`
+	Source       *anthropicImageSource  `json:"source,omitempty"`
+	Thinking     string                 `json:"thinking,omitempty"`
+	Signature    string                 `json:"signature,omitempty"`
+	Data         string                 `json:"data,omitempty"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicTool struct {
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  any                    `json:"input_schema"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicThinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	Messages    []anthropicMessage   `json:"messages"`
+	System      []anthropicContent   `json:"system,omitempty"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Thinking    *anthropicThinking   `json:"thinking,omitempty"`
+	Temperature *float64             `json:"temperature,omitempty"`
+	TopP        *float64             `json:"top_p,omitempty"`
+	TopK        *int                 `json:"top_k,omitempty"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream      bool                 `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+type anthropicResponse struct {
+	ID         string             `json:"id"`
+	Model      string             `json:"model"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      anthropicUsage     `json:"usage"`
+}
+
+type anthropicErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type anthropicStreamEvent struct {
+	Type         string             `json:"type"`
+	Index        int                `json:"index"`
+	Message      *anthropicResponse `json:"message,omitempty"`
+	ContentBlock *anthropicContent  `json:"content_block,omitempty"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		Thinking    string `json:"thinking"`
+		Signature   string `json:"signature"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage,omitempty"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (ap *AnthropicProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	ap.outputSchema = schema
+	ap.schemaName = schemaName
+}
+
+func (ap *AnthropicProvider) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	ap.reasoning = reasoning
+}
+
+func (ap *AnthropicProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := ap.buildRequest(messages, tools...)
+
+	resp, err := ap.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Anthropic response: %w", err)
+	}
+	return ap.complete(ctx, &response), nil
+}
+
+// ChatCompletionStream streams the response over server-sent events. Text and tool input deltas
+// are passed to streamFunc as OpenAI chunks while the full response is accumulated.
+func (ap *AnthropicProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := ap.buildRequest(messages, tools...)
+	request.Stream = true
+
+	resp, err := ap.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	stream := &anthropicStream{streamFunc: streamFunc, toolIndexes: map[int]int64{}, partialInputs: map[int]*strings.Builder{}}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode Anthropic stream event: %w", err)
+		}
+		if err := stream.handle(&event); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if stream.response == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+	return ap.complete(ctx, stream.response), nil
+}
+
+// complete converts a response to a chat completion, reporting the model's thinking as its
+// reasoning summary and keeping the thinking blocks on the message for the next call
+func (ap *AnthropicProvider) complete(ctx context.Context, response *anthropicResponse) *openai.ChatCompletion {
+	completion := convertAnthropicResponse(response)
+	if ap.reasoning == nil {
+		return completion
+	}
+
+	var blocks []anthropicContent
+	for _, block := range response.Content {
+		switch block.Type {
+		case "thinking":
+			if ap.reasoning.Summary {
+				addReasoningSummary(ctx, block.Thinking)
+			}
+			blocks = append(blocks, block)
+		case "redacted_thinking":
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) > 0 {
+		completion.Choices[0].Message = withThinkingBlocks(completion.Choices[0].Message, anthropicThinkingField, blocks)
+	}
+	return completion
+}
+
+// anthropicStream accumulates a streamed response and forwards deltas as OpenAI chunks
+type anthropicStream struct {
+	streamFunc    func(*openai.ChatCompletionChunk) error
+	response      *anthropicResponse
+	toolIndexes   map[int]int64
+	partialInputs map[int]*strings.Builder
+}
+
+func (s *anthropicStream) handle(event *anthropicStreamEvent) error {
+	switch event.Type {
+	case "error":
+		if event.Error != nil {
+			return &AnthropicError{StatusCode: http.StatusInternalServerError, Type: event.Error.Type, Message: event.Error.Message}
+		}
+		return fmt.Errorf("anthropic stream failed")
+	case "message_start":
+		if event.Message != nil {
+			s.response = event.Message
+		}
+		return nil
+	}
+
+	if s.response == nil {
+		return fmt.Errorf("anthropic stream event %s received before message_start", event.Type)
+	}
+
+	switch event.Type {
+	case "content_block_start":
+		if event.ContentBlock == nil {
+			return nil
+		}
+		block := *event.ContentBlock
+		block.Input = nil
+		s.response.Content = append(s.response.Content, block)
+		if block.Type != "tool_use" {
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{Role: "assistant", Content: block.Text}, "")
+		}
+		s.partialInputs[event.Index] = &strings.Builder{}
+		if block.Name == structuredOutputToolName {
+			return nil
+		}
+		toolIndex := int64(len(s.toolIndexes))
+		s.toolIndexes[event.Index] = toolIndex
+		return s.emit(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    toolIndex,
+				ID:       block.ID,
+				Type:     "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: block.Name},
+			}},
+		}, "")
+	case "content_block_delta":
+		block := s.block(event.Index)
+		if block == nil {
+			return nil
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			block.Text += event.Delta.Text
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta.Text}, "")
+		case "thinking_delta":
+			block.Thinking += event.Delta.Thinking
+		case "signature_delta":
+			block.Signature += event.Delta.Signature
+		case "input_json_delta":
+			if partial, ok := s.partialInputs[event.Index]; ok {
+				partial.WriteString(event.Delta.PartialJSON)
+			}
+			if block.Name == structuredOutputToolName {
+				return s.emit(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta.PartialJSON}, "")
+			}
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{
+				ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+					Index:    s.toolIndexes[event.Index],
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: event.Delta.PartialJSON},
+				}},
+			}, "")
+		}
+	case "content_block_stop":
+		block := s.block(event.Index)
+		if partial, ok := s.partialInputs[event.Index]; ok && block != nil {
+			block.Input = json.RawMessage(partial.String())
+		}
+	case "message_delta":
+		if event.Usage != nil {
+			s.response.Usage.OutputTokens = event.Usage.OutputTokens
+		}
+		if event.Delta.StopReason != "" {
+			s.response.StopReason = event.Delta.StopReason
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{}, anthropicFinishReason(s.response))
+		}
+	}
+	return nil
+}
+
+// block returns the content block with the given stream index, which is its position in the response
+func (s *anthropicStream) block(index int) *anthropicContent {
+	if index < 0 || index >= len(s.response.Content) {
+		return nil
+	}
+	return &s.response.Content[index]
+}
+
+func (s *anthropicStream) emit(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) error {
+	if delta.Content == "" && len(delta.ToolCalls) == 0 && finishReason == "" && delta.Role == "" {
+		return nil
+	}
+	return s.streamFunc(&openai.ChatCompletionChunk{
+		ID:      s.response.ID,
+		Object:  "chat.completion.chunk",
+		Model:   s.response.Model,
+		Choices: []openai.ChatCompletionChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+	})
+}
+
+func (ap *AnthropicProvider) buildRequest(messages []Message, tools ...[]openai.ChatCompletionToolParam) anthropicRequest {
+	anthropicMessages, system := convertAnthropicMessages(messages)
+
+	request := anthropicRequest{
+		Model:     ap.Model,
+		Messages:  anthropicMessages,
+		System:    system,
+		MaxTokens: getIntProperty(ap.Properties, "max_tokens", anthropicDefaultMaxTokens),
+	}
+	if _, ok := ap.Properties["temperature"]; ok {
+		temperature := getFloatProperty(ap.Properties, "temperature", 1.0)
+		request.Temperature = &temperature
+	}
+	if _, ok := ap.Properties["top_p"]; ok {
+		topP := getFloatProperty(ap.Properties, "top_p", 1.0)
+		request.TopP = &topP
+	}
+	if _, ok := ap.Properties["top_k"]; ok {
+		topK := getIntProperty(ap.Properties, "top_k", 0)
+		request.TopK = &topK
+	}
+
+	if len(tools) > 0 {
+		request.Tools = convertAnthropicTools(tools[0])
+	}
+
+	// Thinking cannot be combined with a temperature, top_k or a forced tool call, so the
+	// output schema is given as instructions instead
+	if ap.reasoning != nil {
+		budget := reasoningBudget(ap.reasoning)
+		request.Thinking = &anthropicThinking{Type: "enabled", BudgetTokens: budget}
+		request.MaxTokens = reasoningMaxTokens(request.MaxTokens, budget)
+		request.Temperature = nil
+		request.TopK = nil
+		if ap.outputSchema != nil && len(ap.outputSchema.Raw) > 0 {
+			request.System = append(request.System, anthropicTextBlocks(withOutputSchemaInstructions("", ap.outputSchema))...)
+		}
+	}
+
+	// Structured output is requested by forcing a call to a tool whose input schema is the
+	// output schema. With other tools present the model chooses between them, since forcing any
+	// tool call would keep it from answering in text after the other tools.
+	if ap.reasoning == nil && ap.outputSchema != nil && len(ap.outputSchema.Raw) > 0 {
+		var schema any
+		if err := json.Unmarshal(ap.outputSchema.Raw, &schema); err == nil {
+			request.Tools = append(request.Tools, anthropicTool{
+				Name:        structuredOutputToolName,
+				Description: "Respond with the final answer in the required format",
+				InputSchema: schema,
+			})
+			if len(request.Tools) == 1 {
+				request.ToolChoice = &anthropicToolChoice{Type: "tool", Name: structuredOutputToolName}
+			} else {
+				request.ToolChoice = &anthropicToolChoice{Type: "auto"}
+			}
+		}
+	}
+
+	if ap.PromptCaching {
+		if len(request.System) > 0 {
+			request.System[len(request.System)-1].CacheControl = anthropicEphemeralCache
+		}
+		if len(request.Tools) > 0 {
+			request.Tools[len(request.Tools)-1].CacheControl = anthropicEphemeralCache
+		}
+	}
+
+	return request
+}
+
+func (ap *AnthropicProvider) send(ctx context.Context, request anthropicRequest) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Anthropic request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ap.messagesURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("x-api-key", ap.APIKey)
+	req.Header.Set("anthropic-version", ap.apiVersion())
+	if request.Stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if len(ap.Headers) > 0 {
+		logf.FromContext(ctx).V(1).Info("applying custom headers to client", "model", ap.Model, "header_count", len(ap.Headers))
+		for name, value := range ap.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := ap.httpClient(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newAnthropicError(resp)
+	}
+	return resp, nil
+}
+
+func newAnthropicError(resp *http.Response) error {
+	apiErr := &AnthropicError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Header: resp.Header}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var errorResponse anthropicErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.Message != "" {
+		apiErr.Type = errorResponse.Error.Type
+		apiErr.Message = errorResponse.Error.Message
+	}
+	return apiErr
+}
+
+// messagesURL appends the Messages API path to the base URL, which may already end in /v1
+func (ap *AnthropicProvider) messagesURL() string {
+	baseURL := strings.TrimSuffix(ap.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = anthropicDefaultBaseURL
+	}
+	if strings.HasSuffix(baseURL, "/v1") {
+		return baseURL + "/messages"
+	}
+	return baseURL + "/v1/messages"
+}
+
+func (ap *AnthropicProvider) apiVersion() string {
+	if ap.APIVersion != "" {
+		return ap.APIVersion
+	}
+	return anthropicDefaultAPIVersion
+}
+
+func (ap *AnthropicProvider) httpClient(ctx context.Context) *http.Client {
+	if IsProbeContext(ctx) {
+		return common.NewHTTPClientWithoutTracing()
+	}
+	return common.NewHTTPClientWithLogging(ctx)
+}
+
+// convertAnthropicMessages converts messages to Messages API format. System and developer
+// messages become the system prompt, tool results are sent as user content and consecutive
+// messages with the same role are merged, as the API requires roles to alternate. The thinking
+// blocks kept on assistant messages are sent back before their content.
+func convertAnthropicMessages(messages []Message) ([]anthropicMessage, []anthropicContent) {
+	var result []anthropicMessage
+	var system []anthropicContent
+
+	appendBlocks := func(role string, blocks []anthropicContent) {
+		if len(blocks) == 0 {
+			return
+		}
+		if last := len(result) - 1; last >= 0 && result[last].Role == role {
+			result[last].Content = append(result[last].Content, blocks...)
+			return
+		}
+		result = append(result, anthropicMessage{Role: role, Content: blocks})
+	}
+
+	for _, msg := range messages {
+		union := openai.ChatCompletionMessageParamUnion(msg)
+		switch {
+		case union.OfSystem != nil:
+			text := union.OfSystem.Content.OfString.Value
+			for _, part := range union.OfSystem.Content.OfArrayOfContentParts {
+				text += part.Text
+			}
+			system = append(system, anthropicTextBlocks(text)...)
+		case union.OfDeveloper != nil:
+			text := union.OfDeveloper.Content.OfString.Value
+			for _, part := range union.OfDeveloper.Content.OfArrayOfContentParts {
+				text += part.Text
+			}
+			system = append(system, anthropicTextBlocks(text)...)
+		case union.OfUser != nil:
+			blocks := anthropicTextBlocks(union.OfUser.Content.OfString.Value)
+			for _, part := range union.OfUser.Content.OfArrayOfContentParts {
+				switch {
+				case part.OfText != nil:
+					blocks = append(blocks, anthropicTextBlocks(part.OfText.Text)...)
+				case part.OfImageURL != nil:
+					blocks = append(blocks, anthropicContent{Type: "image", Source: anthropicImage(part.OfImageURL.ImageURL.URL)})
+				}
+			}
+			appendBlocks(RoleUser, blocks)
+		case union.OfAssistant != nil:
+			var blocks []anthropicContent
+			thinkingBlocks(union.OfAssistant, anthropicThinkingField, &blocks)
+			blocks = append(blocks, anthropicTextBlocks(union.OfAssistant.Content.OfString.Value)...)
+			for _, part := range union.OfAssistant.Content.OfArrayOfContentParts {
+				if part.OfText != nil {
+					blocks = append(blocks, anthropicTextBlocks(part.OfText.Text)...)
+				}
+			}
+			for _, toolCall := range union.OfAssistant.ToolCalls {
+				input := json.RawMessage(toolCall.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, anthropicContent{Type: "tool_use", ID: toolCall.ID, Name: toolCall.Function.Name, Input: input})
+			}
+			appendBlocks(RoleAssistant, blocks)
+		case union.OfTool != nil:
+			content := union.OfTool.Content.OfString.Value
+			for _, part := range union.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			appendBlocks(RoleUser, []anthropicContent{{Type: "tool_result", ToolUseID: union.OfTool.ToolCallID, Content: content}})
+		}
+	}
+
+	return result, system
+}
+
+// anthropicTextBlocks returns a text block for text, or none as the API rejects empty text blocks
+func anthropicTextBlocks(text string) []anthropicContent {
+	if text == "" {
+		return nil
+	}
+	return []anthropicContent{{Type: "text", Text: text}}
+}
+
+// anthropicImage converts an image URL, which may be a base64 data URL, to an image source
+func anthropicImage(url string) *anthropicImageSource {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if mediaType, data, ok := strings.Cut(rest, ";base64,"); ok {
+			return &anthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}
+		}
+	}
+	return &anthropicImageSource{Type: "url", URL: url}
+}
+
+func convertAnthropicTools(tools []openai.ChatCompletionToolParam) []anthropicTool {
+	var anthropicTools []anthropicTool
+	for _, tool := range tools {
+		inputSchema := any(map[string]any{"type": "object"})
+		if tool.Function.Parameters != nil {
+			inputSchema = map[string]any(tool.Function.Parameters)
+		}
+		anthropicTools = append(anthropicTools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description.Value,
+			InputSchema: inputSchema,
+		})
+	}
+	return anthropicTools
+}
+
+// convertAnthropicResponse converts a Messages API response to a chat completion. The input of
+// a structured output tool call becomes the message content.
+func convertAnthropicResponse(response *anthropicResponse) *openai.ChatCompletion {
+	var content strings.Builder
+	var toolCalls []openai.ChatCompletionMessageToolCall
+
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			content.WriteString(block.Text)
+		case "tool_use":
+			arguments := string(block.Input)
+			if arguments == "" {
+				arguments = "{}"
+			}
+			if block.Name == structuredOutputToolName {
+				content.Reset()
+				content.WriteString(arguments)
+				continue
+			}
+			toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
+				ID:   block.ID,
+				Type: "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      block.Name,
+					Arguments: arguments,
+				},
+			})
+		}
+	}
+
+	message := openai.ChatCompletionMessage{
+		Role:    "assistant",
+		Content: content.String(),
+	}
+	if len(toolCalls) > 0 {
+		message.ToolCalls = toolCalls
+	}
+
+	promptTokens := response.Usage.InputTokens + response.Usage.CacheCreationInputTokens + response.Usage.CacheReadInputTokens
+	return &openai.ChatCompletion{
+		ID:     response.ID,
+		Object: "chat.completion",
+		Model:  response.Model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Index:        0,
+				Message:      message,
+				FinishReason: anthropicFinishReason(response),
+			},
+		},
+		Usage: openai.CompletionUsage{
+			PromptTokens:        promptTokens,
+			CompletionTokens:    response.Usage.OutputTokens,
+			TotalTokens:         promptTokens + response.Usage.OutputTokens,
+			PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{CachedTokens: response.Usage.CacheReadInputTokens},
+		},
+	}
+}
+
+// anthropicFinishReason maps the stop reason to an OpenAI finish reason. A forced structured
+// output tool call finishes the response rather than requesting tool execution.
+func anthropicFinishReason(response *anthropicResponse) string {
+	switch response.StopReason {
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		for _, block := range response.Content {
+			if block.Type == "tool_use" && block.Name != structuredOutputToolName {
+				return "tool_calls"
+			}
+		}
+	}
+	return "stop"
+}
+
+func (ap *AnthropicProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl":    ap.BaseURL,
+		"apiVersion": ap.apiVersion(),
+	}
+	if ap.APIKey != "" {
+		config["apiKey"] = ap.APIKey
+	}
+	for key, value := range ap.Properties {
+		config[key] = value
+	}
+	return config
+}
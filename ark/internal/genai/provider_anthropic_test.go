@@ -0,0 +1,337 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// fakeAnthropicServer replies to every request with response and records the last request
+type fakeAnthropicServer struct {
+	*httptest.Server
+	request map[string]any
+	headers http.Header
+	path    string
+}
+
+func newFakeAnthropicServer(t *testing.T, status int, response string) *fakeAnthropicServer {
+	fake := &fakeAnthropicServer{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.path = r.URL.Path
+		fake.headers = r.Header.Clone()
+		fake.request = nil
+		_ = json.NewDecoder(r.Body).Decode(&fake.request)
+		if fake.request["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func newTestAnthropicProvider(baseURL string) *AnthropicProvider {
+	return &AnthropicProvider{
+		Model:   "claude-sonnet-4-5",
+		BaseURL: baseURL,
+		APIKey:  "test-key",
+		Headers: map[string]string{"X-Team": "ark"},
+	}
+}
+
+var weatherTool = openai.ChatCompletionToolParam{
+	Function: openai.FunctionDefinitionParam{
+		Name:        "get_weather",
+		Description: openai.String("Get the weather for a city"),
+		Parameters:  openai.FunctionParameters{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+	},
+}
+
+func TestAnthropicChatCompletion_ToolUse(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{
+		"id": "msg_1", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+		"content": [
+			{"type": "text", "text": "Checking the weather."},
+			{"type": "tool_use", "id": "toolu_2", "name": "get_weather", "input": {"city": "Paris"}}
+		],
+		"usage": {"input_tokens": 20, "output_tokens": 10, "cache_read_input_tokens": 5}
+	}`)
+	provider := newTestAnthropicProvider(server.URL)
+
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "toolu_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"London"}`}},
+	}
+	messages := []Message{
+		NewSystemMessage("You report the weather."),
+		NewUserMessage("Weather in London and Paris?"),
+		Message(assistant),
+		ToolMessage("rainy", "toolu_1"),
+		NewUserMessage("And Paris?"),
+	}
+
+	response, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/messages", server.path)
+	assert.Equal(t, "test-key", server.headers.Get("x-api-key"))
+	assert.Equal(t, anthropicDefaultAPIVersion, server.headers.Get("anthropic-version"))
+	assert.Equal(t, "ark", server.headers.Get("X-Team"))
+	assert.Empty(t, server.headers.Get("anthropic-beta"))
+
+	request, err := json.Marshal(server.request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "claude-sonnet-4-5",
+		"max_tokens": 4096,
+		"system": [{"type": "text", "text": "You report the weather."}],
+		"messages": [
+			{"role": "user", "content": [{"type": "text", "text": "Weather in London and Paris?"}]},
+			{"role": "assistant", "content": [{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"city": "London"}}]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "toolu_1", "content": "rainy"},
+				{"type": "text", "text": "And Paris?"}
+			]}
+		],
+		"tools": [{"name": "get_weather", "description": "Get the weather for a city", "input_schema": {"type": "object", "properties": {"city": {"type": "string"}}}}]
+	}`, string(request))
+
+	choice := response.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "Checking the weather.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "toolu_2", choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, "get_weather", choice.Message.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(25), response.Usage.PromptTokens)
+	assert.Equal(t, int64(5), response.Usage.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, int64(35), response.Usage.TotalTokens)
+}
+
+func TestAnthropicChatCompletion_StructuredOutput(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{
+		"id": "msg_1", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+		"content": [{"type": "tool_use", "id": "toolu_1", "name": "structured_output", "input": {"city": "Paris", "temperature": 21}}],
+		"usage": {"input_tokens": 10, "output_tokens": 5}
+	}`)
+	provider := newTestAnthropicProvider(server.URL)
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}, "weather")
+
+	response, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"type": "tool", "name": structuredOutputToolName}, server.request["tool_choice"])
+	tools := server.request["tools"].([]any)
+	require.Len(t, tools, 1)
+	assert.Equal(t, structuredOutputToolName, tools[0].(map[string]any)["name"])
+
+	choice := response.Choices[0]
+	assert.Equal(t, "stop", choice.FinishReason)
+	assert.Empty(t, choice.Message.ToolCalls)
+	assert.JSONEq(t, `{"city":"Paris","temperature":21}`, choice.Message.Content)
+}
+
+func TestAnthropicChatCompletion_StructuredOutputWithTools(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{"id": "msg_1", "stop_reason": "end_turn", "content": [{"type": "text", "text": "hi"}]}`)
+	provider := newTestAnthropicProvider(server.URL)
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}, "weather")
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	// The model may call the other tools before answering, or answer in text after them
+	assert.Equal(t, map[string]any{"type": "auto"}, server.request["tool_choice"])
+	assert.Len(t, server.request["tools"].([]any), 2)
+}
+
+func TestAnthropicChatCompletion_PromptCaching(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{"id": "msg_1", "stop_reason": "end_turn", "content": [{"type": "text", "text": "hi"}]}`)
+	provider := newTestAnthropicProvider(server.URL + "/v1")
+	provider.PromptCaching = true
+	provider.Properties = map[string]string{"max_tokens": "512", "temperature": "0.2"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewSystemMessage("system"), NewUserMessage("hi")}, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/messages", server.path)
+	assert.Empty(t, server.headers.Get("anthropic-beta"), "prompt caching is generally available")
+	assert.Equal(t, 512.0, server.request["max_tokens"])
+	assert.Equal(t, 0.2, server.request["temperature"])
+	ephemeral := map[string]any{"type": "ephemeral"}
+	assert.Equal(t, ephemeral, server.request["system"].([]any)[0].(map[string]any)["cache_control"])
+	assert.Equal(t, ephemeral, server.request["tools"].([]any)[0].(map[string]any)["cache_control"])
+}
+
+func TestAnthropicChatCompletion_Thinking(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{
+		"id": "msg_1", "model": "claude-sonnet-4-5", "stop_reason": "tool_use",
+		"content": [
+			{"type": "thinking", "thinking": "The user wants the weather.", "signature": "sig"},
+			{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"city": "Paris"}}
+		],
+		"usage": {"input_tokens": 20, "output_tokens": 10}
+	}`)
+	provider := newTestAnthropicProvider(server.URL)
+	provider.Properties = map[string]string{"temperature": "0.2", "top_k": "5"}
+	provider.SetReasoning(&arkv1alpha1.ModelReasoning{Effort: "medium", Summary: true})
+
+	ctx, summary := contextWithReasoningSummary(context.Background())
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+	response, err := provider.ChatCompletion(ctx, messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"type": "enabled", "budget_tokens": 4096.0}, server.request["thinking"])
+	assert.Equal(t, float64(4096+anthropicDefaultMaxTokens), server.request["max_tokens"])
+	assert.NotContains(t, server.request, "temperature")
+	assert.NotContains(t, server.request, "top_k")
+	assert.Equal(t, "The user wants the weather.", summary.String())
+
+	// The thinking block is sent back with the assistant message that called the tool
+	messages = append(messages, assistantMessageFromCompletion(response.Choices[0].Message), ToolMessage("sunny", "toolu_1"))
+	_, err = provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assistant := server.request["messages"].([]any)[1].(map[string]any)
+	content := assistant["content"].([]any)
+	require.Len(t, content, 2)
+	assert.Equal(t, map[string]any{"type": "thinking", "thinking": "The user wants the weather.", "signature": "sig"}, content[0])
+	assert.Equal(t, "tool_use", content[1].(map[string]any)["type"])
+
+	// The blocks belong to the message, so another conversation on the same provider does not get them
+	other := []Message{NewUserMessage("Weather in Rome?"), Message(response.Choices[0].Message.ToParam()), ToolMessage("sunny", "toolu_1")}
+	_, err = provider.ChatCompletion(context.Background(), other, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	content = server.request["messages"].([]any)[1].(map[string]any)["content"].([]any)
+	require.Len(t, content, 1)
+	assert.Equal(t, "tool_use", content[0].(map[string]any)["type"])
+
+	// The blocks are not sent to OpenAI compatible APIs
+	data, err := json.Marshal(withoutThinkingBlocks(messages[1]))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), anthropicThinkingField)
+}
+
+func TestAnthropicChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4-5","content":[],"usage":{"input_tokens":12,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"ping"}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me "}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"check."}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\": "}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":30}}`,
+		`{"type":"message_stop"}`,
+	}
+	var body string
+	for _, event := range events {
+		var parsed struct{ Type string }
+		require.NoError(t, json.Unmarshal([]byte(event), &parsed))
+		body += "event: " + parsed.Type + "\ndata: " + event + "\n\n"
+	}
+	server := newFakeAnthropicServer(t, http.StatusOK, body)
+	provider := newTestAnthropicProvider(server.URL)
+
+	var chunks []*openai.ChatCompletionChunk
+	response, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("weather?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, server.request["stream"])
+	assert.Equal(t, "text/event-stream", server.headers.Get("Accept"))
+
+	var streamedText, streamedArguments string
+	for _, chunk := range chunks {
+		streamedText += chunk.Choices[0].Delta.Content
+		for _, toolCall := range chunk.Choices[0].Delta.ToolCalls {
+			streamedArguments += toolCall.Function.Arguments
+		}
+	}
+	assert.Equal(t, "Let me check.", streamedText)
+	assert.Equal(t, `{"city": "Paris"}`, streamedArguments)
+	assert.Equal(t, "tool_calls", chunks[len(chunks)-1].Choices[0].FinishReason)
+
+	choice := response.Choices[0]
+	assert.Equal(t, "Let me check.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "toolu_1", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(12), response.Usage.PromptTokens)
+	assert.Equal(t, int64(30), response.Usage.CompletionTokens)
+}
+
+func TestAnthropicChatCompletion_Error(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusTooManyRequests, `{"type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}`)
+	provider := newTestAnthropicProvider(server.URL)
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+
+	var anthropicErr *AnthropicError
+	require.ErrorAs(t, err, &anthropicErr)
+	assert.Equal(t, "rate_limit_error", anthropicErr.Type)
+	assert.True(t, IsRetryableModelError(err))
+	assert.Equal(t, "Number of requests has exceeded your rate limit (429)", extractStableError(err, time.Second))
+}
+
+func TestConvertAnthropicMessages_Images(t *testing.T) {
+	user := openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What is this?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,aGVsbG8="}),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "https://example.com/cat.png"}),
+	})
+
+	messages, system := convertAnthropicMessages([]Message{Message(user)})
+
+	assert.Empty(t, system)
+	require.Len(t, messages, 1)
+	require.Len(t, messages[0].Content, 3)
+	assert.Equal(t, &anthropicImageSource{Type: "base64", MediaType: "image/png", Data: "aGVsbG8="}, messages[0].Content[1].Source)
+	assert.Equal(t, &anthropicImageSource{Type: "url", URL: "https://example.com/cat.png"}, messages[0].Content[2].Source)
+}
+
+func TestProbeModel_Anthropic(t *testing.T) {
+	server := newFakeAnthropicServer(t, http.StatusOK, `{"id": "msg_1", "stop_reason": "end_turn", "content": [{"type": "text", "text": "Hello!"}]}`)
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "claude", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "claude-sonnet-4-5"},
+			Provider: ProviderAnthropic,
+			Config: arkv1alpha1.ModelConfig{Anthropic: &arkv1alpha1.AnthropicModelConfig{
+				BaseURL: &arkv1alpha1.ValueSource{Value: server.URL},
+				APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}})
+
+	model, err := LoadModel(context.Background(), k8sClient, "claude", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	require.IsType(t, &AnthropicProvider{}, model.Provider)
+
+	result := ProbeModel(context.Background(), model)
+
+	assert.True(t, result.Available, result.Message)
+	assert.Equal(t, "claude-sonnet-4-5", server.request["model"])
+}
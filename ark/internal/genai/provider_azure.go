@@ -8,6 +8,7 @@ import (
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"k8s.io/apimachinery/pkg/runtime"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 )
 
@@ -20,6 +21,7 @@ type AzureProvider struct {
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	reasoning    *arkv1alpha1.ModelReasoning
 }
 
 func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -27,10 +29,14 @@ func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNam
 	ap.schemaName = schemaName
 }
 
+func (ap *AzureProvider) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	ap.reasoning = reasoning
+}
+
 func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+		openaiMessages[i] = withoutThinkingBlocks(msg)
 	}
 
 	params := openai.ChatCompletionNewParams{
@@ -40,6 +46,7 @@ func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message,
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyReasoningToParams(ap.reasoning, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -56,16 +63,20 @@ func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message,
 func (ap *AzureProvider) prepareStreamParams(messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) openai.ChatCompletionNewParams {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+		openaiMessages[i] = withoutThinkingBlocks(msg)
 	}
 
 	params := openai.ChatCompletionNewParams{
 		Model:    ap.Model,
 		Messages: openaiMessages,
 		N:        openai.Int(n),
+		StreamOptions: openai.ChatCompletionStreamOptionsParam{
+			IncludeUsage: openai.Bool(true),
+		},
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyReasoningToParams(ap.reasoning, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -94,6 +105,10 @@ func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Me
 
 		// Use the same accumulation logic as OpenAIProvider
 		accumulateStreamChunk(&chunk, &fullResponse, toolCallsMap)
+
+		if chunk.Usage.TotalTokens > 0 {
+			fullResponse.Usage = chunk.Usage
+		}
 	}
 
 	// Add accumulated tool calls to the response in index order
@@ -137,6 +152,12 @@ func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Me
 	return fullResponse, nil
 }
 
+// Embed calls the embeddings endpoint of the deployment
+func (ap *AzureProvider) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	client := ap.createClient(ctx)
+	return client.Embeddings.New(ctx, newEmbeddingParams(ap.Model, ap.Properties, inputs))
+}
+
 func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
 	var httpClient *http.Client
 	if IsProbeContext(ctx) {
@@ -151,6 +172,7 @@ func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
 		option.WithHeader("api-key", ap.APIKey),
 		option.WithAPIKey(ap.APIKey),
 		option.WithHTTPClient(httpClient),
+		openAIClientWithoutRetries,
 		option.WithQueryAdd("api-version", ap.APIVersion),
 	}
 
@@ -2,18 +2,29 @@ package genai
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
+const bedrockDefaultMaxTokens = 4096
+
+// BedrockModel calls models on AWS Bedrock through the Converse API, which has the same
+// request shape for every model family
 type BedrockModel struct {
 	Model           string
 	Region          string
@@ -26,45 +37,41 @@ type BedrockModel struct {
 	client          *bedrockruntime.Client
 	outputSchema    *runtime.RawExtension
 	schemaName      string
+	reasoning       *arkv1alpha1.ModelReasoning
 }
 
-type bedrockMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type bedrockRequest struct {
-	Messages         []bedrockMessage `json:"messages"`
-	MaxTokens        int              `json:"max_tokens"`
-	Temperature      float64          `json:"temperature"`
-	SystemPrompt     string           `json:"system,omitempty"`
-	AnthropicVersion string           `json:"anthropic_version,omitempty"`
-	Tools            []bedrockTool    `json:"tools,omitempty"`
-}
-
-type bedrockTool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
+// bedrockReasoningBlock is a reasoning block of a response, kept on the assistant message built
+// from it
+type bedrockReasoningBlock struct {
+	Text      string `json:"text,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Redacted  []byte `json:"redacted,omitempty"`
 }
 
-type bedrockResponse struct {
-	Content    []bedrockContent `json:"content"`
-	ID         string           `json:"id"`
-	Model      string           `json:"model"`
-	StopReason string           `json:"stop_reason"`
-	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+func (b bedrockReasoningBlock) contentBlock() types.ContentBlock {
+	var content types.ReasoningContentBlock = &types.ReasoningContentBlockMemberReasoningText{Value: types.ReasoningTextBlock{
+		Text:      aws.String(b.Text),
+		Signature: aws.String(b.Signature),
+	}}
+	if len(b.Redacted) > 0 {
+		content = &types.ReasoningContentBlockMemberRedactedContent{Value: b.Redacted}
+	}
+	return &types.ContentBlockMemberReasoningContent{Value: content}
 }
 
-type bedrockContent struct {
-	Text  string                 `json:"text,omitempty"`
-	Type  string                 `json:"type"`
-	ID    string                 `json:"id,omitempty"`
-	Name  string                 `json:"name,omitempty"`
-	Input map[string]interface{} `json:"input,omitempty"`
+// bedrockBlock is a content block of a Converse response, accumulated from either a complete
+// response or a stream
+type bedrockBlock struct {
+	text      string
+	toolUseID string
+	toolName  string
+	toolInput string
+	isToolUse bool
+	// reasoning, signature and redacted hold a reasoning block
+	reasoning   string
+	signature   string
+	redacted    []byte
+	isReasoning bool
 }
 
 func NewBedrockModel(model, region, baseURL, accessKeyID, secretAccessKey, sessionToken, modelArn string, properties map[string]string) *BedrockModel {
@@ -104,7 +111,7 @@ func (bm *BedrockModel) initClient(ctx context.Context) error {
 		cfg.BaseEndpoint = aws.String(bm.BaseURL)
 	}
 
-	bm.client = bedrockruntime.NewFromConfig(cfg)
+	bm.client = bedrockruntime.NewFromConfig(cfg, bedrockClientWithoutRetries)
 	return nil
 }
 
@@ -113,257 +120,672 @@ func (bm *BedrockModel) SetOutputSchema(schema *runtime.RawExtension, schemaName
 	bm.schemaName = schemaName
 }
 
+func (bm *BedrockModel) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	bm.reasoning = reasoning
+}
+
 func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	var toolsParam []openai.ChatCompletionToolParam
 	if len(tools) > 0 {
 		toolsParam = tools[0]
 	}
+	return bm.chatCompletion(ctx, messages, bm.outputSchema, toolsParam)
+}
+
+func (bm *BedrockModel) chatCompletion(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, toolsParam []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	if err := bm.initClient(ctx); err != nil {
 		return nil, err
 	}
 
-	bedrockMessages, systemPrompt := bm.convertMessages(messages)
-	bedrockTools := bm.convertTools(toolsParam)
+	input := bm.buildConverseInput(ctx, messages, outputSchema, toolsParam)
+	output, err := bm.client.Converse(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bedrock Converse: %w", err)
+	}
+
+	var blocks []bedrockBlock
+	if message, ok := output.Output.(*types.ConverseOutputMemberMessage); ok {
+		blocks = convertBedrockContent(message.Value.Content)
+	}
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(output.ResultMetadata)
+	return bm.complete(ctx, requestID, blocks, output.StopReason, output.Usage), nil
+}
 
-	request := bm.buildRequest(bedrockMessages, systemPrompt, bedrockTools)
+func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return bm.chatCompletion(ctx, messages, outputSchema, tools)
+}
 
-	if strings.Contains(strings.ToLower(bm.Model), "claude") {
-		request.AnthropicVersion = "bedrock-2023-05-31"
+// ChatCompletionStream calls ConverseStream and passes text and tool input deltas to streamFunc
+// as OpenAI chunks while the full response is accumulated
+func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	if err := bm.initClient(ctx); err != nil {
+		return nil, err
 	}
 
-	requestBody, err := json.Marshal(request)
+	var toolsParam []openai.ChatCompletionToolParam
+	if len(tools) > 0 {
+		toolsParam = tools[0]
+	}
+	input := bm.buildConverseInput(ctx, messages, bm.outputSchema, toolsParam)
+
+	output, err := bm.client.ConverseStream(ctx, &bedrockruntime.ConverseStreamInput{
+		ModelId:                      input.ModelId,
+		Messages:                     input.Messages,
+		System:                       input.System,
+		InferenceConfig:              input.InferenceConfig,
+		ToolConfig:                   input.ToolConfig,
+		AdditionalModelRequestFields: input.AdditionalModelRequestFields,
+	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to call Bedrock ConverseStream: %w", err)
 	}
+	requestID, _ := awsmiddleware.GetRequestIDMetadata(output.ResultMetadata)
 
-	modelID := bm.Model
-	if bm.ModelArn != "" {
-		modelID = bm.ModelArn
+	eventStream := output.GetStream()
+	defer func() { _ = eventStream.Close() }()
+
+	stream := &bedrockStream{model: bm, id: requestID, streamFunc: streamFunc, blocks: map[int32]*bedrockBlock{}, toolIndexes: map[int32]int64{}}
+	for event := range eventStream.Events() {
+		if err := stream.handle(event); err != nil {
+			return nil, err
+		}
 	}
+	if err := eventStream.Err(); err != nil {
+		return nil, fmt.Errorf("bedrock stream failed: %w", err)
+	}
+
+	return bm.complete(ctx, requestID, stream.orderedBlocks(), stream.stopReason, stream.usage), nil
+}
 
-	input := &bedrockruntime.InvokeModelInput{
-		ModelId:     aws.String(modelID),
-		Body:        requestBody,
-		ContentType: aws.String("application/json"),
-		Accept:      aws.String("application/json"),
+// complete builds the completion of a response, reporting the model's reasoning as its summary
+// and keeping the reasoning blocks on the message for the next call
+func (bm *BedrockModel) complete(ctx context.Context, id string, blocks []bedrockBlock, stopReason types.StopReason, usage *types.TokenUsage) *openai.ChatCompletion {
+	completion := bm.buildCompletion(id, blocks, stopReason, usage)
+	if bm.reasoning == nil {
+		return completion
 	}
 
-	result, err := bm.client.InvokeModel(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+	var reasoning []bedrockReasoningBlock
+	for _, block := range blocks {
+		if !block.isReasoning {
+			continue
+		}
+		if bm.reasoning.Summary {
+			addReasoningSummary(ctx, block.reasoning)
+		}
+		reasoning = append(reasoning, bedrockReasoningBlock{Text: block.reasoning, Signature: block.signature, Redacted: block.redacted})
+	}
+	if len(reasoning) > 0 {
+		completion.Choices[0].Message = withThinkingBlocks(completion.Choices[0].Message, bedrockReasoningField, reasoning)
 	}
+	return completion
+}
 
-	var response bedrockResponse
-	if err := json.Unmarshal(result.Body, &response); err != nil {
-		return nil, err
+// bedrockStream accumulates ConverseStream events and forwards deltas as OpenAI chunks
+type bedrockStream struct {
+	model       *BedrockModel
+	id          string
+	streamFunc  func(*openai.ChatCompletionChunk) error
+	blocks      map[int32]*bedrockBlock
+	order       []int32
+	toolIndexes map[int32]int64
+	stopReason  types.StopReason
+	usage       *types.TokenUsage
+}
+
+func (s *bedrockStream) handle(event types.ConverseStreamOutput) error {
+	switch e := event.(type) {
+	case *types.ConverseStreamOutputMemberMessageStart:
+		return s.emit(openai.ChatCompletionChunkChoiceDelta{Role: "assistant"}, "")
+	case *types.ConverseStreamOutputMemberContentBlockStart:
+		index := aws.ToInt32(e.Value.ContentBlockIndex)
+		toolUse, ok := e.Value.Start.(*types.ContentBlockStartMemberToolUse)
+		if !ok {
+			return nil
+		}
+		block := s.block(index)
+		block.isToolUse = true
+		block.toolUseID = aws.ToString(toolUse.Value.ToolUseId)
+		block.toolName = aws.ToString(toolUse.Value.Name)
+		if block.toolName == structuredOutputToolName {
+			return nil
+		}
+		toolIndex := int64(len(s.toolIndexes))
+		s.toolIndexes[index] = toolIndex
+		return s.emit(openai.ChatCompletionChunkChoiceDelta{
+			ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    toolIndex,
+				ID:       block.toolUseID,
+				Type:     "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: block.toolName},
+			}},
+		}, "")
+	case *types.ConverseStreamOutputMemberContentBlockDelta:
+		index := aws.ToInt32(e.Value.ContentBlockIndex)
+		block := s.block(index)
+		switch delta := e.Value.Delta.(type) {
+		case *types.ContentBlockDeltaMemberText:
+			block.text += delta.Value
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{Content: delta.Value}, "")
+		case *types.ContentBlockDeltaMemberReasoningContent:
+			block.isReasoning = true
+			switch reasoning := delta.Value.(type) {
+			case *types.ReasoningContentBlockDeltaMemberText:
+				block.reasoning += reasoning.Value
+			case *types.ReasoningContentBlockDeltaMemberSignature:
+				block.signature += reasoning.Value
+			case *types.ReasoningContentBlockDeltaMemberRedactedContent:
+				block.redacted = append(block.redacted, reasoning.Value...)
+			}
+		case *types.ContentBlockDeltaMemberToolUse:
+			partial := aws.ToString(delta.Value.Input)
+			block.toolInput += partial
+			if block.toolName == structuredOutputToolName {
+				return s.emit(openai.ChatCompletionChunkChoiceDelta{Content: partial}, "")
+			}
+			return s.emit(openai.ChatCompletionChunkChoiceDelta{
+				ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+					Index:    s.toolIndexes[index],
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: partial},
+				}},
+			}, "")
+		}
+	case *types.ConverseStreamOutputMemberMessageStop:
+		s.stopReason = e.Value.StopReason
+		return s.emit(openai.ChatCompletionChunkChoiceDelta{}, bedrockFinishReason(s.stopReason, s.orderedBlocks()))
+	case *types.ConverseStreamOutputMemberMetadata:
+		s.usage = e.Value.Usage
 	}
+	return nil
+}
 
-	return bm.convertResponse(response), nil
+func (s *bedrockStream) block(index int32) *bedrockBlock {
+	block, ok := s.blocks[index]
+	if !ok {
+		block = &bedrockBlock{}
+		s.blocks[index] = block
+		s.order = append(s.order, index)
+	}
+	return block
 }
 
-func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	return bm.ChatCompletion(ctx, messages, 1, tools)
+func (s *bedrockStream) orderedBlocks() []bedrockBlock {
+	blocks := make([]bedrockBlock, 0, len(s.order))
+	for _, index := range s.order {
+		blocks = append(blocks, *s.blocks[index])
+	}
+	return blocks
 }
 
-func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	// Per OpenAI spec, when streaming is requested for a model that doesn't support it,
-	// return the complete response as a single chunk
-	completion, err := bm.ChatCompletion(ctx, messages, n, tools...)
-	if err != nil {
-		return nil, err
+func (s *bedrockStream) emit(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) error {
+	if delta.Content == "" && len(delta.ToolCalls) == 0 && finishReason == "" && delta.Role == "" {
+		return nil
 	}
+	return s.streamFunc(&openai.ChatCompletionChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Model:   s.model.Model,
+		Choices: []openai.ChatCompletionChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+	})
+}
 
-	// Convert the completion to a single streaming chunk
-	// We send the full content in one chunk as per the OpenAI fallback spec
-	for _, choice := range completion.Choices {
-		chunk := &openai.ChatCompletionChunk{
-			ID:      completion.ID,
-			Object:  "chat.completion.chunk",
-			Created: completion.Created,
-			Model:   completion.Model,
-			Choices: []openai.ChatCompletionChunkChoice{
-				{
-					Index: choice.Index,
-					Delta: openai.ChatCompletionChunkChoiceDelta{
-						Content: choice.Message.Content,
-						Role:    "assistant",
-					},
-					FinishReason: choice.FinishReason,
-				},
-			},
+func (bm *BedrockModel) modelID() string {
+	if bm.ModelArn != "" {
+		return bm.ModelArn
+	}
+	return bm.Model
+}
+
+// supportsThinking reports whether the model family takes a thinking budget, which Bedrock
+// only supports for Anthropic Claude models
+func (bm *BedrockModel) supportsThinking() bool {
+	modelID := strings.ToLower(bm.modelID())
+	return strings.Contains(modelID, "anthropic") || strings.Contains(modelID, "claude")
+}
+
+// supportsToolChoice reports whether the model family accepts toolChoice, which Bedrock
+// only supports for Anthropic, Mistral Large and Amazon Nova models
+func (bm *BedrockModel) supportsToolChoice() bool {
+	modelID := strings.ToLower(bm.modelID())
+	for _, family := range []string{"anthropic", "claude", "mistral-large", "nova"} {
+		if strings.Contains(modelID, family) {
+			return true
 		}
+	}
+	return false
+}
 
-		// Send the chunk via the stream callback
-		if err := streamFunc(chunk); err != nil {
-			return nil, err
+func (bm *BedrockModel) buildConverseInput(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, toolsParam []openai.ChatCompletionToolParam) *bedrockruntime.ConverseInput {
+	tools := convertBedrockTools(toolsParam)
+
+	// Structured output is requested by forcing a call to a tool whose input schema is the
+	// output schema. Models that cannot be forced to call a tool get the schema as instructions.
+	// Thinking cannot be combined with a forced tool call, so with thinking the output schema is
+	// given as instructions
+	thinking := bm.reasoning != nil && bm.supportsThinking()
+	if bm.reasoning != nil && !thinking {
+		logf.FromContext(ctx).V(1).Info("reasoning settings are only sent to Claude models on Bedrock", "model", bm.modelID())
+	}
+	var toolChoice types.ToolChoice
+	var schemaInstructions *runtime.RawExtension
+	if outputSchema != nil && len(outputSchema.Raw) > 0 {
+		var schema any
+		if err := json.Unmarshal(outputSchema.Raw, &schema); err == nil && bm.supportsToolChoice() && !thinking {
+			tools = append(tools, &types.ToolMemberToolSpec{Value: types.ToolSpecification{
+				Name:        aws.String(structuredOutputToolName),
+				Description: aws.String("Respond with the final answer in the required format"),
+				InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(schema)},
+			}})
+			if len(tools) == 1 {
+				toolChoice = &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(structuredOutputToolName)}}
+			} else {
+				toolChoice = &types.ToolChoiceMemberAny{}
+			}
+		} else {
+			schemaInstructions = outputSchema
 		}
 	}
 
-	// Return the original completion
-	return completion, nil
-}
+	bedrockMessages, systemPrompt := convertBedrockMessages(ctx, messages, len(tools) > 0, thinking)
+	systemPrompt = withOutputSchemaInstructions(systemPrompt, schemaInstructions)
 
-func (bm *BedrockModel) buildRequest(messages []bedrockMessage, systemPrompt string, tools []bedrockTool) bedrockRequest {
-	temperature := getFloatProperty(bm.Properties, "temperature", 1.0)
-	maxTokens := getIntProperty(bm.Properties, "max_tokens", 4096)
+	input := &bedrockruntime.ConverseInput{
+		ModelId:         aws.String(bm.modelID()),
+		Messages:        bedrockMessages,
+		InferenceConfig: bm.buildInferenceConfig(),
+	}
+	if systemPrompt != "" {
+		input.System = []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: systemPrompt}}
+	}
+	if len(tools) > 0 {
+		input.ToolConfig = &types.ToolConfiguration{Tools: tools, ToolChoice: toolChoice}
+	}
+	// Thinking cannot be combined with a temperature or top_k
+	if thinking {
+		budget := reasoningBudget(bm.reasoning)
+		input.InferenceConfig.MaxTokens = aws.Int32(int32(reasoningMaxTokens(int(aws.ToInt32(input.InferenceConfig.MaxTokens)), budget)))
+		input.InferenceConfig.Temperature = nil
+		input.AdditionalModelRequestFields = document.NewLazyDocument(map[string]any{
+			"thinking": map[string]any{"type": "enabled", "budget_tokens": budget},
+		})
+	} else if _, ok := bm.Properties["top_k"]; ok {
+		if fields := bm.topKFields(getIntProperty(bm.Properties, "top_k", 0)); fields != nil {
+			input.AdditionalModelRequestFields = document.NewLazyDocument(fields)
+		} else {
+			logf.FromContext(ctx).V(1).Info("top_k is not sent to this model family on Bedrock", "model", bm.modelID())
+		}
+	}
+	return input
+}
 
-	return bedrockRequest{
-		Messages:     messages,
-		MaxTokens:    maxTokens,
-		Temperature:  temperature,
-		SystemPrompt: systemPrompt,
-		Tools:        tools,
+// topKFields returns the additional request fields that set top_k in the format of the model
+// family, or nil for families that do not support it, which reject unknown fields
+func (bm *BedrockModel) topKFields(topK int) map[string]any {
+	modelID := strings.ToLower(bm.modelID())
+	switch {
+	case strings.Contains(modelID, "anthropic"), strings.Contains(modelID, "claude"), strings.Contains(modelID, "mistral"):
+		return map[string]any{"top_k": topK}
+	case strings.Contains(modelID, "cohere"):
+		return map[string]any{"k": topK}
+	case strings.Contains(modelID, "nova"):
+		return map[string]any{"inferenceConfig": map[string]any{"topK": topK}}
 	}
+	return nil
 }
 
-func (bm *BedrockModel) convertMessages(messages []Message) ([]bedrockMessage, string) {
-	var bedrockMessages []bedrockMessage
-	var systemPrompt string
+func (bm *BedrockModel) buildInferenceConfig() *types.InferenceConfiguration {
+	inferenceConfig := &types.InferenceConfiguration{
+		MaxTokens: aws.Int32(int32(getIntProperty(bm.Properties, "max_tokens", bedrockDefaultMaxTokens))),
+	}
+	if _, ok := bm.Properties["temperature"]; ok {
+		inferenceConfig.Temperature = aws.Float32(float32(getFloatProperty(bm.Properties, "temperature", 1.0)))
+	}
+	if _, ok := bm.Properties["top_p"]; ok {
+		inferenceConfig.TopP = aws.Float32(float32(getFloatProperty(bm.Properties, "top_p", 1.0)))
+	}
+	return inferenceConfig
+}
 
-	for _, msg := range messages {
-		content, role := extractMessageContent(msg)
-		if content == "" {
-			continue
+// convertBedrockMessages converts messages to Converse format. Consecutive messages with the
+// same role are merged as Converse requires roles to alternate, and tool results are sent as
+// user content. Converse rejects tool blocks in requests without tools, so without tools the
+// tool calls and results in the history are sent as text. With thinking enabled, the reasoning
+// blocks kept on assistant messages are sent back before their content.
+func convertBedrockMessages(ctx context.Context, messages []Message, toolsEnabled, thinking bool) ([]types.Message, string) {
+	var result []types.Message
+	var systemPrompts []string
+
+	appendBlocks := func(role types.ConversationRole, blocks []types.ContentBlock) {
+		if len(blocks) == 0 {
+			return
+		}
+		if last := len(result) - 1; last >= 0 && result[last].Role == role {
+			result[last].Content = append(result[last].Content, blocks...)
+			return
 		}
+		result = append(result, types.Message{Role: role, Content: blocks})
+	}
 
-		switch role {
-		case RoleSystem:
-			systemPrompt = content
-		case RoleUser, RoleAssistant, RoleTool:
-			msgRole := role
-			if role == RoleTool {
-				msgRole = RoleUser
+	for _, msg := range messages {
+		union := openai.ChatCompletionMessageParamUnion(msg)
+		switch {
+		case union.OfSystem != nil:
+			text := union.OfSystem.Content.OfString.Value
+			for _, part := range union.OfSystem.Content.OfArrayOfContentParts {
+				text += part.Text
 			}
-			bedrockMessages = append(bedrockMessages, bedrockMessage{
-				Role:    msgRole,
-				Content: content,
-			})
+			if text != "" {
+				systemPrompts = append(systemPrompts, text)
+			}
+		case union.OfDeveloper != nil:
+			text := union.OfDeveloper.Content.OfString.Value
+			for _, part := range union.OfDeveloper.Content.OfArrayOfContentParts {
+				text += part.Text
+			}
+			if text != "" {
+				systemPrompts = append(systemPrompts, text)
+			}
+		case union.OfUser != nil:
+			blocks := bedrockTextBlocks(union.OfUser.Content.OfString.Value)
+			for _, part := range union.OfUser.Content.OfArrayOfContentParts {
+				switch {
+				case part.OfText != nil:
+					blocks = append(blocks, bedrockTextBlocks(part.OfText.Text)...)
+				case part.OfImageURL != nil:
+					image, err := bedrockImage(part.OfImageURL.ImageURL.URL)
+					if err != nil {
+						logf.FromContext(ctx).Info("skipping image that cannot be sent to Bedrock", "error", err.Error())
+						continue
+					}
+					blocks = append(blocks, image)
+				}
+			}
+			appendBlocks(types.ConversationRoleUser, blocks)
+		case union.OfAssistant != nil:
+			var blocks []types.ContentBlock
+			var reasoning []bedrockReasoningBlock
+			if thinking && thinkingBlocks(union.OfAssistant, bedrockReasoningField, &reasoning) {
+				for _, block := range reasoning {
+					blocks = append(blocks, block.contentBlock())
+				}
+			}
+			blocks = append(blocks, bedrockTextBlocks(union.OfAssistant.Content.OfString.Value)...)
+			for _, part := range union.OfAssistant.Content.OfArrayOfContentParts {
+				if part.OfText != nil {
+					blocks = append(blocks, bedrockTextBlocks(part.OfText.Text)...)
+				}
+			}
+			for _, toolCall := range union.OfAssistant.ToolCalls {
+				if !toolsEnabled {
+					blocks = append(blocks, bedrockTextBlocks(fmt.Sprintf("Called tool %s with arguments %s", toolCall.Function.Name, toolCall.Function.Arguments))...)
+					continue
+				}
+				var input any = map[string]any{}
+				if toolCall.Function.Arguments != "" {
+					_ = json.Unmarshal([]byte(toolCall.Function.Arguments), &input)
+				}
+				blocks = append(blocks, &types.ContentBlockMemberToolUse{Value: types.ToolUseBlock{
+					ToolUseId: aws.String(toolCall.ID),
+					Name:      aws.String(toolCall.Function.Name),
+					Input:     document.NewLazyDocument(input),
+				}})
+			}
+			appendBlocks(types.ConversationRoleAssistant, blocks)
+		case union.OfTool != nil:
+			content := union.OfTool.Content.OfString.Value
+			for _, part := range union.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			if !toolsEnabled {
+				appendBlocks(types.ConversationRoleUser, bedrockTextBlocks(fmt.Sprintf("Tool result: %s", content)))
+				continue
+			}
+			appendBlocks(types.ConversationRoleUser, []types.ContentBlock{&types.ContentBlockMemberToolResult{Value: types.ToolResultBlock{
+				ToolUseId: aws.String(union.OfTool.ToolCallID),
+				Content:   []types.ToolResultContentBlock{&types.ToolResultContentBlockMemberText{Value: content}},
+			}}})
 		}
 	}
 
-	return bedrockMessages, systemPrompt
+	return result, strings.Join(systemPrompts, "\n\n")
 }
 
-func (bm *BedrockModel) convertResponse(response bedrockResponse) *openai.ChatCompletion {
-	var content string
-	var toolCalls []openai.ChatCompletionMessageToolCall
+// bedrockTextBlocks returns a text block for text, or none as Converse rejects empty text blocks
+func bedrockTextBlocks(text string) []types.ContentBlock {
+	if text == "" {
+		return nil
+	}
+	return []types.ContentBlock{&types.ContentBlockMemberText{Value: text}}
+}
 
-	for _, c := range response.Content {
-		switch c.Type {
-		case "text":
-			content = c.Text
-		case "tool_use":
-			toolCall := openai.ChatCompletionMessageToolCall{
-				ID:   c.ID,
-				Type: "function",
-				Function: openai.ChatCompletionMessageToolCallFunction{
-					Name:      c.Name,
-					Arguments: mustMarshalJSON(c.Input),
-				},
+// bedrockImage converts a base64 data URL to an image block. Converse cannot fetch image URLs.
+func bedrockImage(url string) (types.ContentBlock, error) {
+	rest, ok := strings.CutPrefix(url, "data:image/")
+	if !ok {
+		return nil, fmt.Errorf("only base64 data URLs are supported for images")
+	}
+	format, data, ok := strings.Cut(rest, ";base64,")
+	if !ok {
+		return nil, fmt.Errorf("only base64 data URLs are supported for images")
+	}
+	if format == "jpg" {
+		format = string(types.ImageFormatJpeg)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 image data: %w", err)
+	}
+	return &types.ContentBlockMemberImage{Value: types.ImageBlock{
+		Format: types.ImageFormat(format),
+		Source: &types.ImageSourceMemberBytes{Value: decoded},
+	}}, nil
+}
+
+func convertBedrockTools(tools []openai.ChatCompletionToolParam) []types.Tool {
+	var bedrockTools []types.Tool
+	for _, tool := range tools {
+		var inputSchema any = map[string]any{"type": "object"}
+		if tool.Function.Parameters != nil {
+			inputSchema = map[string]any(tool.Function.Parameters)
+		}
+		spec := types.ToolSpecification{
+			Name:        aws.String(tool.Function.Name),
+			InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(inputSchema)},
+		}
+		if tool.Function.Description.Value != "" {
+			spec.Description = aws.String(tool.Function.Description.Value)
+		}
+		bedrockTools = append(bedrockTools, &types.ToolMemberToolSpec{Value: spec})
+	}
+	return bedrockTools
+}
+
+func convertBedrockContent(content []types.ContentBlock) []bedrockBlock {
+	var blocks []bedrockBlock
+	for _, block := range content {
+		switch b := block.(type) {
+		case *types.ContentBlockMemberText:
+			blocks = append(blocks, bedrockBlock{text: b.Value})
+		case *types.ContentBlockMemberReasoningContent:
+			switch reasoning := b.Value.(type) {
+			case *types.ReasoningContentBlockMemberReasoningText:
+				blocks = append(blocks, bedrockBlock{isReasoning: true, reasoning: aws.ToString(reasoning.Value.Text), signature: aws.ToString(reasoning.Value.Signature)})
+			case *types.ReasoningContentBlockMemberRedactedContent:
+				blocks = append(blocks, bedrockBlock{isReasoning: true, redacted: reasoning.Value})
+			}
+		case *types.ContentBlockMemberToolUse:
+			input := "{}"
+			if b.Value.Input != nil {
+				if data, err := b.Value.Input.MarshalSmithyDocument(); err == nil {
+					input = string(data)
+				}
 			}
-			toolCalls = append(toolCalls, toolCall)
+			blocks = append(blocks, bedrockBlock{
+				isToolUse: true,
+				toolUseID: aws.ToString(b.Value.ToolUseId),
+				toolName:  aws.ToString(b.Value.Name),
+				toolInput: input,
+			})
 		}
 	}
+	return blocks
+}
 
-	finishReason := "stop"
-	switch response.StopReason {
-	case "max_tokens":
-		finishReason = "length"
-	case "tool_use":
-		finishReason = "tool_calls"
+// buildCompletion converts Converse content to a chat completion. The input of a structured
+// output tool call becomes the message content.
+func (bm *BedrockModel) buildCompletion(id string, blocks []bedrockBlock, stopReason types.StopReason, usage *types.TokenUsage) *openai.ChatCompletion {
+	var content strings.Builder
+	var toolCalls []openai.ChatCompletionMessageToolCall
+
+	for _, block := range blocks {
+		if block.isReasoning {
+			continue
+		}
+		if !block.isToolUse {
+			content.WriteString(block.text)
+			continue
+		}
+		arguments := block.toolInput
+		if arguments == "" {
+			arguments = "{}"
+		}
+		if block.toolName == structuredOutputToolName {
+			content.Reset()
+			content.WriteString(arguments)
+			continue
+		}
+		toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
+			ID:   block.toolUseID,
+			Type: "function",
+			Function: openai.ChatCompletionMessageToolCallFunction{
+				Name:      block.toolName,
+				Arguments: arguments,
+			},
+		})
 	}
 
 	message := openai.ChatCompletionMessage{
 		Role:    "assistant",
-		Content: content,
+		Content: content.String(),
 	}
-
 	if len(toolCalls) > 0 {
 		message.ToolCalls = toolCalls
 	}
 
 	return &openai.ChatCompletion{
-		ID:     response.ID,
+		ID:     id,
 		Object: "chat.completion",
-		Model:  response.Model,
+		Model:  bm.Model,
 		Choices: []openai.ChatCompletionChoice{
 			{
 				Index:        0,
 				Message:      message,
-				FinishReason: finishReason,
+				FinishReason: bedrockFinishReason(stopReason, blocks),
 			},
 		},
-		Usage: openai.CompletionUsage{
-			PromptTokens:     int64(response.Usage.InputTokens),
-			CompletionTokens: int64(response.Usage.OutputTokens),
-			TotalTokens:      int64(response.Usage.InputTokens + response.Usage.OutputTokens),
-		},
+		Usage: convertBedrockUsage(usage),
 	}
 }
 
-func mustMarshalJSON(v interface{}) string {
-	if v == nil {
-		return "{}"
+// bedrockFinishReason maps the stop reason to an OpenAI finish reason. A forced structured
+// output tool call finishes the response rather than requesting tool execution.
+func bedrockFinishReason(stopReason types.StopReason, blocks []bedrockBlock) string {
+	switch stopReason {
+	case types.StopReasonMaxTokens:
+		return "length"
+	case types.StopReasonGuardrailIntervened, types.StopReasonContentFiltered:
+		return "content_filter"
+	case types.StopReasonToolUse:
+		for _, block := range blocks {
+			if block.isToolUse && block.toolName != structuredOutputToolName {
+				return "tool_calls"
+			}
+		}
 	}
-	data, err := json.Marshal(v)
-	if err != nil {
-		return "{}"
+	return "stop"
+}
+
+func convertBedrockUsage(usage *types.TokenUsage) openai.CompletionUsage {
+	if usage == nil {
+		return openai.CompletionUsage{}
+	}
+	cacheRead := int64(aws.ToInt32(usage.CacheReadInputTokens))
+	promptTokens := int64(aws.ToInt32(usage.InputTokens)) + cacheRead + int64(aws.ToInt32(usage.CacheWriteInputTokens))
+	completionTokens := int64(aws.ToInt32(usage.OutputTokens))
+	return openai.CompletionUsage{
+		PromptTokens:        promptTokens,
+		CompletionTokens:    completionTokens,
+		TotalTokens:         promptTokens + completionTokens,
+		PromptTokensDetails: openai.CompletionUsagePromptTokensDetails{CachedTokens: cacheRead},
 	}
-	return string(data)
 }
 
-func (bm *BedrockModel) convertTools(tools []openai.ChatCompletionToolParam) []bedrockTool {
-	var bedrockTools []bedrockTool
+// Embed calls InvokeModel with the request format of the embedding model family. Titan models
+// embed one input per call and Cohere models embed all inputs in one call.
+func (bm *BedrockModel) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	if err := bm.initClient(ctx); err != nil {
+		return nil, err
+	}
 
-	for _, tool := range tools {
-		if tool.Type == "function" {
-			bedrockTool := bedrockTool{
-				Name: tool.Function.Name,
+	response := &openai.CreateEmbeddingResponse{Model: bm.Model, Object: "list"}
+	modelID := strings.ToLower(bm.modelID())
+	switch {
+	case strings.Contains(modelID, "titan-embed"):
+		for i, input := range inputs {
+			request := map[string]any{"inputText": input}
+			if dimensions := getIntProperty(bm.Properties, "dimensions", 0); dimensions > 0 {
+				request["dimensions"] = dimensions
 			}
-
-			if tool.Function.Description.Value != "" {
-				bedrockTool.Description = tool.Function.Description.Value
+			var output struct {
+				Embedding           []float64 `json:"embedding"`
+				InputTextTokenCount int64     `json:"inputTextTokenCount"`
 			}
-
-			if tool.Function.Parameters != nil {
-				bedrockTool.InputSchema = map[string]interface{}(tool.Function.Parameters)
+			if err := bm.invokeModel(ctx, request, &output); err != nil {
+				return nil, err
 			}
-
-			bedrockTools = append(bedrockTools, bedrockTool)
+			response.Data = append(response.Data, openai.Embedding{Embedding: output.Embedding, Index: int64(i), Object: "embedding"})
+			response.Usage.PromptTokens += output.InputTextTokenCount
 		}
-	}
-
-	return bedrockTools
-}
-
-func extractMessageContent(msg Message) (string, string) {
-	openaiMsg := openai.ChatCompletionMessageParamUnion(msg)
-
-	if systemMsg := openaiMsg.OfSystem; systemMsg != nil {
-		if content := systemMsg.Content.OfString; content.Value != "" {
-			return content.Value, "system"
+	case strings.Contains(modelID, "cohere.embed"):
+		inputType := "search_document"
+		if value, ok := bm.Properties["input_type"]; ok {
+			inputType = value
 		}
-	}
-
-	if userMsg := openaiMsg.OfUser; userMsg != nil {
-		if content := userMsg.Content.OfString; content.Value != "" {
-			return content.Value, RoleUser
+		var output struct {
+			Embeddings [][]float64 `json:"embeddings"`
 		}
-	}
-
-	if assistantMsg := openaiMsg.OfAssistant; assistantMsg != nil {
-		if content := assistantMsg.Content.OfString; content.Value != "" {
-			return content.Value, "assistant"
+		if err := bm.invokeModel(ctx, map[string]any{"texts": inputs, "input_type": inputType}, &output); err != nil {
+			return nil, err
 		}
-	}
-
-	if toolMsg := openaiMsg.OfTool; toolMsg != nil {
-		if content := toolMsg.Content.OfString; content.Value != "" {
-			return content.Value, "tool"
+		for i, embedding := range output.Embeddings {
+			response.Data = append(response.Data, openai.Embedding{Embedding: embedding, Index: int64(i), Object: "embedding"})
 		}
+	default:
+		return nil, fmt.Errorf("bedrock model %s is not a supported embedding model: use an Amazon Titan or Cohere embedding model", bm.Model)
 	}
+	response.Usage.TotalTokens = response.Usage.PromptTokens
+
+	return response, nil
+}
 
-	return "", ""
+func (bm *BedrockModel) invokeModel(ctx context.Context, request any, output any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Bedrock request: %w", err)
+	}
+	result, err := bm.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(bm.modelID()),
+		Body:        body,
+		ContentType: aws.String(ContentTypeJSON),
+		Accept:      aws.String(ContentTypeJSON),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call Bedrock InvokeModel: %w", err)
+	}
+	if err := json.Unmarshal(result.Body, output); err != nil {
+		return fmt.Errorf("failed to decode Bedrock response: %w", err)
+	}
+	return nil
 }
 
 func (bm *BedrockModel) BuildConfig() map[string]any {
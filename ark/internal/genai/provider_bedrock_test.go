@@ -0,0 +1,270 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// fakeBedrockServer replies to every Converse request with response and records the last request
+type fakeBedrockServer struct {
+	*httptest.Server
+	request map[string]any
+	path    string
+}
+
+func newFakeBedrockServer(t *testing.T, response string) *fakeBedrockServer {
+	fake := &fakeBedrockServer{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.path = r.URL.Path
+		fake.request = nil
+		_ = json.NewDecoder(r.Body).Decode(&fake.request)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func newTestBedrockModel(model, baseURL string) *BedrockModel {
+	return NewBedrockModel(model, "us-east-1", baseURL, "test-access-key", "test-secret-key", "", "", nil)
+}
+
+func TestBedrockChatCompletion_ToolUse(t *testing.T) {
+	server := newFakeBedrockServer(t, `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"text": "Checking the weather."},
+			{"toolUse": {"toolUseId": "tool_2", "name": "get_weather", "input": {"city": "Paris"}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 20, "outputTokens": 10, "totalTokens": 30},
+		"metrics": {"latencyMs": 100}
+	}`)
+	model := newTestBedrockModel("meta.llama3-1-70b-instruct-v1:0", server.URL)
+
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "tool_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"London"}`}},
+	}
+	messages := []Message{
+		NewSystemMessage("You report the weather."),
+		NewUserMessage("Weather in London and Paris?"),
+		Message(assistant),
+		ToolMessage("rainy", "tool_1"),
+	}
+
+	response, err := model.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/model/meta.llama3-1-70b-instruct-v1:0/converse", server.path)
+	request, err := json.Marshal(server.request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"system": [{"text": "You report the weather."}],
+		"messages": [
+			{"role": "user", "content": [{"text": "Weather in London and Paris?"}]},
+			{"role": "assistant", "content": [{"toolUse": {"toolUseId": "tool_1", "name": "get_weather", "input": {"city": "London"}}}]},
+			{"role": "user", "content": [{"toolResult": {"toolUseId": "tool_1", "content": [{"text": "rainy"}]}}]}
+		],
+		"inferenceConfig": {"maxTokens": 4096},
+		"toolConfig": {"tools": [{"toolSpec": {
+			"name": "get_weather",
+			"description": "Get the weather for a city",
+			"inputSchema": {"json": {"type": "object", "properties": {"city": {"type": "string"}}}}
+		}}]}
+	}`, string(request))
+
+	choice := response.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "Checking the weather.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "tool_2", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(20), response.Usage.PromptTokens)
+	assert.Equal(t, int64(30), response.Usage.TotalTokens)
+}
+
+func TestBedrockChatCompletion_StructuredOutputWithToolChoice(t *testing.T) {
+	server := newFakeBedrockServer(t, `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"toolUse": {"toolUseId": "tool_1", "name": "structured_output", "input": {"city": "Paris", "temperature": 21}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 10, "outputTokens": 5, "totalTokens": 15}
+	}`)
+	model := newTestBedrockModel("us.anthropic.claude-3-5-haiku-20241022-v1:0", server.URL)
+	model.SetOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}, "weather")
+
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1)
+	require.NoError(t, err)
+
+	toolConfig := server.request["toolConfig"].(map[string]any)
+	assert.Equal(t, map[string]any{"tool": map[string]any{"name": structuredOutputToolName}}, toolConfig["toolChoice"])
+	assert.NotContains(t, server.request, "system")
+
+	choice := response.Choices[0]
+	assert.Equal(t, "stop", choice.FinishReason)
+	assert.Empty(t, choice.Message.ToolCalls)
+	assert.JSONEq(t, `{"city":"Paris","temperature":21}`, choice.Message.Content)
+}
+
+func TestBedrockChatCompletion_StructuredOutputWithoutToolChoice(t *testing.T) {
+	server := newFakeBedrockServer(t, `{
+		"output": {"message": {"role": "assistant", "content": [{"text": "{\"city\": \"Paris\", \"temperature\": 21}"}]}},
+		"stopReason": "end_turn"
+	}`)
+	model := newTestBedrockModel("meta.llama3-1-70b-instruct-v1:0", server.URL)
+	model.SetOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}, "weather")
+
+	response, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1)
+	require.NoError(t, err)
+
+	assert.NotContains(t, server.request, "toolConfig")
+	system := server.request["system"].([]any)[0].(map[string]any)["text"]
+	assert.Contains(t, system, testOutputSchema)
+	assert.Equal(t, "stop", response.Choices[0].FinishReason)
+}
+
+func TestBedrockChatCompletion_Thinking(t *testing.T) {
+	server := newFakeBedrockServer(t, `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"reasoningContent": {"reasoningText": {"text": "The user wants the weather.", "signature": "sig"}}},
+			{"toolUse": {"toolUseId": "tool_1", "name": "get_weather", "input": {"city": "Paris"}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 20, "outputTokens": 10, "totalTokens": 30}
+	}`)
+	model := newTestBedrockModel("us.anthropic.claude-sonnet-4-20250514-v1:0", server.URL)
+	model.Properties = map[string]string{"temperature": "0.2", "top_k": "5"}
+	model.SetReasoning(&arkv1alpha1.ModelReasoning{Effort: "low", Summary: true})
+
+	ctx, summary := contextWithReasoningSummary(context.Background())
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+	response, err := model.ChatCompletion(ctx, messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"thinking": map[string]any{"type": "enabled", "budget_tokens": 1024.0}}, server.request["additionalModelRequestFields"])
+	assert.Equal(t, map[string]any{"maxTokens": float64(1024 + anthropicDefaultMaxTokens)}, server.request["inferenceConfig"])
+	assert.Equal(t, "The user wants the weather.", summary.String())
+	assert.Empty(t, response.Choices[0].Message.Content)
+
+	// The reasoning block is sent back with the assistant message that called the tool
+	messages = append(messages, assistantMessageFromCompletion(response.Choices[0].Message), ToolMessage("sunny", "tool_1"))
+	_, err = model.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	content := server.request["messages"].([]any)[1].(map[string]any)["content"].([]any)
+	require.Len(t, content, 2)
+	assert.Equal(t, map[string]any{"reasoningContent": map[string]any{"reasoningText": map[string]any{"text": "The user wants the weather.", "signature": "sig"}}}, content[0])
+	assert.Contains(t, content[1], "toolUse")
+}
+
+func TestBedrockTopKFields(t *testing.T) {
+	tests := []struct {
+		model  string
+		fields map[string]any
+	}{
+		{model: "us.anthropic.claude-sonnet-4-20250514-v1:0", fields: map[string]any{"top_k": 5}},
+		{model: "mistral.mistral-large-2407-v1:0", fields: map[string]any{"top_k": 5}},
+		{model: "cohere.command-r-plus-v1:0", fields: map[string]any{"k": 5}},
+		{model: "us.amazon.nova-pro-v1:0", fields: map[string]any{"inferenceConfig": map[string]any{"topK": 5}}},
+		{model: "meta.llama3-1-70b-instruct-v1:0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			assert.Equal(t, tt.fields, newTestBedrockModel(tt.model, "").topKFields(5))
+		})
+	}
+}
+
+func TestBedrockChatCompletion_TopKNotSupported(t *testing.T) {
+	server := newFakeBedrockServer(t, `{
+		"output": {"message": {"role": "assistant", "content": [{"text": "hi"}]}},
+		"stopReason": "end_turn"
+	}`)
+	model := newTestBedrockModel("meta.llama3-1-70b-instruct-v1:0", server.URL)
+	model.Properties = map[string]string{"top_k": "5"}
+
+	_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+	require.NoError(t, err)
+
+	assert.NotContains(t, server.request, "additionalModelRequestFields")
+}
+
+func TestBedrockStream(t *testing.T) {
+	var chunks []*openai.ChatCompletionChunk
+	stream := &bedrockStream{
+		model:       newTestBedrockModel("amazon.nova-pro-v1:0", ""),
+		id:          "request-1",
+		blocks:      map[int32]*bedrockBlock{},
+		toolIndexes: map[int32]int64{},
+		streamFunc: func(chunk *openai.ChatCompletionChunk) error {
+			chunks = append(chunks, chunk)
+			return nil
+		},
+	}
+	events := []types.ConverseStreamOutput{
+		&types.ConverseStreamOutputMemberMessageStart{Value: types.MessageStartEvent{Role: types.ConversationRoleAssistant}},
+		&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{ContentBlockIndex: aws.Int32(0), Delta: &types.ContentBlockDeltaMemberText{Value: "Let me "}}},
+		&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{ContentBlockIndex: aws.Int32(0), Delta: &types.ContentBlockDeltaMemberText{Value: "check."}}},
+		&types.ConverseStreamOutputMemberContentBlockStop{Value: types.ContentBlockStopEvent{ContentBlockIndex: aws.Int32(0)}},
+		&types.ConverseStreamOutputMemberContentBlockStart{Value: types.ContentBlockStartEvent{ContentBlockIndex: aws.Int32(1), Start: &types.ContentBlockStartMemberToolUse{Value: types.ToolUseBlockStart{ToolUseId: aws.String("tool_1"), Name: aws.String("get_weather")}}}},
+		&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{ContentBlockIndex: aws.Int32(1), Delta: &types.ContentBlockDeltaMemberToolUse{Value: types.ToolUseBlockDelta{Input: aws.String(`{"city": `)}}}},
+		&types.ConverseStreamOutputMemberContentBlockDelta{Value: types.ContentBlockDeltaEvent{ContentBlockIndex: aws.Int32(1), Delta: &types.ContentBlockDeltaMemberToolUse{Value: types.ToolUseBlockDelta{Input: aws.String(`"Paris"}`)}}}},
+		&types.ConverseStreamOutputMemberMessageStop{Value: types.MessageStopEvent{StopReason: types.StopReasonToolUse}},
+		&types.ConverseStreamOutputMemberMetadata{Value: types.ConverseStreamMetadataEvent{Usage: &types.TokenUsage{InputTokens: aws.Int32(12), OutputTokens: aws.Int32(30), TotalTokens: aws.Int32(42)}}},
+	}
+	for _, event := range events {
+		require.NoError(t, stream.handle(event))
+	}
+
+	var streamedText, streamedArguments string
+	for _, chunk := range chunks {
+		streamedText += chunk.Choices[0].Delta.Content
+		for _, toolCall := range chunk.Choices[0].Delta.ToolCalls {
+			streamedArguments += toolCall.Function.Arguments
+		}
+	}
+	assert.Equal(t, "Let me check.", streamedText)
+	assert.Equal(t, `{"city": "Paris"}`, streamedArguments)
+	assert.Equal(t, "tool_calls", chunks[len(chunks)-1].Choices[0].FinishReason)
+
+	response := stream.model.buildCompletion(stream.id, stream.orderedBlocks(), stream.stopReason, stream.usage)
+	choice := response.Choices[0]
+	assert.Equal(t, "Let me check.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "tool_1", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(42), response.Usage.TotalTokens)
+}
+
+func TestConvertBedrockMessages_WithoutTools(t *testing.T) {
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "tool_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"London"}`}},
+	}
+	messages := []Message{NewUserMessage("weather?"), Message(assistant), ToolMessage("rainy", "tool_1"), NewUserMessage("thanks")}
+
+	converted, system := convertBedrockMessages(context.Background(), messages, false, false)
+
+	assert.Empty(t, system)
+	require.Len(t, converted, 3)
+	assert.Equal(t, &types.ContentBlockMemberText{Value: `Called tool get_weather with arguments {"city":"London"}`}, converted[1].Content[0])
+	assert.Equal(t, []types.ContentBlock{
+		&types.ContentBlockMemberText{Value: "Tool result: rainy"},
+		&types.ContentBlockMemberText{Value: "thanks"},
+	}, converted[2].Content)
+}
@@ -0,0 +1,842 @@
+package genai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	geminiDefaultBaseURL  = "https://generativelanguage.googleapis.com"
+	geminiDefaultLocation = "us-central1"
+	geminiDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	geminiVertexScope     = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GeminiProvider calls the Gemini generateContent API, either on the Gemini API with an API key
+// or on Vertex AI with a service account
+type GeminiProvider struct {
+	Model             string
+	BaseURL           string
+	APIKey            string
+	ServiceAccountKey string
+	Project           string
+	Location          string
+	Headers           map[string]string
+	Properties        map[string]string
+	outputSchema      *runtime.RawExtension
+	schemaName        string
+	reasoning         *arkv1alpha1.ModelReasoning
+
+	// tokenSource is shared by the calls to the model, and created from the service account key
+	// on first use when the provider was not loaded from a model
+	tokenSource     *geminiTokenSource
+	tokenSourceOnce sync.Once
+	tokenSourceErr  error
+}
+
+// GeminiError is an error response from the Gemini or Vertex AI API
+type GeminiError struct {
+	StatusCode int
+	Status     string
+	Message    string
+	// Header holds the response headers, which may ask for a delay before retrying
+	Header http.Header
+}
+
+func (e *GeminiError) Error() string {
+	return fmt.Sprintf("gemini API error %d (%s): %s", e.StatusCode, e.Status, e.Message)
+}
+
+// geminiServiceAccountKey holds the fields of a service account key JSON file that are needed to
+// request access tokens
+type geminiServiceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	ProjectID    string `json:"project_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// parseGeminiServiceAccountKey validates a service account key JSON document
+func parseGeminiServiceAccountKey(data string) (*geminiServiceAccountKey, error) {
+	var key geminiServiceAccountKey
+	if err := json.Unmarshal([]byte(data), &key); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("invalid service account key: client_email and private_key are required")
+	}
+	return &key, nil
+}
+
+type geminiInlineData struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type geminiFileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	InlineData       *geminiInlineData       `json:"inlineData,omitempty"`
+	FileData         *geminiFileData         `json:"fileData,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	TopK             *int     `json:"topK,omitempty"`
+	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
+	CandidateCount   *int64   `json:"candidateCount,omitempty"`
+	ResponseMimeType string   `json:"responseMimeType,omitempty"`
+	ResponseSchema   any      `json:"responseSchema,omitempty"`
+	// ThinkingConfig sets the thinking budget of Gemini 2.5 and later models
+	ThinkingConfig *geminiThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+type geminiThinkingConfig struct {
+	ThinkingBudget  int  `json:"thinkingBudget"`
+	IncludeThoughts bool `json:"includeThoughts,omitempty"`
+}
+
+type geminiRequest struct {
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiCandidate struct {
+	Index        int64         `json:"index"`
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+type geminiUsage struct {
+	PromptTokenCount        int64 `json:"promptTokenCount"`
+	CandidatesTokenCount    int64 `json:"candidatesTokenCount"`
+	ThoughtsTokenCount      int64 `json:"thoughtsTokenCount"`
+	TotalTokenCount         int64 `json:"totalTokenCount"`
+	CachedContentTokenCount int64 `json:"cachedContentTokenCount"`
+}
+
+type geminiResponse struct {
+	ResponseID    string            `json:"responseId"`
+	ModelVersion  string            `json:"modelVersion"`
+	Candidates    []geminiCandidate `json:"candidates"`
+	UsageMetadata *geminiUsage      `json:"usageMetadata,omitempty"`
+}
+
+type geminiErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+func (gp *GeminiProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	gp.outputSchema = schema
+	gp.schemaName = schemaName
+}
+
+func (gp *GeminiProvider) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	gp.reasoning = reasoning
+}
+
+func (gp *GeminiProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := gp.buildRequest(messages, n, tools...)
+
+	resp, err := gp.send(ctx, "generateContent", request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode Gemini response: %w", err)
+	}
+	reportGeminiThoughts(ctx, &response)
+	return gp.convertResponse(&response), nil
+}
+
+// ChatCompletionStream calls streamGenerateContent with server-sent events. Text deltas and
+// function calls are passed to streamFunc as OpenAI chunks while the full response is accumulated.
+func (gp *GeminiProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := gp.buildRequest(messages, 1, tools...)
+
+	resp, err := gp.send(ctx, "streamGenerateContent", request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var accumulated *geminiResponse
+	var toolCallCount int64
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event geminiResponse
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode Gemini stream event: %w", err)
+		}
+
+		if accumulated == nil {
+			accumulated = &geminiResponse{ResponseID: event.ResponseID, ModelVersion: event.ModelVersion, Candidates: []geminiCandidate{{}}}
+		}
+		if event.UsageMetadata != nil {
+			accumulated.UsageMetadata = event.UsageMetadata
+		}
+		if len(event.Candidates) == 0 {
+			continue
+		}
+
+		candidate := event.Candidates[0]
+		accumulatedCandidate := &accumulated.Candidates[0]
+		delta := openai.ChatCompletionChunkChoiceDelta{Role: "assistant"}
+		for _, part := range candidate.Content.Parts {
+			accumulatedCandidate.Content.Parts = append(accumulatedCandidate.Content.Parts, part)
+			switch {
+			case part.Thought:
+			case part.FunctionCall != nil:
+				delta.ToolCalls = append(delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+					Index: toolCallCount,
+					ID:    geminiToolCallID(part.FunctionCall, int(toolCallCount)),
+					Type:  "function",
+					Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{
+						Name:      part.FunctionCall.Name,
+						Arguments: geminiArguments(part.FunctionCall.Args),
+					},
+				})
+				toolCallCount++
+			default:
+				delta.Content += part.Text
+			}
+		}
+		var finishReason string
+		if candidate.FinishReason != "" {
+			accumulatedCandidate.FinishReason = candidate.FinishReason
+			finishReason = geminiFinishReason(accumulatedCandidate)
+		}
+
+		if delta.Content == "" && len(delta.ToolCalls) == 0 && finishReason == "" {
+			continue
+		}
+		if err := streamFunc(&openai.ChatCompletionChunk{
+			ID:      accumulated.ResponseID,
+			Object:  "chat.completion.chunk",
+			Model:   gp.Model,
+			Choices: []openai.ChatCompletionChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if accumulated == nil {
+		return nil, fmt.Errorf("streaming completed but no response was accumulated")
+	}
+	reportGeminiThoughts(ctx, accumulated)
+	return gp.convertResponse(accumulated), nil
+}
+
+func (gp *GeminiProvider) buildRequest(messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) geminiRequest {
+	contents, system := convertGeminiMessages(messages)
+	request := geminiRequest{Contents: contents}
+	if len(system.Parts) > 0 {
+		request.SystemInstruction = &system
+	}
+
+	if len(tools) > 0 && len(tools[0]) > 0 {
+		declarations := make([]geminiFunctionDeclaration, 0, len(tools[0]))
+		for _, tool := range tools[0] {
+			declaration := geminiFunctionDeclaration{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description.Value,
+			}
+			if len(tool.Function.Parameters) > 0 {
+				declaration.Parameters = sanitizeGeminiSchema(map[string]any(tool.Function.Parameters))
+			}
+			declarations = append(declarations, declaration)
+		}
+		request.Tools = []geminiTool{{FunctionDeclarations: declarations}}
+	}
+
+	config := &geminiGenerationConfig{}
+	if _, ok := gp.Properties["temperature"]; ok {
+		temperature := getFloatProperty(gp.Properties, "temperature", 1.0)
+		config.Temperature = &temperature
+	}
+	if _, ok := gp.Properties["top_p"]; ok {
+		topP := getFloatProperty(gp.Properties, "top_p", 1.0)
+		config.TopP = &topP
+	}
+	if _, ok := gp.Properties["top_k"]; ok {
+		topK := getIntProperty(gp.Properties, "top_k", 0)
+		config.TopK = &topK
+	}
+	if _, ok := gp.Properties["max_tokens"]; ok {
+		maxTokens := getIntProperty(gp.Properties, "max_tokens", 0)
+		config.MaxOutputTokens = &maxTokens
+	}
+	if n > 1 {
+		config.CandidateCount = &n
+	}
+	if gp.reasoning != nil {
+		config.ThinkingConfig = &geminiThinkingConfig{ThinkingBudget: reasoningBudget(gp.reasoning), IncludeThoughts: gp.reasoning.Summary}
+	}
+	if gp.outputSchema != nil && len(gp.outputSchema.Raw) > 0 {
+		var schema any
+		if err := json.Unmarshal(gp.outputSchema.Raw, &schema); err == nil {
+			config.ResponseMimeType = "application/json"
+			config.ResponseSchema = sanitizeGeminiSchema(schema)
+		}
+	}
+	if *config != (geminiGenerationConfig{}) {
+		request.GenerationConfig = config
+	}
+
+	return request
+}
+
+// sanitizeGeminiSchema removes JSON schema keywords that Gemini's OpenAPI schema subset rejects
+func sanitizeGeminiSchema(schema any) any {
+	switch value := schema.(type) {
+	case map[string]any:
+		sanitized := make(map[string]any, len(value))
+		for key, child := range value {
+			switch key {
+			case "$schema", "$id", "additionalProperties":
+				continue
+			}
+			sanitized[key] = sanitizeGeminiSchema(child)
+		}
+		return sanitized
+	case []any:
+		sanitized := make([]any, len(value))
+		for i, child := range value {
+			sanitized[i] = sanitizeGeminiSchema(child)
+		}
+		return sanitized
+	default:
+		return value
+	}
+}
+
+func (gp *GeminiProvider) send(ctx context.Context, method string, request geminiRequest) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Gemini request: %w", err)
+	}
+
+	endpoint := gp.endpoint(method)
+	if method == "streamGenerateContent" {
+		endpoint += "?alt=sse"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("User-Agent", UserAgent)
+	if gp.isVertex() {
+		token, err := gp.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		req.Header.Set("x-goog-api-key", gp.APIKey)
+	}
+	if len(gp.Headers) > 0 {
+		logf.FromContext(ctx).V(1).Info("applying custom headers to client", "model", gp.Model, "header_count", len(gp.Headers))
+		for name, value := range gp.Headers {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := gp.httpClient(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newGeminiError(resp)
+	}
+	return resp, nil
+}
+
+func newGeminiError(resp *http.Response) error {
+	apiErr := &GeminiError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Header: resp.Header}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var errorResponse geminiErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.Message != "" {
+		apiErr.Status = errorResponse.Error.Status
+		apiErr.Message = errorResponse.Error.Message
+	}
+	return apiErr
+}
+
+// ContextWindow looks up the input token limit of the model. Only the Gemini API advertises it.
+func (gp *GeminiProvider) ContextWindow(ctx context.Context) (int64, error) {
+	if gp.isVertex() {
+		return 0, nil
+	}
+
+	baseURL := strings.TrimSuffix(gp.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = geminiDefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1beta/models/%s", baseURL, url.PathEscape(gp.Model)), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("x-goog-api-key", gp.APIKey)
+	for name, value := range gp.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := gp.httpClient(ctx).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, newGeminiError(resp)
+	}
+
+	var model struct {
+		InputTokenLimit int64 `json:"inputTokenLimit"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&model); err != nil {
+		return 0, fmt.Errorf("failed to parse model %s: %w", gp.Model, err)
+	}
+	return model.InputTokenLimit, nil
+}
+
+func (gp *GeminiProvider) isVertex() bool {
+	return gp.ServiceAccountKey != ""
+}
+
+// endpoint returns the URL of a model method on the Gemini API or Vertex AI
+func (gp *GeminiProvider) endpoint(method string) string {
+	model := url.PathEscape(gp.Model)
+	if !gp.isVertex() {
+		baseURL := strings.TrimSuffix(gp.BaseURL, "/")
+		if baseURL == "" {
+			baseURL = geminiDefaultBaseURL
+		}
+		return fmt.Sprintf("%s/v1beta/models/%s:%s", baseURL, model, method)
+	}
+
+	location := gp.Location
+	if location == "" {
+		location = geminiDefaultLocation
+	}
+	baseURL := strings.TrimSuffix(gp.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com", location)
+		if location == "global" {
+			baseURL = "https://aiplatform.googleapis.com"
+		}
+	}
+	return fmt.Sprintf("%s/v1/projects/%s/locations/%s/publishers/google/models/%s:%s",
+		baseURL, url.PathEscape(gp.Project), url.PathEscape(location), model, method)
+}
+
+// geminiTokenSource requests Vertex AI access tokens for a service account and caches them until
+// they expire
+type geminiTokenSource struct {
+	config      *jwt.Config
+	tokenClient *http.Client
+	// mu guards token, so concurrent calls wait for a single token request
+	mu    sync.Mutex
+	token *oauth2.Token
+	// fingerprint identifies the service account key the token source was created from
+	fingerprint string
+}
+
+var geminiTokenSources = struct {
+	sync.Mutex
+	sources map[string]*geminiTokenSource
+}{sources: map[string]*geminiTokenSource{}}
+
+// loadGeminiTokenSource returns the token source of a model, so its tokens are shared by all
+// queries. The token source is recreated when the service account key changes.
+func loadGeminiTokenSource(namespace, name, serviceAccountKey string) (*geminiTokenSource, error) {
+	scope := namespace + "/" + name
+	fingerprint := fmt.Sprintf("%x", sha256.Sum256([]byte(serviceAccountKey)))
+
+	geminiTokenSources.Lock()
+	defer geminiTokenSources.Unlock()
+	if existing, ok := geminiTokenSources.sources[scope]; ok && existing.fingerprint == fingerprint {
+		return existing, nil
+	}
+	source, err := newGeminiTokenSource(serviceAccountKey)
+	if err != nil {
+		return nil, err
+	}
+	source.fingerprint = fingerprint
+	geminiTokenSources.sources[scope] = source
+	return source, nil
+}
+
+// EvictGeminiTokenSource drops the cached token source of a model, such as when the model is
+// deleted
+func EvictGeminiTokenSource(namespace, name string) {
+	geminiTokenSources.Lock()
+	defer geminiTokenSources.Unlock()
+	delete(geminiTokenSources.sources, namespace+"/"+name)
+}
+
+func newGeminiTokenSource(serviceAccountKey string) (*geminiTokenSource, error) {
+	key, err := parseGeminiServiceAccountKey(serviceAccountKey)
+	if err != nil {
+		return nil, err
+	}
+	config := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{geminiVertexScope},
+		TokenURL:     key.TokenURI,
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = geminiDefaultTokenURL
+	}
+	return &geminiTokenSource{config: config, tokenClient: common.NewHTTPClientWithoutTracing()}, nil
+}
+
+// accessToken returns the current access token, requesting a new one with the context of the call
+// when it is missing or expired
+func (s *geminiTokenSource) accessToken(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	// The JWT flow sends the token request without a context, so the transport adds it
+	tokenClient := &http.Client{Transport: &contextTransport{ctx: ctx, base: s.tokenClient.Transport}}
+	token, err := s.config.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, tokenClient)).Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// contextTransport sends requests with ctx, for clients that create them without one
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// accessToken returns a Vertex AI access token for the service account
+func (gp *GeminiProvider) accessToken(ctx context.Context) (string, error) {
+	gp.tokenSourceOnce.Do(func() {
+		if gp.tokenSource == nil {
+			gp.tokenSource, gp.tokenSourceErr = newGeminiTokenSource(gp.ServiceAccountKey)
+		}
+	})
+	if gp.tokenSourceErr != nil {
+		return "", gp.tokenSourceErr
+	}
+
+	token, err := gp.tokenSource.accessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Vertex AI access token: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+func (gp *GeminiProvider) httpClient(ctx context.Context) *http.Client {
+	if IsProbeContext(ctx) {
+		return common.NewHTTPClientWithoutTracing()
+	}
+	return common.NewHTTPClientWithLogging(ctx)
+}
+
+// convertGeminiMessages converts messages to Gemini contents. System and developer messages
+// become the system instruction and tool results become function responses, named after the
+// tool call they answer. Consecutive contents with the same role are merged.
+func convertGeminiMessages(messages []Message) ([]geminiContent, geminiContent) {
+	var contents []geminiContent
+	var system geminiContent
+	toolNames := map[string]string{}
+
+	appendParts := func(role string, parts []geminiPart) {
+		if len(parts) == 0 {
+			return
+		}
+		if last := len(contents) - 1; last >= 0 && contents[last].Role == role {
+			contents[last].Parts = append(contents[last].Parts, parts...)
+			return
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: parts})
+	}
+
+	for _, msg := range messages {
+		union := openai.ChatCompletionMessageParamUnion(msg)
+		switch {
+		case union.OfSystem != nil:
+			system.Parts = append(system.Parts, geminiTextParts(union.OfSystem.Content.OfString.Value)...)
+			for _, part := range union.OfSystem.Content.OfArrayOfContentParts {
+				system.Parts = append(system.Parts, geminiTextParts(part.Text)...)
+			}
+		case union.OfDeveloper != nil:
+			system.Parts = append(system.Parts, geminiTextParts(union.OfDeveloper.Content.OfString.Value)...)
+			for _, part := range union.OfDeveloper.Content.OfArrayOfContentParts {
+				system.Parts = append(system.Parts, geminiTextParts(part.Text)...)
+			}
+		case union.OfUser != nil:
+			parts := geminiTextParts(union.OfUser.Content.OfString.Value)
+			for _, part := range union.OfUser.Content.OfArrayOfContentParts {
+				switch {
+				case part.OfText != nil:
+					parts = append(parts, geminiTextParts(part.OfText.Text)...)
+				case part.OfImageURL != nil:
+					parts = append(parts, geminiImagePart(part.OfImageURL.ImageURL.URL))
+				}
+			}
+			appendParts(RoleUser, parts)
+		case union.OfAssistant != nil:
+			parts := geminiTextParts(union.OfAssistant.Content.OfString.Value)
+			for _, part := range union.OfAssistant.Content.OfArrayOfContentParts {
+				if part.OfText != nil {
+					parts = append(parts, geminiTextParts(part.OfText.Text)...)
+				}
+			}
+			for _, toolCall := range union.OfAssistant.ToolCalls {
+				toolNames[toolCall.ID] = toolCall.Function.Name
+				args := json.RawMessage(toolCall.Function.Arguments)
+				if !json.Valid(args) {
+					args = json.RawMessage("{}")
+				}
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{Name: toolCall.Function.Name, Args: args}})
+			}
+			appendParts("model", parts)
+		case union.OfTool != nil:
+			content := union.OfTool.Content.OfString.Value
+			for _, part := range union.OfTool.Content.OfArrayOfContentParts {
+				content += part.Text
+			}
+			appendParts(RoleUser, []geminiPart{{FunctionResponse: &geminiFunctionResponse{
+				Name:     toolNames[union.OfTool.ToolCallID],
+				Response: geminiFunctionResponseContent(content),
+			}}})
+		}
+	}
+
+	return contents, system
+}
+
+func geminiTextParts(text string) []geminiPart {
+	if text == "" {
+		return nil
+	}
+	return []geminiPart{{Text: text}}
+}
+
+// geminiImagePart converts an image URL, which may be a base64 data URL, to a part
+func geminiImagePart(imageURL string) geminiPart {
+	if rest, ok := strings.CutPrefix(imageURL, "data:"); ok {
+		if mimeType, data, ok := strings.Cut(rest, ";base64,"); ok {
+			return geminiPart{InlineData: &geminiInlineData{MimeType: mimeType, Data: data}}
+		}
+	}
+	return geminiPart{FileData: &geminiFileData{FileURI: imageURL}}
+}
+
+// geminiFunctionResponseContent wraps a tool result in the object Gemini requires, passing JSON
+// objects through unchanged
+func geminiFunctionResponseContent(content string) json.RawMessage {
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	wrapped, _ := json.Marshal(map[string]string{"content": content})
+	return wrapped
+}
+
+// geminiToolCallID returns the function call's ID, or one derived from its position as older
+// models do not return IDs
+func geminiToolCallID(call *geminiFunctionCall, index int) string {
+	if call.ID != "" {
+		return call.ID
+	}
+	return fmt.Sprintf("call_%d_%s", index, call.Name)
+}
+
+func geminiArguments(args json.RawMessage) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	return string(args)
+}
+
+func (gp *GeminiProvider) convertResponse(response *geminiResponse) *openai.ChatCompletion {
+	completion := &openai.ChatCompletion{
+		ID:     response.ResponseID,
+		Object: "chat.completion",
+		Model:  gp.Model,
+	}
+
+	for i := range response.Candidates {
+		candidate := &response.Candidates[i]
+		var content strings.Builder
+		var toolCalls []openai.ChatCompletionMessageToolCall
+		for _, part := range candidate.Content.Parts {
+			switch {
+			case part.Thought:
+			case part.FunctionCall != nil:
+				toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
+					ID:   geminiToolCallID(part.FunctionCall, len(toolCalls)),
+					Type: "function",
+					Function: openai.ChatCompletionMessageToolCallFunction{
+						Name:      part.FunctionCall.Name,
+						Arguments: geminiArguments(part.FunctionCall.Args),
+					},
+				})
+			default:
+				content.WriteString(part.Text)
+			}
+		}
+
+		message := openai.ChatCompletionMessage{Role: "assistant", Content: content.String()}
+		if len(toolCalls) > 0 {
+			message.ToolCalls = toolCalls
+		}
+		completion.Choices = append(completion.Choices, openai.ChatCompletionChoice{
+			Index:        int64(i),
+			Message:      message,
+			FinishReason: geminiFinishReason(candidate),
+		})
+	}
+
+	if usage := response.UsageMetadata; usage != nil {
+		completionTokens := usage.CandidatesTokenCount + usage.ThoughtsTokenCount
+		completion.Usage = openai.CompletionUsage{
+			PromptTokens:            usage.PromptTokenCount,
+			CompletionTokens:        completionTokens,
+			TotalTokens:             usage.PromptTokenCount + completionTokens,
+			PromptTokensDetails:     openai.CompletionUsagePromptTokensDetails{CachedTokens: usage.CachedContentTokenCount},
+			CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{ReasoningTokens: usage.ThoughtsTokenCount},
+		}
+	}
+
+	return completion
+}
+
+// reportGeminiThoughts reports the thought summaries of the first candidate, which the response
+// only includes when they are requested
+func reportGeminiThoughts(ctx context.Context, response *geminiResponse) {
+	if len(response.Candidates) == 0 {
+		return
+	}
+	var thoughts strings.Builder
+	for _, part := range response.Candidates[0].Content.Parts {
+		if part.Thought {
+			thoughts.WriteString(part.Text)
+		}
+	}
+	addReasoningSummary(ctx, thoughts.String())
+}
+
+// geminiFinishReason maps a candidate's finish reason to an OpenAI finish reason. Gemini reports
+// STOP for function calls.
+func geminiFinishReason(candidate *geminiCandidate) string {
+	switch candidate.FinishReason {
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return "content_filter"
+	}
+	for _, part := range candidate.Content.Parts {
+		if part.FunctionCall != nil {
+			return "tool_calls"
+		}
+	}
+	return "stop"
+}
+
+func (gp *GeminiProvider) BuildConfig() map[string]any {
+	config := map[string]any{}
+	if gp.BaseURL != "" {
+		config["baseUrl"] = gp.BaseURL
+	}
+	if gp.APIKey != "" {
+		config["apiKey"] = gp.APIKey
+	}
+	if gp.ServiceAccountKey != "" {
+		config["serviceAccountKey"] = gp.ServiceAccountKey
+		config["project"] = gp.Project
+		config["location"] = gp.Location
+	}
+	for key, value := range gp.Properties {
+		config[key] = value
+	}
+	return config
+}
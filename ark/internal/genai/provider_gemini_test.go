@@ -0,0 +1,374 @@
+package genai
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// fakeGeminiServer replies to every model request with response and records the last request.
+// It also serves an OAuth2 token endpoint at /token for service account tests.
+type fakeGeminiServer struct {
+	*httptest.Server
+	request map[string]any
+	headers http.Header
+	path    string
+	query   string
+}
+
+func newFakeGeminiServer(t *testing.T, status int, response string) *fakeGeminiServer {
+	fake := &fakeGeminiServer{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"access_token": "vertex-token", "token_type": "Bearer", "expires_in": 3600}`)
+			return
+		}
+		fake.path = r.URL.Path
+		fake.query = r.URL.RawQuery
+		fake.headers = r.Header.Clone()
+		fake.request = nil
+		_ = json.NewDecoder(r.Body).Decode(&fake.request)
+		if r.URL.Query().Get("alt") == "sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, response)
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func newTestGeminiProvider(baseURL string) *GeminiProvider {
+	return &GeminiProvider{
+		Model:   "gemini-2.5-flash",
+		BaseURL: baseURL,
+		APIKey:  "test-key",
+		Headers: map[string]string{"X-Team": "ark"},
+	}
+}
+
+// testServiceAccountKey returns a service account key JSON document with a freshly generated
+// private key that requests tokens from tokenURI
+func testServiceAccountKey(t *testing.T, tokenURI string) string {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	key, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "ark-project",
+		"private_key_id": "key-1",
+		"private_key":    string(keyPEM),
+		"client_email":   "ark@ark-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	require.NoError(t, err)
+	return string(key)
+}
+
+func TestGeminiChatCompletion_FunctionCalling(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusOK, `{
+		"responseId": "resp_1",
+		"candidates": [{"content": {"role": "model", "parts": [
+			{"text": "thinking about it", "thought": true},
+			{"text": "Checking the weather."},
+			{"functionCall": {"name": "get_weather", "args": {"city": "Paris"}}}
+		]}, "finishReason": "STOP"}],
+		"usageMetadata": {"promptTokenCount": 20, "candidatesTokenCount": 10, "thoughtsTokenCount": 4, "cachedContentTokenCount": 5}
+	}`)
+	provider := newTestGeminiProvider(server.URL)
+	provider.Properties = map[string]string{"temperature": "0.2", "max_tokens": "512"}
+
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{
+		{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: "get_weather", Arguments: `{"city":"London"}`}},
+	}
+	messages := []Message{
+		NewSystemMessage("You report the weather."),
+		NewUserMessage("Weather in London and Paris?"),
+		Message(assistant),
+		ToolMessage("rainy", "call_1"),
+	}
+
+	response, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1beta/models/gemini-2.5-flash:generateContent", server.path)
+	assert.Equal(t, "test-key", server.headers.Get("x-goog-api-key"))
+	assert.Equal(t, "ark", server.headers.Get("X-Team"))
+	request, err := json.Marshal(server.request)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"systemInstruction": {"parts": [{"text": "You report the weather."}]},
+		"contents": [
+			{"role": "user", "parts": [{"text": "Weather in London and Paris?"}]},
+			{"role": "model", "parts": [{"functionCall": {"name": "get_weather", "args": {"city": "London"}}}]},
+			{"role": "user", "parts": [{"functionResponse": {"name": "get_weather", "response": {"content": "rainy"}}}]}
+		],
+		"tools": [{"functionDeclarations": [{
+			"name": "get_weather",
+			"description": "Get the weather for a city",
+			"parameters": {"type": "object", "properties": {"city": {"type": "string"}}}
+		}]}],
+		"generationConfig": {"temperature": 0.2, "maxOutputTokens": 512}
+	}`, string(request))
+
+	choice := response.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	assert.Equal(t, "Checking the weather.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.NotEmpty(t, choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, "get_weather", choice.Message.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(20), response.Usage.PromptTokens)
+	assert.Equal(t, int64(14), response.Usage.CompletionTokens)
+	assert.Equal(t, int64(4), response.Usage.CompletionTokensDetails.ReasoningTokens)
+	assert.Equal(t, int64(5), response.Usage.PromptTokensDetails.CachedTokens)
+}
+
+func TestGeminiChatCompletion_Thinking(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusOK, `{
+		"candidates": [{"content": {"role": "model", "parts": [
+			{"text": "The user wants a greeting.", "thought": true},
+			{"text": "Hello!"}
+		]}, "finishReason": "STOP"}],
+		"usageMetadata": {"promptTokenCount": 5, "candidatesTokenCount": 2, "thoughtsTokenCount": 30}
+	}`)
+	provider := newTestGeminiProvider(server.URL)
+	provider.SetReasoning(&arkv1alpha1.ModelReasoning{Effort: "low", BudgetTokens: 2048, Summary: true})
+
+	ctx, summary := contextWithReasoningSummary(context.Background())
+	response, err := provider.ChatCompletion(ctx, []Message{NewUserMessage("hi")}, 1)
+	require.NoError(t, err)
+
+	config := server.request["generationConfig"].(map[string]any)
+	assert.Equal(t, map[string]any{"thinkingBudget": 2048.0, "includeThoughts": true}, config["thinkingConfig"])
+	assert.Equal(t, "Hello!", response.Choices[0].Message.Content)
+	assert.Equal(t, "The user wants a greeting.", summary.String())
+	assert.Equal(t, int64(30), response.Usage.CompletionTokensDetails.ReasoningTokens)
+}
+
+func TestGeminiChatCompletion_ResponseSchema(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusOK, `{
+		"candidates": [{"content": {"role": "model", "parts": [{"text": "{\"city\": \"Paris\", \"temperature\": 21}"}]}, "finishReason": "STOP"}]
+	}`)
+	provider := newTestGeminiProvider(server.URL)
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {"city": {"type": "string"}, "temperature": {"type": "number"}}
+	}`)}, "weather")
+
+	response, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1)
+	require.NoError(t, err)
+
+	config := server.request["generationConfig"].(map[string]any)
+	assert.Equal(t, "application/json", config["responseMimeType"])
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}, "temperature": map[string]any{"type": "number"}},
+	}, config["responseSchema"])
+	assert.Equal(t, "stop", response.Choices[0].FinishReason)
+	assert.JSONEq(t, `{"city":"Paris","temperature":21}`, response.Choices[0].Message.Content)
+}
+
+func TestGeminiChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"responseId":"resp_1","candidates":[{"content":{"role":"model","parts":[{"text":"Let me "}]}}]}`,
+		`{"responseId":"resp_1","candidates":[{"content":{"role":"model","parts":[{"text":"check."}]}}]}`,
+		`{"responseId":"resp_1","candidates":[{"content":{"role":"model","parts":[{"functionCall":{"id":"fc_1","name":"get_weather","args":{"city":"Paris"}}}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":12,"candidatesTokenCount":30}}`,
+	}
+	var body string
+	for _, event := range events {
+		body += "data: " + event + "\r\n\r\n"
+	}
+	server := newFakeGeminiServer(t, http.StatusOK, body)
+	provider := newTestGeminiProvider(server.URL)
+
+	var chunks []*openai.ChatCompletionChunk
+	response, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("weather?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1beta/models/gemini-2.5-flash:streamGenerateContent", server.path)
+	assert.Equal(t, "alt=sse", server.query)
+
+	var streamedText, streamedArguments string
+	for _, chunk := range chunks {
+		streamedText += chunk.Choices[0].Delta.Content
+		for _, toolCall := range chunk.Choices[0].Delta.ToolCalls {
+			streamedArguments += toolCall.Function.Arguments
+		}
+	}
+	assert.Equal(t, "Let me check.", streamedText)
+	assert.JSONEq(t, `{"city":"Paris"}`, streamedArguments)
+	assert.Equal(t, "tool_calls", chunks[len(chunks)-1].Choices[0].FinishReason)
+
+	choice := response.Choices[0]
+	assert.Equal(t, "Let me check.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "fc_1", choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, int64(42), response.Usage.TotalTokens)
+}
+
+func TestGeminiChatCompletion_VertexServiceAccount(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusOK, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}, "finishReason": "STOP"}]}`)
+	provider := &GeminiProvider{
+		Model:             "gemini-2.5-pro",
+		BaseURL:           server.URL,
+		ServiceAccountKey: testServiceAccountKey(t, server.URL+"/token"),
+		Project:           "ark-project",
+		Location:          "europe-west4",
+	}
+
+	response, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/projects/ark-project/locations/europe-west4/publishers/google/models/gemini-2.5-pro:generateContent", server.path)
+	assert.Equal(t, "Bearer vertex-token", server.headers.Get("Authorization"))
+	assert.Empty(t, server.headers.Get("x-goog-api-key"))
+	assert.Equal(t, "hi", response.Choices[0].Message.Content)
+}
+
+func TestGeminiAccessToken_UsesCallContext(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Query().Get("slow") != "" {
+			<-r.Context().Done()
+			return
+		}
+		tokenRequests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token": "vertex-token", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	slow, err := newGeminiTokenSource(testServiceAccountKey(t, tokenServer.URL+"?slow=1"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = (&GeminiProvider{tokenSource: slow}).accessToken(ctx)
+	assert.ErrorContains(t, err, context.DeadlineExceeded.Error(), "the token request ends with the call")
+
+	source, err := newGeminiTokenSource(testServiceAccountKey(t, tokenServer.URL))
+	require.NoError(t, err)
+	for range 2 {
+		token, err := (&GeminiProvider{tokenSource: source}).accessToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "vertex-token", token)
+	}
+	assert.Equal(t, 1, tokenRequests, "providers sharing a token source share its token")
+}
+
+func TestLoadGeminiTokenSource(t *testing.T) {
+	t.Cleanup(func() { EvictGeminiTokenSource("default", "vertex") })
+	key := testServiceAccountKey(t, "https://oauth2.example.com/token")
+
+	source, err := loadGeminiTokenSource("default", "vertex", key)
+	require.NoError(t, err)
+	same, err := loadGeminiTokenSource("default", "vertex", key)
+	require.NoError(t, err)
+	assert.Same(t, source, same, "the token source is shared by the loads of a model")
+
+	rotated, err := loadGeminiTokenSource("default", "vertex", testServiceAccountKey(t, "https://oauth2.example.com/token"))
+	require.NoError(t, err)
+	assert.NotSame(t, source, rotated, "a new service account key gets a new token source")
+
+	EvictGeminiTokenSource("default", "vertex")
+	reloaded, err := loadGeminiTokenSource("default", "vertex", key)
+	require.NoError(t, err)
+	assert.NotSame(t, source, reloaded)
+}
+
+func TestGeminiChatCompletion_Error(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusServiceUnavailable, `{"error": {"code": 503, "message": "The model is overloaded.", "status": "UNAVAILABLE"}}`)
+	provider := newTestGeminiProvider(server.URL)
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 1)
+
+	var geminiErr *GeminiError
+	require.ErrorAs(t, err, &geminiErr)
+	assert.Equal(t, "UNAVAILABLE", geminiErr.Status)
+	assert.True(t, IsRetryableModelError(err))
+	assert.Equal(t, "The model is overloaded. (503)", extractStableError(err, time.Second))
+}
+
+func TestConvertGeminiMessages_Images(t *testing.T) {
+	user := openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What is this?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "data:image/png;base64,aGVsbG8="}),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "gs://bucket/cat.png"}),
+	})
+
+	contents, system := convertGeminiMessages([]Message{Message(user)})
+
+	assert.Empty(t, system.Parts)
+	require.Len(t, contents, 1)
+	require.Len(t, contents[0].Parts, 3)
+	assert.Equal(t, &geminiInlineData{MimeType: "image/png", Data: "aGVsbG8="}, contents[0].Parts[1].InlineData)
+	assert.Equal(t, &geminiFileData{FileURI: "gs://bucket/cat.png"}, contents[0].Parts[2].FileData)
+}
+
+func TestProbeModel_Gemini(t *testing.T) {
+	server := newFakeGeminiServer(t, http.StatusOK, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "Hello!"}]}, "finishReason": "STOP"}]}`)
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "gemini", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "gemini-2.5-flash"},
+			Provider: ProviderGemini,
+			Config: arkv1alpha1.ModelConfig{Gemini: &arkv1alpha1.GeminiModelConfig{
+				BaseURL: &arkv1alpha1.ValueSource{Value: server.URL},
+				APIKey:  &arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}})
+
+	model, err := LoadModel(context.Background(), k8sClient, "gemini", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	require.IsType(t, &GeminiProvider{}, model.Provider)
+
+	result := ProbeModel(context.Background(), model)
+
+	assert.True(t, result.Available, result.Message)
+	assert.Equal(t, "/v1beta/models/gemini-2.5-flash:generateContent", server.path)
+}
+
+func TestLoadModel_GeminiRequiresOneCredential(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "gemini", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Model:    arkv1alpha1.ValueSource{Value: "gemini-2.5-flash"},
+			Provider: ProviderGemini,
+			Config:   arkv1alpha1.ModelConfig{Gemini: &arkv1alpha1.GeminiModelConfig{}},
+		},
+	}})
+
+	_, err := LoadModel(context.Background(), k8sClient, "gemini", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+
+	assert.ErrorContains(t, err, "exactly one of apiKey and serviceAccountKey")
+}
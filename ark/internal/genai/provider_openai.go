@@ -2,6 +2,7 @@ package genai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/shared/constant"
 	"k8s.io/apimachinery/pkg/runtime"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -21,6 +23,7 @@ type OpenAIProvider struct {
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	reasoning    *arkv1alpha1.ModelReasoning
 }
 
 func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -28,10 +31,14 @@ func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNa
 	op.schemaName = schemaName
 }
 
+func (op *OpenAIProvider) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	op.reasoning = reasoning
+}
+
 func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+		openaiMessages[i] = withoutThinkingBlocks(msg)
 	}
 
 	params := openai.ChatCompletionNewParams{
@@ -41,6 +48,7 @@ func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyReasoningToParams(op.reasoning, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -190,7 +198,7 @@ func (op *OpenAIProvider) sendFinalToolCallChunk(fullResponse *openai.ChatComple
 func (op *OpenAIProvider) prepareStreamParams(messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) openai.ChatCompletionNewParams {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
-		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+		openaiMessages[i] = withoutThinkingBlocks(msg)
 	}
 
 	params := openai.ChatCompletionNewParams{
@@ -203,6 +211,7 @@ func (op *OpenAIProvider) prepareStreamParams(messages []Message, n int64, tools
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyReasoningToParams(op.reasoning, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
@@ -237,11 +246,7 @@ func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []M
 		accumulateStreamChunk(&chunk, &fullResponse, toolCallsMap)
 
 		if chunk.Usage.TotalTokens > 0 {
-			fullResponse.Usage = openai.CompletionUsage{
-				PromptTokens:     chunk.Usage.PromptTokens,
-				CompletionTokens: chunk.Usage.CompletionTokens,
-				TotalTokens:      chunk.Usage.TotalTokens,
-			}
+			fullResponse.Usage = chunk.Usage
 		}
 	}
 
@@ -262,6 +267,12 @@ func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []M
 	return fullResponse, nil
 }
 
+// Embed calls the embeddings endpoint
+func (op *OpenAIProvider) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	client := op.createClient(ctx)
+	return client.Embeddings.New(ctx, newEmbeddingParams(op.Model, op.Properties, inputs))
+}
+
 func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 	var httpClient *http.Client
 	if IsProbeContext(ctx) {
@@ -274,6 +285,7 @@ func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 		option.WithBaseURL(op.BaseURL),
 		option.WithAPIKey(op.APIKey),
 		option.WithHTTPClient(httpClient),
+		openAIClientWithoutRetries,
 	}
 
 	options = applyHeadersToOptions(ctx, op.Headers, options, op.Model)
@@ -281,6 +293,26 @@ func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 	return openai.NewClient(options...)
 }
 
+// ContextWindow looks up the context window of the model in the models API. OpenAI does not
+// advertise it, but compatible servers such as vLLM and OpenRouter do.
+func (op *OpenAIProvider) ContextWindow(ctx context.Context) (int64, error) {
+	client := op.createClient(ctx)
+	model, err := client.Models.Get(ctx, op.Model)
+	if err != nil {
+		return 0, err
+	}
+
+	var fields struct {
+		MaxModelLen   int64 `json:"max_model_len"`
+		ContextLength int64 `json:"context_length"`
+		ContextWindow int64 `json:"context_window"`
+	}
+	if err := json.Unmarshal([]byte(model.RawJSON()), &fields); err != nil {
+		return 0, fmt.Errorf("failed to parse model %s: %w", op.Model, err)
+	}
+	return max(fields.MaxModelLen, fields.ContextLength, fields.ContextWindow), nil
+}
+
 func (op *OpenAIProvider) BuildConfig() map[string]any {
 	config := map[string]any{
 		"baseUrl": op.BaseURL,
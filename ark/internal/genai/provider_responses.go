@@ -0,0 +1,642 @@
+package genai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+// ResponsesProvider calls OpenAI or Azure OpenAI models through the Responses API and maps the
+// responses to chat completions. Consecutive calls of an agent loop are chained: with store
+// enabled (the default) only the new messages are sent along with previous_response_id, and with
+// store disabled the output items of the previous response, including encrypted reasoning, are
+// sent back in place of the assistant message. Agents with a context policy always send the full
+// trimmed history.
+type ResponsesProvider struct {
+	Model      string
+	BaseURL    string
+	APIKey     string
+	Headers    map[string]string
+	Properties map[string]string
+	// APIVersion is set for Azure OpenAI, which serves the Responses API under /openai
+	APIVersion   string
+	Azure        bool
+	outputSchema *runtime.RawExtension
+	schemaName   string
+	reasoning    *arkv1alpha1.ModelReasoning
+
+	mu    sync.Mutex
+	chain *responsesChain
+}
+
+// responsesChain records the last response so the next call of the same conversation can
+// continue from it
+type responsesChain struct {
+	responseID string
+	// position is the index the assistant message for the response has in the next call
+	position    int
+	fingerprint string
+	items       []json.RawMessage
+}
+
+// newResponsesProvider creates a Responses API provider from the configuration of a loaded
+// OpenAI or Azure provider
+func newResponsesProvider(provider ChatCompletionProvider) (*ResponsesProvider, error) {
+	switch p := provider.(type) {
+	case *OpenAIProvider:
+		return &ResponsesProvider{Model: p.Model, BaseURL: p.BaseURL, APIKey: p.APIKey, Headers: p.Headers, Properties: p.Properties}, nil
+	case *AzureProvider:
+		return &ResponsesProvider{Model: p.Model, BaseURL: p.BaseURL, APIKey: p.APIKey, Headers: p.Headers, Properties: p.Properties, APIVersion: p.APIVersion, Azure: true}, nil
+	default:
+		return nil, fmt.Errorf("type %s is only supported by the openai and azure providers", ModelTypeResponses)
+	}
+}
+
+type responsesContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type responsesInputItem struct {
+	Type      string             `json:"type"`
+	Role      string             `json:"role,omitempty"`
+	Content   []responsesContent `json:"content,omitempty"`
+	CallID    string             `json:"call_id,omitempty"`
+	Name      string             `json:"name,omitempty"`
+	Arguments string             `json:"arguments,omitempty"`
+	Output    *string            `json:"output,omitempty"`
+}
+
+type responsesTextFormat struct {
+	Type   string `json:"type"`
+	Name   string `json:"name,omitempty"`
+	Schema any    `json:"schema,omitempty"`
+	Strict bool   `json:"strict,omitempty"`
+}
+
+type responsesText struct {
+	Format responsesTextFormat `json:"format"`
+}
+
+type responsesReasoning struct {
+	Effort  string `json:"effort,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type responsesRequest struct {
+	Model              string              `json:"model"`
+	Input              []any               `json:"input"`
+	Instructions       string              `json:"instructions,omitempty"`
+	PreviousResponseID string              `json:"previous_response_id,omitempty"`
+	Tools              []any               `json:"tools,omitempty"`
+	Text               *responsesText      `json:"text,omitempty"`
+	Reasoning          *responsesReasoning `json:"reasoning,omitempty"`
+	Temperature        *float64            `json:"temperature,omitempty"`
+	TopP               *float64            `json:"top_p,omitempty"`
+	MaxOutputTokens    *int                `json:"max_output_tokens,omitempty"`
+	ParallelToolCalls  *bool               `json:"parallel_tool_calls,omitempty"`
+	Store              *bool               `json:"store,omitempty"`
+	Include            []string            `json:"include,omitempty"`
+	Stream             bool                `json:"stream,omitempty"`
+}
+
+type responsesAnnotation struct {
+	Type       string `json:"type"`
+	URL        string `json:"url"`
+	Title      string `json:"title"`
+	StartIndex int64  `json:"start_index"`
+	EndIndex   int64  `json:"end_index"`
+}
+
+type responsesOutputContent struct {
+	Type        string                `json:"type"`
+	Text        string                `json:"text"`
+	Refusal     string                `json:"refusal"`
+	Annotations []responsesAnnotation `json:"annotations"`
+}
+
+type responsesOutputItem struct {
+	Type      string                   `json:"type"`
+	ID        string                   `json:"id"`
+	Content   []responsesOutputContent `json:"content"`
+	CallID    string                   `json:"call_id"`
+	Name      string                   `json:"name"`
+	Arguments string                   `json:"arguments"`
+	// Summary holds the summary_text parts of a reasoning item
+	Summary []responsesOutputContent `json:"summary"`
+	raw     json.RawMessage
+}
+
+func (i *responsesOutputItem) UnmarshalJSON(data []byte) error {
+	type item responsesOutputItem
+	if err := json.Unmarshal(data, (*item)(i)); err != nil {
+		return err
+	}
+	i.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+type responsesUsage struct {
+	InputTokens        int64 `json:"input_tokens"`
+	OutputTokens       int64 `json:"output_tokens"`
+	TotalTokens        int64 `json:"total_tokens"`
+	InputTokensDetails struct {
+		CachedTokens int64 `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
+	OutputTokensDetails struct {
+		ReasoningTokens int64 `json:"reasoning_tokens"`
+	} `json:"output_tokens_details"`
+}
+
+type responsesResponse struct {
+	ID        string                `json:"id"`
+	CreatedAt float64               `json:"created_at"`
+	Model     string                `json:"model"`
+	Status    string                `json:"status"`
+	Output    []responsesOutputItem `json:"output"`
+	Usage     *responsesUsage       `json:"usage"`
+	Error     *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details"`
+}
+
+type responsesStreamEvent struct {
+	Type        string               `json:"type"`
+	Response    *responsesResponse   `json:"response"`
+	OutputIndex int64                `json:"output_index"`
+	Item        *responsesOutputItem `json:"item"`
+	Delta       string               `json:"delta"`
+	Code        string               `json:"code"`
+	Message     string               `json:"message"`
+}
+
+func (rp *ResponsesProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
+	rp.outputSchema = schema
+	rp.schemaName = schemaName
+}
+
+func (rp *ResponsesProvider) SetReasoning(reasoning *arkv1alpha1.ModelReasoning) {
+	rp.reasoning = reasoning
+}
+
+func (rp *ResponsesProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request, err := rp.buildRequest(ctx, messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := rp.createClient(ctx)
+	var response responsesResponse
+	if err := client.Post(ctx, "responses", request, &response); err != nil {
+		return nil, err
+	}
+	return rp.complete(ctx, messages, &response)
+}
+
+// ChatCompletionStream streams the response and passes text and function call argument deltas to
+// streamFunc as OpenAI chunks. The completion is built from the final response event.
+func (rp *ResponsesProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request, err := rp.buildRequest(ctx, messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+	request.Stream = true
+
+	client := rp.createClient(ctx)
+	var resp *http.Response
+	if err := client.Post(ctx, "responses", request, &resp, option.WithHeader("Accept", "text/event-stream")); err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var responseID string
+	toolIndexes := map[int64]int64{}
+	emit := func(delta openai.ChatCompletionChunkChoiceDelta, finishReason string) error {
+		return streamFunc(&openai.ChatCompletionChunk{
+			ID:      responseID,
+			Object:  "chat.completion.chunk",
+			Model:   rp.Model,
+			Choices: []openai.ChatCompletionChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		})
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event responsesStreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode Responses stream event: %w", err)
+		}
+
+		switch event.Type {
+		case "response.created":
+			if event.Response != nil {
+				responseID = event.Response.ID
+			}
+			if err := emit(openai.ChatCompletionChunkChoiceDelta{Role: "assistant"}, ""); err != nil {
+				return nil, err
+			}
+		case "response.output_text.delta":
+			if err := emit(openai.ChatCompletionChunkChoiceDelta{Content: event.Delta}, ""); err != nil {
+				return nil, err
+			}
+		case "response.output_item.added":
+			if event.Item == nil || event.Item.Type != "function_call" {
+				continue
+			}
+			index := int64(len(toolIndexes))
+			toolIndexes[event.OutputIndex] = index
+			if err := emit(openai.ChatCompletionChunkChoiceDelta{ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    index,
+				ID:       event.Item.CallID,
+				Type:     "function",
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: event.Item.Name},
+			}}}, ""); err != nil {
+				return nil, err
+			}
+		case "response.function_call_arguments.delta":
+			index, ok := toolIndexes[event.OutputIndex]
+			if !ok {
+				continue
+			}
+			if err := emit(openai.ChatCompletionChunkChoiceDelta{ToolCalls: []openai.ChatCompletionChunkChoiceDeltaToolCall{{
+				Index:    index,
+				Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Arguments: event.Delta},
+			}}}, ""); err != nil {
+				return nil, err
+			}
+		case "response.completed", "response.incomplete", "response.failed":
+			if event.Response == nil {
+				return nil, fmt.Errorf("responses stream event %s has no response", event.Type)
+			}
+			completion, err := rp.complete(ctx, messages, event.Response)
+			if err != nil {
+				return nil, err
+			}
+			if err := emit(openai.ChatCompletionChunkChoiceDelta{}, completion.Choices[0].FinishReason); err != nil {
+				return nil, err
+			}
+			return completion, nil
+		case "error":
+			return nil, fmt.Errorf("responses API error %s: %s", event.Code, event.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("streaming completed but no response was accumulated")
+}
+
+func (rp *ResponsesProvider) store() bool {
+	value, ok := rp.Properties["store"]
+	return !ok || value != "false"
+}
+
+type responseChainingKey struct{}
+
+// withoutResponseChaining returns a context in which calls send the full history instead of
+// previous_response_id. Callers that trim the history need it, since the stored response would
+// bring back the messages the trimming removed.
+func withoutResponseChaining(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseChainingKey{}, false)
+}
+
+func responseChainingEnabled(ctx context.Context) bool {
+	enabled, ok := ctx.Value(responseChainingKey{}).(bool)
+	return !ok || enabled
+}
+
+func (rp *ResponsesProvider) buildRequest(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (responsesRequest, error) {
+	if n > 1 {
+		return responsesRequest{}, fmt.Errorf("the Responses API returns a single response, so n=%d is not supported", n)
+	}
+	request := responsesRequest{Model: rp.Model}
+
+	rp.mu.Lock()
+	chain := rp.chain
+	rp.mu.Unlock()
+	start := 0
+	if chain != nil && chain.position < len(messages) && assistantFingerprint(messages[chain.position]) == chain.fingerprint {
+		if rp.store() && responseChainingEnabled(ctx) {
+			request.PreviousResponseID = chain.responseID
+			start = chain.position + 1
+		} else if rp.store() {
+			chain = nil
+		}
+	} else {
+		chain = nil
+	}
+
+	var instructions []string
+	for i, msg := range messages {
+		union := openai.ChatCompletionMessageParamUnion(msg)
+		switch {
+		case union.OfSystem != nil:
+			instructions = append(instructions, systemText(union.OfSystem.Content.OfString.Value, union.OfSystem.Content.OfArrayOfContentParts))
+			continue
+		case union.OfDeveloper != nil:
+			instructions = append(instructions, systemText(union.OfDeveloper.Content.OfString.Value, union.OfDeveloper.Content.OfArrayOfContentParts))
+			continue
+		}
+		if i < start {
+			continue
+		}
+		if chain != nil && i == chain.position {
+			// With store disabled the previous output items are sent back as they were returned
+			for _, item := range chain.items {
+				request.Input = append(request.Input, item)
+			}
+			continue
+		}
+		request.Input = append(request.Input, convertResponsesMessage(union)...)
+	}
+	request.Instructions = strings.Join(instructions, "\n\n")
+
+	if len(tools) > 0 {
+		for _, tool := range tools[0] {
+			request.Tools = append(request.Tools, map[string]any{
+				"type":        "function",
+				"name":        tool.Function.Name,
+				"description": tool.Function.Description.Value,
+				"parameters":  tool.Function.Parameters,
+				"strict":      false,
+			})
+		}
+	}
+	if builtinTools, ok := rp.Properties["builtin_tools"]; ok {
+		var parsed []any
+		if err := json.Unmarshal([]byte(builtinTools), &parsed); err == nil {
+			request.Tools = append(request.Tools, parsed...)
+		}
+	}
+
+	if rp.outputSchema != nil && len(rp.outputSchema.Raw) > 0 {
+		var schema any
+		if err := json.Unmarshal(rp.outputSchema.Raw, &schema); err == nil {
+			request.Text = &responsesText{Format: responsesTextFormat{Type: "json_schema", Name: rp.schemaName, Schema: schema, Strict: true}}
+		}
+	}
+
+	rp.applyProperties(&request)
+	return request, nil
+}
+
+func (rp *ResponsesProvider) applyProperties(request *responsesRequest) {
+	if _, ok := rp.Properties["temperature"]; ok {
+		temperature := getFloatProperty(rp.Properties, "temperature", 1.0)
+		request.Temperature = &temperature
+	}
+	if _, ok := rp.Properties["top_p"]; ok {
+		topP := getFloatProperty(rp.Properties, "top_p", 1.0)
+		request.TopP = &topP
+	}
+	for _, key := range []string{"max_output_tokens", "max_tokens"} {
+		if _, ok := rp.Properties[key]; ok {
+			maxTokens := getIntProperty(rp.Properties, key, 0)
+			request.MaxOutputTokens = &maxTokens
+			break
+		}
+	}
+	if value, ok := rp.Properties["parallel_tool_calls"]; ok {
+		if parallel, err := strconv.ParseBool(value); err == nil {
+			request.ParallelToolCalls = &parallel
+		}
+	}
+	if effort, summary := rp.Properties["reasoning_effort"], rp.Properties["reasoning_summary"]; effort != "" || summary != "" {
+		request.Reasoning = &responsesReasoning{Effort: effort, Summary: summary}
+	}
+	if rp.reasoning != nil {
+		request.Reasoning = &responsesReasoning{Effort: rp.reasoning.Effort}
+		if rp.reasoning.Summary {
+			request.Reasoning.Summary = "auto"
+		}
+	}
+	if !rp.store() {
+		store := false
+		request.Store = &store
+		request.Include = []string{"reasoning.encrypted_content"}
+	}
+}
+
+func systemText(text string, parts []openai.ChatCompletionContentPartTextParam) string {
+	for _, part := range parts {
+		text += part.Text
+	}
+	return text
+}
+
+// convertResponsesMessage converts a user, assistant or tool message to Responses input items.
+// Assistant tool calls become function_call items and tool results function_call_output items.
+func convertResponsesMessage(union openai.ChatCompletionMessageParamUnion) []any {
+	switch {
+	case union.OfUser != nil:
+		var content []responsesContent
+		if text := union.OfUser.Content.OfString.Value; text != "" {
+			content = append(content, responsesContent{Type: "input_text", Text: text})
+		}
+		for _, part := range union.OfUser.Content.OfArrayOfContentParts {
+			switch {
+			case part.OfText != nil:
+				content = append(content, responsesContent{Type: "input_text", Text: part.OfText.Text})
+			case part.OfImageURL != nil:
+				content = append(content, responsesContent{Type: "input_image", ImageURL: part.OfImageURL.ImageURL.URL})
+			}
+		}
+		return []any{responsesInputItem{Type: "message", Role: RoleUser, Content: content}}
+	case union.OfAssistant != nil:
+		var items []any
+		text := union.OfAssistant.Content.OfString.Value
+		for _, part := range union.OfAssistant.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				text += part.OfText.Text
+			}
+		}
+		if text != "" {
+			items = append(items, responsesInputItem{Type: "message", Role: RoleAssistant, Content: []responsesContent{{Type: "output_text", Text: text}}})
+		}
+		for _, toolCall := range union.OfAssistant.ToolCalls {
+			items = append(items, responsesInputItem{Type: "function_call", CallID: toolCall.ID, Name: toolCall.Function.Name, Arguments: toolCall.Function.Arguments})
+		}
+		return items
+	case union.OfTool != nil:
+		output := union.OfTool.Content.OfString.Value
+		for _, part := range union.OfTool.Content.OfArrayOfContentParts {
+			output += part.Text
+		}
+		return []any{responsesInputItem{Type: "function_call_output", CallID: union.OfTool.ToolCallID, Output: &output}}
+	}
+	return nil
+}
+
+// assistantFingerprint identifies an assistant message by its text and tool call IDs, which is
+// enough to recognise the message built from a previous response
+func assistantFingerprint(msg Message) string {
+	assistant := openai.ChatCompletionMessageParamUnion(msg).OfAssistant
+	if assistant == nil {
+		return ""
+	}
+	fingerprint := "assistant:" + assistant.Content.OfString.Value
+	for _, toolCall := range assistant.ToolCalls {
+		fingerprint += "|" + toolCall.ID
+	}
+	return fingerprint
+}
+
+// complete converts a response to a chat completion and records it as the head of the chain
+func (rp *ResponsesProvider) complete(ctx context.Context, messages []Message, response *responsesResponse) (*openai.ChatCompletion, error) {
+	if response.Status == "failed" {
+		if response.Error != nil {
+			return nil, fmt.Errorf("responses API error %s: %s", response.Error.Code, response.Error.Message)
+		}
+		return nil, fmt.Errorf("response %s failed", response.ID)
+	}
+
+	completion := convertResponsesResponse(response)
+	assistant := Message(completion.Choices[0].Message.ToParam())
+
+	chain := &responsesChain{responseID: response.ID, position: len(messages), fingerprint: assistantFingerprint(assistant)}
+	for _, item := range response.Output {
+		chain.items = append(chain.items, item.raw)
+		for _, part := range item.Summary {
+			addReasoningSummary(ctx, part.Text)
+		}
+	}
+	rp.mu.Lock()
+	rp.chain = chain
+	rp.mu.Unlock()
+
+	return completion, nil
+}
+
+// convertResponsesResponse maps the output items to a single choice. Reasoning and built-in tool
+// call items are not part of the message: they stay in the chain and their results reach the
+// message through the model's text and url_citation annotations.
+func convertResponsesResponse(response *responsesResponse) *openai.ChatCompletion {
+	message := openai.ChatCompletionMessage{Role: "assistant"}
+	var content strings.Builder
+	for _, item := range response.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				switch part.Type {
+				case "output_text":
+					offset := int64(content.Len())
+					content.WriteString(part.Text)
+					for _, annotation := range part.Annotations {
+						if annotation.Type != "url_citation" {
+							continue
+						}
+						message.Annotations = append(message.Annotations, openai.ChatCompletionMessageAnnotation{
+							Type: "url_citation",
+							URLCitation: openai.ChatCompletionMessageAnnotationURLCitation{
+								URL:        annotation.URL,
+								Title:      annotation.Title,
+								StartIndex: offset + annotation.StartIndex,
+								EndIndex:   offset + annotation.EndIndex,
+							},
+						})
+					}
+				case "refusal":
+					message.Refusal += part.Refusal
+				}
+			}
+		case "function_call":
+			message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+				ID:       item.CallID,
+				Type:     "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{Name: item.Name, Arguments: item.Arguments},
+			})
+		}
+	}
+	message.Content = content.String()
+
+	finishReason := "stop"
+	switch {
+	case len(message.ToolCalls) > 0:
+		finishReason = "tool_calls"
+	case response.IncompleteDetails != nil && response.IncompleteDetails.Reason == "max_output_tokens":
+		finishReason = "length"
+	case response.IncompleteDetails != nil && response.IncompleteDetails.Reason == "content_filter":
+		finishReason = "content_filter"
+	}
+
+	completion := &openai.ChatCompletion{
+		ID:      response.ID,
+		Object:  "chat.completion",
+		Created: int64(response.CreatedAt),
+		Model:   response.Model,
+		Choices: []openai.ChatCompletionChoice{{Index: 0, Message: message, FinishReason: finishReason}},
+	}
+	if usage := response.Usage; usage != nil {
+		completion.Usage = openai.CompletionUsage{
+			PromptTokens:            usage.InputTokens,
+			CompletionTokens:        usage.OutputTokens,
+			TotalTokens:             usage.TotalTokens,
+			PromptTokensDetails:     openai.CompletionUsagePromptTokensDetails{CachedTokens: usage.InputTokensDetails.CachedTokens},
+			CompletionTokensDetails: openai.CompletionUsageCompletionTokensDetails{ReasoningTokens: usage.OutputTokensDetails.ReasoningTokens},
+		}
+	}
+	return completion
+}
+
+func (rp *ResponsesProvider) createClient(ctx context.Context) openai.Client {
+	var httpClient *http.Client
+	if IsProbeContext(ctx) {
+		httpClient = common.NewHTTPClientWithoutTracing()
+	} else {
+		httpClient = common.NewHTTPClientWithLogging(ctx)
+	}
+
+	options := []option.RequestOption{
+		option.WithAPIKey(rp.APIKey),
+		option.WithHTTPClient(httpClient),
+		openAIClientWithoutRetries,
+	}
+	if rp.Azure {
+		options = append(options,
+			option.WithBaseURL(strings.TrimSuffix(rp.BaseURL, "/")+"/openai/"),
+			option.WithHeader("api-key", rp.APIKey),
+			option.WithQueryAdd("api-version", rp.APIVersion),
+		)
+	} else {
+		options = append(options, option.WithBaseURL(rp.BaseURL))
+	}
+
+	options = applyHeadersToOptions(ctx, rp.Headers, options, rp.Model)
+
+	return openai.NewClient(options...)
+}
+
+func (rp *ResponsesProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl": rp.BaseURL,
+	}
+	if rp.APIVersion != "" {
+		config["apiVersion"] = rp.APIVersion
+	}
+	if rp.APIKey != "" {
+		config["apiKey"] = rp.APIKey
+	}
+	return config
+}
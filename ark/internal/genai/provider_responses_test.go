@@ -0,0 +1,333 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// fakeResponsesServer replies to requests with the given responses in turn and records every
+// request
+type fakeResponsesServer struct {
+	*httptest.Server
+	requests []map[string]any
+	paths    []string
+	queries  []string
+}
+
+func newFakeResponsesServer(t *testing.T, status int, responses ...string) *fakeResponsesServer {
+	fake := &fakeResponsesServer{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		fake.requests = append(fake.requests, request)
+		fake.paths = append(fake.paths, r.URL.Path)
+		fake.queries = append(fake.queries, r.URL.RawQuery)
+		if request["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, responses[min(len(fake.requests), len(responses))-1])
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeResponsesServer) lastRequest(t *testing.T) string {
+	request, err := json.Marshal(f.requests[len(f.requests)-1])
+	require.NoError(t, err)
+	return string(request)
+}
+
+const testResponsesToolCall = `{
+	"id": "resp_1", "model": "o4-mini", "status": "completed",
+	"output": [
+		{"type": "reasoning", "id": "rs_1", "summary": [], "encrypted_content": "secret"},
+		{"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_weather", "arguments": "{\"city\":\"Paris\"}", "status": "completed"}
+	],
+	"usage": {"input_tokens": 20, "output_tokens": 30, "total_tokens": 50, "input_tokens_details": {"cached_tokens": 5}, "output_tokens_details": {"reasoning_tokens": 24}}
+}`
+
+const testResponsesMessage = `{
+	"id": "resp_2", "model": "o4-mini", "status": "completed",
+	"output": [
+		{"type": "message", "id": "msg_1", "role": "assistant", "content": [
+			{"type": "output_text", "text": "It is sunny in Paris.", "annotations": [
+				{"type": "url_citation", "url": "https://weather.example.com", "title": "Weather", "start_index": 0, "end_index": 5}
+			]}
+		]}
+	],
+	"usage": {"input_tokens": 40, "output_tokens": 10, "total_tokens": 50}
+}`
+
+// runToolLoop makes a call that returns a tool call, then a second call with the tool result
+func runToolLoop(t *testing.T, provider *ResponsesProvider) *openai.ChatCompletion {
+	messages := []Message{NewSystemMessage("You report the weather."), NewUserMessage("Weather in Paris?")}
+	first, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	choice := first.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "call_1", choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, int64(24), first.Usage.CompletionTokensDetails.ReasoningTokens)
+	assert.Equal(t, int64(5), first.Usage.PromptTokensDetails.CachedTokens)
+
+	messages = append(messages, Message(choice.Message.ToParam()), ToolMessage("sunny", "call_1"))
+	second, err := provider.ChatCompletion(context.Background(), messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	return second
+}
+
+func TestResponsesChatCompletion_PreviousResponseChaining(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, testResponsesToolCall, testResponsesMessage)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key", Properties: map[string]string{"reasoning_effort": "high"}}
+
+	response := runToolLoop(t, provider)
+
+	assert.Equal(t, "/responses", server.paths[0])
+	request, err := json.Marshal(server.requests[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "o4-mini",
+		"instructions": "You report the weather.",
+		"input": [{"type": "message", "role": "user", "content": [{"type": "input_text", "text": "Weather in Paris?"}]}],
+		"tools": [{
+			"type": "function", "name": "get_weather", "description": "Get the weather for a city", "strict": false,
+			"parameters": {"type": "object", "properties": {"city": {"type": "string"}}}
+		}],
+		"reasoning": {"effort": "high"}
+	}`, string(request))
+
+	second := server.requests[1]
+	assert.Equal(t, "resp_1", second["previous_response_id"])
+	assert.Equal(t, "You report the weather.", second["instructions"])
+	assert.Equal(t, []any{map[string]any{"type": "function_call_output", "call_id": "call_1", "output": "sunny"}}, second["input"])
+
+	choice := response.Choices[0]
+	assert.Equal(t, "stop", choice.FinishReason)
+	assert.Equal(t, "It is sunny in Paris.", choice.Message.Content)
+	require.Len(t, choice.Message.Annotations, 1)
+	assert.Equal(t, "https://weather.example.com", choice.Message.Annotations[0].URLCitation.URL)
+}
+
+func TestResponsesChatCompletion_ReplaysItemsWithoutStore(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, testResponsesToolCall, testResponsesMessage)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key", Properties: map[string]string{"store": "false"}}
+
+	runToolLoop(t, provider)
+
+	second := server.requests[1]
+	assert.NotContains(t, second, "previous_response_id")
+	assert.Equal(t, false, second["store"])
+	assert.Equal(t, []any{"reasoning.encrypted_content"}, second["include"])
+	input := second["input"].([]any)
+	require.Len(t, input, 4)
+	assert.Equal(t, "message", input[0].(map[string]any)["type"])
+	assert.Equal(t, map[string]any{"type": "reasoning", "id": "rs_1", "summary": []any{}, "encrypted_content": "secret"}, input[1])
+	assert.Equal(t, "fc_1", input[2].(map[string]any)["id"])
+	assert.Equal(t, "function_call_output", input[3].(map[string]any)["type"])
+}
+
+func TestResponsesChatCompletion_NotChainedWhenHistoryIsTrimmed(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, testResponsesToolCall, testResponsesMessage)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key"}
+	ctx := withoutResponseChaining(context.Background())
+
+	messages := []Message{NewUserMessage("Weather in Paris?")}
+	first, err := provider.ChatCompletion(ctx, messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+	messages = append(messages, Message(first.Choices[0].Message.ToParam()), ToolMessage("sunny", "call_1"))
+	_, err = provider.ChatCompletion(ctx, messages, 1, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	second := server.requests[1]
+	assert.NotContains(t, second, "previous_response_id")
+	input := second["input"].([]any)
+	require.Len(t, input, 3)
+	assert.Equal(t, "function_call", input[1].(map[string]any)["type"])
+	assert.Equal(t, "function_call_output", input[2].(map[string]any)["type"])
+}
+
+func TestResponsesChatCompletion_RejectsMultipleChoices(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, testResponsesMessage)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, 2)
+
+	require.ErrorContains(t, err, "n=2 is not supported")
+	assert.Empty(t, server.requests)
+}
+
+func TestResponsesChatCompletion_NewConversationIsNotChained(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, testResponsesToolCall, testResponsesMessage)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("first")}, 1)
+	require.NoError(t, err)
+	_, err = provider.ChatCompletion(context.Background(), []Message{NewUserMessage("second"), NewAssistantMessage("other"), NewUserMessage("third")}, 1)
+	require.NoError(t, err)
+
+	assert.NotContains(t, server.requests[1], "previous_response_id")
+	assert.Len(t, server.requests[1]["input"], 3)
+}
+
+func TestResponsesChatCompletion_StructuredOutputOnAzure(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, `{
+		"id": "resp_1", "status": "completed",
+		"output": [{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "{\"city\":\"Paris\",\"temperature\":21}"}]}]
+	}`)
+	provider := &ResponsesProvider{Model: "gpt-4.1", BaseURL: server.URL, APIKey: "test-key", APIVersion: "2025-04-01-preview", Azure: true}
+	provider.SetOutputSchema(&runtime.RawExtension{Raw: []byte(testOutputSchema)}, "weather")
+
+	response, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("weather?")}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/openai/responses", server.paths[0])
+	assert.Equal(t, "api-version=2025-04-01-preview", server.queries[0])
+	format := server.requests[0]["text"].(map[string]any)["format"].(map[string]any)
+	assert.Equal(t, "json_schema", format["type"])
+	assert.Equal(t, "weather", format["name"])
+	assert.Equal(t, true, format["strict"])
+	assert.JSONEq(t, `{"city":"Paris","temperature":21}`, response.Choices[0].Message.Content)
+}
+
+func TestResponsesChatCompletion_ReasoningSummary(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusOK, `{
+		"id": "resp_1", "status": "completed",
+		"output": [
+			{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "The user greets me."}]},
+			{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "Hello!"}]}
+		]
+	}`)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key", Properties: map[string]string{"reasoning_effort": "high"}}
+	provider.SetReasoning(&arkv1alpha1.ModelReasoning{Effort: "low", Summary: true})
+
+	ctx, summary := contextWithReasoningSummary(context.Background())
+	response, err := provider.ChatCompletion(ctx, []Message{NewUserMessage("hi")}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"effort": "low", "summary": "auto"}, server.requests[0]["reasoning"], "the reasoning settings take precedence over the properties")
+	assert.Equal(t, "Hello!", response.Choices[0].Message.Content)
+	assert.Equal(t, "The user greets me.", summary.String())
+}
+
+func TestResponsesChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"type":"response.created","response":{"id":"resp_1","status":"in_progress","output":[]}}`,
+		`{"type":"response.output_item.added","output_index":0,"item":{"type":"reasoning","id":"rs_1"}}`,
+		`{"type":"response.output_item.added","output_index":1,"item":{"type":"message","id":"msg_1","role":"assistant","content":[]}}`,
+		`{"type":"response.output_text.delta","output_index":1,"delta":"Let me "}`,
+		`{"type":"response.output_text.delta","output_index":1,"delta":"check."}`,
+		`{"type":"response.output_item.added","output_index":2,"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_weather","arguments":""}}`,
+		`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"{\"city\": "}`,
+		`{"type":"response.function_call_arguments.delta","output_index":2,"delta":"\"Paris\"}"}`,
+		`{"type":"response.completed","response":{"id":"resp_1","status":"completed","output":[
+			{"type":"reasoning","id":"rs_1","summary":[]},
+			{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Let me check.","annotations":[]}]},
+			{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_weather","arguments":"{\"city\": \"Paris\"}"}
+		],"usage":{"input_tokens":12,"output_tokens":30,"total_tokens":42}}}`,
+	}
+	var body string
+	for _, event := range events {
+		var compact map[string]any
+		require.NoError(t, json.Unmarshal([]byte(event), &compact))
+		data, err := json.Marshal(compact)
+		require.NoError(t, err)
+		body += "event: " + compact["type"].(string) + "\ndata: " + string(data) + "\n\n"
+	}
+	server := newFakeResponsesServer(t, http.StatusOK, body)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key"}
+
+	var chunks []*openai.ChatCompletionChunk
+	response, err := provider.ChatCompletionStream(context.Background(), []Message{NewUserMessage("weather?")}, 1, func(chunk *openai.ChatCompletionChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}, []openai.ChatCompletionToolParam{weatherTool})
+	require.NoError(t, err)
+
+	assert.Equal(t, true, server.requests[0]["stream"])
+	var streamedText, streamedArguments string
+	for _, chunk := range chunks {
+		streamedText += chunk.Choices[0].Delta.Content
+		for _, toolCall := range chunk.Choices[0].Delta.ToolCalls {
+			streamedArguments += toolCall.Function.Arguments
+		}
+	}
+	assert.Equal(t, "Let me check.", streamedText)
+	assert.Equal(t, `{"city": "Paris"}`, streamedArguments)
+	assert.Equal(t, "tool_calls", chunks[len(chunks)-1].Choices[0].FinishReason)
+
+	choice := response.Choices[0]
+	assert.Equal(t, "Let me check.", choice.Message.Content)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "call_1", choice.Message.ToolCalls[0].ID)
+	assert.Equal(t, int64(42), response.Usage.TotalTokens)
+}
+
+func TestResponsesChatCompletion_Error(t *testing.T) {
+	server := newFakeResponsesServer(t, http.StatusTooManyRequests, `{"error": {"message": "Rate limit reached", "type": "requests", "code": "rate_limit_exceeded"}}`)
+	provider := &ResponsesProvider{Model: "o4-mini", BaseURL: server.URL, APIKey: "test-key"}
+	ctx := contextWithProbeMode(context.Background())
+
+	_, err := provider.ChatCompletion(ctx, []Message{NewUserMessage("hi")}, 1)
+
+	var openaiErr *openai.Error
+	require.ErrorAs(t, err, &openaiErr)
+	assert.True(t, IsRetryableModelError(err))
+}
+
+func TestLoadModel_Responses(t *testing.T) {
+	k8sClient := setupModelTestClient([]client.Object{
+		&arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "o4-mini", Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Model:    arkv1alpha1.ValueSource{Value: "o4-mini"},
+				Type:     ModelTypeResponses,
+				Provider: ProviderOpenAI,
+				Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+					BaseURL:    arkv1alpha1.ValueSource{Value: "https://api.openai.com/v1"},
+					APIKey:     arkv1alpha1.ValueSource{Value: "test-key"},
+					Properties: map[string]arkv1alpha1.ValueSource{"reasoning_effort": {Value: "low"}},
+				}},
+			},
+		},
+		&arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "claude", Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Model:    arkv1alpha1.ValueSource{Value: "claude-sonnet-4-5"},
+				Type:     ModelTypeResponses,
+				Provider: ProviderAnthropic,
+				Config: arkv1alpha1.ModelConfig{Anthropic: &arkv1alpha1.AnthropicModelConfig{
+					APIKey: arkv1alpha1.ValueSource{Value: "test-key"},
+				}},
+			},
+		},
+	})
+
+	model, err := LoadModel(context.Background(), k8sClient, "o4-mini", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	require.NoError(t, err)
+	provider, ok := model.Provider.(*ResponsesProvider)
+	require.True(t, ok)
+	assert.Equal(t, "https://api.openai.com/v1", provider.BaseURL)
+	assert.Equal(t, "low", provider.Properties["reasoning_effort"])
+
+	_, err = LoadModel(context.Background(), k8sClient, "claude", "default", nil, noop.NewModelRecorder(), eventnoop.NewModelRecorder())
+	assert.ErrorContains(t, err, "only supported by the openai and azure providers")
+}
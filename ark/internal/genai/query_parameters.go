@@ -117,6 +117,21 @@ func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace
 		return "", nil
 	}
 
+	templateData, err := resolveBodyTemplateData(ctx, k8sClient, namespace, parameters, inputData)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := common.ResolveTemplate(bodyTemplate, templateData)
+	if err != nil {
+		return "", fmt.Errorf("body template resolution failed: %w", err)
+	}
+	return resolved, nil
+}
+
+// resolveBodyTemplateData returns the data of body templates: the tool input as .input and the
+// body parameters by name
+func resolveBodyTemplateData(ctx context.Context, k8sClient client.Client, namespace string, parameters []arkv1alpha1.Parameter, inputData map[string]any) (map[string]any, error) {
 	templateData := make(map[string]any)
 
 	if inputData != nil {
@@ -126,19 +141,14 @@ func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace
 	if len(parameters) > 0 {
 		paramData, err := resolveQueryParameters(ctx, k8sClient, namespace, parameters)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve body parameters: %w", err)
+			return nil, fmt.Errorf("failed to resolve body parameters: %w", err)
 		}
 
 		for key, value := range paramData {
 			templateData[key] = value
 		}
 	}
-
-	resolved, err := common.ResolveTemplate(bodyTemplate, templateData)
-	if err != nil {
-		return "", fmt.Errorf("body template resolution failed: %w", err)
-	}
-	return resolved, nil
+	return templateData, nil
 }
 
 // GetQueryInputMessages returns a message array based on query type, handling both input and messages
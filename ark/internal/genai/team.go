@@ -29,6 +29,11 @@ type Team struct {
 	Namespace         string
 	memory            MemoryInterface
 	eventStream       EventStreamInterface
+	// maxIterationsReached is set when a member's final message was forced after it exhausted
+	// its iterations
+	maxIterationsReached bool
+	// Guardrails check the team's input before the first member runs and its final response
+	Guardrails []*Guardrail
 }
 
 // FullName returns the namespace/name format for the team
@@ -41,9 +46,14 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 		return nil, fmt.Errorf("team %s has no members configured", t.FullName())
 	}
 
-	// Store memory and streaming parameters for member execution
+	// Store memory and streaming parameters for member execution. Output that guardrails check
+	// is streamed once it has been checked.
 	t.memory = memory
 	t.eventStream = eventStream
+	if HasOutputGuardrails(t.Guardrails) {
+		t.eventStream = nil
+	}
+	t.maxIterationsReached = false
 
 	var execFunc func(context.Context, Message, []Message) ([]Message, error)
 	switch t.Strategy {
@@ -59,8 +69,24 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 		return nil, fmt.Errorf("unsupported strategy %s for team %s", t.Strategy, t.FullName())
 	}
 
+	userInput, err := ApplyGuardrailsToMessage(ctx, t.Guardrails, GuardrailStageInput, userInput)
+	if err != nil {
+		return nil, err
+	}
+
 	messages, err := t.executeWithTracking(execFunc, ctx, userInput, history)
-	return &ExecutionResult{Messages: messages}, err
+	result := &ExecutionResult{Messages: messages, MaxIterationsReached: t.maxIterationsReached}
+	if err != nil {
+		return result, err
+	}
+
+	if err := ApplyOutputGuardrails(ctx, t.Guardrails, result); err != nil {
+		return nil, err
+	}
+	if t.eventStream == nil {
+		StreamCheckedResult(ctx, eventStream, result, "")
+	}
+	return result, nil
 }
 
 func (t *Team) executeSequential(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
@@ -185,6 +211,11 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		return nil, err
 	}
 
+	guardrails, err := LoadGuardrails(ctx, k8sClient, crd.Spec.Guardrails, crd.Namespace, telemetryProvider, eventingProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guardrails for team %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+
 	return &Team{
 		Name:              crd.Name,
 		Members:           members,
@@ -199,6 +230,7 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		eventing:          eventingProvider,
 		Client:            k8sClient,
 		Namespace:         crd.Namespace,
+		Guardrails:        guardrails,
 	}, nil
 }
 
@@ -234,6 +266,16 @@ func (t *Team) executeWithTracking(execFunc func(context.Context, Message, []Mes
 	teamctx = t.eventingRecorder.Start(teamctx, "TeamExecution", fmt.Sprintf("Executing team %s", t.FullName()), operationData)
 
 	result, err := execFunc(teamctx, userInput, history)
+
+	// Tokens used before a failure are still charged to the caller
+	usage := t.eventingRecorder.GetTokenSummary(teamctx)
+	t.eventingRecorder.AddTokenUsage(ctx, usage)
+	AddTokenUsageToOperationData(operationData, usage)
+	t.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+	if len(usage.Cost) > 0 {
+		t.telemetryRecorder.RecordCost(span, usage.Cost)
+	}
+
 	if err != nil {
 		t.telemetryRecorder.RecordError(span, err)
 		t.eventingRecorder.Fail(teamctx, "TeamExecution", fmt.Sprintf("Team execution failed: %v", err), err, operationData)
@@ -241,14 +283,7 @@ func (t *Team) executeWithTracking(execFunc func(context.Context, Message, []Mes
 	}
 
 	t.telemetryRecorder.RecordSuccess(span)
-	usage := t.eventingRecorder.GetTokenSummary(teamctx)
-	operationData["promptTokens"] = fmt.Sprintf("%d", usage.PromptTokens)
-	operationData["completionTokens"] = fmt.Sprintf("%d", usage.CompletionTokens)
-	operationData["totalTokens"] = fmt.Sprintf("%d", usage.TotalTokens)
 	t.eventingRecorder.Complete(teamctx, "TeamExecution", "Team execution completed successfully", operationData)
-
-	t.telemetryRecorder.RecordTokenUsage(span, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
-	t.eventingRecorder.AddTokenUsage(ctx, usage)
 	return result, err
 }
 
@@ -267,22 +302,32 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 		"teamName":   t.Name,
 		"turn":       fmt.Sprintf("%d", turn),
 	}
-	ctx = t.eventingRecorder.Start(ctx, "TeamMember", fmt.Sprintf("Executing member %s in team %s", member.GetName(), t.Name), operationData)
+	// The turn's tokens are collected separately to report its cost, then added to the team's
+	turnctx := t.eventingRecorder.StartTokenCollection(ctx)
+	turnctx = t.eventingRecorder.Start(turnctx, "TeamMember", fmt.Sprintf("Executing member %s in team %s", member.GetName(), t.Name), operationData)
+
+	result, err := member.Execute(turnctx, userInput, *messages, t.memory, t.eventStream)
+
+	usage := t.eventingRecorder.GetTokenSummary(turnctx)
+	t.eventingRecorder.AddTokenUsage(ctx, usage)
+	AddTokenUsageToOperationData(operationData, usage)
+	if result != nil && result.MaxIterationsReached {
+		t.maxIterationsReached = true
+	}
 
-	result, err := member.Execute(ctx, userInput, *messages, t.memory, t.eventStream)
 	if err != nil {
 		// Still accumulate messages even on error if result is not nil
 		if result != nil {
 			*messages = append(*messages, result.Messages...)
 			*newMessages = append(*newMessages, result.Messages...)
 		}
-		t.eventingRecorder.Fail(ctx, "TeamMember", fmt.Sprintf("Team member execution failed: %v", err), err, operationData)
+		t.eventingRecorder.Fail(turnctx, "TeamMember", fmt.Sprintf("Team member execution failed: %v", err), err, operationData)
 		return err
 	}
 
 	*messages = append(*messages, result.Messages...)
 	*newMessages = append(*newMessages, result.Messages...)
-	t.eventingRecorder.Complete(ctx, "TeamMember", "Team member execution completed successfully", operationData)
+	t.eventingRecorder.Complete(turnctx, "TeamMember", "Team member execution completed successfully", operationData)
 	return nil
 }
 
@@ -0,0 +1,100 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const toolApproverKey contextKey = "toolApprover"
+
+// ToolApprovalRequest describes a tool call that needs a human decision before it runs
+type ToolApprovalRequest struct {
+	ToolCallID string
+	ToolName   string
+	Arguments  string
+	Agent      string
+}
+
+// ToolApprovalDecision is the approver's answer to a ToolApprovalRequest
+type ToolApprovalDecision struct {
+	Approved bool
+	Reason   string
+}
+
+// ToolApprover blocks until a decision is made for the request or the context ends
+type ToolApprover interface {
+	RequestApproval(ctx context.Context, request ToolApprovalRequest) (ToolApprovalDecision, error)
+}
+
+// WithToolApprover makes approver available to agents executing with ctx
+func WithToolApprover(ctx context.Context, approver ToolApprover) context.Context {
+	return context.WithValue(ctx, toolApproverKey, approver)
+}
+
+// GetToolApprover returns the approver stored in ctx, or nil if there is none
+func GetToolApprover(ctx context.Context) ToolApprover {
+	if approver, ok := ctx.Value(toolApproverKey).(ToolApprover); ok {
+		return approver
+	}
+	return nil
+}
+
+// toolRequiresApproval reports whether calls to the tool exposed as toolName must be approved
+func toolRequiresApproval(policy *arkv1alpha1.ToolApprovalPolicy, toolName string, tool *arkv1alpha1.Tool) bool {
+	if policy == nil {
+		return false
+	}
+	if slices.Contains(policy.Tools, toolName) {
+		return true
+	}
+	annotations := tool.Spec.Annotations
+	return policy.Destructive && annotations != nil && annotations.DestructiveHint && !annotations.ReadOnlyHint
+}
+
+// rejectedToolMessage is the tool result the model sees when a call is not approved
+func rejectedToolMessage(toolName, reason string) string {
+	if reason == "" {
+		return fmt.Sprintf("The call to tool %s was rejected by the approver and was not executed.", toolName)
+	}
+	return fmt.Sprintf("The call to tool %s was rejected by the approver and was not executed. Reason: %s", toolName, reason)
+}
+
+// requestToolApproval asks the approver in ctx to decide on the tool call. Without an
+// approver the call is rejected, since nobody can approve it.
+func (a *Agent) requestToolApproval(ctx context.Context, call ToolCall) (ToolApprovalDecision, error) {
+	operationData := map[string]string{
+		"agent":    a.FullName(),
+		"toolName": call.Function.Name,
+		"toolId":   call.ID,
+	}
+	ctx = a.eventingRecorder.Start(ctx, "ToolApproval", fmt.Sprintf("Waiting for approval of tool %s", call.Function.Name), operationData)
+
+	approver := GetToolApprover(ctx)
+	if approver == nil {
+		decision := ToolApprovalDecision{Reason: "no approver is available for this execution"}
+		operationData["decision"] = "rejected"
+		a.eventingRecorder.Complete(ctx, "ToolApproval", "Tool call rejected: no approver available", operationData)
+		return decision, nil
+	}
+
+	decision, err := approver.RequestApproval(ctx, ToolApprovalRequest{
+		ToolCallID: call.ID,
+		ToolName:   call.Function.Name,
+		Arguments:  call.Function.Arguments,
+		Agent:      a.FullName(),
+	})
+	if err != nil {
+		a.eventingRecorder.Fail(ctx, "ToolApproval", fmt.Sprintf("Tool approval failed: %v", err), err, operationData)
+		return ToolApprovalDecision{}, err
+	}
+
+	operationData["decision"] = "rejected"
+	if decision.Approved {
+		operationData["decision"] = "approved"
+	}
+	a.eventingRecorder.Complete(ctx, "ToolApproval", fmt.Sprintf("Tool call %s", operationData["decision"]), operationData)
+	return decision, nil
+}
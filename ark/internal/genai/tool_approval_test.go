@@ -0,0 +1,99 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	eventnoop "mckinsey.com/ark/internal/eventing/noop"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+type staticApprover struct {
+	decision ToolApprovalDecision
+	requests []ToolApprovalRequest
+}
+
+func (a *staticApprover) RequestApproval(ctx context.Context, request ToolApprovalRequest) (ToolApprovalDecision, error) {
+	a.requests = append(a.requests, request)
+	return a.decision, nil
+}
+
+func newApprovalTestAgent(executor *flakyExecutor) *Agent {
+	eventing := eventnoop.NewProvider()
+	tools := NewToolRegistry(nil, noop.NewToolRecorder(), eventing.ToolRecorder())
+	tools.RegisterTool(ToolDefinition{Name: "delete-records"}, executor)
+	tools.approvalRequired["delete-records"] = true
+
+	return &Agent{
+		Name:             "approval-agent",
+		Namespace:        "default",
+		Tools:            tools,
+		eventingRecorder: eventing.AgentRecorder(),
+	}
+}
+
+func deleteRecordsCall() openai.ChatCompletionMessageToolCall {
+	return openai.ChatCompletionMessageToolCall{
+		ID:       "call-1",
+		Type:     "function",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "delete-records", Arguments: `{"table":"users"}`},
+	}
+}
+
+func TestToolRequiresApproval(t *testing.T) {
+	destructive := &arkv1alpha1.Tool{Spec: arkv1alpha1.ToolSpec{Annotations: &arkv1alpha1.ToolAnnotations{DestructiveHint: true}}}
+	readOnly := &arkv1alpha1.Tool{Spec: arkv1alpha1.ToolSpec{Annotations: &arkv1alpha1.ToolAnnotations{DestructiveHint: true, ReadOnlyHint: true}}}
+	plain := &arkv1alpha1.Tool{}
+
+	assert.False(t, toolRequiresApproval(nil, "drop", destructive))
+	assert.True(t, toolRequiresApproval(&arkv1alpha1.ToolApprovalPolicy{Destructive: true}, "drop", destructive))
+	assert.False(t, toolRequiresApproval(&arkv1alpha1.ToolApprovalPolicy{Destructive: true}, "read", readOnly))
+	assert.False(t, toolRequiresApproval(&arkv1alpha1.ToolApprovalPolicy{Destructive: true}, "plain", plain))
+	assert.True(t, toolRequiresApproval(&arkv1alpha1.ToolApprovalPolicy{Tools: []string{"plain"}}, "plain", plain))
+	assert.False(t, toolRequiresApproval(&arkv1alpha1.ToolApprovalPolicy{}, "drop", destructive))
+}
+
+func TestExecuteToolCall_Approved(t *testing.T) {
+	executor := &flakyExecutor{}
+	agent := newApprovalTestAgent(executor)
+	approver := &staticApprover{decision: ToolApprovalDecision{Approved: true}}
+	ctx := WithToolApprover(context.Background(), approver)
+
+	msg, err := agent.executeToolCall(ctx, deleteRecordsCall())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, executor.calls)
+	assert.Equal(t, "ok", msg.OfTool.Content.OfString.Value)
+	require.Len(t, approver.requests, 1)
+	assert.Equal(t, ToolApprovalRequest{ToolCallID: "call-1", ToolName: "delete-records", Arguments: `{"table":"users"}`, Agent: "default/approval-agent"}, approver.requests[0])
+}
+
+func TestExecuteToolCall_Rejected(t *testing.T) {
+	executor := &flakyExecutor{}
+	agent := newApprovalTestAgent(executor)
+	ctx := WithToolApprover(context.Background(), &staticApprover{decision: ToolApprovalDecision{Reason: "production data"}})
+
+	msg, err := agent.executeToolCall(ctx, deleteRecordsCall())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, executor.calls)
+	assert.Equal(t, "call-1", msg.OfTool.ToolCallID)
+	assert.Contains(t, msg.OfTool.Content.OfString.Value, "rejected")
+	assert.Contains(t, msg.OfTool.Content.OfString.Value, "production data")
+}
+
+func TestExecuteToolCall_NoApproverRejects(t *testing.T) {
+	executor := &flakyExecutor{}
+	agent := newApprovalTestAgent(executor)
+
+	msg, err := agent.executeToolCall(context.Background(), deleteRecordsCall())
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, executor.calls)
+	assert.Contains(t, msg.OfTool.Content.OfString.Value, "no approver")
+}
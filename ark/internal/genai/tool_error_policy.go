@@ -0,0 +1,142 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	ToolErrorPolicyFail   = "fail"
+	ToolErrorPolicyReport = "report"
+	ToolErrorPolicyRetry  = "retry"
+)
+
+// Tool call outcomes recorded in tool eventing operation data
+const (
+	ToolOutcomeSucceeded = "succeeded"
+	ToolOutcomeReported  = "reported"
+	ToolOutcomeFailed    = "failed"
+)
+
+var (
+	toolRetryBaseDelay = 500 * time.Millisecond
+	toolRetryMaxDelay  = 10 * time.Second
+)
+
+// ToolErrorPolicy controls what happens when a tool call returns an error
+type ToolErrorPolicy struct {
+	Action     string
+	MaxRetries int
+}
+
+// ParseToolErrorPolicy parses fail, report or retry:N. An empty value means fail.
+func ParseToolErrorPolicy(value string) (ToolErrorPolicy, error) {
+	switch value {
+	case "", ToolErrorPolicyFail:
+		return ToolErrorPolicy{Action: ToolErrorPolicyFail}, nil
+	case ToolErrorPolicyReport:
+		return ToolErrorPolicy{Action: ToolErrorPolicyReport}, nil
+	}
+
+	retries, found := strings.CutPrefix(value, ToolErrorPolicyRetry+":")
+	if !found {
+		return ToolErrorPolicy{}, fmt.Errorf("invalid tool error policy %q: must be fail, report or retry:N", value)
+	}
+	maxRetries, err := strconv.Atoi(retries)
+	if err != nil || maxRetries < 1 {
+		return ToolErrorPolicy{}, fmt.Errorf("invalid tool error policy %q: retry count must be a positive integer", value)
+	}
+	return ToolErrorPolicy{Action: ToolErrorPolicyRetry, MaxRetries: maxRetries}, nil
+}
+
+func (p ToolErrorPolicy) String() string {
+	if p.Action == ToolErrorPolicyRetry {
+		return fmt.Sprintf("%s:%d", ToolErrorPolicyRetry, p.MaxRetries)
+	}
+	if p.Action == "" {
+		return ToolErrorPolicyFail
+	}
+	return p.Action
+}
+
+// executeWithPolicy runs the executor, retrying transient failures with exponential backoff when
+// the policy allows. Team termination, context cancellation and errors that would fail again, such
+// as invalid arguments, are never retried.
+func executeWithPolicy(ctx context.Context, executor ToolExecutor, call ToolCall, policy ToolErrorPolicy) (ToolResult, int, error) {
+	attempts := 0
+	for {
+		attempts++
+		result, err := executor.Execute(ctx, call)
+		if err == nil || policy.Action != ToolErrorPolicyRetry || attempts > policy.MaxRetries || IsTerminateTeam(err) || ctx.Err() != nil || !isTransientToolError(err) {
+			return result, attempts, err
+		}
+
+		delay := toolRetryDelay(attempts)
+		logf.FromContext(ctx).Info("retrying tool call", "tool", call.Function.Name, "attempt", attempts, "delay", delay, "error", err.Error())
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result, attempts, err
+		}
+	}
+}
+
+// isTransientToolError reports whether a tool call failed in a way that may succeed if repeated:
+// network errors, timeouts, HTTP responses with a retryable status such as 503, and gRPC calls
+// that failed with Unavailable, DeadlineExceeded, ResourceExhausted or Aborted
+func isTransientToolError(err error) bool {
+	var statusErr *httpToolStatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableHTTPToolStatus(statusErr.StatusCode)
+	}
+	var grpcErr *grpcToolCallError
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.status.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return isRetryableError(err)
+}
+
+func toolRetryDelay(attempt int) time.Duration {
+	delay := toolRetryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > toolRetryMaxDelay {
+		return toolRetryMaxDelay
+	}
+	return delay
+}
+
+// reportedToolResult turns a tool error into a result the model can read and act on
+func reportedToolResult(call ToolCall, err error) ToolResult {
+	return ToolResult{
+		ID:      call.ID,
+		Name:    call.Function.Name,
+		Content: fmt.Sprintf("Error executing tool %s: %v", call.Function.Name, err),
+		Error:   err.Error(),
+	}
+}
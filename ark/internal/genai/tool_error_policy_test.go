@@ -0,0 +1,243 @@
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// flakyExecutor fails the first failures calls and succeeds afterwards
+type flakyExecutor struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (e *flakyExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	e.calls++
+	if e.calls <= e.failures {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: e.err.Error()}, e.err
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: "ok"}, nil
+}
+
+// capturingToolRecorder keeps the operation data of the last completed or failed tool call
+type capturingToolRecorder struct {
+	outcome string
+	data    map[string]string
+}
+
+func (r *capturingToolRecorder) InitializeQueryContext(ctx context.Context, query *arkv1alpha1.Query) context.Context {
+	return ctx
+}
+
+func (r *capturingToolRecorder) Start(ctx context.Context, operation, message string, data map[string]string) context.Context {
+	return ctx
+}
+
+func (r *capturingToolRecorder) Complete(ctx context.Context, operation, message string, data map[string]string) {
+	r.outcome, r.data = "complete", data
+}
+
+func (r *capturingToolRecorder) Fail(ctx context.Context, operation, message string, err error, data map[string]string) {
+	r.outcome, r.data = "fail", data
+}
+
+func newPolicyTestRegistry(t *testing.T, policy string, executor ToolExecutor) (*ToolRegistry, *capturingToolRecorder) {
+	t.Helper()
+	toolRecorder := &capturingToolRecorder{}
+	registry := NewToolRegistry(nil, noop.NewToolRecorder(), toolRecorder)
+	parsed, err := ParseToolErrorPolicy(policy)
+	require.NoError(t, err)
+	registry.SetErrorPolicy(parsed)
+	registry.RegisterTool(ToolDefinition{Name: "lookup"}, executor)
+	return registry, toolRecorder
+}
+
+func lookupCall() ToolCall {
+	return ToolCall{ID: "call-1", Type: "function", Function: openai.ChatCompletionMessageToolCallFunction{Name: "lookup", Arguments: "{}"}}
+}
+
+func TestParseToolErrorPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ToolErrorPolicy
+		wantErr bool
+	}{
+		{value: "", want: ToolErrorPolicy{Action: ToolErrorPolicyFail}},
+		{value: "fail", want: ToolErrorPolicy{Action: ToolErrorPolicyFail}},
+		{value: "report", want: ToolErrorPolicy{Action: ToolErrorPolicyReport}},
+		{value: "retry:3", want: ToolErrorPolicy{Action: ToolErrorPolicyRetry, MaxRetries: 3}},
+		{value: "retry:0", wantErr: true},
+		{value: "retry:x", wantErr: true},
+		{value: "ignore", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseToolErrorPolicy(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			if tt.value != "" {
+				assert.Equal(t, tt.value, got.String())
+			}
+		})
+	}
+}
+
+func TestExecuteTool_FailPolicy(t *testing.T) {
+	executor := &flakyExecutor{failures: 1, err: errors.New("HTTP 503")}
+	registry, recorder := newPolicyTestRegistry(t, "fail", executor)
+
+	_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+	require.EqualError(t, err, "HTTP 503")
+	assert.Equal(t, 1, executor.calls)
+	assert.Equal(t, "fail", recorder.outcome)
+	assert.Equal(t, ToolOutcomeFailed, recorder.data["outcome"])
+	assert.Equal(t, "fail", recorder.data["errorPolicy"])
+}
+
+func TestExecuteTool_ReportPolicy(t *testing.T) {
+	executor := &flakyExecutor{failures: 1, err: errors.New("HTTP 404")}
+	registry, recorder := newPolicyTestRegistry(t, "report", executor)
+
+	result, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+	require.NoError(t, err)
+	assert.Equal(t, "call-1", result.ID)
+	assert.Contains(t, result.Content, "HTTP 404")
+	assert.Equal(t, "complete", recorder.outcome)
+	assert.Equal(t, ToolOutcomeReported, recorder.data["outcome"])
+}
+
+func TestExecuteTool_ReportPolicyUnknownTool(t *testing.T) {
+	registry, recorder := newPolicyTestRegistry(t, "report", &flakyExecutor{})
+	call := lookupCall()
+	call.Function.Name = "missing"
+
+	result, err := registry.ExecuteTool(context.Background(), call)
+
+	require.NoError(t, err)
+	assert.Contains(t, result.Content, "tool missing not found")
+	assert.Equal(t, "complete", recorder.outcome)
+	assert.Equal(t, ToolOutcomeReported, recorder.data["outcome"])
+	assert.Equal(t, "unknown", recorder.data["toolType"])
+}
+
+func TestExecuteTool_RetryPolicy(t *testing.T) {
+	originalDelay := toolRetryBaseDelay
+	toolRetryBaseDelay = time.Millisecond
+	t.Cleanup(func() { toolRetryBaseDelay = originalDelay })
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		executor := &flakyExecutor{failures: 2, err: errors.New("timeout")}
+		registry, recorder := newPolicyTestRegistry(t, "retry:2", executor)
+
+		result, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result.Content)
+		assert.Equal(t, 3, executor.calls)
+		assert.Equal(t, ToolOutcomeSucceeded, recorder.data["outcome"])
+		assert.Equal(t, "3", recorder.data["attempts"])
+	})
+
+	t.Run("fails when retries are exhausted", func(t *testing.T) {
+		executor := &flakyExecutor{failures: 5, err: errors.New("timeout")}
+		registry, recorder := newPolicyTestRegistry(t, "retry:2", executor)
+
+		_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+		require.Error(t, err)
+		assert.Equal(t, 3, executor.calls)
+		assert.Equal(t, ToolOutcomeFailed, recorder.data["outcome"])
+	})
+
+	t.Run("retries retryable HTTP status codes", func(t *testing.T) {
+		executor := &flakyExecutor{failures: 1, err: &httpToolStatusError{StatusCode: 503, Status: "503 Service Unavailable"}}
+		registry, _ := newPolicyTestRegistry(t, "retry:2", executor)
+
+		_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, executor.calls)
+	})
+
+	t.Run("does not retry errors that would fail again", func(t *testing.T) {
+		executor := &flakyExecutor{failures: 5, err: &httpToolStatusError{StatusCode: 400, Status: "400 Bad Request"}}
+		registry, recorder := newPolicyTestRegistry(t, "retry:2", executor)
+
+		_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+		require.Error(t, err)
+		assert.Equal(t, 1, executor.calls)
+		assert.Equal(t, "1", recorder.data["attempts"])
+	})
+
+	t.Run("does not retry team termination", func(t *testing.T) {
+		executor := &flakyExecutor{failures: 5, err: &TerminateTeam{}}
+		registry, _ := newPolicyTestRegistry(t, "retry:2", executor)
+
+		_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+		require.True(t, IsTerminateTeam(err))
+		assert.Equal(t, 1, executor.calls)
+	})
+}
+
+func TestExecuteTool_PerToolPolicyOverridesDefault(t *testing.T) {
+	executor := &flakyExecutor{failures: 1, err: errors.New("bad arguments")}
+	registry, _ := newPolicyTestRegistry(t, "fail", executor)
+	registry.SetToolErrorPolicy("lookup", ToolErrorPolicy{Action: ToolErrorPolicyReport})
+
+	_, err := registry.ExecuteTool(context.Background(), lookupCall())
+
+	require.NoError(t, err)
+}
+
+func TestIsTransientToolError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server error", err: fmt.Errorf("wrapped: %w", &httpToolStatusError{StatusCode: 502, Status: "502 Bad Gateway"}), want: true},
+		{name: "rate limited", err: &httpToolStatusError{StatusCode: 429, Status: "429 Too Many Requests"}, want: true},
+		{name: "client error", err: &httpToolStatusError{StatusCode: 404, Status: "404 Not Found"}, want: false},
+		{name: "grpc unavailable", err: &grpcToolCallError{method: "/orders.v1.Orders/Get", status: status.New(codes.Unavailable, "down")}, want: true},
+		{name: "grpc invalid argument", err: &grpcToolCallError{method: "/orders.v1.Orders/Get", status: status.New(codes.InvalidArgument, "bad id")}, want: false},
+		{name: "deadline exceeded", err: fmt.Errorf("failed to fetch URL: %w", context.DeadlineExceeded), want: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "invalid arguments", err: errors.New("failed to parse arguments: unexpected end of JSON input"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isTransientToolError(tt.err))
+		})
+	}
+}
+
+func TestToolRetryDelay(t *testing.T) {
+	assert.Equal(t, toolRetryBaseDelay, toolRetryDelay(1))
+	assert.Equal(t, 2*toolRetryBaseDelay, toolRetryDelay(2))
+	assert.Equal(t, toolRetryMaxDelay, toolRetryDelay(40))
+}
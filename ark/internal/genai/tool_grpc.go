@@ -0,0 +1,575 @@
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultGRPCToolTimeout = 30 * time.Second
+	// defaultGRPCToolMaxResponseBytes is the size responses are truncated to unless the tool sets one
+	defaultGRPCToolMaxResponseBytes = 1 << 20
+	// GRPCToolRefreshInterval is how often the method of a gRPC tool is loaded again, so the tool
+	// follows changes of the server
+	GRPCToolRefreshInterval = 5 * time.Minute
+)
+
+// GRPCExecutor calls a unary gRPC method, converting the JSON arguments to the request message
+// and the response message to JSON
+type GRPCExecutor struct {
+	K8sClient client.Client
+	// ToolName and Generation identify the tool, whose connection and method are shared by calls
+	ToolName   string
+	Generation int64
+	Spec       *arkv1alpha1.GRPCSpec
+	Namespace  string
+}
+
+// grpcToolClient is the connection to the server of a gRPC tool and the method it calls. It is
+// shared by the calls to the tool until the tool changes or the method is loaded again.
+type grpcToolClient struct {
+	conn   *grpc.ClientConn
+	method protoreflect.MethodDescriptor
+	types  *dynamicpb.Types
+	// fingerprint identifies the generation and spec of the tool the client was created for
+	fingerprint string
+	loadedAt    time.Time
+	// calls counts the calls using the connection, which is closed after the last one once the
+	// client is replaced
+	calls    int
+	replaced bool
+}
+
+var grpcToolClients = struct {
+	sync.Mutex
+	clients map[string]*grpcToolClient
+}{clients: map[string]*grpcToolClient{}}
+
+// grpcToolFingerprint identifies a generation and spec of a gRPC tool
+func grpcToolFingerprint(generation int64, spec *arkv1alpha1.GRPCSpec) string {
+	data, _ := json.Marshal(spec)
+	return fmt.Sprintf("%d|%x", generation, sha256.Sum256(data))
+}
+
+// acquireGRPCToolClient returns the shared client of a gRPC tool, creating it when the tool has
+// none, the tool changed, or its method was loaded more than GRPCToolRefreshInterval ago. With
+// refresh set, the method is loaded again in any case. Callers release the client after the call.
+func acquireGRPCToolClient(ctx context.Context, k8sClient client.Client, scope string, generation int64, spec *arkv1alpha1.GRPCSpec, namespace string, refresh bool) (*grpcToolClient, error) {
+	fingerprint := grpcToolFingerprint(generation, spec)
+
+	grpcToolClients.Lock()
+	existing, ok := grpcToolClients.clients[scope]
+	if ok && !refresh && existing.fingerprint == fingerprint && time.Since(existing.loadedAt) < GRPCToolRefreshInterval {
+		existing.calls++
+		grpcToolClients.Unlock()
+		return existing, nil
+	}
+	grpcToolClients.Unlock()
+
+	conn, err := dialGRPCTool(ctx, k8sClient, spec, namespace)
+	if err != nil {
+		return nil, err
+	}
+	method, files, err := loadGRPCMethod(ctx, k8sClient, conn, spec, namespace)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	loaded := &grpcToolClient{
+		conn:        conn,
+		method:      method,
+		types:       dynamicpb.NewTypes(files),
+		fingerprint: fingerprint,
+		loadedAt:    time.Now(),
+		calls:       1,
+	}
+
+	grpcToolClients.Lock()
+	defer grpcToolClients.Unlock()
+	if previous, ok := grpcToolClients.clients[scope]; ok {
+		previous.replaced = true
+		previous.closeIfUnused()
+	}
+	grpcToolClients.clients[scope] = loaded
+	return loaded, nil
+}
+
+// release ends a call using the client
+func (c *grpcToolClient) release() {
+	grpcToolClients.Lock()
+	defer grpcToolClients.Unlock()
+	c.calls--
+	c.closeIfUnused()
+}
+
+// closeIfUnused closes the connection of a replaced client once no call uses it. The caller holds
+// the lock of grpcToolClients.
+func (c *grpcToolClient) closeIfUnused() {
+	if c.replaced && c.calls == 0 {
+		_ = c.conn.Close()
+	}
+}
+
+// EvictGRPCToolClient closes the connection of a gRPC tool, such as when the tool is deleted
+func EvictGRPCToolClient(namespace, name string) {
+	grpcToolClients.Lock()
+	defer grpcToolClients.Unlock()
+	scope := namespace + "/" + name
+	if existing, ok := grpcToolClients.clients[scope]; ok {
+		existing.replaced = true
+		existing.closeIfUnused()
+		delete(grpcToolClients.clients, scope)
+	}
+}
+
+func (g *GRPCExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	content, err := g.execute(ctx, call.Function.Arguments)
+	if err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: err.Error(),
+		}, err
+	}
+
+	maxResponseBytes := defaultGRPCToolMaxResponseBytes
+	if g.Spec.MaxResponseBytes > 0 {
+		maxResponseBytes = g.Spec.MaxResponseBytes
+	}
+	if len(content) > maxResponseBytes {
+		content = truncateUTF8(content, maxResponseBytes) + fmt.Sprintf("\n[response truncated to %d of %d bytes]", maxResponseBytes, len(content))
+	}
+
+	return ToolResult{
+		ID:      call.ID,
+		Name:    call.Function.Name,
+		Content: content,
+	}, nil
+}
+
+func (g *GRPCExecutor) execute(ctx context.Context, arguments string) (string, error) {
+	timeout := defaultGRPCToolTimeout
+	if g.Spec.Timeout != "" {
+		if parsed, err := time.ParseDuration(g.Spec.Timeout); err == nil {
+			timeout = parsed
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	headers, err := ResolveHeaders(ctx, g.K8sClient, g.Spec.Headers, g.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if len(headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(headers))
+	}
+
+	toolClient, err := acquireGRPCToolClient(ctx, g.K8sClient, g.Namespace+"/"+g.ToolName, g.Generation, g.Spec, g.Namespace, false)
+	if err != nil {
+		return "", err
+	}
+	defer toolClient.release()
+	conn, method, types := toolClient.conn, toolClient.method, toolClient.types
+
+	request := dynamicpb.NewMessage(method.Input())
+	if strings.TrimSpace(arguments) != "" {
+		if err := (protojson.UnmarshalOptions{Resolver: types}).Unmarshal([]byte(arguments), request); err != nil {
+			return "", fmt.Errorf("failed to convert arguments to %s: %w", method.Input().FullName(), err)
+		}
+	}
+
+	response := dynamicpb.NewMessage(method.Output())
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	if err := conn.Invoke(ctx, fullMethod, request, response); err != nil {
+		if st, ok := status.FromError(err); ok {
+			return "", &grpcToolCallError{method: fullMethod, status: st}
+		}
+		return "", fmt.Errorf("gRPC call %s failed: %w", fullMethod, err)
+	}
+
+	content, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", Resolver: types}.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert response %s to JSON: %w", method.Output().FullName(), err)
+	}
+	return string(content), nil
+}
+
+// ResolveGRPCToolSchema loads the method of a gRPC tool, with server reflection or the tool's
+// descriptor set, and returns the JSON schema of its request message. The method replaces the one
+// shared by the calls to the tool. Metadata that refers to query parameters is not sent, as there
+// is no query.
+func ResolveGRPCToolSchema(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool) (map[string]any, error) {
+	spec, namespace := tool.Spec.GRPC, tool.Namespace
+	ctx, cancel := context.WithTimeout(ctx, defaultGRPCToolTimeout)
+	defer cancel()
+
+	var headers []arkv1alpha1.Header
+	for _, header := range spec.Headers {
+		if header.Value.ValueFrom == nil || header.Value.ValueFrom.QueryParameterRef == nil {
+			headers = append(headers, header)
+		}
+	}
+	resolvedHeaders, err := ResolveHeaders(ctx, k8sClient, headers, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolvedHeaders) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(resolvedHeaders))
+	}
+
+	toolClient, err := acquireGRPCToolClient(ctx, k8sClient, namespace+"/"+tool.Name, tool.Generation, spec, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	defer toolClient.release()
+	return grpcMessageSchema(toolClient.method.Input(), map[protoreflect.FullName]bool{}), nil
+}
+
+// grpcToolCallError is returned when the server answers a call with an error status, which the
+// tool error policy uses to tell transient failures apart
+type grpcToolCallError struct {
+	method string
+	status *status.Status
+}
+
+func (e *grpcToolCallError) Error() string {
+	return fmt.Sprintf("gRPC call %s failed with %s: %s", e.method, e.status.Code(), e.status.Message())
+}
+
+func (e *grpcToolCallError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// dialGRPCTool returns a connection to the server of a gRPC tool. Addresses resolved from
+// service references are URLs, so their scheme is removed.
+func dialGRPCTool(ctx context.Context, k8sClient client.Client, spec *arkv1alpha1.GRPCSpec, namespace string) (*grpc.ClientConn, error) {
+	resolver := common.NewValueSourceResolver(k8sClient)
+	address, err := resolver.ResolveValueSource(ctx, spec.Address, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve address: %w", err)
+	}
+	if parsed, err := url.Parse(address); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" {
+		address = parsed.Host
+	}
+
+	transportCredentials, err := grpcToolCredentials(ctx, resolver, spec.TLS, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	return conn, nil
+}
+
+// grpcToolCredentials returns plaintext credentials without TLS settings, and otherwise TLS
+// credentials with the CA and client certificate of the settings
+func grpcToolCredentials(ctx context.Context, resolver *common.ValueSourceResolver, spec *arkv1alpha1.GRPCTLS, namespace string) (credentials.TransportCredentials, error) {
+	if spec == nil {
+		return insecure.NewCredentials(), nil
+	}
+
+	values := map[string]string{}
+	for name, source := range map[string]*arkv1alpha1.ValueSource{"ca": spec.CA, "cert": spec.Cert, "key": spec.Key} {
+		if source == nil {
+			continue
+		}
+		value, err := resolver.ResolveValueSource(ctx, *source, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tls %s: %w", name, err)
+		}
+		values[name] = value
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if values["cert"] != "" || values["key"] != "" {
+		certificateConfig, err := clientCertificateTLSConfig(values["cert"], values["key"], values["ca"])
+		if err != nil {
+			return nil, err
+		}
+		config = certificateConfig
+	} else if values["ca"] != "" {
+		pool, err := caCertPool(values["ca"])
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	config.ServerName = spec.ServerName
+	config.InsecureSkipVerify = spec.InsecureSkipVerify //nolint:gosec // explicitly requested by the tool
+	return credentials.NewTLS(config), nil
+}
+
+// loadGRPCMethod returns the descriptor of the tool's method and the files it was found in, from
+// the tool's descriptor set or else from server reflection
+func loadGRPCMethod(ctx context.Context, k8sClient client.Client, conn *grpc.ClientConn, spec *arkv1alpha1.GRPCSpec, namespace string) (protoreflect.MethodDescriptor, *protoregistry.Files, error) {
+	var files *protoregistry.Files
+	var err error
+	if spec.DescriptorSet != nil {
+		files, err = loadGRPCDescriptorSet(ctx, k8sClient, spec.DescriptorSet, namespace)
+	} else {
+		files, err = reflectGRPCFiles(ctx, conn, spec.Service)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(spec.Service))
+	if err != nil {
+		return nil, nil, fmt.Errorf("service %s not found: %w", spec.Service, err)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a service", spec.Service)
+	}
+	method := service.Methods().ByName(protoreflect.Name(spec.Method))
+	if method == nil {
+		return nil, nil, fmt.Errorf("method %s not found in service %s", spec.Method, spec.Service)
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, nil, fmt.Errorf("method %s/%s is a streaming method, only unary methods are supported", spec.Service, spec.Method)
+	}
+	return method, files, nil
+}
+
+// loadGRPCDescriptorSet parses a FileDescriptorSet, base64 encoded or as is
+func loadGRPCDescriptorSet(ctx context.Context, k8sClient client.Client, source *arkv1alpha1.ValueSource, namespace string) (*protoregistry.Files, error) {
+	value, err := common.NewValueSourceResolver(k8sClient).ResolveValueSource(ctx, *source, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve descriptor set: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		data = []byte(value)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set: %w", err)
+	}
+	return files, nil
+}
+
+// reflectGRPCFiles loads the file that defines the service, and the files it depends on, with
+// server reflection. Dependencies the server does not return, such as well-known types, are
+// taken from the types compiled into the controller.
+func reflectGRPCFiles(ctx context.Context, conn *grpc.ClientConn, service string) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection failed: %w", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	files := map[string]*descriptorpb.FileDescriptorProto{}
+	requested := map[string]bool{}
+	pending := []*reflectionpb.ServerReflectionRequest{{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	}}
+	for len(pending) > 0 {
+		request := pending[0]
+		pending = pending[1:]
+		if err := stream.Send(request); err != nil {
+			return nil, fmt.Errorf("server reflection failed: %w", err)
+		}
+		response, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("server reflection failed: %w", err)
+		}
+
+		if errorResponse := response.GetErrorResponse(); errorResponse != nil {
+			fileName := request.GetFileByFilename()
+			if fileName == "" {
+				return nil, fmt.Errorf("server reflection failed for %s: %s", service, errorResponse.GetErrorMessage())
+			}
+			known, err := protoregistry.GlobalFiles.FindFileByPath(fileName)
+			if err != nil {
+				return nil, fmt.Errorf("server reflection failed for %s: %s", fileName, errorResponse.GetErrorMessage())
+			}
+			files[fileName] = protodesc.ToFileDescriptorProto(known)
+		}
+		for _, raw := range response.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return nil, fmt.Errorf("invalid file descriptor from server reflection: %w", err)
+			}
+			files[file.GetName()] = file
+		}
+
+		for _, file := range files {
+			for _, dependency := range file.GetDependency() {
+				if _, ok := files[dependency]; ok || requested[dependency] {
+					continue
+				}
+				requested[dependency] = true
+				pending = append(pending, &reflectionpb.ServerReflectionRequest{
+					MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dependency},
+				})
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+	registry, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file descriptors from server reflection: %w", err)
+	}
+	return registry, nil
+}
+
+// grpcWellKnownTypeSchemas are the schemas of well-known types, which protojson encodes as
+// scalars or free-form JSON instead of objects with their fields
+var grpcWellKnownTypeSchemas = map[protoreflect.FullName]map[string]any{
+	"google.protobuf.Timestamp":   {"type": "string", "format": "date-time"},
+	"google.protobuf.Duration":    {"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`},
+	"google.protobuf.FieldMask":   {"type": "string"},
+	"google.protobuf.Struct":      {"type": "object"},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {"type": "array"},
+	"google.protobuf.Empty":       {"type": "object"},
+	"google.protobuf.Any":         {"type": "object", "properties": map[string]any{"@type": map[string]any{"type": "string"}}, "required": []string{"@type"}},
+	"google.protobuf.DoubleValue": {"type": "number"},
+	"google.protobuf.FloatValue":  {"type": "number"},
+	"google.protobuf.Int64Value":  {"type": "integer"},
+	"google.protobuf.UInt64Value": {"type": "integer"},
+	"google.protobuf.Int32Value":  {"type": "integer"},
+	"google.protobuf.UInt32Value": {"type": "integer"},
+	"google.protobuf.BoolValue":   {"type": "boolean"},
+	"google.protobuf.StringValue": {"type": "string"},
+	"google.protobuf.BytesValue":  {"type": "string", "contentEncoding": "base64"},
+}
+
+// grpcMessageSchema returns the JSON schema of a message in its protojson encoding. Recursive
+// messages are cut at the first repetition.
+func grpcMessageSchema(message protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool) map[string]any {
+	if schema, ok := grpcWellKnownTypeSchemas[message.FullName()]; ok {
+		// Copied, so adding a description does not change the shared schema
+		return maps.Clone(schema)
+	}
+	if seen[message.FullName()] {
+		return map[string]any{"type": "object"}
+	}
+	seen[message.FullName()] = true
+	defer delete(seen, message.FullName())
+
+	properties := map[string]any{}
+	var required []string
+	fields := message.Fields()
+	for i := range fields.Len() {
+		field := fields.Get(i)
+		schema := grpcFieldSchema(field, seen)
+
+		var description []string
+		if comment := grpcComment(field); comment != "" {
+			description = append(description, comment)
+		}
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			var names []string
+			for j := range oneof.Fields().Len() {
+				names = append(names, oneof.Fields().Get(j).JSONName())
+			}
+			description = append(description, fmt.Sprintf("Only one of %s may be set.", strings.Join(names, ", ")))
+		}
+		if len(description) > 0 {
+			schema["description"] = strings.Join(description, " ")
+		}
+
+		properties[field.JSONName()] = schema
+		if field.Cardinality() == protoreflect.Required {
+			required = append(required, field.JSONName())
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if comment := grpcComment(message); comment != "" {
+		schema["description"] = comment
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func grpcFieldSchema(field protoreflect.FieldDescriptor, seen map[protoreflect.FullName]bool) map[string]any {
+	if field.IsMap() {
+		return map[string]any{"type": "object", "additionalProperties": grpcValueSchema(field.MapValue(), seen)}
+	}
+	schema := grpcValueSchema(field, seen)
+	if field.IsList() {
+		return map[string]any{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+func grpcValueSchema(field protoreflect.FieldDescriptor, seen map[protoreflect.FullName]bool) map[string]any {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.EnumKind:
+		if field.Enum().FullName() == "google.protobuf.NullValue" {
+			return map[string]any{"type": "null"}
+		}
+		var names []string
+		values := field.Enum().Values()
+		for i := range values.Len() {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "integer"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return grpcMessageSchema(field.Message(), seen)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// grpcComment returns the leading comment of a descriptor on one line, if the file has source
+// information. Descriptor sets built with --include_source_info have it, reflection usually not.
+func grpcComment(descriptor protoreflect.Descriptor) string {
+	comment := descriptor.ParentFile().SourceLocations().ByDescriptor(descriptor).LeadingComments
+	return strings.Join(strings.Fields(comment), " ")
+}
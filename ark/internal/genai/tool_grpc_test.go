@@ -0,0 +1,212 @@
+package genai
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// startGRPCHealthServer serves the gRPC health service with server reflection, and records the
+// metadata of the calls
+func startGRPCHealthServer(t *testing.T) (string, *metadata.MD) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var received metadata.MD
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		received, _ = metadata.FromIncomingContext(ctx)
+		return handler(ctx, req)
+	}))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String(), &received
+}
+
+func executeGRPCTool(t *testing.T, executor *GRPCExecutor, arguments string) (string, error) {
+	result, err := executor.Execute(context.Background(), ToolCall{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "health", Arguments: arguments}})
+	if err != nil {
+		assert.Equal(t, err.Error(), result.Error)
+	}
+	return result.Content, err
+}
+
+func TestGRPCExecutor_ServerReflection(t *testing.T) {
+	address, received := startGRPCHealthServer(t)
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	executor := &GRPCExecutor{
+		K8sClient: k8sClient,
+		Spec: &arkv1alpha1.GRPCSpec{
+			Address: arkv1alpha1.ValueSource{Value: address},
+			Service: "grpc.health.v1.Health",
+			Method:  "Check",
+			Headers: []arkv1alpha1.Header{{Name: "X-Tenant", Value: arkv1alpha1.HeaderValue{Value: "shop"}}},
+		},
+		Namespace: "default",
+	}
+
+	content, err := executeGRPCTool(t, executor, `{"service": "orders"}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": "NOT_SERVING"}`, content)
+	assert.Equal(t, []string{"shop"}, received.Get("x-tenant"))
+
+	_, err = executeGRPCTool(t, executor, `{"service": "payments"}`)
+	assert.ErrorContains(t, err, "failed with NotFound")
+
+	_, err = executeGRPCTool(t, executor, `{"name": "orders"}`)
+	assert.ErrorContains(t, err, "failed to convert arguments to grpc.health.v1.HealthCheckRequest")
+
+	executor.Spec.Method = "Watch"
+	_, err = executeGRPCTool(t, executor, `{}`)
+	assert.ErrorContains(t, err, "only unary methods are supported")
+
+	executor.Spec.Service = "grpc.health.v1.Missing"
+	_, err = executeGRPCTool(t, executor, `{}`)
+	assert.ErrorContains(t, err, "server reflection failed for grpc.health.v1.Missing")
+}
+
+func TestGRPCExecutor_DescriptorSet(t *testing.T) {
+	address, _ := startGRPCHealthServer(t)
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto),
+	}}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	spec := &arkv1alpha1.GRPCSpec{
+		Address:       arkv1alpha1.ValueSource{Value: "http://" + address},
+		Service:       "grpc.health.v1.Health",
+		Method:        "Check",
+		DescriptorSet: &arkv1alpha1.ValueSource{Value: base64.StdEncoding.EncodeToString(data)},
+	}
+
+	content, err := executeGRPCTool(t, &GRPCExecutor{K8sClient: k8sClient, Spec: spec, Namespace: "default"}, `{}`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status": "SERVING"}`, content)
+
+	tool := &arkv1alpha1.Tool{ObjectMeta: metav1.ObjectMeta{Name: "health", Namespace: "default"}, Spec: arkv1alpha1.ToolSpec{Type: ToolTypeGRPC, GRPC: spec}}
+	schema, err := ResolveGRPCToolSchema(context.Background(), k8sClient, tool)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{"service": map[string]any{"type": "string"}}}, schema)
+}
+
+func sharedGRPCToolClient(scope string) *grpcToolClient {
+	grpcToolClients.Lock()
+	defer grpcToolClients.Unlock()
+	return grpcToolClients.clients[scope]
+}
+
+func TestGRPCExecutor_SharesClient(t *testing.T) {
+	address, _ := startGRPCHealthServer(t)
+	k8sClient := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	executor := &GRPCExecutor{
+		K8sClient:  k8sClient,
+		ToolName:   "shared-health",
+		Generation: 1,
+		Spec:       &arkv1alpha1.GRPCSpec{Address: arkv1alpha1.ValueSource{Value: address}, Service: "grpc.health.v1.Health", Method: "Check"},
+		Namespace:  "default",
+	}
+	t.Cleanup(func() { EvictGRPCToolClient("default", "shared-health") })
+
+	_, err := executeGRPCTool(t, executor, `{}`)
+	require.NoError(t, err)
+	first := sharedGRPCToolClient("default/shared-health")
+	require.NotNil(t, first)
+	_, err = executeGRPCTool(t, executor, `{}`)
+	require.NoError(t, err)
+	assert.Same(t, first, sharedGRPCToolClient("default/shared-health"), "calls share the connection and the method")
+	assert.Equal(t, 0, first.calls)
+
+	executor.Generation = 2
+	_, err = executeGRPCTool(t, executor, `{}`)
+	require.NoError(t, err)
+	assert.NotSame(t, first, sharedGRPCToolClient("default/shared-health"), "a change to the tool loads the method again")
+	assert.Equal(t, connectivity.Shutdown, first.conn.GetState(), "the replaced connection is closed")
+
+	EvictGRPCToolClient("default", "shared-health")
+	assert.Nil(t, sharedGRPCToolClient("default/shared-health"))
+}
+
+func TestGRPCMessageSchema(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("orders.proto"),
+		Package:    proto.String("orders.v1"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_OPEN"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("order_id"), JsonName: proto.String("orderId"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("quantity"), JsonName: proto.String("quantity"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("status"), JsonName: proto.String("status"), Number: proto.Int32(3), Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: proto.String(".orders.v1.Status"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("tags"), JsonName: proto.String("tags"), Number: proto.Int32(4), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				{Name: proto.String("labels"), JsonName: proto.String("labels"), Number: proto.Int32(5), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".orders.v1.Order.LabelsEntry"), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				{Name: proto.String("created_at"), JsonName: proto.String("createdAt"), Number: proto.Int32(6), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".google.protobuf.Timestamp"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("parent"), JsonName: proto.String("parent"), Number: proto.Int32(7), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: proto.String(".orders.v1.Order"), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("email"), JsonName: proto.String("email"), Number: proto.Int32(8), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), OneofIndex: proto.Int32(0)},
+				{Name: proto.String("phone"), JsonName: proto.String("phone"), Number: proto.Int32(9), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), OneofIndex: proto.Int32(0)},
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("contact")}},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("LabelsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+					{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			// message_type 0, field 0
+			{Path: []int32{4, 0, 2, 0}, Span: []int32{1, 0, 10}, LeadingComments: proto.String(" Identifier of the\n order\n")},
+		}},
+	}
+	descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	schema := grpcMessageSchema(descriptor.Messages().ByName("Order"), map[protoreflect.FullName]bool{})
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "description": "Identifier of the order"}, properties["orderId"])
+	assert.Equal(t, map[string]any{"type": "integer"}, properties["quantity"])
+	assert.Equal(t, map[string]any{"type": "string", "enum": []string{"STATUS_UNKNOWN", "STATUS_OPEN"}}, properties["status"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, properties["tags"])
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}}, properties["labels"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, properties["createdAt"])
+	assert.Equal(t, map[string]any{"type": "object"}, properties["parent"])
+	assert.Equal(t, "Only one of email, phone may be set.", properties["email"].(map[string]any)["description"])
+	assert.NotContains(t, grpcWellKnownTypeSchemas["google.protobuf.Timestamp"], "description")
+}
@@ -0,0 +1,409 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	// defaultHTTPToolMaxResponseBytes is the size responses are truncated to unless the tool sets one
+	defaultHTTPToolMaxResponseBytes = 1 << 20
+	// httpToolMaxReadBytes bounds how much of a response is read before it is converted and truncated
+	httpToolMaxReadBytes = 10 << 20
+	// noTemplateValue is what templates render for missing keys
+	noTemplateValue = "<no value>"
+)
+
+// httpToolBody is the body of an HTTP tool's request. It is kept as bytes so retries can send it
+// again.
+type httpToolBody struct {
+	content     []byte
+	contentType string
+}
+
+// hasHTTPToolBody reports whether requests with the method carry the tool's body
+func hasHTTPToolBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// needsTemplateData reports whether the tool's request has templates besides the URL
+func needsTemplateData(httpSpec *arkv1alpha1.HTTPSpec, method string) bool {
+	if len(httpSpec.QueryParameters) > 0 {
+		return true
+	}
+	return hasHTTPToolBody(method) && (httpSpec.Body != "" || len(httpSpec.Form) > 0 || len(httpSpec.Multipart) > 0)
+}
+
+// addHTTPToolQueryParameters adds the tool's query parameters to the URL. Parameters whose
+// templates render empty, such as optional arguments the model left out, are skipped.
+func addHTTPToolQueryParameters(parsedURL *url.URL, parameters []arkv1alpha1.HTTPQueryParameter, data map[string]any) error {
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	query := parsedURL.Query()
+	for _, parameter := range parameters {
+		value, err := common.ResolveTemplate(parameter.Value, data)
+		if err != nil {
+			return fmt.Errorf("failed to resolve query parameter %s: %w", parameter.Name, err)
+		}
+		if value == "" || value == noTemplateValue {
+			continue
+		}
+		query.Add(parameter.Name, value)
+	}
+	parsedURL.RawQuery = query.Encode()
+	return nil
+}
+
+// buildHTTPToolBody renders the tool's body, form or multipart body
+func buildHTTPToolBody(httpSpec *arkv1alpha1.HTTPSpec, data map[string]any) (*httpToolBody, error) {
+	switch {
+	case len(httpSpec.Form) > 0:
+		form := url.Values{}
+		for _, field := range httpSpec.Form {
+			value, err := common.ResolveTemplate(field.Value, data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve form field %s: %w", field.Name, err)
+			}
+			form.Add(field.Name, value)
+		}
+		return &httpToolBody{content: []byte(form.Encode()), contentType: "application/x-www-form-urlencoded"}, nil
+
+	case len(httpSpec.Multipart) > 0:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for _, field := range httpSpec.Multipart {
+			value, err := common.ResolveTemplate(field.Value, data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve multipart field %s: %w", field.Name, err)
+			}
+			if err := writeMultipartField(writer, field, value); err != nil {
+				return nil, fmt.Errorf("failed to write multipart field %s: %w", field.Name, err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to write multipart body: %w", err)
+		}
+		return &httpToolBody{content: buf.Bytes(), contentType: writer.FormDataContentType()}, nil
+
+	case httpSpec.Body != "":
+		content, err := common.ResolveTemplate(httpSpec.Body, data)
+		if err != nil {
+			return nil, fmt.Errorf("body template resolution failed: %w", err)
+		}
+		return &httpToolBody{content: []byte(content)}, nil
+	}
+	return nil, nil
+}
+
+// writeMultipartField writes a field of a multipart body, as a file if the field has a file name
+func writeMultipartField(writer *multipart.Writer, field arkv1alpha1.HTTPFormField, value string) error {
+	if field.FileName == "" {
+		return writer.WriteField(field.Name, value)
+	}
+
+	contentType := field.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": field.Name, "filename": field.FileName}))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, value)
+	return err
+}
+
+// isHTTPToolSuccess reports whether the status code is one of the tool's success status codes
+func isHTTPToolSuccess(statusCode int, successStatusCodes []string) bool {
+	if len(successStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 400
+	}
+	for _, codes := range successStatusCodes {
+		low, high, err := ParseStatusCodeRange(codes)
+		if err == nil && statusCode >= low && statusCode <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStatusCodeRange parses a status code such as 200 or a range such as 200-299
+func ParseStatusCodeRange(codes string) (int, int, error) {
+	lowText, highText, isRange := strings.Cut(codes, "-")
+	low, err := strconv.Atoi(lowText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid status code %q", codes)
+	}
+	if !isRange {
+		return low, low, nil
+	}
+	high, err := strconv.Atoi(highText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid status code range %q", codes)
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("invalid status code range %q: %d is greater than %d", codes, low, high)
+	}
+	return low, high, nil
+}
+
+// httpToolRetryPolicy returns the retry policy of the tool's requests. Only requests with
+// idempotent methods are retried, as others may have taken effect before they failed.
+func httpToolRetryPolicy(spec *arkv1alpha1.HTTPRetryPolicy, method string) retryPolicy {
+	if spec == nil || (method != http.MethodGet && method != http.MethodPut && method != http.MethodDelete) {
+		return retryPolicy{maxAttempts: 1}
+	}
+
+	policy := retryPolicy{
+		maxAttempts:    defaultRetryMaxAttempts,
+		initialBackoff: defaultRetryInitialBackoff,
+		maxBackoff:     defaultRetryMaxBackoff,
+	}
+	if spec.MaxAttempts > 0 {
+		policy.maxAttempts = spec.MaxAttempts
+	}
+	if spec.InitialBackoff != nil {
+		policy.initialBackoff = spec.InitialBackoff.Duration
+	}
+	if spec.MaxBackoff != nil {
+		policy.maxBackoff = spec.MaxBackoff.Duration
+	}
+	return policy
+}
+
+// isRetryableHTTPToolStatus reports whether a response with the status may succeed if repeated
+func isRetryableHTTPToolStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// httpToolStatusError is returned when an HTTP tool gets a response with a status code that is
+// not one of its success status codes
+type httpToolStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *httpToolStatusError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Status)
+}
+
+// doWithRetry sends the requests made by newRequest until one gets a response that is not
+// retryable, attempts run out or the delay before the next attempt ends after the deadline of
+// ctx. The bodies of discarded responses are closed.
+func doWithRetry(ctx context.Context, httpClient *http.Client, policy retryPolicy, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	maxAttempts := max(policy.maxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpClient.Do(req)
+
+		retryable := err != nil || isRetryableHTTPToolStatus(resp.StatusCode)
+		if !retryable || attempt >= maxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := policy.delay(attempt, nil)
+		if err == nil {
+			if retryAfter, ok := retryAfterFromHeader(resp.Header, resp.StatusCode == http.StatusTooManyRequests); ok {
+				delay = retryAfter
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, httpToolMaxReadBytes))
+			_ = resp.Body.Close()
+		}
+		logf.FromContext(ctx).Info("HTTP request failed, retrying", "attempt", attempt, "delay", delay.String(), "reason", reason)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// readHTTPToolResponse reads the response body and converts it to the text passed to the model,
+// truncated to maxResponseBytes with a notice at the end
+func readHTTPToolResponse(resp *http.Response, responseFormat string, maxResponseBytes int) (string, error) {
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = defaultHTTPToolMaxResponseBytes
+	}
+	readLimit := max(maxResponseBytes, httpToolMaxReadBytes)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(readLimit)+1))
+	if err != nil {
+		return "", err
+	}
+	complete := len(body) <= readLimit
+	if !complete {
+		body = body[:readLimit]
+	}
+
+	content := string(body)
+	if responseFormat != "raw" {
+		content = convertHTTPToolResponse(body, resp.Header.Get("Content-Type"), complete)
+	}
+	if len(content) <= maxResponseBytes && complete {
+		return content, nil
+	}
+
+	notice := fmt.Sprintf("[response truncated: the response is larger than %d bytes]", readLimit)
+	if complete {
+		notice = fmt.Sprintf("[response truncated to %d of %d bytes]", maxResponseBytes, len(content))
+	}
+	return truncateUTF8(content, maxResponseBytes) + "\n\n" + notice, nil
+}
+
+// convertHTTPToolResponse pretty-prints JSON and converts HTML to text. Other content, and JSON
+// that is incomplete or invalid, is returned unchanged.
+func convertHTTPToolResponse(body []byte, contentType string, complete bool) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return string(body)
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var buf bytes.Buffer
+		if complete && json.Indent(&buf, body, "", "  ") == nil {
+			return buf.String()
+		}
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		if text, err := htmlToText(body); err == nil {
+			return text
+		}
+	}
+	return string(body)
+}
+
+// htmlSkippedElements are elements whose content is not shown to readers
+var htmlSkippedElements = []atom.Atom{atom.Head, atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Svg, atom.Iframe}
+
+// htmlBlockElements are elements that start on a new line
+var htmlBlockElements = []atom.Atom{
+	atom.Address, atom.Article, atom.Aside, atom.Blockquote, atom.Br, atom.Dd, atom.Div, atom.Dl, atom.Dt,
+	atom.Figcaption, atom.Figure, atom.Footer, atom.Form, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5,
+	atom.H6, atom.Header, atom.Hr, atom.Li, atom.Main, atom.Nav, atom.Ol, atom.P, atom.Pre, atom.Section,
+	atom.Table, atom.Tr, atom.Ul,
+}
+
+// htmlWhitespace replaces the line breaks of text outside pre elements, which browsers show as spaces
+var htmlWhitespace = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// htmlToText returns the readable text of an HTML document, with a line per block element. The
+// whitespace of pre elements is kept as is.
+func htmlToText(body []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	var blocks []string
+	var text strings.Builder
+	// flush adds the text written so far, collapsing the whitespace within lines, which in HTML
+	// source is only formatting
+	flush := func() {
+		for line := range strings.SplitSeq(text.String(), "\n") {
+			if line = strings.Join(strings.Fields(line), " "); line != "" {
+				blocks = append(blocks, line)
+			}
+		}
+		text.Reset()
+	}
+	preformatted := 0
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			if preformatted > 0 {
+				text.WriteString(node.Data)
+			} else {
+				text.WriteString(htmlWhitespace.Replace(node.Data))
+			}
+			return
+		case html.ElementNode:
+			if slices.Contains(htmlSkippedElements, node.DataAtom) {
+				return
+			}
+			if node.DataAtom == atom.Li {
+				text.WriteString("\n- ")
+			}
+			if node.DataAtom == atom.Td || node.DataAtom == atom.Th {
+				text.WriteString(" ")
+			}
+			if node.DataAtom == atom.Pre && preformatted == 0 {
+				flush()
+				preformatted++
+				defer func() {
+					preformatted--
+					if pre := strings.TrimRight(strings.TrimLeft(text.String(), "\r\n"), " \t\r\n"); pre != "" {
+						blocks = append(blocks, pre)
+					}
+					text.Reset()
+				}()
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if node.Type == html.ElementNode && slices.Contains(htmlBlockElements, node.DataAtom) {
+			text.WriteString("\n")
+		}
+	}
+	walk(doc)
+	flush()
+
+	return strings.Join(blocks, "\n"), nil
+}
+
+// truncateUTF8 truncates s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,201 @@
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+// httpToolAuth authenticates the requests of an HTTP tool. It is shared by every call to the
+// tool, so OAuth2 tokens are only requested again when they expire.
+type httpToolAuth struct {
+	oauth2      *clientcredentials.Config
+	tokenClient *http.Client
+	// mu guards token, so concurrent calls wait for a single token request
+	mu       sync.Mutex
+	token    *oauth2.Token
+	username string
+	password string
+	// transport presents the client certificate, or is nil without one
+	transport *http.Transport
+	// fingerprint identifies the configuration the auth was created from
+	fingerprint string
+}
+
+var httpToolAuths = struct {
+	sync.Mutex
+	auths map[string]*httpToolAuth
+}{auths: map[string]*httpToolAuth{}}
+
+// loadHTTPToolAuth returns the auth of an HTTP tool, or nil if the tool has none. The auth is
+// recreated when the tool's auth configuration or the values it references change.
+func loadHTTPToolAuth(ctx context.Context, resolver *common.ValueSourceResolver, namespace, name string, spec *arkv1alpha1.HTTPAuth) (*httpToolAuth, error) {
+	scope := namespace + "/" + name
+	if spec == nil {
+		httpToolAuths.Lock()
+		delete(httpToolAuths.auths, scope)
+		httpToolAuths.Unlock()
+		return nil, nil
+	}
+
+	values, err := resolveHTTPAuthValues(ctx, resolver, namespace, spec)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		_, _ = fmt.Fprintf(fingerprint, "%s=%d:%s|", name, len(values[name]), values[name])
+	}
+	if spec.OAuth2 != nil {
+		_, _ = fmt.Fprintf(fingerprint, "%s|%s", spec.OAuth2.TokenURL, strings.Join(spec.OAuth2.Scopes, " "))
+	}
+
+	auth := &httpToolAuth{fingerprint: fmt.Sprintf("%x", fingerprint.Sum(nil))}
+	httpToolAuths.Lock()
+	defer httpToolAuths.Unlock()
+	existing, ok := httpToolAuths.auths[scope]
+	if ok && existing.fingerprint == auth.fingerprint {
+		return existing, nil
+	}
+	if ok && existing.transport != nil {
+		existing.transport.CloseIdleConnections()
+	}
+
+	if spec.ClientCertificate != nil {
+		tlsConfig, err := clientCertificateTLSConfig(values["cert"], values["key"], values["ca"])
+		if err != nil {
+			return nil, err
+		}
+		auth.transport = http.DefaultTransport.(*http.Transport).Clone()
+		auth.transport.TLSClientConfig = tlsConfig
+	}
+	if spec.Basic != nil {
+		auth.username, auth.password = values["username"], values["password"]
+	}
+	if spec.OAuth2 != nil {
+		auth.oauth2 = &clientcredentials.Config{
+			ClientID:     values["clientId"],
+			ClientSecret: values["clientSecret"],
+			TokenURL:     spec.OAuth2.TokenURL,
+			Scopes:       spec.OAuth2.Scopes,
+		}
+		auth.tokenClient = &http.Client{Timeout: 30 * time.Second}
+		if auth.transport != nil {
+			auth.tokenClient.Transport = auth.transport
+		}
+	}
+	httpToolAuths.auths[scope] = auth
+	return auth, nil
+}
+
+// resolveHTTPAuthValues resolves the value sources of the auth, keyed by their field names
+func resolveHTTPAuthValues(ctx context.Context, resolver *common.ValueSourceResolver, namespace string, spec *arkv1alpha1.HTTPAuth) (map[string]string, error) {
+	sources := map[string]*arkv1alpha1.ValueSource{}
+	if spec.OAuth2 != nil {
+		sources["clientId"] = &spec.OAuth2.ClientID
+		sources["clientSecret"] = &spec.OAuth2.ClientSecret
+	}
+	if spec.Basic != nil {
+		sources["username"] = &spec.Basic.Username
+		sources["password"] = &spec.Basic.Password
+	}
+	if spec.ClientCertificate != nil {
+		sources["cert"] = &spec.ClientCertificate.Cert
+		sources["key"] = &spec.ClientCertificate.Key
+		sources["ca"] = spec.ClientCertificate.CA
+	}
+
+	values := map[string]string{}
+	for name, source := range sources {
+		if source == nil {
+			continue
+		}
+		value, err := resolver.ResolveValueSource(ctx, *source, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve auth %s: %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// clientCertificateTLSConfig returns a TLS configuration that presents the client certificate and,
+// if a CA is given, verifies the server with it instead of the system roots
+func clientCertificateTLSConfig(cert, key, ca string) (*tls.Config, error) {
+	certificate, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	if ca != "" {
+		pool, err := caCertPool(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// caCertPool returns a pool with the PEM encoded CA certificates
+func caCertPool(ca string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("invalid CA certificate: no PEM certificates found")
+	}
+	return pool, nil
+}
+
+// authorize adds the credentials to a request
+func (a *httpToolAuth) authorize(req *http.Request) error {
+	if a.oauth2 != nil {
+		token, err := a.oauth2Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get OAuth2 token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
+	if a.username != "" || a.password != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+	return nil
+}
+
+// oauth2Token returns the current OAuth2 token, requesting a new one with the context of the
+// tool call when it is missing or expired
+func (a *httpToolAuth) oauth2Token(ctx context.Context) (*oauth2.Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token.Valid() {
+		return a.token, nil
+	}
+	token, err := a.oauth2.Token(context.WithValue(ctx, oauth2.HTTPClient, a.tokenClient))
+	if err != nil {
+		return nil, err
+	}
+	a.token = token
+	return token, nil
+}
+
+// httpClient returns a client for the tool's requests, presenting the client certificate if any
+func (a *httpToolAuth) httpClient(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if a.transport != nil {
+		client.Transport = a.transport
+	}
+	return client
+}
@@ -0,0 +1,175 @@
+package genai
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newHTTPAuthTestTool(name, url string, auth *arkv1alpha1.HTTPAuth) *arkv1alpha1.Tool {
+	return &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type: ToolTypeHTTP,
+			HTTP: &arkv1alpha1.HTTPSpec{URL: url, Auth: auth},
+		},
+	}
+}
+
+func executeHTTPTool(t *testing.T, executor *HTTPExecutor) ToolResult {
+	result, err := executor.Execute(context.Background(), ToolCall{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: executor.ToolName, Arguments: "{}"}})
+	require.NoError(t, err)
+	return result
+}
+
+func TestHTTPExecutor_OAuth2ClientCredentials(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		clientID, clientSecret, _ := r.BasicAuth()
+		if r.PostForm.Get("grant_type") != "client_credentials" || clientID != "ark" || (clientSecret != "s3cret" && clientSecret != "rotated") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": 3600}`, tokenRequests)
+	}))
+	t.Cleanup(tokenServer.Close)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	t.Cleanup(apiServer.Close)
+
+	tool := newHTTPAuthTestTool("oauth2-tool", apiServer.URL, &arkv1alpha1.HTTPAuth{OAuth2: &arkv1alpha1.OAuth2ClientCredentials{
+		TokenURL:     tokenServer.URL,
+		ClientID:     arkv1alpha1.ValueSource{Value: "ark"},
+		ClientSecret: arkv1alpha1.ValueSource{ValueFrom: &arkv1alpha1.ValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "oauth2"}, Key: "secret"}}},
+		Scopes:       []string{"read", "write"},
+	}})
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "oauth2", Namespace: "default"}, Data: map[string][]byte{"secret": []byte("s3cret")}}
+	k8sClient := setupModelTestClient([]client.Object{tool, secret})
+
+	// Executors are created per query, but share the token
+	for range 2 {
+		executor := &HTTPExecutor{K8sClient: k8sClient, ToolName: tool.Name, ToolNamespace: "default"}
+		assert.Equal(t, "Bearer token-1", executeHTTPTool(t, executor).Content)
+	}
+	assert.Equal(t, 1, tokenRequests)
+
+	// A new client secret replaces the token
+	secret.Data["secret"] = []byte("rotated")
+	require.NoError(t, k8sClient.Update(context.Background(), secret))
+	executor := &HTTPExecutor{K8sClient: k8sClient, ToolName: tool.Name, ToolNamespace: "default"}
+	assert.Equal(t, "Bearer token-2", executeHTTPTool(t, executor).Content)
+}
+
+func TestHTTPToolAuth_OAuth2TokenUsesRequestContext(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Query().Get("slow") != "" {
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`)
+	}))
+	t.Cleanup(tokenServer.Close)
+	auth := &httpToolAuth{oauth2: &clientcredentials.Config{ClientID: "ark", ClientSecret: "s3cret", TokenURL: tokenServer.URL + "?slow=1"}, tokenClient: &http.Client{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://api.example.com", nil)
+	require.NoError(t, err)
+	err = auth.authorize(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the token request ends with the tool call")
+
+	auth.oauth2.TokenURL = tokenServer.URL
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, "http://api.example.com", nil)
+	require.NoError(t, err)
+	require.NoError(t, auth.authorize(req))
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+}
+
+func TestHTTPExecutor_BasicAuth(t *testing.T) {
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		_, _ = fmt.Fprintf(w, "%v %s %s", ok, username, password)
+	}))
+	t.Cleanup(apiServer.Close)
+
+	tool := newHTTPAuthTestTool("basic-tool", apiServer.URL, &arkv1alpha1.HTTPAuth{Basic: &arkv1alpha1.BasicAuth{
+		Username: arkv1alpha1.ValueSource{Value: "ark"},
+		Password: arkv1alpha1.ValueSource{Value: "s3cret"},
+	}})
+	executor := &HTTPExecutor{K8sClient: setupModelTestClient([]client.Object{tool}), ToolName: tool.Name, ToolNamespace: "default"}
+
+	assert.Equal(t, "true ark s3cret", executeHTTPTool(t, executor).Content)
+}
+
+func TestHTTPExecutor_ClientCertificate(t *testing.T) {
+	cert, key := testClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(cert)))
+
+	apiServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	apiServer.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	apiServer.StartTLS()
+	t.Cleanup(apiServer.Close)
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: apiServer.Certificate().Raw}))
+
+	tool := newHTTPAuthTestTool("mtls-tool", apiServer.URL, &arkv1alpha1.HTTPAuth{ClientCertificate: &arkv1alpha1.TLSClientCertificate{
+		Cert: arkv1alpha1.ValueSource{Value: cert},
+		Key:  arkv1alpha1.ValueSource{Value: key},
+		CA:   &arkv1alpha1.ValueSource{Value: serverCA},
+	}})
+	executor := &HTTPExecutor{K8sClient: setupModelTestClient([]client.Object{tool}), ToolName: tool.Name, ToolNamespace: "default"}
+
+	assert.Equal(t, "ark-tool", executeHTTPTool(t, executor).Content)
+}
+
+// testClientCertificate returns a PEM encoded self-signed client certificate and its key
+func testClientCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ark-tool"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
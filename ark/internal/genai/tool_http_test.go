@@ -0,0 +1,224 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// executeHTTPSpec calls an HTTP tool with the spec and arguments
+func executeHTTPSpec(t *testing.T, spec *arkv1alpha1.HTTPSpec, arguments string) (ToolResult, error) {
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "http-tool", Namespace: "default"},
+		Spec:       arkv1alpha1.ToolSpec{Type: ToolTypeHTTP, HTTP: spec},
+	}
+	executor := &HTTPExecutor{K8sClient: setupModelTestClient([]client.Object{tool}), ToolName: tool.Name, ToolNamespace: "default"}
+	return executor.Execute(context.Background(), ToolCall{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: tool.Name, Arguments: arguments}})
+}
+
+func TestHTTPExecutor_QueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.URL.RawQuery)
+	}))
+	t.Cleanup(server.Close)
+
+	spec := &arkv1alpha1.HTTPSpec{
+		URL: server.URL + "/search?version=2",
+		QueryParameters: []arkv1alpha1.HTTPQueryParameter{
+			{Name: "q", Value: "{{ .input.query }}"},
+			{Name: "limit", Value: "{{ .input.limit }}"},
+		},
+	}
+
+	result, err := executeHTTPSpec(t, spec, `{"query": "red & blue"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "q=red+%26+blue&version=2", result.Content)
+}
+
+func TestHTTPExecutor_FormBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			file, header, err := r.FormFile("report")
+			require.NoError(t, err)
+			content, _ := io.ReadAll(file)
+			_, _ = fmt.Fprintf(w, "%s %s %s %s", r.FormValue("title"), header.Filename, header.Header.Get("Content-Type"), content)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		_, _ = fmt.Fprintf(w, "%s %s", r.Header.Get("Content-Type"), r.PostForm.Encode())
+	}))
+	t.Cleanup(server.Close)
+
+	result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{
+		URL:    server.URL,
+		Method: "POST",
+		Form:   []arkv1alpha1.HTTPFormField{{Name: "q", Value: "{{ .input.query }}"}},
+	}, `{"query": "status"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded q=status", result.Content)
+
+	result, err = executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{
+		URL:    server.URL,
+		Method: "POST",
+		Multipart: []arkv1alpha1.HTTPFormField{
+			{Name: "title", Value: "{{ .input.title }}"},
+			{Name: "report", Value: "{{ .input.report }}", FileName: "report.csv", ContentType: "text/csv"},
+		},
+	}, `{"title": "Q3", "report": "a,b"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Q3 report.csv text/csv a,b", result.Content)
+}
+
+func TestHTTPExecutor_SuccessStatusCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, "no such order")
+	}))
+	t.Cleanup(server.Close)
+
+	result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL}, "{}")
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "HTTP error 404")
+	assert.Contains(t, result.Error, "no such order")
+
+	result, err = executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, SuccessStatusCodes: []string{"200-299", "404"}}, "{}")
+	require.NoError(t, err)
+	assert.Equal(t, "no such order", result.Content)
+}
+
+func TestHTTPExecutor_ErrorsOmitQueryString(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL + "/orders?api_key=secret"}, "{}")
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "HTTP error 401")
+	assert.Contains(t, result.Error, server.URL+"/orders")
+	assert.NotContains(t, result.Error, "secret")
+
+	server.Close()
+	result, err = executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL + "/orders?api_key=secret"}, "{}")
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "failed to fetch URL")
+	assert.NotContains(t, result.Error, "secret")
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestHTTPExecutor_ResponseConversion(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		responseFormat string
+		expected       string
+	}{
+		{
+			name:        "json is pretty-printed",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"id":1,"tags":["a"]}`,
+			expected:    "{\n  \"id\": 1,\n  \"tags\": [\n    \"a\"\n  ]\n}",
+		},
+		{
+			name:        "invalid json is unchanged",
+			contentType: "application/json",
+			body:        `{"id":`,
+			expected:    `{"id":`,
+		},
+		{
+			name:        "html is converted to text",
+			contentType: "text/html",
+			body: `<html><head><title>Orders</title><style>p { color: red }</style></head>
+<body><h1>Orders</h1><script>track()</script>
+<p>Two   orders
+are <b>open</b>.</p><ul><li>A-1</li><li>A-2</li></ul><pre>id  qty
+1   2</pre></body></html>`,
+			expected: "Orders\nTwo orders are open.\n- A-1\n- A-2\nid  qty\n1   2",
+		},
+		{
+			name:        "html keeps the indentation of preformatted text",
+			contentType: "text/html",
+			body:        "<p>Example</p><pre><code>def total(items):\n    return sum(items)\n</code></pre><p>Done</p>",
+			expected:    "Example\ndef total(items):\n    return sum(items)\nDone",
+		},
+		{
+			name:           "raw responses are unchanged",
+			contentType:    "application/json",
+			body:           `{"id":1}`,
+			responseFormat: "raw",
+			expected:       `{"id":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(server.Close)
+
+			result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, ResponseFormat: tt.responseFormat}, "{}")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Content)
+		})
+	}
+}
+
+func TestHTTPExecutor_MaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = fmt.Fprint(w, "héllo world")
+	}))
+	t.Cleanup(server.Close)
+
+	// The limit falls inside é, which is left out rather than split
+	result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, MaxResponseBytes: 2}, "{}")
+	require.NoError(t, err)
+	assert.Equal(t, "h\n\n[response truncated to 2 of 12 bytes]", result.Content)
+
+	result, err = executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, MaxResponseBytes: 12}, "{}")
+	require.NoError(t, err)
+	assert.Equal(t, "héllo world", result.Content)
+}
+
+func TestHTTPExecutor_RetryPolicy(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintf(w, "%s after %d attempts", r.Method, attempts)
+	}))
+	t.Cleanup(server.Close)
+	retryPolicy := &arkv1alpha1.HTTPRetryPolicy{MaxAttempts: 3, InitialBackoff: &metav1.Duration{Duration: time.Millisecond}}
+
+	result, err := executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, RetryPolicy: retryPolicy}, "{}")
+	require.NoError(t, err)
+	assert.Equal(t, "GET after 3 attempts", result.Content)
+
+	// POST requests are not retried, as they may have taken effect
+	attempts = 0
+	result, err = executeHTTPSpec(t, &arkv1alpha1.HTTPSpec{URL: server.URL, Method: "POST", RetryPolicy: retryPolicy}, "{}")
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "HTTP error 503")
+	assert.Equal(t, 1, attempts)
+}
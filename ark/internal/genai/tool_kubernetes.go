@@ -0,0 +1,615 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	// defaultKubernetesToolMaxResponseBytes is the size output is truncated to unless the tool sets one
+	defaultKubernetesToolMaxResponseBytes = 64 << 10
+	defaultKubernetesToolTailLines        = 100
+	kubernetesToolListLimit               = 100
+	kubernetesToolMaxEvents               = 20
+	kubernetesAllNamespaces               = "*"
+)
+
+// Kubernetes tool verbs
+const (
+	KubernetesVerbGet      = "get"
+	KubernetesVerbList     = "list"
+	KubernetesVerbDescribe = "describe"
+	KubernetesVerbLogs     = "logs"
+	KubernetesVerbPatch    = "patch"
+	KubernetesVerbScale    = "scale"
+)
+
+// defaultKubernetesVerbs are the verbs of a kubernetes tool that does not list any
+var defaultKubernetesVerbs = []string{KubernetesVerbGet, KubernetesVerbList, KubernetesVerbDescribe, KubernetesVerbLogs}
+
+// IsKubernetesWriteVerb reports whether the verb changes resources
+func IsKubernetesWriteVerb(verb string) bool {
+	return verb == KubernetesVerbPatch || verb == KubernetesVerbScale
+}
+
+// KubernetesToolAllowsWrites reports whether the tool annotations allow write verbs: the tool
+// must declare that it is destructive and not read-only, so approval policies for destructive
+// tools apply to it
+func KubernetesToolAllowsWrites(annotations *arkv1alpha1.ToolAnnotations) bool {
+	return annotations != nil && annotations.DestructiveHint && !annotations.ReadOnlyHint
+}
+
+// ImpersonatingClient is the client of a query that runs as a service account. Kubernetes tools
+// only run with such a client, so they never act with the permissions of the controller.
+type ImpersonatingClient interface {
+	client.Client
+	// GetPodLogs reads the logs of a pod container, which the controller-runtime client cannot
+	GetPodLogs(ctx context.Context, namespace, name string, options *corev1.PodLogOptions) (string, error)
+}
+
+type impersonatingClient struct {
+	client.Client
+	clientset kubernetes.Interface
+}
+
+// NewImpersonatingClient returns a client that acts as the service account with the given
+// namespace and name
+func NewImpersonatingClient(config *rest.Config, options client.Options, namespace, serviceAccount string) (ImpersonatingClient, error) {
+	config = rest.CopyConfig(config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+	}
+
+	k8sClient, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &impersonatingClient{Client: k8sClient, clientset: clientset}, nil
+}
+
+func (c *impersonatingClient) GetPodLogs(ctx context.Context, namespace, name string, options *corev1.PodLogOptions) (string, error) {
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, options).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = stream.Close() }()
+
+	logs, err := io.ReadAll(stream)
+	return string(logs), err
+}
+
+// KubernetesExecutor executes kubernetes tools with the client of the query
+type KubernetesExecutor struct {
+	K8sClient   client.Client
+	Spec        *arkv1alpha1.KubernetesToolSpec
+	Namespace   string
+	AllowWrites bool
+}
+
+type kubernetesToolArguments struct {
+	Verb          string          `json:"verb"`
+	Resource      string          `json:"resource"`
+	Name          string          `json:"name"`
+	Namespace     string          `json:"namespace"`
+	LabelSelector string          `json:"labelSelector"`
+	Container     string          `json:"container"`
+	TailLines     int64           `json:"tailLines"`
+	Previous      bool            `json:"previous"`
+	Patch         json.RawMessage `json:"patch"`
+	PatchType     string          `json:"patchType"`
+	Replicas      *int32          `json:"replicas"`
+}
+
+// Execute implements ToolExecutor interface for kubernetes tools
+func (k *KubernetesExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var arguments kubernetesToolArguments
+	if call.Function.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+			return ToolResult{
+				ID:    call.ID,
+				Name:  call.Function.Name,
+				Error: fmt.Sprintf("failed to parse arguments: %v", err),
+			}, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+	}
+
+	content, err := k.execute(ctx, arguments)
+	if err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: err.Error(),
+		}, err
+	}
+
+	maxResponseBytes := defaultKubernetesToolMaxResponseBytes
+	if k.Spec.MaxResponseBytes > 0 {
+		maxResponseBytes = k.Spec.MaxResponseBytes
+	}
+	if len(content) > maxResponseBytes {
+		content = truncateUTF8(content, maxResponseBytes) + fmt.Sprintf("\n[output truncated to %d of %d bytes]", maxResponseBytes, len(content))
+	}
+
+	return ToolResult{
+		ID:      call.ID,
+		Name:    call.Function.Name,
+		Content: content,
+	}, nil
+}
+
+func (k *KubernetesExecutor) execute(ctx context.Context, arguments kubernetesToolArguments) (string, error) {
+	queryClient, ok := k.K8sClient.(ImpersonatingClient)
+	if !ok {
+		return "", fmt.Errorf("kubernetes tools run with the permissions of the query's service account: set serviceAccount on the query")
+	}
+
+	verbs := kubernetesToolVerbs(k.Spec)
+	if !slices.Contains(verbs, arguments.Verb) {
+		return "", fmt.Errorf("verb %q is not allowed, use one of %s", arguments.Verb, strings.Join(verbs, ", "))
+	}
+	if IsKubernetesWriteVerb(arguments.Verb) && !k.AllowWrites {
+		return "", fmt.Errorf("verb %s changes resources, which this tool does not allow", arguments.Verb)
+	}
+
+	resource := arguments.Resource
+	if resource == "" && arguments.Verb == KubernetesVerbLogs {
+		resource = "pods"
+	}
+	if resource == "" {
+		return "", fmt.Errorf("resource is required")
+	}
+	mapping, err := k.resolveResource(queryClient.RESTMapper(), resource)
+	if err != nil {
+		return "", err
+	}
+	namespace, err := k.resolveNamespace(arguments.Namespace, arguments.Verb, mapping)
+	if err != nil {
+		return "", err
+	}
+	if arguments.Verb != KubernetesVerbList && arguments.Name == "" {
+		return "", fmt.Errorf("name is required for %s", arguments.Verb)
+	}
+
+	switch arguments.Verb {
+	case KubernetesVerbGet:
+		obj, err := k.get(ctx, queryClient, mapping, namespace, arguments.Name)
+		if err != nil {
+			return "", err
+		}
+		return formatKubernetesObject(obj)
+	case KubernetesVerbList:
+		return k.list(ctx, queryClient, mapping, namespace, arguments.LabelSelector)
+	case KubernetesVerbDescribe:
+		return k.describe(ctx, queryClient, mapping, namespace, arguments.Name)
+	case KubernetesVerbLogs:
+		if mapping.Resource.GroupResource() != (schema.GroupResource{Resource: "pods"}) {
+			return "", fmt.Errorf("logs are only available for pods")
+		}
+		return k.logs(ctx, queryClient, namespace, arguments)
+	case KubernetesVerbPatch:
+		return k.patch(ctx, queryClient, mapping, namespace, arguments)
+	case KubernetesVerbScale:
+		return k.scale(ctx, queryClient, mapping, namespace, arguments)
+	}
+	return "", fmt.Errorf("unsupported verb %s", arguments.Verb)
+}
+
+// resolveResource maps a resource name such as pods or deployments.apps to its kind, if the tool
+// allows it
+func (k *KubernetesExecutor) resolveResource(mapper meta.RESTMapper, resource string) (*meta.RESTMapping, error) {
+	gvk, err := kindForResource(mapper, resource)
+	if err != nil {
+		return nil, err
+	}
+	if len(k.Spec.Resources) > 0 && !slices.ContainsFunc(k.Spec.Resources, func(allowed string) bool {
+		allowedGVK, err := kindForResource(mapper, allowed)
+		return err == nil && allowedGVK.GroupKind() == gvk.GroupKind()
+	}) {
+		return nil, fmt.Errorf("resource %s is not allowed, use one of %s", resource, strings.Join(k.Spec.Resources, ", "))
+	}
+	return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+func kindForResource(mapper meta.RESTMapper, resource string) (schema.GroupVersionKind, error) {
+	groupResource := schema.ParseGroupResource(strings.ToLower(resource))
+	gvk, err := mapper.KindFor(groupResource.WithVersion(""))
+	if err != nil {
+		return gvk, fmt.Errorf("unknown resource %s: %w", resource, err)
+	}
+	return gvk, nil
+}
+
+// resolveNamespace returns the namespace of a call, or "" for cluster-scoped resources and lists
+// across all namespaces
+func (k *KubernetesExecutor) resolveNamespace(requested, verb string, mapping *meta.RESTMapping) (string, error) {
+	allowed := k.Spec.Namespaces
+	if len(allowed) == 0 {
+		allowed = []string{k.Namespace}
+	}
+	allNamespaces := slices.Contains(allowed, kubernetesAllNamespaces)
+
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		if !allNamespaces {
+			return "", fmt.Errorf("cluster-scoped resource %s is not allowed", mapping.Resource.Resource)
+		}
+		return "", nil
+	}
+
+	switch {
+	case requested == "":
+		if allNamespaces || slices.Contains(allowed, k.Namespace) {
+			return k.Namespace, nil
+		}
+		return allowed[0], nil
+	case requested == kubernetesAllNamespaces && verb == KubernetesVerbList && allNamespaces:
+		return "", nil
+	case requested != kubernetesAllNamespaces && (allNamespaces || slices.Contains(allowed, requested)):
+		return requested, nil
+	}
+	return "", fmt.Errorf("namespace %s is not allowed, use one of %s", requested, strings.Join(allowed, ", "))
+}
+
+func (k *KubernetesExecutor) get(ctx context.Context, k8sClient client.Client, mapping *meta.RESTMapping, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.GroupVersionKind)
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", mapping.Resource.Resource, name, err)
+	}
+	return obj, nil
+}
+
+// list summarizes the resources in a table with their status and age
+func (k *KubernetesExecutor) list(ctx context.Context, k8sClient client.Client, mapping *meta.RESTMapping, namespace, labelSelector string) (string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(mapping.GroupVersionKind.GroupVersion().WithKind(mapping.GroupVersionKind.Kind + "List"))
+	options := []client.ListOption{client.Limit(kubernetesToolListLimit)}
+	if namespace != "" {
+		options = append(options, client.InNamespace(namespace))
+	}
+	if labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			return "", fmt.Errorf("invalid labelSelector: %w", err)
+		}
+		options = append(options, client.MatchingLabelsSelector{Selector: selector})
+	}
+	if err := k8sClient.List(ctx, list, options...); err != nil {
+		return "", fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+	}
+	if len(list.Items) == 0 {
+		return fmt.Sprintf("No %s found.", mapping.Resource.Resource), nil
+	}
+
+	showNamespace := namespace == "" && mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	var output strings.Builder
+	writer := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		_, _ = fmt.Fprint(writer, "NAMESPACE\t")
+	}
+	_, _ = fmt.Fprintln(writer, "NAME\tSTATUS\tAGE")
+	for i := range list.Items {
+		item := &list.Items[i]
+		if showNamespace {
+			_, _ = fmt.Fprintf(writer, "%s\t", item.GetNamespace())
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", item.GetName(), summarizeKubernetesStatus(item), kubernetesAge(item.GetCreationTimestamp().Time))
+	}
+	_ = writer.Flush()
+
+	if list.GetContinue() != "" {
+		fmt.Fprintf(&output, "\nOnly the first %d %s are shown. Narrow the list with a labelSelector.", kubernetesToolListLimit, mapping.Resource.Resource)
+	}
+	return output.String(), nil
+}
+
+// describe returns the resource with its recent events
+func (k *KubernetesExecutor) describe(ctx context.Context, k8sClient client.Client, mapping *meta.RESTMapping, namespace, name string) (string, error) {
+	obj, err := k.get(ctx, k8sClient, mapping, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	content, err := formatKubernetesObject(obj)
+	if err != nil {
+		return "", err
+	}
+
+	var events corev1.EventList
+	options := []client.ListOption{client.MatchingFields{"involvedObject.name": name}}
+	if namespace != "" {
+		options = append(options, client.InNamespace(namespace))
+	}
+	if err := k8sClient.List(ctx, &events, options...); err != nil {
+		return content + fmt.Sprintf("\nEvents: unavailable: %v\n", err), nil
+	}
+	objectEvents := slices.DeleteFunc(events.Items, func(event corev1.Event) bool {
+		return event.InvolvedObject.Kind != obj.GetKind()
+	})
+	if len(objectEvents) == 0 {
+		return content + "\nEvents: <none>\n", nil
+	}
+	sort.SliceStable(objectEvents, func(i, j int) bool {
+		return kubernetesEventTime(objectEvents[i]).Before(kubernetesEventTime(objectEvents[j]))
+	})
+	if len(objectEvents) > kubernetesToolMaxEvents {
+		objectEvents = objectEvents[len(objectEvents)-kubernetesToolMaxEvents:]
+	}
+
+	var output strings.Builder
+	output.WriteString(content)
+	output.WriteString("\nEvents:\n")
+	writer := tabwriter.NewWriter(&output, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "TYPE\tREASON\tAGE\tMESSAGE")
+	for _, event := range objectEvents {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", event.Type, event.Reason, kubernetesAge(kubernetesEventTime(event)), strings.TrimSpace(event.Message))
+	}
+	_ = writer.Flush()
+	return output.String(), nil
+}
+
+func (k *KubernetesExecutor) logs(ctx context.Context, queryClient ImpersonatingClient, namespace string, arguments kubernetesToolArguments) (string, error) {
+	tailLines := int64(defaultKubernetesToolTailLines)
+	if arguments.TailLines > 0 {
+		tailLines = arguments.TailLines
+	}
+	limitBytes := int64(defaultKubernetesToolMaxResponseBytes)
+	if k.Spec.MaxResponseBytes > 0 {
+		limitBytes = int64(k.Spec.MaxResponseBytes)
+	}
+	options := &corev1.PodLogOptions{
+		Container:  arguments.Container,
+		Previous:   arguments.Previous,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}
+
+	logs, err := queryClient.GetPodLogs(ctx, namespace, arguments.Name, options)
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs of pod %s: %w", arguments.Name, err)
+	}
+	if logs == "" {
+		return "The container has not written any logs.", nil
+	}
+	return logs, nil
+}
+
+func (k *KubernetesExecutor) patch(ctx context.Context, k8sClient client.Client, mapping *meta.RESTMapping, namespace string, arguments kubernetesToolArguments) (string, error) {
+	if len(arguments.Patch) == 0 || !json.Valid(arguments.Patch) {
+		return "", fmt.Errorf("patch is required and must be a JSON object")
+	}
+	var patchType types.PatchType
+	switch arguments.PatchType {
+	case "", "merge":
+		patchType = types.MergePatchType
+	case "strategic":
+		patchType = types.StrategicMergePatchType
+	default:
+		return "", fmt.Errorf("invalid patchType %q, use merge or strategic", arguments.PatchType)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.GroupVersionKind)
+	obj.SetNamespace(namespace)
+	obj.SetName(arguments.Name)
+	if err := k8sClient.Patch(ctx, obj, client.RawPatch(patchType, arguments.Patch)); err != nil {
+		return "", fmt.Errorf("failed to patch %s %s: %w", mapping.Resource.Resource, arguments.Name, err)
+	}
+
+	content, err := formatKubernetesObject(obj)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Patched %s %s.\n\n%s", mapping.Resource.Resource, arguments.Name, content), nil
+}
+
+// scale sets the replicas of the resource through its scale subresource
+func (k *KubernetesExecutor) scale(ctx context.Context, k8sClient client.Client, mapping *meta.RESTMapping, namespace string, arguments kubernetesToolArguments) (string, error) {
+	if arguments.Replicas == nil || *arguments.Replicas < 0 {
+		return "", fmt.Errorf("replicas is required and must not be negative")
+	}
+
+	// Typed objects are used when the scheme knows the kind, as the scale subresource of some
+	// clients only supports them
+	var obj client.Object
+	if typed, err := k8sClient.Scheme().New(mapping.GroupVersionKind); err == nil {
+		obj, _ = typed.(client.Object)
+	}
+	if obj == nil {
+		unstructuredObj := &unstructured.Unstructured{}
+		unstructuredObj.SetGroupVersionKind(mapping.GroupVersionKind)
+		obj = unstructuredObj
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: arguments.Name}, obj); err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", mapping.Resource.Resource, arguments.Name, err)
+	}
+
+	scale := &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: *arguments.Replicas},
+	}
+	if err := k8sClient.SubResource("scale").Update(ctx, obj, client.WithSubResourceBody(scale)); err != nil {
+		return "", fmt.Errorf("failed to scale %s %s: %w", mapping.Resource.Resource, arguments.Name, err)
+	}
+	return fmt.Sprintf("Scaled %s %s to %d replicas.", mapping.Resource.Resource, arguments.Name, *arguments.Replicas), nil
+}
+
+func kubernetesToolVerbs(spec *arkv1alpha1.KubernetesToolSpec) []string {
+	if len(spec.Verbs) > 0 {
+		return spec.Verbs
+	}
+	return defaultKubernetesVerbs
+}
+
+// formatKubernetesObject renders the object as YAML without the fields that only add noise for
+// the model, and with the values of secrets redacted
+func formatKubernetesObject(obj *unstructured.Unstructured) (string, error) {
+	trimmed := obj.DeepCopy()
+	unstructured.RemoveNestedField(trimmed.Object, "metadata", "managedFields")
+	if annotations := trimmed.GetAnnotations(); annotations != nil {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(trimmed.Object, "metadata", "annotations")
+		} else {
+			trimmed.SetAnnotations(annotations)
+		}
+	}
+	if trimmed.GroupVersionKind().GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+		for _, field := range []string{"data", "stringData"} {
+			values, found, _ := unstructured.NestedMap(trimmed.Object, field)
+			if !found {
+				continue
+			}
+			for key := range values {
+				values[key] = "<redacted>"
+			}
+			_ = unstructured.SetNestedMap(trimmed.Object, values, field)
+		}
+	}
+
+	content, err := yaml.Marshal(trimmed.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	return string(content), nil
+}
+
+// summarizeKubernetesStatus returns a short status such as a pod's phase, ready containers and
+// restarts, or the ready replicas of a workload
+func summarizeKubernetesStatus(obj *unstructured.Unstructured) string {
+	if obj.GetKind() == "Pod" {
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+		ready, restarts := 0, int64(0)
+		for _, status := range statuses {
+			containerStatus, ok := status.(map[string]any)
+			if !ok {
+				continue
+			}
+			if isReady, _, _ := unstructured.NestedBool(containerStatus, "ready"); isReady {
+				ready++
+			}
+			count, _, _ := unstructured.NestedInt64(containerStatus, "restartCount")
+			restarts += count
+			if reason, _, _ := unstructured.NestedString(containerStatus, "state", "waiting", "reason"); reason != "" {
+				phase = reason
+			}
+		}
+		return fmt.Sprintf("%s, %d/%d ready, %d restarts", phase, ready, len(statuses), restarts)
+	}
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return fmt.Sprintf("%d/%d ready", ready, replicas)
+	}
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		return phase
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if conditionType := fields["type"]; conditionType == "Ready" || conditionType == "Available" {
+			return fmt.Sprintf("%s=%v", conditionType, fields["status"])
+		}
+	}
+	return "-"
+}
+
+func kubernetesAge(timestamp time.Time) string {
+	if timestamp.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(timestamp))
+}
+
+func kubernetesEventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// kubernetesToolParameters describes the arguments of a kubernetes tool that has no inputSchema,
+// limited to the verbs, resources and namespaces it allows
+func kubernetesToolParameters(spec *arkv1alpha1.KubernetesToolSpec, namespace string) map[string]any {
+	if spec == nil {
+		spec = &arkv1alpha1.KubernetesToolSpec{}
+	}
+	verbs := kubernetesToolVerbs(spec)
+
+	resource := map[string]any{
+		"type":        "string",
+		"description": "Plural resource name such as pods, or with its API group such as deployments.apps",
+	}
+	if len(spec.Resources) > 0 {
+		resource["enum"] = spec.Resources
+	}
+	namespaceDescription := fmt.Sprintf("Namespace of the resource (default %s)", namespace)
+	if slices.Contains(spec.Namespaces, kubernetesAllNamespaces) {
+		namespaceDescription += ". Use * to list resources in all namespaces"
+	} else if len(spec.Namespaces) > 0 {
+		namespaceDescription = fmt.Sprintf("Namespace of the resource, one of %s", strings.Join(spec.Namespaces, ", "))
+	}
+
+	properties := map[string]any{
+		"verb": map[string]any{
+			"type":        "string",
+			"enum":        verbs,
+			"description": "Operation to perform. describe returns the resource with its recent events",
+		},
+		"resource":  resource,
+		"name":      map[string]any{"type": "string", "description": "Name of the resource, required except for list"},
+		"namespace": map[string]any{"type": "string", "description": namespaceDescription},
+	}
+	if slices.Contains(verbs, KubernetesVerbList) {
+		properties["labelSelector"] = map[string]any{"type": "string", "description": "Label selector for list, such as app=web,tier!=cache"}
+	}
+	if slices.Contains(verbs, KubernetesVerbLogs) {
+		properties["container"] = map[string]any{"type": "string", "description": "Container for logs, required for pods with several containers"}
+		properties["tailLines"] = map[string]any{"type": "integer", "description": fmt.Sprintf("Number of log lines from the end (default %d)", defaultKubernetesToolTailLines)}
+		properties["previous"] = map[string]any{"type": "boolean", "description": "Return the logs of the previous, terminated container"}
+	}
+	if slices.Contains(verbs, KubernetesVerbPatch) {
+		properties["patch"] = map[string]any{"type": "object", "description": "Merge patch for patch, such as {\"spec\": {\"paused\": true}}"}
+		properties["patchType"] = map[string]any{"type": "string", "enum": []string{"merge", "strategic"}, "description": "Patch type for patch (default merge)"}
+	}
+	if slices.Contains(verbs, KubernetesVerbScale) {
+		properties["replicas"] = map[string]any{"type": "integer", "minimum": 0, "description": "Replicas for scale"}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"verb"},
+	}
+}
@@ -0,0 +1,214 @@
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type fakeImpersonatingClient struct {
+	client.Client
+	logs       string
+	logOptions *corev1.PodLogOptions
+}
+
+func (c *fakeImpersonatingClient) GetPodLogs(_ context.Context, _, _ string, options *corev1.PodLogOptions) (string, error) {
+	c.logOptions = options
+	return c.logs, nil
+}
+
+func setupKubernetesToolClient(t *testing.T) *fakeImpersonatingClient {
+	replicas := int32(2)
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Event"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Node"), meta.RESTScopeRoot)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+
+	objects := []client.Object{
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web"},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{}}`)}}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "web", RestartCount: 4, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				},
+			},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "shop", Labels: map[string]string{"app": "db"}}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-password", Namespace: "shop"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Deployment", Name: "web"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "ScalingReplicaSet",
+			Message:        "Scaled up replica set web-5d4 to 2",
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "web-1.1", Namespace: "shop"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+			Reason:         "BackOff",
+		},
+	}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(mapper).
+		WithObjects(objects...).
+		WithIndex(&corev1.Event{}, "involvedObject.name", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Event).InvolvedObject.Name}
+		}).
+		Build()
+	return &fakeImpersonatingClient{Client: k8sClient, logs: "listening on :8080\n"}
+}
+
+func executeKubernetesTool(t *testing.T, executor *KubernetesExecutor, arguments string) (string, error) {
+	result, err := executor.Execute(context.Background(), ToolCall{ID: "call_1", Function: openai.ChatCompletionMessageToolCallFunction{Name: "cluster", Arguments: arguments}})
+	if err != nil {
+		assert.Equal(t, err.Error(), result.Error)
+	}
+	return result.Content, err
+}
+
+func TestKubernetesExecutor_ReadVerbs(t *testing.T) {
+	k8sClient := setupKubernetesToolClient(t)
+	executor := &KubernetesExecutor{K8sClient: k8sClient, Spec: &arkv1alpha1.KubernetesToolSpec{}, Namespace: "shop"}
+
+	content, err := executeKubernetesTool(t, executor, `{"verb": "get", "resource": "pods", "name": "web-1"}`)
+	require.NoError(t, err)
+	assert.Contains(t, content, "name: web-1")
+	assert.NotContains(t, content, "managedFields")
+
+	content, err = executeKubernetesTool(t, executor, `{"verb": "get", "resource": "secrets", "name": "db-password"}`)
+	require.NoError(t, err)
+	assert.Contains(t, content, "password: <redacted>")
+
+	content, err = executeKubernetesTool(t, executor, `{"verb": "list", "resource": "pods", "labelSelector": "app=web"}`)
+	require.NoError(t, err)
+	assert.Contains(t, content, "NAME")
+	assert.Contains(t, content, "CrashLoopBackOff, 0/1 ready, 4 restarts")
+	assert.NotContains(t, content, "db-1")
+
+	content, err = executeKubernetesTool(t, executor, `{"verb": "describe", "resource": "deployments.apps", "name": "web"}`)
+	require.NoError(t, err)
+	assert.Contains(t, content, "replicas: 2")
+	assert.Contains(t, content, "Scaled up replica set web-5d4 to 2")
+	assert.NotContains(t, content, "BackOff")
+
+	content, err = executeKubernetesTool(t, executor, `{"verb": "logs", "name": "web-1", "container": "web"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "listening on :8080\n", content)
+	assert.Equal(t, "web", k8sClient.logOptions.Container)
+	assert.Equal(t, int64(defaultKubernetesToolTailLines), *k8sClient.logOptions.TailLines)
+}
+
+func TestKubernetesExecutor_Restrictions(t *testing.T) {
+	k8sClient := setupKubernetesToolClient(t)
+	executor := &KubernetesExecutor{
+		K8sClient: k8sClient,
+		Spec:      &arkv1alpha1.KubernetesToolSpec{Resources: []string{"pods"}, Namespaces: []string{"shop"}},
+		Namespace: "default",
+	}
+
+	_, err := executeKubernetesTool(t, executor, `{"verb": "list", "resource": "pods"}`)
+	require.NoError(t, err)
+
+	_, err = executeKubernetesTool(t, executor, `{"verb": "get", "resource": "secrets", "name": "db-password"}`)
+	assert.ErrorContains(t, err, "resource secrets is not allowed")
+
+	_, err = executeKubernetesTool(t, executor, `{"verb": "list", "resource": "pods", "namespace": "kube-system"}`)
+	assert.ErrorContains(t, err, "namespace kube-system is not allowed")
+
+	executor.Spec.Resources = nil
+	_, err = executeKubernetesTool(t, executor, `{"verb": "list", "resource": "nodes"}`)
+	assert.ErrorContains(t, err, "cluster-scoped resource nodes is not allowed")
+
+	_, err = executeKubernetesTool(t, executor, `{"verb": "scale", "resource": "deployments", "name": "web", "replicas": 3}`)
+	assert.ErrorContains(t, err, `verb "scale" is not allowed`)
+
+	_, err = executeKubernetesTool(t, &KubernetesExecutor{K8sClient: k8sClient.Client, Spec: executor.Spec}, `{"verb": "list", "resource": "pods"}`)
+	assert.ErrorContains(t, err, "set serviceAccount on the query")
+}
+
+func TestKubernetesExecutor_WriteVerbs(t *testing.T) {
+	k8sClient := setupKubernetesToolClient(t)
+	executor := &KubernetesExecutor{
+		K8sClient: k8sClient,
+		Spec:      &arkv1alpha1.KubernetesToolSpec{Verbs: []string{"get", "patch", "scale"}},
+		Namespace: "shop",
+	}
+
+	_, err := executeKubernetesTool(t, executor, `{"verb": "scale", "resource": "deployments", "name": "web", "replicas": 3}`)
+	assert.ErrorContains(t, err, "verb scale changes resources, which this tool does not allow")
+
+	executor.AllowWrites = true
+	content, err := executeKubernetesTool(t, executor, `{"verb": "scale", "resource": "deployments", "name": "web", "replicas": 3}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Scaled deployments web to 3 replicas.", content)
+
+	content, err = executeKubernetesTool(t, executor, `{"verb": "patch", "resource": "deployments", "name": "web", "patch": {"spec": {"paused": true}}}`)
+	require.NoError(t, err)
+	assert.Contains(t, content, "paused: true")
+
+	var deployment appsv1.Deployment
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "shop", Name: "web"}, &deployment))
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	assert.True(t, deployment.Spec.Paused)
+}
+
+func TestKubernetesExecutor_TruncatesOutput(t *testing.T) {
+	k8sClient := setupKubernetesToolClient(t)
+	executor := &KubernetesExecutor{K8sClient: k8sClient, Spec: &arkv1alpha1.KubernetesToolSpec{MaxResponseBytes: 10}, Namespace: "shop"}
+
+	content, err := executeKubernetesTool(t, executor, `{"verb": "logs", "name": "web-1"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "listening \n[output truncated to 10 of 19 bytes]", content)
+	assert.Equal(t, int64(10), *k8sClient.logOptions.LimitBytes)
+}
+
+func TestKubernetesToolDefinition(t *testing.T) {
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "shop"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type:       ToolTypeKubernetes,
+			Kubernetes: &arkv1alpha1.KubernetesToolSpec{Verbs: []string{"get", "scale"}, Resources: []string{"deployments.apps"}},
+		},
+	}
+
+	definition := CreateToolFromCRD(tool)
+	assert.Equal(t, "Kubernetes cluster access to get, scale resources", definition.Description)
+	properties := definition.Parameters["properties"].(map[string]any)
+	assert.Equal(t, []string{"get", "scale"}, properties["verb"].(map[string]any)["enum"])
+	assert.Equal(t, []string{"deployments.apps"}, properties["resource"].(map[string]any)["enum"])
+	assert.Contains(t, properties, "replicas")
+	assert.NotContains(t, properties, "patch")
+	assert.NotContains(t, properties, "tailLines")
+	assert.Equal(t, []string{"verb"}, definition.Parameters["required"])
+}
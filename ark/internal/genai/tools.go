@@ -1,14 +1,17 @@
 package genai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +23,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
 	"mckinsey.com/ark/internal/eventing"
 	"mckinsey.com/ark/internal/telemetry"
 )
@@ -95,32 +99,28 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult,
 		method = "GET"
 	}
 
-	// Handle request body for POST/PUT/PATCH requests
-	var requestBody io.Reader
-	if httpSpec.Body != "" && (method == "POST" || method == "PUT" || method == "PATCH") {
-		bodyContent, err := ResolveBodyTemplate(ctx, h.K8sClient, tool.Namespace, httpSpec.Body, httpSpec.BodyParameters, arguments)
+	// Resolve the query parameters and the body, which share the data of body templates
+	var body *httpToolBody
+	if needsTemplateData(httpSpec, method) {
+		data, err := resolveBodyTemplateData(ctx, h.K8sClient, tool.Namespace, httpSpec.BodyParameters, arguments)
+		if err == nil {
+			err = addHTTPToolQueryParameters(parsedURL, httpSpec.QueryParameters, data)
+		}
+		if err == nil && hasHTTPToolBody(method) {
+			body, err = buildHTTPToolBody(httpSpec, data)
+		}
 		if err != nil {
-			log.Error(err, "failed to resolve body template", "template", httpSpec.Body)
+			log.Error(err, "failed to resolve request templates")
 			return ToolResult{
 				ID:    call.ID,
 				Name:  call.Function.Name,
-				Error: fmt.Sprintf("failed to resolve body template: %v", err),
-			}, fmt.Errorf("failed to resolve body template: %w", err)
+				Error: fmt.Sprintf("failed to resolve request templates: %v", err),
+			}, fmt.Errorf("failed to resolve request templates: %w", err)
 		}
-		requestBody = strings.NewReader(bodyContent)
-	}
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, method, parsedURL.String(), requestBody)
-	if err != nil {
-		return ToolResult{
-			ID:    call.ID,
-			Name:  call.Function.Name,
-			Error: fmt.Sprintf("failed to create request: %v", err),
-		}, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add headers
+	// Resolve headers
+	headers := http.Header{}
 	for _, header := range httpSpec.Headers {
 		value, err := h.resolveHeaderValue(ctx, header.Value, tool.Namespace)
 		if err != nil {
@@ -130,17 +130,61 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult,
 				Error: fmt.Sprintf("failed to resolve header %s: %v", header.Name, err),
 			}, fmt.Errorf("failed to resolve header %s: %w", header.Name, err)
 		}
-		req.Header.Set(header.Name, value)
+		headers.Set(header.Name, value)
 	}
 
 	// Set timeout
 	timeout := h.getTimeout(httpSpec.Timeout)
 	httpClient := &http.Client{Timeout: timeout}
 
+	auth, err := loadHTTPToolAuth(ctx, common.NewValueSourceResolver(h.K8sClient), tool.Namespace, tool.Name, httpSpec.Auth)
+	if err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: fmt.Sprintf("failed to authenticate request: %v", err),
+		}, fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	if auth != nil {
+		httpClient = auth.httpClient(timeout)
+	}
+
+	// Each attempt sends a new request, with a fresh token if the previous one expired
+	newRequest := func() (*http.Request, error) {
+		var requestBody io.Reader
+		if body != nil {
+			requestBody = bytes.NewReader(body.content)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, parsedURL.String(), requestBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header = headers.Clone()
+		// Form bodies set their content type, which must match their encoding
+		if body != nil && body.contentType != "" {
+			req.Header.Set("Content-Type", body.contentType)
+		}
+		// Credentials take precedence over the headers
+		if auth != nil {
+			if err := auth.authorize(req); err != nil {
+				return nil, fmt.Errorf("failed to authenticate request: %w", err)
+			}
+		}
+		return req, nil
+	}
+
 	// Make the request
-	log.Info("making HTTP request", "method", method, "url", parsedURL.String())
-	resp, err := httpClient.Do(req)
+	// The query string may carry credentials, such as API keys, so it is neither logged nor
+	// reported in errors
+	displayURL := *parsedURL
+	displayURL.RawQuery, displayURL.Fragment = "", ""
+	log.Info("making HTTP request", "method", method, "url", displayURL.Redacted())
+	resp, err := doWithRetry(ctx, httpClient, httpToolRetryPolicy(httpSpec.RetryPolicy, method), newRequest)
 	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = displayURL.Redacted()
+		}
 		return ToolResult{
 			ID:    call.ID,
 			Name:  call.Function.Name,
@@ -151,31 +195,35 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult,
 		_ = resp.Body.Close()
 	}()
 
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
+	// Read response body
+	content, err := readHTTPToolResponse(resp, httpSpec.ResponseFormat, httpSpec.MaxResponseBytes)
+	if err != nil {
 		return ToolResult{
 			ID:    call.ID,
 			Name:  call.Function.Name,
-			Error: fmt.Sprintf("HTTP error %d: %s (URL: %s)", resp.StatusCode, resp.Status, parsedURL.String()),
-		}, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+			Error: fmt.Sprintf("failed to read response: %v", err),
+		}, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	// Check for HTTP errors, passing the response so the model can see what went wrong
+	if !isHTTPToolSuccess(resp.StatusCode, httpSpec.SuccessStatusCodes) {
+		message := fmt.Sprintf("HTTP error %d: %s (URL: %s)", resp.StatusCode, resp.Status, displayURL.Redacted())
+		if content != "" {
+			message += "\n" + content
+		}
 		return ToolResult{
 			ID:    call.ID,
 			Name:  call.Function.Name,
-			Error: fmt.Sprintf("failed to read response: %v", err),
-		}, fmt.Errorf("failed to read response: %w", err)
+			Error: message,
+		}, &httpToolStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
-	log.Info("HTTP request completed", "status", resp.StatusCode, "responseSize", len(body))
+	log.Info("HTTP request completed", "status", resp.StatusCode, "responseSize", len(content))
 
 	return ToolResult{
 		ID:      call.ID,
 		Name:    call.Function.Name,
-		Content: string(body),
+		Content: content,
 	}, nil
 }
 
@@ -186,6 +234,10 @@ type ToolRegistry struct {
 	mcpSettings       map[string]MCPSettings // MCP settings per MCP server (namespace/name)
 	telemetryRecorder telemetry.ToolRecorder
 	eventingRecorder  eventing.ToolRecorder
+	errorPolicy       ToolErrorPolicy            // Default policy for tools without their own
+	toolErrorPolicies map[string]ToolErrorPolicy // Per-tool overrides keyed by exposed tool name
+	approvalPolicy    *arkv1alpha1.ToolApprovalPolicy
+	approvalRequired  map[string]bool // Tools whose calls must be approved, keyed by exposed tool name
 }
 
 func NewToolRegistry(mcpSettings map[string]MCPSettings, telemetryRecorder telemetry.ToolRecorder, eventingRecorder eventing.ToolRecorder) *ToolRegistry {
@@ -196,9 +248,39 @@ func NewToolRegistry(mcpSettings map[string]MCPSettings, telemetryRecorder telem
 		mcpSettings:       mcpSettings,
 		telemetryRecorder: telemetryRecorder,
 		eventingRecorder:  eventingRecorder,
+		errorPolicy:       ToolErrorPolicy{Action: ToolErrorPolicyFail},
+		toolErrorPolicies: make(map[string]ToolErrorPolicy),
+		approvalRequired:  make(map[string]bool),
 	}
 }
 
+// SetApprovalPolicy sets the policy deciding which tools registered afterwards require approval
+func (tr *ToolRegistry) SetApprovalPolicy(policy *arkv1alpha1.ToolApprovalPolicy) {
+	tr.approvalPolicy = policy
+}
+
+// RequiresApproval reports whether calls to the tool must be approved before execution
+func (tr *ToolRegistry) RequiresApproval(toolName string) bool {
+	return tr.approvalRequired[toolName]
+}
+
+// SetErrorPolicy sets the policy applied to tools without their own error policy
+func (tr *ToolRegistry) SetErrorPolicy(policy ToolErrorPolicy) {
+	tr.errorPolicy = policy
+}
+
+// SetToolErrorPolicy overrides the error policy for a single tool
+func (tr *ToolRegistry) SetToolErrorPolicy(toolName string, policy ToolErrorPolicy) {
+	tr.toolErrorPolicies[toolName] = policy
+}
+
+func (tr *ToolRegistry) getErrorPolicy(toolName string) ToolErrorPolicy {
+	if policy, exists := tr.toolErrorPolicies[toolName]; exists {
+		return policy
+	}
+	return tr.errorPolicy
+}
+
 func (tr *ToolRegistry) RegisterTool(def ToolDefinition, executor ToolExecutor) {
 	tr.tools[def.Name] = def
 	tr.executors[def.Name] = executor
@@ -235,39 +317,53 @@ func (tr *ToolRegistry) GetToolType(toolName string) string {
 }
 
 func (tr *ToolRegistry) ExecuteTool(ctx context.Context, call ToolCall) (ToolResult, error) {
-	executor, exists := tr.executors[call.Function.Name]
-	if !exists {
-		return ToolResult{
-			ID:    call.ID,
-			Name:  call.Function.Name,
-			Error: fmt.Sprintf("tool %s not found", call.Function.Name),
-		}, fmt.Errorf("tool %s not found", call.Function.Name)
-	}
+	policy := tr.getErrorPolicy(call.Function.Name)
 
 	toolType := tr.GetToolType(call.Function.Name)
 	ctx, span := tr.telemetryRecorder.StartToolExecution(ctx, call.Function.Name, toolType, call.ID, call.Function.Arguments)
 	defer span.End()
 
 	operationData := map[string]string{
-		"toolName":   call.Function.Name,
-		"toolType":   toolType,
-		"toolId":     call.ID,
-		"parameters": call.Function.Arguments,
+		"toolName":    call.Function.Name,
+		"toolType":    toolType,
+		"toolId":      call.ID,
+		"parameters":  call.Function.Arguments,
+		"errorPolicy": policy.String(),
 	}
 	ctx = tr.eventingRecorder.Start(ctx, "ToolCall", fmt.Sprintf("Executing tool %s", call.Function.Name), operationData)
 
-	result, err := executor.Execute(ctx, call)
+	var result ToolResult
+	var attempts int
+	var err error
+	if executor, exists := tr.executors[call.Function.Name]; exists {
+		result, attempts, err = executeWithPolicy(ctx, executor, call, policy)
+	} else {
+		err = fmt.Errorf("tool %s not found", call.Function.Name)
+		result = ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: err.Error(),
+		}
+	}
+	operationData["attempts"] = strconv.Itoa(attempts)
 	if err != nil {
 		tr.telemetryRecorder.RecordError(span, err)
 		if IsTerminateTeam(err) {
 			operationData["terminationMessage"] = "TerminateTeam"
 			tr.eventingRecorder.Complete(ctx, "ToolCall", "Tool execution completed with termination", operationData)
-		} else {
-			tr.eventingRecorder.Fail(ctx, "ToolCall", fmt.Sprintf("Tool execution failed: %v", err), err, operationData)
+			return result, err
 		}
+		if policy.Action == ToolErrorPolicyReport && ctx.Err() == nil {
+			operationData["outcome"] = ToolOutcomeReported
+			tr.eventingRecorder.Complete(ctx, "ToolCall", fmt.Sprintf("Tool execution failed, error reported to model: %v", err), operationData)
+			return reportedToolResult(call, err), nil
+		}
+		operationData["outcome"] = ToolOutcomeFailed
+		tr.eventingRecorder.Fail(ctx, "ToolCall", fmt.Sprintf("Tool execution failed: %v", err), err, operationData)
 		return result, err
 	}
 
+	operationData["outcome"] = ToolOutcomeSucceeded
 	tr.telemetryRecorder.RecordToolResult(span, result.Content)
 	tr.telemetryRecorder.RecordSuccess(span)
 	tr.eventingRecorder.Complete(ctx, "ToolCall", "Tool execution completed successfully", operationData)
@@ -530,6 +626,16 @@ func getDefaultToolDescription(toolCRD *arkv1alpha1.Tool) string {
 	case ToolTypeBuiltin:
 		// For builtin tools, use the description from the CRD itself
 		return fmt.Sprintf("Built-in tool: %s", toolCRD.Name)
+	case ToolTypeKubernetes:
+		verbs := kubernetesToolVerbs(&arkv1alpha1.KubernetesToolSpec{})
+		if toolCRD.Spec.Kubernetes != nil {
+			verbs = kubernetesToolVerbs(toolCRD.Spec.Kubernetes)
+		}
+		return fmt.Sprintf("Kubernetes cluster access to %s resources", strings.Join(verbs, ", "))
+	case ToolTypeGRPC:
+		if toolCRD.Spec.GRPC != nil {
+			return fmt.Sprintf("gRPC call to %s/%s", toolCRD.Spec.GRPC.Service, toolCRD.Spec.GRPC.Method)
+		}
 	default:
 		return fmt.Sprintf("Custom tool: %s", toolCRD.Name)
 	}
@@ -537,13 +643,23 @@ func getDefaultToolDescription(toolCRD *arkv1alpha1.Tool) string {
 }
 
 func getToolParameters(toolCRD *arkv1alpha1.Tool) map[string]any {
+	if toolCRD.Spec.Type == ToolTypeKubernetes && (toolCRD.Spec.InputSchema == nil || len(toolCRD.Spec.InputSchema.Raw) == 0) {
+		return kubernetesToolParameters(toolCRD.Spec.Kubernetes, toolCRD.Namespace)
+	}
+
 	parameters := map[string]any{
 		"type":       "object",
 		"properties": map[string]any{},
 	}
 
-	if toolCRD.Spec.InputSchema != nil && len(toolCRD.Spec.InputSchema.Raw) > 0 {
-		if err := json.Unmarshal(toolCRD.Spec.InputSchema.Raw, &parameters); err != nil {
+	inputSchema := toolCRD.Spec.InputSchema
+	// gRPC tools without an input schema use the one the controller derived from the method
+	if toolCRD.Spec.Type == ToolTypeGRPC && (inputSchema == nil || len(inputSchema.Raw) == 0) {
+		inputSchema = toolCRD.Status.InputSchema
+	}
+
+	if inputSchema != nil && len(inputSchema.Raw) > 0 {
+		if err := json.Unmarshal(inputSchema.Raw, &parameters); err != nil {
 			logf.Log.Error(err, "failed to unmarshal tool input schema")
 		}
 	}
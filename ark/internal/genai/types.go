@@ -1,8 +1,10 @@
 package genai
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/openai/openai-go"
 )
@@ -62,3 +64,68 @@ func IsTerminateTeam(err error) bool {
 	var terminateErr *TerminateTeam
 	return errors.As(err, &terminateErr)
 }
+
+// MaxIterationsReached is returned when an agent exhausts its iteration budget
+// while the model is still requesting tool calls.
+type MaxIterationsReached struct {
+	Agent         string
+	MaxIterations int
+}
+
+func (e *MaxIterationsReached) Error() string {
+	return fmt.Sprintf("agent %s reached the maximum of %d iterations", e.Agent, e.MaxIterations)
+}
+
+func IsMaxIterationsReached(err error) bool {
+	if err == nil {
+		return false
+	}
+	var maxIterationsErr *MaxIterationsReached
+	return errors.As(err, &maxIterationsErr)
+}
+
+// OutputSchemaInvalid is returned when an agent's final response still does not match its
+// outputSchema after the configured repair attempts
+type OutputSchemaInvalid struct {
+	Agent    string
+	Attempts int
+	Reason   string
+}
+
+func (e *OutputSchemaInvalid) Error() string {
+	return fmt.Sprintf("agent %s response does not match its outputSchema after %d repair attempts: %s", e.Agent, e.Attempts, e.Reason)
+}
+
+func IsOutputSchemaInvalid(err error) bool {
+	if err == nil {
+		return false
+	}
+	var schemaErr *OutputSchemaInvalid
+	return errors.As(err, &schemaErr)
+}
+
+// GuardrailViolation records a guardrail that triggered on input or output content
+type GuardrailViolation struct {
+	Guardrail string
+	Stage     string
+	Action    string
+	Reason    string
+}
+
+// GuardrailBlocked is returned when a guardrail with the block action triggers
+type GuardrailBlocked struct {
+	Violation GuardrailViolation
+	Message   string
+}
+
+func (e *GuardrailBlocked) Error() string {
+	return fmt.Sprintf("guardrail %s blocked %s: %s", e.Violation.Guardrail, e.Violation.Stage, cmp.Or(e.Message, e.Violation.Reason))
+}
+
+func IsGuardrailBlocked(err error) bool {
+	if err == nil {
+		return false
+	}
+	var blockedErr *GuardrailBlocked
+	return errors.As(err, &blockedErr)
+}
@@ -3,6 +3,7 @@
 package labels
 
 const (
-	MCPServerLabel = "mcp/server"
-	A2AServerLabel = "a2a/server"
+	MCPServerLabel      = "mcp/server"
+	A2AServerLabel      = "a2a/server"
+	OpenAPIToolSetLabel = "openapi/toolset"
 )
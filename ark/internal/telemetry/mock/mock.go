@@ -243,6 +243,10 @@ func (r *MockQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens,
 	)
 }
 
+func (r *MockQueryRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *MockQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
@@ -315,6 +319,10 @@ func (r *MockAgentRecorder) RecordTokenUsage(span telemetry.Span, promptTokens,
 	)
 }
 
+func (r *MockAgentRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *MockAgentRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
@@ -369,6 +377,10 @@ func (r *MockTeamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, c
 	)
 }
 
+func (r *MockTeamRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *MockTeamRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
@@ -4,6 +4,7 @@ package noop
 
 import (
 	"context"
+	"time"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/telemetry"
@@ -55,6 +56,8 @@ func (r *noopQueryRecorder) RecordRootOutput(span telemetry.Span, content string
 func (r *noopQueryRecorder) RecordInput(span telemetry.Span, content string)      {} //nolint:revive
 func (r *noopQueryRecorder) RecordOutput(span telemetry.Span, content string)     {} //nolint:revive
 func (r *noopQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
+} //nolint:revive
+func (r *noopQueryRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
 }                                                                                            //nolint:revive
 func (r *noopQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string)           {} //nolint:revive
 func (r *noopQueryRecorder) RecordConversationID(span telemetry.Span, conversationID string) {} //nolint:revive
@@ -84,6 +87,8 @@ func (r *noopAgentRecorder) StartToolCall(ctx context.Context, toolName, toolTyp
 
 func (r *noopAgentRecorder) RecordToolResult(span telemetry.Span, result string) {} //nolint:revive
 func (r *noopAgentRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
+} //nolint:revive
+func (r *noopAgentRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
 }                                                                       //nolint:revive
 func (r *noopAgentRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
 func (r *noopAgentRecorder) RecordError(span telemetry.Span, err error) {} //nolint:revive
@@ -106,10 +111,26 @@ func (r *noopModelRecorder) RecordInput(span telemetry.Span, messages any) {} //
 func (r *noopModelRecorder) RecordOutput(span telemetry.Span, output any)  {} //nolint:revive
 func (r *noopModelRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
 } //nolint:revive
+func (r *noopModelRecorder) RecordTokenDetails(span telemetry.Span, reasoningTokens, cachedTokens int64) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordReasoningSummary(span telemetry.Span, summary string) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+} //nolint:revive
 func (r *noopModelRecorder) RecordModelDetails(span telemetry.Span, modelName, modelType string) {
 }                                                                       //nolint:revive
 func (r *noopModelRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
 func (r *noopModelRecorder) RecordError(span telemetry.Span, err error) {} //nolint:revive
+func (r *noopModelRecorder) RecordFallback(span telemetry.Span, failedModel string) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordRoute(span telemetry.Span, model string) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordRateLimitWait(span telemetry.Span, wait time.Duration) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordRetry(span telemetry.Span, attempt int, delay time.Duration, err error) {
+} //nolint:revive
+func (r *noopModelRecorder) RecordCacheResult(span telemetry.Span, hit bool) {
+} //nolint:revive
 
 type noopToolRecorder struct{}
 
@@ -142,6 +163,8 @@ func (r *noopTeamRecorder) StartTurn(ctx context.Context, turn int, memberName,
 func (r *noopTeamRecorder) RecordTurnOutput(span telemetry.Span, messages any, messageCount int) {
 } //nolint:revive
 func (r *noopTeamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
+} //nolint:revive
+func (r *noopTeamRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
 }                                                                      //nolint:revive
 func (r *noopTeamRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
 func (r *noopTeamRecorder) RecordError(span telemetry.Span, err error) {} //nolint:revive
@@ -79,6 +79,11 @@ func (r *agentRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+// RecordCost records the cost of the tokens by currency.
+func (r *agentRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 // RecordSuccess marks a span as successfully completed.
 func (r *agentRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
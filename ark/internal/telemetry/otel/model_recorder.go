@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/openai/openai-go"
 	"mckinsey.com/ark/internal/telemetry"
@@ -161,6 +162,21 @@ func (r *modelRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+func (r *modelRecorder) RecordTokenDetails(span telemetry.Span, reasoningTokens, cachedTokens int64) {
+	span.SetAttributes(
+		telemetry.Int64(telemetry.AttrTokensReasoning, reasoningTokens),
+		telemetry.Int64(telemetry.AttrTokensCached, cachedTokens),
+	)
+}
+
+func (r *modelRecorder) RecordReasoningSummary(span telemetry.Span, summary string) {
+	span.SetAttributes(telemetry.String(telemetry.AttrReasoningSummary, summary))
+}
+
+func (r *modelRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *modelRecorder) RecordModelDetails(span telemetry.Span, modelName, modelType string) {
 	span.SetAttributes(
 		telemetry.String(telemetry.AttrModelName, modelName),
@@ -170,6 +186,31 @@ func (r *modelRecorder) RecordModelDetails(span telemetry.Span, modelName, model
 	)
 }
 
+func (r *modelRecorder) RecordFallback(span telemetry.Span, failedModel string) {
+	span.SetAttributes(telemetry.String(telemetry.AttrModelFallback, failedModel))
+}
+
+func (r *modelRecorder) RecordRoute(span telemetry.Span, model string) {
+	span.SetAttributes(telemetry.String(telemetry.AttrModelRoutedTo, model))
+}
+
+func (r *modelRecorder) RecordRateLimitWait(span telemetry.Span, wait time.Duration) {
+	span.SetAttributes(telemetry.Int64(telemetry.AttrModelRateWait, wait.Milliseconds()))
+}
+
+func (r *modelRecorder) RecordRetry(span telemetry.Span, attempt int, delay time.Duration, err error) {
+	span.AddEvent("retry",
+		telemetry.Int("attempt", attempt),
+		telemetry.Int64("delay_ms", delay.Milliseconds()),
+		telemetry.String("error", err.Error()),
+	)
+	span.SetAttributes(telemetry.Int(telemetry.AttrModelAttempts, attempt+1))
+}
+
+func (r *modelRecorder) RecordCacheResult(span telemetry.Span, hit bool) {
+	span.SetAttributes(telemetry.Bool(telemetry.AttrModelCacheHit, hit))
+}
+
 func (r *modelRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
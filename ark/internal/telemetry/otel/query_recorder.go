@@ -81,6 +81,10 @@ func (r *queryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+func (r *queryRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *queryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
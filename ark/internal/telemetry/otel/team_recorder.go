@@ -76,6 +76,10 @@ func (r *teamRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, compl
 	)
 }
 
+func (r *teamRecorder) RecordCost(span telemetry.Span, cost map[string]string) {
+	span.SetAttributes(telemetry.CostAttributes(cost)...)
+}
+
 func (r *teamRecorder) RecordSuccess(span telemetry.Span) {
 	span.SetStatus(telemetry.StatusOk, "success")
 }
@@ -4,6 +4,10 @@ package telemetry
 
 import (
 	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
@@ -32,6 +36,9 @@ type QueryRecorder interface {
 	// RecordTokenUsage records LLM token consumption.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordCost records the cost of the query's tokens by currency.
+	RecordCost(span Span, cost map[string]string)
+
 	// RecordSessionID associates a span with a session for multi-query tracking.
 	RecordSessionID(span Span, sessionID string)
 
@@ -63,6 +70,9 @@ type AgentRecorder interface {
 	// RecordTokenUsage records token consumption for LLM calls.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordCost records the cost of the agent's tokens by currency.
+	RecordCost(span Span, cost map[string]string)
+
 	// RecordSuccess marks a span as successfully completed.
 	RecordSuccess(span Span)
 
@@ -89,9 +99,34 @@ type ModelRecorder interface {
 	// RecordTokenUsage records token consumption for the model call.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordTokenDetails records the reasoning tokens included in the completion tokens and the
+	// cached tokens included in the prompt tokens of the model call.
+	RecordTokenDetails(span Span, reasoningTokens, cachedTokens int64)
+
+	// RecordReasoningSummary records the summary of the model's reasoning before its answer.
+	RecordReasoningSummary(span Span, summary string)
+
+	// RecordCost records the cost of the model call's tokens by currency.
+	RecordCost(span Span, cost map[string]string)
+
 	// RecordModelDetails records model configuration. Provider is extracted from modelType.
 	RecordModelDetails(span Span, modelName, modelType string)
 
+	// RecordFallback marks a model call as served by a fallback after the named model failed.
+	RecordFallback(span Span, failedModel string)
+
+	// RecordRoute records the model a router dispatched the call to.
+	RecordRoute(span Span, model string)
+
+	// RecordRateLimitWait records how long a model call waited for the model's rate limits.
+	RecordRateLimitWait(span Span, wait time.Duration)
+
+	// RecordRetry records a failed attempt of a model call that is retried after delay.
+	RecordRetry(span Span, attempt int, delay time.Duration, err error)
+
+	// RecordCacheResult records whether a model call was served from the model's cache.
+	RecordCacheResult(span Span, hit bool)
+
 	// RecordSuccess marks a span as successfully completed.
 	RecordSuccess(span Span)
 
@@ -130,6 +165,9 @@ type TeamRecorder interface {
 	// RecordTokenUsage records token consumption for team execution.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordCost records the cost of the team's tokens by currency.
+	RecordCost(span Span, cost map[string]string)
+
 	// RecordSuccess marks a span as successfully completed.
 	RecordSuccess(span Span)
 
@@ -163,11 +201,24 @@ const (
 	AttrModelName     = "llm.model.name"
 	AttrModelProvider = "llm.model.provider"
 	AttrModelType     = "llm.model.type"
+	AttrModelFallback = "llm.model.fallback_for"
+	AttrModelRoutedTo = "llm.model.routed_to"
+	AttrModelRateWait = "llm.model.rate_limit_wait_ms"
+	AttrModelAttempts = "llm.model.attempts"
+	AttrModelCacheHit = "llm.model.cache_hit"
 
 	// Token usage (aligned with OpenTelemetry GenAI conventions)
 	AttrTokensPrompt     = "gen_ai.usage.input_tokens"
 	AttrTokensCompletion = "gen_ai.usage.output_tokens"
 	AttrTokensTotal      = "gen_ai.usage.total_tokens"
+	AttrTokensReasoning  = "gen_ai.usage.reasoning_tokens"
+	AttrTokensCached     = "gen_ai.usage.cache_read_input_tokens"
+
+	// Summary of the model's reasoning, for models with reasoning summaries enabled
+	AttrReasoningSummary = "llm.reasoning.summary"
+
+	// Cost of the tokens, suffixed with the currency (e.g. llm.usage.cost.USD)
+	AttrCostPrefix = "llm.usage.cost."
 
 	// Langfuse-specific attributes for compatibility
 	AttrLangfuseModel    = "model"
@@ -201,6 +252,19 @@ const (
 	AttrFinishReason = "gen_ai.completion.finish_reason"
 )
 
+// CostAttributes returns an attribute per currency for the cost of tokens
+func CostAttributes(cost map[string]string) []Attribute {
+	attrs := make([]Attribute, 0, len(cost))
+	for _, currency := range slices.Sorted(maps.Keys(cost)) {
+		amount, err := strconv.ParseFloat(cost[currency], 64)
+		if err != nil {
+			continue
+		}
+		attrs = append(attrs, Float64(AttrCostPrefix+currency, amount))
+	}
+	return attrs
+}
+
 // Provider is an interface for telemetry providers that can create recorders.
 type Provider interface {
 	Tracer() Tracer
@@ -6,13 +6,16 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
 )
 
 // SetupAgentWebhookWithManager registers the webhook for Agent in the manager.
@@ -54,7 +57,7 @@ func (d *AgentCustomDefaulter) Default(ctx context.Context, obj runtime.Object)
 				agent.Annotations = make(map[string]string)
 			}
 			agent.Annotations[annotations.MigrationWarningPrefix+"tool-type-custom"] = fmt.Sprintf(
-				"agent '%s' tool '%s': type 'custom' is deprecated, use the tool's actual type (mcp, http, agent, team, builtin) instead",
+				"agent '%s' tool '%s': type 'custom' is deprecated, use the tool's actual type (mcp, http, agent, team, builtin, kubernetes, grpc) instead",
 				agent.Name,
 				tool.Name,
 			)
@@ -102,9 +105,11 @@ func (v *AgentCustomValidator) ValidateDelete(ctx context.Context, obj runtime.O
 func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1alpha1.Agent) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
-	if err := v.validateAgentModel(ctx, agent); err != nil {
+	modelWarnings, err := v.validateAgentModel(ctx, agent)
+	if err != nil {
 		return warnings, err
 	}
+	warnings = append(warnings, modelWarnings...)
 
 	if err := v.ValidateParameters(ctx, agent.Namespace, agent.Spec.Parameters); err != nil {
 		return warnings, err
@@ -114,6 +119,14 @@ func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1al
 		return warnings, err
 	}
 
+	if err := genai.ValidateOutputSchema(agent.Spec.OutputSchema); err != nil {
+		return warnings, fmt.Errorf("invalid outputSchema: %w", err)
+	}
+
+	if err := validateContextPolicy(agent.Spec.ContextPolicy); err != nil {
+		return warnings, err
+	}
+
 	for i, tool := range agent.Spec.Tools {
 		toolWarnings, err := v.validateTool(i, tool)
 		if err != nil {
@@ -128,11 +141,53 @@ func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1al
 	return warnings, nil
 }
 
-func (v *AgentCustomValidator) validateAgentModel(ctx context.Context, agent *arkv1alpha1.Agent) error {
-	// Model validation is now handled at runtime via status conditions
+// validateContextPolicy rejects a keep-first-last policy that would remove the latest message
+func validateContextPolicy(policy *arkv1alpha1.ContextPolicy) error {
+	if policy != nil && policy.Strategy == genai.ContextStrategyKeepFirstLast && policy.KeepLast < 1 {
+		return fmt.Errorf("contextPolicy.keepLast must be at least 1 for the keep-first-last strategy, so the latest message is kept")
+	}
+	return nil
+}
+
+// validateAgentModel rejects agents whose tools one of their models is known not to support, from
+// the capabilities detected by the model controller. Models without structured output only get a
+// warning, since the agent validates their answers against the outputSchema and asks for a fix.
+func (v *AgentCustomValidator) validateAgentModel(ctx context.Context, agent *arkv1alpha1.Agent) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	// Model availability is handled at runtime via status conditions
 	// Agents without valid models will show as Available: False
 	// This allows for eventual consistency when models are created after agents
-	return nil
+	var refs []arkv1alpha1.AgentModelRef
+	if agent.Spec.ModelRef != nil {
+		refs = append(refs, *agent.Spec.ModelRef)
+	}
+	refs = append(refs, agent.Spec.FallbackModels...)
+
+	for _, ref := range refs {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		var model arkv1alpha1.Model
+		if err := v.Client.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, &model); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return warnings, fmt.Errorf("failed to get model %s: %w", ref.Name, err)
+		}
+
+		capabilities := model.Status.Capabilities
+		if capabilities == nil {
+			continue
+		}
+		if len(agent.Spec.Tools) > 0 && capabilities.ToolCalling != nil && !*capabilities.ToolCalling {
+			return warnings, fmt.Errorf("model %s does not support tool calling, which the agent's tools require", ref.Name)
+		}
+		if agent.Spec.OutputSchema != nil && capabilities.StructuredOutput != nil && !*capabilities.StructuredOutput {
+			warnings = append(warnings, fmt.Sprintf("model %s does not support structured output, so its answers may need repairs to match the agent's outputSchema", ref.Name))
+		}
+	}
+	return warnings, nil
 }
 
 func (v *AgentCustomValidator) validateBuiltInTool(tool arkv1alpha1.AgentTool, hasName bool, index int) error {
@@ -166,10 +221,10 @@ func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentToo
 		if err := v.validateBuiltInTool(tool, hasName, index); err != nil {
 			return warnings, err
 		}
-	case "custom", "mcp", "http", "agent", "team", "builtin":
+	case "custom", "mcp", "http", "agent", "team", "builtin", "kubernetes", "grpc":
 		return v.validateCustomTool(tool, hasName, index)
 	default:
-		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, mcp, http, agent, team, builtin", index, tool.Type)
+		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, mcp, http, agent, team, builtin, kubernetes, grpc", index, tool.Type)
 	}
 
 	return warnings, nil
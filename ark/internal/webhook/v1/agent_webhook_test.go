@@ -59,6 +59,61 @@ var _ = Describe("Agent Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 
+		It("Should reject an invalid outputSchema", func() {
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type": 5}`)}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("invalid outputSchema")))
+		})
+
+		It("Should reject a keep-first-last context policy that keeps no recent messages", func() {
+			agent.Spec.ContextPolicy = &arkv1alpha1.ContextPolicy{MaxTokens: 1000, Strategy: genai.ContextStrategyKeepFirstLast}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("contextPolicy.keepLast must be at least 1")))
+
+			agent.Spec.ContextPolicy.KeepLast = 2
+			_, err = validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject tools when the model does not support tool calling", func() {
+			toolCalling := false
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-tools", Namespace: "default"},
+				Status:     arkv1alpha1.ModelStatus{Capabilities: &arkv1alpha1.ModelCapabilities{ToolCalling: &toolCalling}},
+			})).To(Succeed())
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "no-tools"}
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "built-in", Name: "noop"}}
+
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("model no-tools does not support tool calling")))
+		})
+
+		It("Should warn about an outputSchema when a fallback model does not support structured output", func() {
+			structuredOutput := false
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+				Status:     arkv1alpha1.ModelStatus{Capabilities: &arkv1alpha1.ModelCapabilities{StructuredOutput: &structuredOutput}},
+			})).To(Succeed())
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "missing"}
+			agent.Spec.FallbackModels = []arkv1alpha1.AgentModelRef{{Name: "plain"}}
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type": "object"}`)}
+
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("model plain does not support structured output")))
+		})
+
+		It("Should allow tools when the model capabilities are not detected yet", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"},
+			})).To(Succeed())
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "new"}
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "built-in", Name: "noop"}}
+
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("Should allow A2A agents without model validation", func() {
 			// Set execution engine to A2A
 			agent.Spec.ExecutionEngine = &arkv1alpha1.ExecutionEngineRef{
@@ -0,0 +1,74 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var guardrailLog = logf.Log.WithName("guardrail-resource")
+
+func SetupGuardrailWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&arkv1alpha1.Guardrail{}).
+		WithValidator(&GuardrailValidator{
+			ResourceValidator: &ResourceValidator{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-guardrail,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=guardrails,verbs=create;update,versions=v1alpha1,name=vguardrail-v1alpha1.kb.io,admissionReviewVersions=v1
+
+type GuardrailValidator struct {
+	*ResourceValidator
+}
+
+var _ webhook.CustomValidator = &GuardrailValidator{}
+
+func (v *GuardrailValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	guardrail, ok := obj.(*arkv1alpha1.Guardrail)
+	if !ok {
+		return nil, fmt.Errorf("expected a Guardrail object but got %T", obj)
+	}
+
+	guardrailLog.Info("Validating Guardrail", "name", guardrail.GetName(), "namespace", guardrail.GetNamespace())
+
+	if err := genai.ValidateGuardrailSpec(&guardrail.Spec); err != nil {
+		return nil, err
+	}
+
+	if classifier := guardrail.Spec.Classifier; classifier != nil {
+		for i, header := range classifier.Headers {
+			if err := ValidateHeader(header, fmt.Sprintf("classifier.headers[%d]", i)); err != nil {
+				return nil, err
+			}
+		}
+		if classifier.ModelRef != nil {
+			modelNamespace := cmp.Or(classifier.ModelRef.Namespace, guardrail.GetNamespace())
+			if err := v.ValidateLoadModel(ctx, classifier.ModelRef.Name, modelNamespace); err != nil {
+				return nil, fmt.Errorf("classifier: %w", err)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (v *GuardrailValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *GuardrailValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
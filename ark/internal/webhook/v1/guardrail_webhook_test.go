@@ -0,0 +1,76 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Guardrail Webhook", func() {
+	var (
+		ctx       context.Context
+		guardrail *arkv1alpha1.Guardrail
+		validator *GuardrailValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		validator = &GuardrailValidator{ResourceValidator: &ResourceValidator{Client: fakeClient}}
+		guardrail = &arkv1alpha1.Guardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-secrets", Namespace: "default"},
+			Spec: arkv1alpha1.GuardrailSpec{
+				Action:   "redact",
+				Patterns: []string{`sk-[A-Za-z0-9]{20,}`},
+				PII:      []string{"email"},
+			},
+		}
+	})
+
+	It("Should accept a valid guardrail", func() {
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should reject an invalid pattern", func() {
+		guardrail.Spec.Patterns = []string{"(unclosed"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("invalid pattern")))
+	})
+
+	It("Should reject a guardrail without checks", func() {
+		guardrail.Spec = arkv1alpha1.GuardrailSpec{Action: "block"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("at least one")))
+	})
+
+	It("Should reject an invalid JSON schema", func() {
+		guardrail.Spec.JSONSchema = &runtime.RawExtension{Raw: []byte(`{"type": 5}`)}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("jsonSchema")))
+	})
+
+	It("Should require exactly one classifier source", func() {
+		guardrail.Spec.Classifier = &arkv1alpha1.GuardrailClassifier{Prompt: "Flag toxic content"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of address and modelRef")))
+	})
+
+	It("Should reject a classifier model that does not exist", func() {
+		guardrail.Spec.Classifier = &arkv1alpha1.GuardrailClassifier{ModelRef: &arkv1alpha1.AgentModelRef{Name: "missing"}}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("model 'missing' does not exist")))
+	})
+})
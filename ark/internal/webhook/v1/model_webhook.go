@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -126,6 +127,18 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 
 	modellog.Info("Validating Model", "name", model.GetName(), "namespace", model.GetNamespace())
 
+	if model.Spec.Type == genai.ModelTypeRouter {
+		if err := v.validateRouter(ctx, model); err != nil {
+			return nil, err
+		}
+		modellog.Info("Model validation complete", "name", model.GetName())
+		return collectMigrationWarnings(model.Annotations), nil
+	}
+
+	if model.Spec.Model.Value == "" && model.Spec.Model.ValueFrom == nil {
+		return nil, fmt.Errorf("spec.model is required")
+	}
+
 	// Validate model field ValueSource
 	if err := v.validateValueSource(ctx, &model.Spec.Model, model.GetNamespace(), "spec.model"); err != nil {
 		return nil, err
@@ -136,11 +149,97 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 		return nil, err
 	}
 
+	if err := validateModelType(model); err != nil {
+		return nil, err
+	}
+
+	if err := v.validateCacheConfig(ctx, model); err != nil {
+		return nil, err
+	}
+
 	modellog.Info("Model validation complete", "name", model.GetName())
 
 	return collectMigrationWarnings(model.Annotations), nil
 }
 
+// validateModelType checks that the provider supports the model type
+func validateModelType(model *arkv1alpha1.Model) error {
+	switch model.Spec.Type {
+	case genai.ModelTypeEmbeddings:
+		if model.Spec.Reasoning != nil {
+			return fmt.Errorf("spec.reasoning cannot be set on embeddings models")
+		}
+		switch model.Spec.Provider {
+		case genai.ProviderOpenAI, genai.ProviderAzure, genai.ProviderBedrock:
+			return nil
+		}
+		return fmt.Errorf("provider %s does not support type embeddings: supported providers are openai, azure and bedrock", model.Spec.Provider)
+	case genai.ModelTypeResponses:
+		switch model.Spec.Provider {
+		case genai.ProviderOpenAI, genai.ProviderAzure:
+			return nil
+		}
+		return fmt.Errorf("provider %s does not support type responses: supported providers are openai and azure", model.Spec.Provider)
+	}
+	return nil
+}
+
+// validateRouter checks a router model. Routers only dispatch calls, so the provider settings,
+// limits, cache, pricing and reasoning are set on the models of the routes.
+func (v *ModelValidator) validateRouter(ctx context.Context, model *arkv1alpha1.Model) error {
+	spec := model.Spec
+	if spec.Router == nil || len(spec.Router.Routes) == 0 {
+		return fmt.Errorf("spec.router.routes is required for router models")
+	}
+	if spec.Provider != "" {
+		return fmt.Errorf("spec.provider cannot be set on router models: set it on the models of the routes")
+	}
+	if spec.RateLimits != nil || spec.RetryPolicy != nil || spec.Cache != nil || spec.Pricing != nil || spec.Reasoning != nil {
+		return fmt.Errorf("spec.rateLimits, spec.retryPolicy, spec.cache, spec.pricing and spec.reasoning cannot be set on router models: set them on the models of the routes")
+	}
+
+	for i, route := range spec.Router.Routes {
+		path := fmt.Sprintf("spec.router.routes[%d]", i)
+		name, namespace, err := genai.ResolveModelSpec(&route.ModelRef, model.GetNamespace())
+		if err != nil {
+			return fmt.Errorf("%s.modelRef: %w", path, err)
+		}
+		if name == model.GetName() && namespace == model.GetNamespace() {
+			return fmt.Errorf("%s.modelRef: a router cannot route to itself", path)
+		}
+		if when := route.When; when != nil && when.MaxPromptTokens > 0 && when.MinPromptTokens > when.MaxPromptTokens {
+			return fmt.Errorf("%s.when: minPromptTokens %d is greater than maxPromptTokens %d", path, when.MinPromptTokens, when.MaxPromptTokens)
+		}
+
+		// Routed models may be created after the router, so only existing ones are checked
+		var routed arkv1alpha1.Model
+		if err := v.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &routed); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("%s.modelRef: %w", path, err)
+		}
+		switch routed.Spec.Type {
+		case genai.ModelTypeRouter:
+			return fmt.Errorf("%s.modelRef: model %s is a router and cannot be routed to", path, name)
+		case genai.ModelTypeEmbeddings:
+			return fmt.Errorf("%s.modelRef: model %s is an embeddings model and cannot be routed to", path, name)
+		}
+	}
+	return nil
+}
+
+// validateCacheConfig checks the value sources of the cache service, if any
+func (v *ModelValidator) validateCacheConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Cache == nil || model.Spec.Cache.HTTP == nil {
+		return nil
+	}
+	if err := v.validateValueSource(ctx, &model.Spec.Cache.HTTP.URL, model.GetNamespace(), "spec.cache.http.url"); err != nil {
+		return err
+	}
+	return v.validateValueSource(ctx, model.Spec.Cache.HTTP.Token, model.GetNamespace(), "spec.cache.http.token")
+}
+
 func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	switch model.Spec.Provider {
 	case genai.ProviderAzure:
@@ -149,6 +248,10 @@ func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv
 		return v.validateOpenAIConfig(ctx, model)
 	case genai.ProviderBedrock:
 		return v.validateBedrockConfig(ctx, model)
+	case genai.ProviderAnthropic:
+		return v.validateAnthropicConfig(ctx, model)
+	case genai.ProviderGemini:
+		return v.validateGeminiConfig(ctx, model)
 	default:
 		if model.Spec.Provider == "" {
 			if genai.IsDeprecatedProviderInType(model.Spec.Type) {
@@ -221,6 +324,77 @@ func (v *ModelValidator) validateOpenAIConfig(ctx context.Context, model *arkv1a
 	return nil
 }
 
+func (v *ModelValidator) validateAnthropicConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Anthropic == nil {
+		return fmt.Errorf("anthropic configuration is required for anthropic model type")
+	}
+
+	if model.Spec.Config.Anthropic.BaseURL != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Anthropic.BaseURL, model.GetNamespace(), "spec.config.anthropic.baseUrl"); err != nil {
+			return err
+		}
+		if _, err := v.Resolver.ResolveValueSource(ctx, *model.Spec.Config.Anthropic.BaseURL, model.GetNamespace()); err != nil {
+			modellog.Error(err, "Failed to resolve Anthropic BaseURL", "model", model.GetName())
+			return fmt.Errorf("failed to resolve Anthropic BaseURL: %w", err)
+		}
+	}
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Anthropic.APIKey, model.GetNamespace(), "spec.config.anthropic.apiKey"); err != nil {
+		return err
+	}
+	if model.Spec.Config.Anthropic.APIVersion != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Anthropic.APIVersion, model.GetNamespace(), "spec.config.anthropic.apiVersion"); err != nil {
+			return err
+		}
+	}
+
+	for i, header := range model.Spec.Config.Anthropic.Headers {
+		contextPrefix := fmt.Sprintf("spec.config.anthropic.headers[%d]", i)
+		if err := ValidateHeader(header, contextPrefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *ModelValidator) validateGeminiConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	config := model.Spec.Config.Gemini
+	if config == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+	if (config.APIKey == nil) == (config.ServiceAccountKey == nil) {
+		return fmt.Errorf("spec.config.gemini requires exactly one of apiKey and serviceAccountKey")
+	}
+
+	valueSources := []struct {
+		source *arkv1alpha1.ValueSource
+		path   string
+	}{
+		{config.BaseURL, "spec.config.gemini.baseUrl"},
+		{config.APIKey, "spec.config.gemini.apiKey"},
+		{config.ServiceAccountKey, "spec.config.gemini.serviceAccountKey"},
+		{config.Project, "spec.config.gemini.project"},
+		{config.Location, "spec.config.gemini.location"},
+	}
+	for _, vs := range valueSources {
+		if vs.source == nil {
+			continue
+		}
+		if err := v.validateValueSource(ctx, vs.source, model.GetNamespace(), vs.path); err != nil {
+			return err
+		}
+	}
+
+	for i, header := range config.Headers {
+		contextPrefix := fmt.Sprintf("spec.config.gemini.headers[%d]", i)
+		if err := ValidateHeader(header, contextPrefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (v *ModelValidator) validateBedrockConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	if model.Spec.Config.Bedrock == nil {
 		return fmt.Errorf("bedrock configuration is required for bedrock model type")
@@ -104,6 +104,181 @@ var _ = Describe("Model Webhook", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(warnings).To(BeEmpty())
 		})
+
+		It("Should allow valid Anthropic model with direct values", func() {
+			model.Spec.Provider = genai.ProviderAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Anthropic: &arkv1alpha1.AnthropicModelConfig{
+					APIKey: arkv1alpha1.ValueSource{
+						Value: "test-key",
+					},
+					PromptCaching: true,
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject Anthropic model without anthropic configuration", func() {
+			model.Spec.Provider = genai.ProviderAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("anthropic configuration is required"))
+		})
+
+		It("Should allow valid Gemini model with an API key", func() {
+			model.Spec.Provider = genai.ProviderGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{
+					APIKey: &arkv1alpha1.ValueSource{
+						Value: "test-key",
+					},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject Gemini model with both an API key and a service account key", func() {
+			model.Spec.Provider = genai.ProviderGemini
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Gemini: &arkv1alpha1.GeminiModelConfig{
+					APIKey:            &arkv1alpha1.ValueSource{Value: "test-key"},
+					ServiceAccountKey: &arkv1alpha1.ValueSource{Value: "{}"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of apiKey and serviceAccountKey"))
+		})
+
+		It("Should allow OpenAI embeddings model", func() {
+			model.Spec.Type = genai.ModelTypeEmbeddings
+
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject embeddings model with reasoning", func() {
+			model.Spec.Type = genai.ModelTypeEmbeddings
+			model.Spec.Reasoning = &arkv1alpha1.ModelReasoning{Effort: "low"}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.reasoning cannot be set on embeddings models"))
+		})
+
+		It("Should reject embeddings model for a provider without embeddings", func() {
+			model.Spec.Type = genai.ModelTypeEmbeddings
+			model.Spec.Provider = genai.ProviderAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Anthropic: &arkv1alpha1.AnthropicModelConfig{
+					APIKey: arkv1alpha1.ValueSource{Value: "test-key"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not support type embeddings"))
+		})
+
+		It("Should reject responses model for a provider other than OpenAI or Azure", func() {
+			model.Spec.Type = genai.ModelTypeResponses
+			model.Spec.Provider = genai.ProviderAnthropic
+			model.Spec.Config = arkv1alpha1.ModelConfig{
+				Anthropic: &arkv1alpha1.AnthropicModelConfig{
+					APIKey: arkv1alpha1.ValueSource{Value: "test-key"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not support type responses"))
+		})
+	})
+
+	Context("When validating router models", func() {
+		BeforeEach(func() {
+			model.Spec = arkv1alpha1.ModelSpec{
+				Type: genai.ModelTypeRouter,
+				Router: &arkv1alpha1.ModelRouter{
+					Routes: []arkv1alpha1.ModelRoute{
+						{ModelRef: arkv1alpha1.AgentModelRef{Name: "gpt-4o"}, Weight: 9},
+						{ModelRef: arkv1alpha1.AgentModelRef{Name: "claude"}, Weight: 1},
+					},
+				},
+			}
+		})
+
+		It("Should allow a router without a provider or model", func() {
+			warnings, err := validator.ValidateCreate(ctx, model)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject a router without routes", func() {
+			model.Spec.Router = nil
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.router.routes is required"))
+		})
+
+		It("Should reject a router with pricing", func() {
+			model.Spec.Pricing = &arkv1alpha1.ModelPricing{InputPerMillion: "1", OutputPerMillion: "2"}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be set on router models"))
+		})
+
+		It("Should reject a router routing to itself", func() {
+			model.Spec.Router.Routes[1].ModelRef.Name = model.Name
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.router.routes[1].modelRef: a router cannot route to itself"))
+		})
+
+		It("Should reject a route to another router", func() {
+			Expect(validator.Client.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "claude", Namespace: "default"},
+				Spec: arkv1alpha1.ModelSpec{
+					Type:   genai.ModelTypeRouter,
+					Router: &arkv1alpha1.ModelRouter{Routes: []arkv1alpha1.ModelRoute{{ModelRef: arkv1alpha1.AgentModelRef{Name: "gpt-4o"}}}},
+				},
+			})).To(Succeed())
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("model claude is a router and cannot be routed to"))
+		})
+
+		It("Should reject a route whose prompt token range is empty", func() {
+			model.Spec.Router.Routes[0].When = &arkv1alpha1.ModelRouteCondition{MinPromptTokens: 1000, MaxPromptTokens: 100}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("minPromptTokens 1000 is greater than maxPromptTokens 100"))
+		})
+	})
+
+	Context("When validating models without a model name", func() {
+		It("Should reject a completions model without spec.model", func() {
+			model.Spec.Model = arkv1alpha1.ValueSource{}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.model is required"))
+		})
 	})
 
 	Context("When validating models with Secret references", func() {
@@ -126,6 +301,26 @@ var _ = Describe("Model Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 
+		It("Should fail when the cache token Secret does not exist", func() {
+			model.Spec.Cache = &arkv1alpha1.ModelCache{
+				HTTP: &arkv1alpha1.ModelCacheHTTP{
+					URL: arkv1alpha1.ValueSource{Value: "http://redis-rest:8080"},
+					Token: &arkv1alpha1.ValueSource{
+						ValueFrom: &arkv1alpha1.ValueFromSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "missing-cache-token"},
+								Key:                  "token",
+							},
+						},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, model)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.cache.http.token"))
+		})
+
 		It("Should fail when referenced Secret key does not exist", func() {
 			// Create a Secret without the expected key
 			secret := &corev1.Secret{
@@ -0,0 +1,95 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var openAPIToolSetLog = logf.Log.WithName("openapitoolset-resource")
+
+func SetupOpenAPIToolSetWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&arkv1alpha1.OpenAPIToolSet{}).
+		WithValidator(&OpenAPIToolSetValidator{
+			ResourceValidator: &ResourceValidator{Client: mgr.GetClient()},
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-openapitoolset,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=openapitoolsets,verbs=create;update,versions=v1alpha1,name=vopenapitoolset-v1alpha1.kb.io,admissionReviewVersions=v1
+
+type OpenAPIToolSetValidator struct {
+	*ResourceValidator
+}
+
+var _ webhook.CustomValidator = &OpenAPIToolSetValidator{}
+
+func (v *OpenAPIToolSetValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	toolSet, ok := obj.(*arkv1alpha1.OpenAPIToolSet)
+	if !ok {
+		return nil, fmt.Errorf("expected an OpenAPIToolSet object but got %T", obj)
+	}
+
+	openAPIToolSetLog.Info("Validating OpenAPIToolSet", "name", toolSet.GetName(), "namespace", toolSet.GetNamespace())
+
+	source := toolSet.Spec.Spec
+	sources := 0
+	for _, set := range []bool{source.URL != "", source.ConfigMapKeyRef != nil, source.Inline != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("spec.spec requires exactly one of url, configMapKeyRef or inline")
+	}
+
+	for name, filter := range map[string]*arkv1alpha1.OpenAPIOperationFilter{"include": toolSet.Spec.Include, "exclude": toolSet.Spec.Exclude} {
+		if err := validateOperationFilter(filter); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	for i, header := range toolSet.Spec.Headers {
+		if err := ValidateHeader(header, fmt.Sprintf("headers[%d]", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	if toolSet.Spec.Auth != nil {
+		return validateHTTPAuth(&arkv1alpha1.HTTPSpec{Headers: toolSet.Spec.Headers, Auth: toolSet.Spec.Auth})
+	}
+
+	return nil, nil
+}
+
+// validateOperationFilter validates the wildcard patterns of a filter
+func validateOperationFilter(filter *arkv1alpha1.OpenAPIOperationFilter) error {
+	if filter == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string{}, filter.OperationIDs...), filter.Paths...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (v *OpenAPIToolSetValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *OpenAPIToolSetValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
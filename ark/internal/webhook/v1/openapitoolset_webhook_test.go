@@ -0,0 +1,78 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("OpenAPIToolSet Webhook", func() {
+	var (
+		ctx       context.Context
+		toolSet   *arkv1alpha1.OpenAPIToolSet
+		validator *OpenAPIToolSetValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		validator = &OpenAPIToolSetValidator{ResourceValidator: &ResourceValidator{Client: fakeClient}}
+		toolSet = &arkv1alpha1.OpenAPIToolSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "petstore", Namespace: "default"},
+			Spec: arkv1alpha1.OpenAPIToolSetSpec{
+				Spec:    arkv1alpha1.OpenAPISpecSource{URL: "https://petstore3.swagger.io/api/v3/openapi.json"},
+				Include: &arkv1alpha1.OpenAPIOperationFilter{OperationIDs: []string{"get*"}, Paths: []string{"/pet/*"}},
+				Headers: []arkv1alpha1.Header{{Name: "X-API-Key", Value: arkv1alpha1.HeaderValue{Value: "key"}}},
+			},
+		}
+	})
+
+	It("Should accept a valid toolset", func() {
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should require exactly one document source", func() {
+		toolSet.Spec.Spec.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "petstore"},
+			Key:                  "openapi.yaml",
+		}
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of url, configMapKeyRef or inline")))
+
+		toolSet.Spec.Spec = arkv1alpha1.OpenAPISpecSource{}
+		_, err = validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of url, configMapKeyRef or inline")))
+	})
+
+	It("Should reject an invalid filter pattern", func() {
+		toolSet.Spec.Exclude = &arkv1alpha1.OpenAPIOperationFilter{Paths: []string{"/pet/[id"}}
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(MatchError(ContainSubstring("exclude: invalid pattern")))
+	})
+
+	It("Should reject an invalid header", func() {
+		toolSet.Spec.Headers = []arkv1alpha1.Header{{Name: "X-API-Key"}}
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(MatchError(ContainSubstring("headers[0]")))
+	})
+
+	It("Should validate auth", func() {
+		toolSet.Spec.Auth = &arkv1alpha1.HTTPAuth{}
+		_, err := validator.ValidateCreate(ctx, toolSet)
+		Expect(err).To(MatchError(ContainSubstring("auth requires one of")))
+	})
+})
@@ -7,9 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -83,8 +86,12 @@ func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.
 		return v.validateTeamTool(tool.Spec.Team.Name)
 	case genai.ToolTypeBuiltin:
 		return v.validateBuiltinTool(tool.Name)
+	case genai.ToolTypeKubernetes:
+		return v.validateKubernetesTool(tool)
+	case genai.ToolTypeGRPC:
+		return v.validateGRPCTool(tool.Spec.GRPC)
 	default:
-		return warnings, fmt.Errorf("unsupported tool type '%s': supported types are: http, mcp, agent, team, builtin", tool.Spec.Type)
+		return warnings, fmt.Errorf("unsupported tool type '%s': supported types are: http, mcp, agent, team, builtin, kubernetes, grpc", tool.Spec.Type)
 	}
 }
 
@@ -114,6 +121,83 @@ func (v *ToolCustomValidator) validateHTTP(httpSpec *arkv1alpha1.HTTPSpec) (admi
 		}
 	}
 
+	requestWarnings, err := validateHTTPRequest(httpSpec)
+	warnings = append(warnings, requestWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+
+	if httpSpec.Auth != nil {
+		authWarnings, err := validateHTTPAuth(httpSpec)
+		return append(warnings, authWarnings...), err
+	}
+
+	return warnings, nil
+}
+
+// validateHTTPRequest validates the body, status codes and retries of an HTTP tool
+func validateHTTPRequest(httpSpec *arkv1alpha1.HTTPSpec) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	method := httpSpec.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	bodies := 0
+	for _, set := range []bool{httpSpec.Body != "", len(httpSpec.Form) > 0, len(httpSpec.Multipart) > 0} {
+		if set {
+			bodies++
+		}
+	}
+	if bodies > 1 {
+		return warnings, fmt.Errorf("only one of body, form and multipart can be set")
+	}
+	if bodies > 0 && method != "POST" && method != "PUT" && method != "PATCH" {
+		warnings = append(warnings, fmt.Sprintf("the body of %s requests is not sent: bodies are only sent with POST, PUT and PATCH requests", method))
+	}
+
+	for _, codes := range httpSpec.SuccessStatusCodes {
+		if _, _, err := genai.ParseStatusCodeRange(codes); err != nil {
+			return warnings, fmt.Errorf("invalid successStatusCodes: %w", err)
+		}
+	}
+
+	if httpSpec.RetryPolicy != nil {
+		policy := httpSpec.RetryPolicy
+		if policy.InitialBackoff != nil && policy.MaxBackoff != nil && policy.InitialBackoff.Duration > policy.MaxBackoff.Duration {
+			return warnings, fmt.Errorf("retryPolicy.initialBackoff cannot be greater than retryPolicy.maxBackoff")
+		}
+		if method != "GET" && method != "PUT" && method != "DELETE" {
+			warnings = append(warnings, fmt.Sprintf("retryPolicy has no effect on %s requests: only GET, PUT and DELETE requests are retried", method))
+		}
+	}
+
+	return warnings, nil
+}
+
+// validateHTTPAuth validates the authentication of an HTTP tool
+func validateHTTPAuth(httpSpec *arkv1alpha1.HTTPSpec) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	auth := httpSpec.Auth
+
+	if auth.OAuth2 == nil && auth.Basic == nil && auth.ClientCertificate == nil {
+		return warnings, fmt.Errorf("auth requires one of oauth2, basic or clientCertificate")
+	}
+	if auth.OAuth2 != nil && auth.Basic != nil {
+		return warnings, fmt.Errorf("auth cannot set both oauth2 and basic")
+	}
+	if auth.OAuth2 != nil && auth.OAuth2.TokenURL == "" {
+		return warnings, fmt.Errorf("auth.oauth2.tokenUrl is required")
+	}
+
+	if auth.OAuth2 != nil || auth.Basic != nil {
+		for _, header := range httpSpec.Headers {
+			if strings.EqualFold(header.Name, "Authorization") {
+				warnings = append(warnings, "the Authorization header is replaced by the credentials of auth")
+			}
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -170,6 +254,82 @@ func (v *ToolCustomValidator) validateBuiltinTool(toolName string) (admission.Wa
 	return warnings, fmt.Errorf("unsupported builtin tool '%s': supported builtin tools are: %v", toolName, supportedBuiltinTools)
 }
 
+// validateKubernetesTool validates that write verbs are allowed by the tool annotations and that
+// the namespaces are valid
+func (v *ToolCustomValidator) validateKubernetesTool(tool *arkv1alpha1.Tool) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	spec := tool.Spec.Kubernetes
+	if spec == nil {
+		return warnings, nil
+	}
+
+	for _, verb := range spec.Verbs {
+		if genai.IsKubernetesWriteVerb(verb) && !genai.KubernetesToolAllowsWrites(tool.Spec.Annotations) {
+			return warnings, fmt.Errorf("kubernetes verb '%s' changes resources and requires the annotations destructiveHint: true and readOnlyHint: false", verb)
+		}
+	}
+
+	for _, namespace := range spec.Namespaces {
+		if namespace == "*" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return warnings, fmt.Errorf("invalid kubernetes namespace '%s': %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+
+	return warnings, nil
+}
+
+// validateGRPCTool validates the address, method, metadata and TLS settings of a gRPC tool
+func (v *ToolCustomValidator) validateGRPCTool(grpcSpec *arkv1alpha1.GRPCSpec) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	if grpcSpec == nil {
+		return warnings, fmt.Errorf("grpc spec is required for grpc type")
+	}
+
+	if grpcSpec.Address.Value == "" && grpcSpec.Address.ValueFrom == nil {
+		return warnings, fmt.Errorf("grpc address must specify either value or valueFrom")
+	}
+	if grpcSpec.Address.Value != "" && grpcSpec.Address.ValueFrom != nil {
+		return warnings, fmt.Errorf("grpc address cannot specify both value and valueFrom")
+	}
+
+	if grpcSpec.Service == "" || grpcSpec.Method == "" {
+		return warnings, fmt.Errorf("grpc service and method are required")
+	}
+	if strings.Contains(grpcSpec.Service, "/") || strings.Contains(grpcSpec.Method, "/") {
+		return warnings, fmt.Errorf("grpc service and method must be names such as orders.v1.OrderService and GetOrder, not paths")
+	}
+
+	for i, header := range grpcSpec.Headers {
+		if err := ValidateHeader(header, fmt.Sprintf("headers[%d]", i)); err != nil {
+			return warnings, err
+		}
+	}
+
+	// The CRD pattern also admits numbers without a unit, which are not durations
+	if grpcSpec.Timeout != "" {
+		if _, err := time.ParseDuration(grpcSpec.Timeout); err != nil {
+			return warnings, fmt.Errorf("grpc timeout %q must be a duration with a unit, such as 30s", grpcSpec.Timeout)
+		}
+	}
+
+	if grpcSpec.TLS == nil {
+		warnings = append(warnings, "grpc tls is not set, so calls and their metadata are sent to the server in plaintext")
+	} else {
+		if (grpcSpec.TLS.Cert == nil) != (grpcSpec.TLS.Key == nil) {
+			return warnings, fmt.Errorf("grpc tls.cert and tls.key must be set together")
+		}
+		if grpcSpec.TLS.InsecureSkipVerify {
+			warnings = append(warnings, "tls.insecureSkipVerify disables the verification of the server certificate")
+		}
+	}
+
+	return warnings, nil
+}
+
 // validateInputSchema validates the tool's inputSchema using jsonschema
 func (v *ToolCustomValidator) validateInputSchema(inputSchema json.RawMessage) error {
 	// Parse the JSON schema
@@ -69,4 +69,224 @@ var _ = Describe("Tool Webhook", func() {
 			Expect(warnings).To(BeEmpty())
 		})
 	})
+
+	Context("When validating http tool auth", func() {
+		var tool *arkv1alpha1.Tool
+
+		BeforeEach(func() {
+			tool = &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "http-tool", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeHTTP,
+					HTTP: &arkv1alpha1.HTTPSpec{
+						URL: "https://api.example.com/orders",
+						Auth: &arkv1alpha1.HTTPAuth{OAuth2: &arkv1alpha1.OAuth2ClientCredentials{
+							TokenURL:     "https://auth.example.com/oauth2/token",
+							ClientID:     arkv1alpha1.ValueSource{Value: "ark"},
+							ClientSecret: arkv1alpha1.ValueSource{Value: "secret"},
+						}},
+					},
+				},
+			}
+		})
+
+		It("Should allow oauth2 with a client certificate", func() {
+			tool.Spec.HTTP.Auth.ClientCertificate = &arkv1alpha1.TLSClientCertificate{
+				Cert: arkv1alpha1.ValueSource{Value: "cert"},
+				Key:  arkv1alpha1.ValueSource{Value: "key"},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject oauth2 with basic auth", func() {
+			tool.Spec.HTTP.Auth.Basic = &arkv1alpha1.BasicAuth{
+				Username: arkv1alpha1.ValueSource{Value: "ark"},
+				Password: arkv1alpha1.ValueSource{Value: "secret"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot set both oauth2 and basic"))
+		})
+
+		It("Should reject empty auth", func() {
+			tool.Spec.HTTP.Auth = &arkv1alpha1.HTTPAuth{}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("auth requires one of oauth2, basic or clientCertificate"))
+		})
+
+		It("Should warn about an Authorization header replaced by auth", func() {
+			tool.Spec.HTTP.Headers = []arkv1alpha1.Header{{Name: "authorization", Value: arkv1alpha1.HeaderValue{Value: "Bearer static"}}}
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Authorization header is replaced")))
+		})
+	})
+
+	Context("When validating http tool requests", func() {
+		var tool *arkv1alpha1.Tool
+
+		BeforeEach(func() {
+			tool = &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "http-tool", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeHTTP,
+					HTTP: &arkv1alpha1.HTTPSpec{URL: "https://api.example.com/orders", Method: "POST"},
+				},
+			}
+		})
+
+		It("Should allow a form body with success status codes", func() {
+			tool.Spec.HTTP.Form = []arkv1alpha1.HTTPFormField{{Name: "q", Value: "{{ .input.query }}"}}
+			tool.Spec.HTTP.SuccessStatusCodes = []string{"200-299", "404"}
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should reject both a body and a form", func() {
+			tool.Spec.HTTP.Body = `{"q": "{{ .input.query }}"}`
+			tool.Spec.HTTP.Form = []arkv1alpha1.HTTPFormField{{Name: "q", Value: "{{ .input.query }}"}}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of body, form and multipart"))
+		})
+
+		It("Should reject a reversed status code range", func() {
+			tool.Spec.HTTP.SuccessStatusCodes = []string{"299-200"}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid successStatusCodes"))
+		})
+
+		It("Should warn about retries of POST requests", func() {
+			tool.Spec.HTTP.RetryPolicy = &arkv1alpha1.HTTPRetryPolicy{MaxAttempts: 3}
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("retryPolicy has no effect on POST requests")))
+		})
+	})
+
+	Context("When validating kubernetes tools", func() {
+		var tool *arkv1alpha1.Tool
+
+		BeforeEach(func() {
+			tool = &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeKubernetes,
+					Kubernetes: &arkv1alpha1.KubernetesToolSpec{
+						Verbs:      []string{"get", "list", "scale"},
+						Namespaces: []string{"default", "*"},
+					},
+					Annotations: &arkv1alpha1.ToolAnnotations{DestructiveHint: true},
+				},
+			}
+		})
+
+		It("Should allow write verbs on destructive tools", func() {
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject write verbs on read-only tools", func() {
+			tool.Spec.Annotations.ReadOnlyHint = true
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("kubernetes verb 'scale' changes resources")))
+		})
+
+		It("Should reject write verbs without annotations", func() {
+			tool.Spec.Annotations = nil
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("destructiveHint: true")))
+		})
+
+		It("Should reject an invalid namespace", func() {
+			tool.Spec.Kubernetes.Namespaces = []string{"Prod_East"}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("invalid kubernetes namespace 'Prod_East'")))
+		})
+	})
+
+	Context("When validating grpc tools", func() {
+		var tool *arkv1alpha1.Tool
+
+		BeforeEach(func() {
+			tool = &arkv1alpha1.Tool{
+				ObjectMeta: metav1.ObjectMeta{Name: "get-order", Namespace: "default"},
+				Spec: arkv1alpha1.ToolSpec{
+					Type: genai.ToolTypeGRPC,
+					GRPC: &arkv1alpha1.GRPCSpec{
+						Address: arkv1alpha1.ValueSource{Value: "orders:50051"},
+						Service: "orders.v1.OrderService",
+						Method:  "GetOrder",
+					},
+				},
+			}
+		})
+
+		It("Should allow a valid grpc tool", func() {
+			tool.Spec.GRPC.TLS = &arkv1alpha1.GRPCTLS{}
+			tool.Spec.GRPC.Timeout = "10s"
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn that a grpc tool without tls uses plaintext", func() {
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("plaintext")))
+		})
+
+		It("Should reject a timeout without a unit", func() {
+			tool.Spec.GRPC.Timeout = "30"
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring(`grpc timeout "30" must be a duration with a unit`)))
+		})
+
+		It("Should reject a grpc tool without a spec", func() {
+			tool.Spec.GRPC = nil
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("grpc spec is required")))
+		})
+
+		It("Should reject a method path", func() {
+			tool.Spec.GRPC.Method = "/orders.v1.OrderService/GetOrder"
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("not paths")))
+		})
+
+		It("Should reject a client certificate without a key", func() {
+			tool.Spec.GRPC.TLS = &arkv1alpha1.GRPCTLS{Cert: &arkv1alpha1.ValueSource{Value: "cert"}}
+
+			_, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).To(MatchError(ContainSubstring("tls.cert and tls.key must be set together")))
+		})
+
+		It("Should warn about skipping the verification of the server certificate", func() {
+			tool.Spec.GRPC.TLS = &arkv1alpha1.GRPCTLS{InsecureSkipVerify: true}
+
+			warnings, err := validator.ValidateCreate(ctx, tool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("insecureSkipVerify")))
+		})
+	})
 })
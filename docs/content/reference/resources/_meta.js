@@ -1,9 +1,11 @@
 export default {
   a2aserver: 'A2AServers',
   agent: 'Agents',
+  guardrail: 'Guardrails',
   mcpserver: 'MCPServers',
   memory: 'Memories',
   models: 'Models',
+  openapitoolset: 'OpenAPIToolSets',
   query: 'Queries',
   team: 'Teams',
   tools: 'Tools',